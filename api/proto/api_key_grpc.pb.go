@@ -0,0 +1,177 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: api/api_key.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// APIKeysClient is the client API for APIKeys service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type APIKeysClient interface {
+	Create(ctx context.Context, in *APIKeyCreateRequest, opts ...grpc.CallOption) (*APIKey, error)
+	List(ctx context.Context, in *APIKeyListRequest, opts ...grpc.CallOption) (*APIKeyListResponse, error)
+	Revoke(ctx context.Context, in *APIKeyRevokeRequest, opts ...grpc.CallOption) (*APIKeyRevokeResponse, error)
+}
+
+type aPIKeysClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAPIKeysClient(cc grpc.ClientConnInterface) APIKeysClient {
+	return &aPIKeysClient{cc}
+}
+
+func (c *aPIKeysClient) Create(ctx context.Context, in *APIKeyCreateRequest, opts ...grpc.CallOption) (*APIKey, error) {
+	out := new(APIKey)
+	err := c.cc.Invoke(ctx, "/proto.APIKeys/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeysClient) List(ctx context.Context, in *APIKeyListRequest, opts ...grpc.CallOption) (*APIKeyListResponse, error) {
+	out := new(APIKeyListResponse)
+	err := c.cc.Invoke(ctx, "/proto.APIKeys/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeysClient) Revoke(ctx context.Context, in *APIKeyRevokeRequest, opts ...grpc.CallOption) (*APIKeyRevokeResponse, error) {
+	out := new(APIKeyRevokeResponse)
+	err := c.cc.Invoke(ctx, "/proto.APIKeys/Revoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// APIKeysServer is the server API for APIKeys service.
+// All implementations must embed UnimplementedAPIKeysServer
+// for forward compatibility
+type APIKeysServer interface {
+	Create(context.Context, *APIKeyCreateRequest) (*APIKey, error)
+	List(context.Context, *APIKeyListRequest) (*APIKeyListResponse, error)
+	Revoke(context.Context, *APIKeyRevokeRequest) (*APIKeyRevokeResponse, error)
+	mustEmbedUnimplementedAPIKeysServer()
+}
+
+// UnimplementedAPIKeysServer must be embedded to have forward compatible implementations.
+type UnimplementedAPIKeysServer struct {
+}
+
+func (UnimplementedAPIKeysServer) Create(context.Context, *APIKeyCreateRequest) (*APIKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedAPIKeysServer) List(context.Context, *APIKeyListRequest) (*APIKeyListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedAPIKeysServer) Revoke(context.Context, *APIKeyRevokeRequest) (*APIKeyRevokeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
+func (UnimplementedAPIKeysServer) mustEmbedUnimplementedAPIKeysServer() {}
+
+// UnsafeAPIKeysServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to APIKeysServer will
+// result in compilation errors.
+type UnsafeAPIKeysServer interface {
+	mustEmbedUnimplementedAPIKeysServer()
+}
+
+func RegisterAPIKeysServer(s grpc.ServiceRegistrar, srv APIKeysServer) {
+	s.RegisterService(&APIKeys_ServiceDesc, srv)
+}
+
+func _APIKeys_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(APIKeyCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeysServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.APIKeys/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeysServer).Create(ctx, req.(*APIKeyCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIKeys_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(APIKeyListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeysServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.APIKeys/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeysServer).List(ctx, req.(*APIKeyListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIKeys_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(APIKeyRevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeysServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.APIKeys/Revoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeysServer).Revoke(ctx, req.(*APIKeyRevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// APIKeys_ServiceDesc is the grpc.ServiceDesc for APIKeys service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var APIKeys_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.APIKeys",
+	HandlerType: (*APIKeysServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _APIKeys_Create_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _APIKeys_List_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _APIKeys_Revoke_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/api_key.proto",
+}
@@ -23,6 +23,10 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type InternalClient interface {
 	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
+	// RevokeUserTokens - отзывает все токены пользователя.
+	RevokeUserTokens(ctx context.Context, in *RevokeUserTokensRequest, opts ...grpc.CallOption) (*RevokeUserTokensResponse, error)
+	// SetUserPlan - назначает пользователю тарифный план.
+	SetUserPlan(ctx context.Context, in *SetUserPlanRequest, opts ...grpc.CallOption) (*SetUserPlanResponse, error)
 }
 
 type internalClient struct {
@@ -42,11 +46,33 @@ func (c *internalClient) Stats(ctx context.Context, in *StatsRequest, opts ...gr
 	return out, nil
 }
 
+func (c *internalClient) RevokeUserTokens(ctx context.Context, in *RevokeUserTokensRequest, opts ...grpc.CallOption) (*RevokeUserTokensResponse, error) {
+	out := new(RevokeUserTokensResponse)
+	err := c.cc.Invoke(ctx, "/proto.Internal/RevokeUserTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *internalClient) SetUserPlan(ctx context.Context, in *SetUserPlanRequest, opts ...grpc.CallOption) (*SetUserPlanResponse, error) {
+	out := new(SetUserPlanResponse)
+	err := c.cc.Invoke(ctx, "/proto.Internal/SetUserPlan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InternalServer is the server API for Internal service.
 // All implementations must embed UnimplementedInternalServer
 // for forward compatibility
 type InternalServer interface {
 	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
+	// RevokeUserTokens - отзывает все токены пользователя.
+	RevokeUserTokens(context.Context, *RevokeUserTokensRequest) (*RevokeUserTokensResponse, error)
+	// SetUserPlan - назначает пользователю тарифный план.
+	SetUserPlan(context.Context, *SetUserPlanRequest) (*SetUserPlanResponse, error)
 	mustEmbedUnimplementedInternalServer()
 }
 
@@ -57,6 +83,12 @@ type UnimplementedInternalServer struct {
 func (UnimplementedInternalServer) Stats(context.Context, *StatsRequest) (*StatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
 }
+func (UnimplementedInternalServer) RevokeUserTokens(context.Context, *RevokeUserTokensRequest) (*RevokeUserTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserTokens not implemented")
+}
+func (UnimplementedInternalServer) SetUserPlan(context.Context, *SetUserPlanRequest) (*SetUserPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserPlan not implemented")
+}
 func (UnimplementedInternalServer) mustEmbedUnimplementedInternalServer() {}
 
 // UnsafeInternalServer may be embedded to opt out of forward compatibility for this service.
@@ -88,6 +120,42 @@ func _Internal_Stats_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Internal_RevokeUserTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeUserTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).RevokeUserTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Internal/RevokeUserTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).RevokeUserTokens(ctx, req.(*RevokeUserTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Internal_SetUserPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InternalServer).SetUserPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Internal/SetUserPlan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InternalServer).SetUserPlan(ctx, req.(*SetUserPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Internal_ServiceDesc is the grpc.ServiceDesc for Internal service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -99,6 +167,14 @@ var Internal_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Stats",
 			Handler:    _Internal_Stats_Handler,
 		},
+		{
+			MethodName: "RevokeUserTokens",
+			Handler:    _Internal_RevokeUserTokens_Handler,
+		},
+		{
+			MethodName: "SetUserPlan",
+			Handler:    _Internal_SetUserPlan_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/internal.proto",
@@ -11,6 +11,7 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -22,19 +23,141 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// QueryTemplate - шаблон query-параметров, которые добавляются к оригинальному URL при переходе.
+// Значения параметров могут содержать плейсхолдеры: {id}, {referrer_host}, {date}.
+// Политика слияния с параметрами URL: keep (по умолчанию), override, append.
+type QueryTemplate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Params map[string]string `protobuf:"bytes,1,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Policy string            `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+}
+
+func (x *QueryTemplate) Reset() {
+	*x = QueryTemplate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QueryTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryTemplate) ProtoMessage() {}
+
+func (x *QueryTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryTemplate.ProtoReflect.Descriptor instead.
+func (*QueryTemplate) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QueryTemplate) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *QueryTemplate) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+// Split - параметры сплит-ссылки: переходы распределяются между вариантами пропорционально их весам.
+// Если задан sticky, то выбранный вариант закрепляется за посетителем.
+type Split struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Variants []*Split_Variant `protobuf:"bytes,1,rep,name=variants,proto3" json:"variants,omitempty"`
+	Sticky   bool             `protobuf:"varint,2,opt,name=sticky,proto3" json:"sticky,omitempty"`
+}
+
+func (x *Split) Reset() {
+	*x = Split{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Split) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Split) ProtoMessage() {}
+
+func (x *Split) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Split.ProtoReflect.Descriptor instead.
+func (*Split) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Split) GetVariants() []*Split_Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *Split) GetSticky() bool {
+	if x != nil {
+		return x.Sticky
+	}
+	return false
+}
+
 // ShortURLCreateRequest - запрос на создание короткой ссылки
 type ShortURLCreateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	QueryTemplate *QueryTemplate         `protobuf:"bytes,2,opt,name=query_template,json=queryTemplate,proto3" json:"query_template,omitempty"`
+	Split         *Split                 `protobuf:"bytes,3,opt,name=split,proto3" json:"split,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Interstitial  bool                   `protobuf:"varint,5,opt,name=interstitial,proto3" json:"interstitial,omitempty"`
+	ActiveFrom    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=active_from,json=activeFrom,proto3" json:"active_from,omitempty"`
+	ActiveUntil   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=active_until,json=activeUntil,proto3" json:"active_until,omitempty"`
+	TeamId        uint32                 `protobuf:"varint,8,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"` // команда, которой передается ссылка
 }
 
 func (x *ShortURLCreateRequest) Reset() {
 	*x = ShortURLCreateRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_short_url_proto_msgTypes[0]
+		mi := &file_api_short_url_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -47,7 +170,7 @@ func (x *ShortURLCreateRequest) String() string {
 func (*ShortURLCreateRequest) ProtoMessage() {}
 
 func (x *ShortURLCreateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_short_url_proto_msgTypes[0]
+	mi := &file_api_short_url_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -60,7 +183,7 @@ func (x *ShortURLCreateRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ShortURLCreateRequest.ProtoReflect.Descriptor instead.
 func (*ShortURLCreateRequest) Descriptor() ([]byte, []int) {
-	return file_api_short_url_proto_rawDescGZIP(), []int{0}
+	return file_api_short_url_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ShortURLCreateRequest) GetUrl() string {
@@ -70,6 +193,55 @@ func (x *ShortURLCreateRequest) GetUrl() string {
 	return ""
 }
 
+func (x *ShortURLCreateRequest) GetQueryTemplate() *QueryTemplate {
+	if x != nil {
+		return x.QueryTemplate
+	}
+	return nil
+}
+
+func (x *ShortURLCreateRequest) GetSplit() *Split {
+	if x != nil {
+		return x.Split
+	}
+	return nil
+}
+
+func (x *ShortURLCreateRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ShortURLCreateRequest) GetInterstitial() bool {
+	if x != nil {
+		return x.Interstitial
+	}
+	return false
+}
+
+func (x *ShortURLCreateRequest) GetActiveFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveFrom
+	}
+	return nil
+}
+
+func (x *ShortURLCreateRequest) GetActiveUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveUntil
+	}
+	return nil
+}
+
+func (x *ShortURLCreateRequest) GetTeamId() uint32 {
+	if x != nil {
+		return x.TeamId
+	}
+	return 0
+}
+
 // ShortURLCreateResponse - ответ на запрос на создание короткой ссылки
 type ShortURLCreateResponse struct {
 	state         protoimpl.MessageState
@@ -82,7 +254,7 @@ type ShortURLCreateResponse struct {
 func (x *ShortURLCreateResponse) Reset() {
 	*x = ShortURLCreateResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_short_url_proto_msgTypes[1]
+		mi := &file_api_short_url_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -95,7 +267,7 @@ func (x *ShortURLCreateResponse) String() string {
 func (*ShortURLCreateResponse) ProtoMessage() {}
 
 func (x *ShortURLCreateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_short_url_proto_msgTypes[1]
+	mi := &file_api_short_url_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -108,7 +280,7 @@ func (x *ShortURLCreateResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ShortURLCreateResponse.ProtoReflect.Descriptor instead.
 func (*ShortURLCreateResponse) Descriptor() ([]byte, []int) {
-	return file_api_short_url_proto_rawDescGZIP(), []int{1}
+	return file_api_short_url_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ShortURLCreateResponse) GetResult() string {
@@ -130,7 +302,7 @@ type ShortURLCreateBatchRequest struct {
 func (x *ShortURLCreateBatchRequest) Reset() {
 	*x = ShortURLCreateBatchRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_short_url_proto_msgTypes[2]
+		mi := &file_api_short_url_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -143,7 +315,7 @@ func (x *ShortURLCreateBatchRequest) String() string {
 func (*ShortURLCreateBatchRequest) ProtoMessage() {}
 
 func (x *ShortURLCreateBatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_short_url_proto_msgTypes[2]
+	mi := &file_api_short_url_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -156,7 +328,7 @@ func (x *ShortURLCreateBatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ShortURLCreateBatchRequest.ProtoReflect.Descriptor instead.
 func (*ShortURLCreateBatchRequest) Descriptor() ([]byte, []int) {
-	return file_api_short_url_proto_rawDescGZIP(), []int{2}
+	return file_api_short_url_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ShortURLCreateBatchRequest) GetItems() []*ShortURLCreateBatchRequest_Item {
@@ -178,7 +350,7 @@ type ShortURLCreateBatchResponse struct {
 func (x *ShortURLCreateBatchResponse) Reset() {
 	*x = ShortURLCreateBatchResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_short_url_proto_msgTypes[3]
+		mi := &file_api_short_url_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -191,7 +363,7 @@ func (x *ShortURLCreateBatchResponse) String() string {
 func (*ShortURLCreateBatchResponse) ProtoMessage() {}
 
 func (x *ShortURLCreateBatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_short_url_proto_msgTypes[3]
+	mi := &file_api_short_url_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -204,7 +376,7 @@ func (x *ShortURLCreateBatchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ShortURLCreateBatchResponse.ProtoReflect.Descriptor instead.
 func (*ShortURLCreateBatchResponse) Descriptor() ([]byte, []int) {
-	return file_api_short_url_proto_rawDescGZIP(), []int{3}
+	return file_api_short_url_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ShortURLCreateBatchResponse) GetItems() []*ShortURLCreateBatchResponse_Item {
@@ -226,7 +398,7 @@ type ShortURLDeleteBatchRequest struct {
 func (x *ShortURLDeleteBatchRequest) Reset() {
 	*x = ShortURLDeleteBatchRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_short_url_proto_msgTypes[4]
+		mi := &file_api_short_url_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -239,7 +411,7 @@ func (x *ShortURLDeleteBatchRequest) String() string {
 func (*ShortURLDeleteBatchRequest) ProtoMessage() {}
 
 func (x *ShortURLDeleteBatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_short_url_proto_msgTypes[4]
+	mi := &file_api_short_url_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -252,7 +424,7 @@ func (x *ShortURLDeleteBatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ShortURLDeleteBatchRequest.ProtoReflect.Descriptor instead.
 func (*ShortURLDeleteBatchRequest) Descriptor() ([]byte, []int) {
-	return file_api_short_url_proto_rawDescGZIP(), []int{4}
+	return file_api_short_url_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ShortURLDeleteBatchRequest) GetItems() []string {
@@ -272,7 +444,7 @@ type ShortURLDeleteBatchResponse struct {
 func (x *ShortURLDeleteBatchResponse) Reset() {
 	*x = ShortURLDeleteBatchResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_short_url_proto_msgTypes[5]
+		mi := &file_api_short_url_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -285,7 +457,7 @@ func (x *ShortURLDeleteBatchResponse) String() string {
 func (*ShortURLDeleteBatchResponse) ProtoMessage() {}
 
 func (x *ShortURLDeleteBatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_short_url_proto_msgTypes[5]
+	mi := &file_api_short_url_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -298,7 +470,7 @@ func (x *ShortURLDeleteBatchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ShortURLDeleteBatchResponse.ProtoReflect.Descriptor instead.
 func (*ShortURLDeleteBatchResponse) Descriptor() ([]byte, []int) {
-	return file_api_short_url_proto_rawDescGZIP(), []int{5}
+	return file_api_short_url_proto_rawDescGZIP(), []int{7}
 }
 
 // ShortURLGetByUserIDRequest - запрос на получение списка коротких ссылок текущего пользователя
@@ -311,7 +483,7 @@ type ShortURLGetByUserIDRequest struct {
 func (x *ShortURLGetByUserIDRequest) Reset() {
 	*x = ShortURLGetByUserIDRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_short_url_proto_msgTypes[6]
+		mi := &file_api_short_url_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -324,7 +496,7 @@ func (x *ShortURLGetByUserIDRequest) String() string {
 func (*ShortURLGetByUserIDRequest) ProtoMessage() {}
 
 func (x *ShortURLGetByUserIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_short_url_proto_msgTypes[6]
+	mi := &file_api_short_url_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -337,7 +509,7 @@ func (x *ShortURLGetByUserIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ShortURLGetByUserIDRequest.ProtoReflect.Descriptor instead.
 func (*ShortURLGetByUserIDRequest) Descriptor() ([]byte, []int) {
-	return file_api_short_url_proto_rawDescGZIP(), []int{6}
+	return file_api_short_url_proto_rawDescGZIP(), []int{8}
 }
 
 // ShortURLGetByUserIDResponse - ответ на запрос на получение списка коротких ссылок текущего пользователя
@@ -352,7 +524,7 @@ type ShortURLGetByUserIDResponse struct {
 func (x *ShortURLGetByUserIDResponse) Reset() {
 	*x = ShortURLGetByUserIDResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_short_url_proto_msgTypes[7]
+		mi := &file_api_short_url_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -365,7 +537,7 @@ func (x *ShortURLGetByUserIDResponse) String() string {
 func (*ShortURLGetByUserIDResponse) ProtoMessage() {}
 
 func (x *ShortURLGetByUserIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_short_url_proto_msgTypes[7]
+	mi := &file_api_short_url_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -378,7 +550,7 @@ func (x *ShortURLGetByUserIDResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ShortURLGetByUserIDResponse.ProtoReflect.Descriptor instead.
 func (*ShortURLGetByUserIDResponse) Descriptor() ([]byte, []int) {
-	return file_api_short_url_proto_rawDescGZIP(), []int{7}
+	return file_api_short_url_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ShortURLGetByUserIDResponse) GetItems() []*ShortURLGetByUserIDResponse_Item {
@@ -388,32 +560,32 @@ func (x *ShortURLGetByUserIDResponse) GetItems() []*ShortURLGetByUserIDResponse_
 	return nil
 }
 
-type ShortURLCreateBatchRequest_Item struct {
+// ShortURLGetByTeamIDRequest - запрос на получение списка коротких ссылок команды
+type ShortURLGetByTeamIDRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CorrelationId string `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
-	OriginalUrl   string `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	TeamId uint32 `protobuf:"varint,1,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
 }
 
-func (x *ShortURLCreateBatchRequest_Item) Reset() {
-	*x = ShortURLCreateBatchRequest_Item{}
+func (x *ShortURLGetByTeamIDRequest) Reset() {
+	*x = ShortURLGetByTeamIDRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_short_url_proto_msgTypes[8]
+		mi := &file_api_short_url_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ShortURLCreateBatchRequest_Item) String() string {
+func (x *ShortURLGetByTeamIDRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShortURLCreateBatchRequest_Item) ProtoMessage() {}
+func (*ShortURLGetByTeamIDRequest) ProtoMessage() {}
 
-func (x *ShortURLCreateBatchRequest_Item) ProtoReflect() protoreflect.Message {
-	mi := &file_api_short_url_proto_msgTypes[8]
+func (x *ShortURLGetByTeamIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -424,51 +596,46 @@ func (x *ShortURLCreateBatchRequest_Item) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ShortURLCreateBatchRequest_Item.ProtoReflect.Descriptor instead.
-func (*ShortURLCreateBatchRequest_Item) Descriptor() ([]byte, []int) {
-	return file_api_short_url_proto_rawDescGZIP(), []int{2, 0}
-}
-
-func (x *ShortURLCreateBatchRequest_Item) GetCorrelationId() string {
-	if x != nil {
-		return x.CorrelationId
-	}
-	return ""
+// Deprecated: Use ShortURLGetByTeamIDRequest.ProtoReflect.Descriptor instead.
+func (*ShortURLGetByTeamIDRequest) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *ShortURLCreateBatchRequest_Item) GetOriginalUrl() string {
+func (x *ShortURLGetByTeamIDRequest) GetTeamId() uint32 {
 	if x != nil {
-		return x.OriginalUrl
+		return x.TeamId
 	}
-	return ""
+	return 0
 }
 
-type ShortURLCreateBatchResponse_Item struct {
+// ShortURLSetTeamRequest - запрос на передачу короткой ссылки команде.
+// Если team_id не задан, ссылка становится личной ссылкой создавшего ее пользователя.
+type ShortURLSetTeamRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CorrelationId string `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
-	ShortUrl      string `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TeamId uint32 `protobuf:"varint,2,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
 }
 
-func (x *ShortURLCreateBatchResponse_Item) Reset() {
-	*x = ShortURLCreateBatchResponse_Item{}
+func (x *ShortURLSetTeamRequest) Reset() {
+	*x = ShortURLSetTeamRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_short_url_proto_msgTypes[9]
+		mi := &file_api_short_url_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ShortURLCreateBatchResponse_Item) String() string {
+func (x *ShortURLSetTeamRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShortURLCreateBatchResponse_Item) ProtoMessage() {}
+func (*ShortURLSetTeamRequest) ProtoMessage() {}
 
-func (x *ShortURLCreateBatchResponse_Item) ProtoReflect() protoreflect.Message {
-	mi := &file_api_short_url_proto_msgTypes[9]
+func (x *ShortURLSetTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -479,51 +646,49 @@ func (x *ShortURLCreateBatchResponse_Item) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ShortURLCreateBatchResponse_Item.ProtoReflect.Descriptor instead.
-func (*ShortURLCreateBatchResponse_Item) Descriptor() ([]byte, []int) {
-	return file_api_short_url_proto_rawDescGZIP(), []int{3, 0}
+// Deprecated: Use ShortURLSetTeamRequest.ProtoReflect.Descriptor instead.
+func (*ShortURLSetTeamRequest) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *ShortURLCreateBatchResponse_Item) GetCorrelationId() string {
+func (x *ShortURLSetTeamRequest) GetId() string {
 	if x != nil {
-		return x.CorrelationId
+		return x.Id
 	}
 	return ""
 }
 
-func (x *ShortURLCreateBatchResponse_Item) GetShortUrl() string {
+func (x *ShortURLSetTeamRequest) GetTeamId() uint32 {
 	if x != nil {
-		return x.ShortUrl
+		return x.TeamId
 	}
-	return ""
+	return 0
 }
 
-type ShortURLGetByUserIDResponse_Item struct {
+// ShortURLSetTeamResponse - ответ на передачу короткой ссылки команде
+type ShortURLSetTeamResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	OriginalUrl string `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
-	ShortUrl    string `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
 }
 
-func (x *ShortURLGetByUserIDResponse_Item) Reset() {
-	*x = ShortURLGetByUserIDResponse_Item{}
+func (x *ShortURLSetTeamResponse) Reset() {
+	*x = ShortURLSetTeamResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_short_url_proto_msgTypes[10]
+		mi := &file_api_short_url_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ShortURLGetByUserIDResponse_Item) String() string {
+func (x *ShortURLSetTeamResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShortURLGetByUserIDResponse_Item) ProtoMessage() {}
+func (*ShortURLSetTeamResponse) ProtoMessage() {}
 
-func (x *ShortURLGetByUserIDResponse_Item) ProtoReflect() protoreflect.Message {
-	mi := &file_api_short_url_proto_msgTypes[10]
+func (x *ShortURLSetTeamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -534,152 +699,2322 @@ func (x *ShortURLGetByUserIDResponse_Item) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ShortURLGetByUserIDResponse_Item.ProtoReflect.Descriptor instead.
-func (*ShortURLGetByUserIDResponse_Item) Descriptor() ([]byte, []int) {
-	return file_api_short_url_proto_rawDescGZIP(), []int{7, 0}
+// Deprecated: Use ShortURLSetTeamResponse.ProtoReflect.Descriptor instead.
+func (*ShortURLSetTeamResponse) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *ShortURLGetByUserIDResponse_Item) GetOriginalUrl() string {
-	if x != nil {
-		return x.OriginalUrl
-	}
-	return ""
+// ShortURLQuotaRequest - запрос квот пользователя и их использования
+type ShortURLQuotaRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 }
 
-func (x *ShortURLGetByUserIDResponse_Item) GetShortUrl() string {
-	if x != nil {
-		return x.ShortUrl
+func (x *ShortURLQuotaRequest) Reset() {
+	*x = ShortURLQuotaRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-var File_api_short_url_proto protoreflect.FileDescriptor
-
-var file_api_short_url_proto_rawDesc = []byte{
-	0x0a, 0x13, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x29, 0x0a, 0x15,
-	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x22, 0x30, 0x0a, 0x16, 0x53, 0x68, 0x6f, 0x72, 0x74,
-	0x55, 0x52, 0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0xac, 0x01, 0x0a, 0x1a, 0x53, 0x68,
-	0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63,
-	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3c, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61,
-	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52,
-	0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x1a, 0x50, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x25,
-	0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61,
-	0x6c, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72, 0x69,
-	0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x22, 0xa8, 0x01, 0x0a, 0x1b, 0x53, 0x68, 0x6f,
-	0x72, 0x74, 0x55, 0x52, 0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61,
-	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x49, 0x74, 0x65, 0x6d,
-	0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x1a, 0x4a, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12,
-	0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f,
-	0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74,
-	0x55, 0x72, 0x6c, 0x22, 0x32, 0x0a, 0x1a, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x1d, 0x0a, 0x1b, 0x53, 0x68, 0x6f, 0x72, 0x74,
-	0x55, 0x52, 0x4c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1c, 0x0a, 0x1a, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
-	0x52, 0x4c, 0x47, 0x65, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x22, 0xa4, 0x01, 0x0a, 0x1b, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
-	0x4c, 0x47, 0x65, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72,
-	0x74, 0x55, 0x52, 0x4c, 0x47, 0x65, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74,
-	0x65, 0x6d, 0x73, 0x1a, 0x46, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x21, 0x0a, 0x0c, 0x6f,
-	0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0b, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x12, 0x1b,
-	0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x32, 0xdb, 0x02, 0x0a, 0x08,
-	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x12, 0x47, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x12, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74,
-	0x55, 0x52, 0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
-	0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x56, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68,
-	0x12, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
-	0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72,
-	0x74, 0x55, 0x52, 0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x56, 0x0a, 0x0b, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42,
-	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x44, 0x65, 0x6c, 0x65,
-	0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x56, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44,
-	0x12, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
-	0x4c, 0x47, 0x65, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72,
-	0x74, 0x55, 0x52, 0x4c, 0x47, 0x65, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x0c, 0x5a, 0x0a, 0x2f, 0x61, 0x70,
-	0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *ShortURLQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var (
-	file_api_short_url_proto_rawDescOnce sync.Once
-	file_api_short_url_proto_rawDescData = file_api_short_url_proto_rawDesc
-)
+func (*ShortURLQuotaRequest) ProtoMessage() {}
 
-func file_api_short_url_proto_rawDescGZIP() []byte {
-	file_api_short_url_proto_rawDescOnce.Do(func() {
-		file_api_short_url_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_short_url_proto_rawDescData)
-	})
-	return file_api_short_url_proto_rawDescData
+func (x *ShortURLQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var file_api_short_url_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
-var file_api_short_url_proto_goTypes = []interface{}{
-	(*ShortURLCreateRequest)(nil),            // 0: proto.ShortURLCreateRequest
-	(*ShortURLCreateResponse)(nil),           // 1: proto.ShortURLCreateResponse
-	(*ShortURLCreateBatchRequest)(nil),       // 2: proto.ShortURLCreateBatchRequest
-	(*ShortURLCreateBatchResponse)(nil),      // 3: proto.ShortURLCreateBatchResponse
-	(*ShortURLDeleteBatchRequest)(nil),       // 4: proto.ShortURLDeleteBatchRequest
-	(*ShortURLDeleteBatchResponse)(nil),      // 5: proto.ShortURLDeleteBatchResponse
-	(*ShortURLGetByUserIDRequest)(nil),       // 6: proto.ShortURLGetByUserIDRequest
-	(*ShortURLGetByUserIDResponse)(nil),      // 7: proto.ShortURLGetByUserIDResponse
-	(*ShortURLCreateBatchRequest_Item)(nil),  // 8: proto.ShortURLCreateBatchRequest.Item
-	(*ShortURLCreateBatchResponse_Item)(nil), // 9: proto.ShortURLCreateBatchResponse.Item
-	(*ShortURLGetByUserIDResponse_Item)(nil), // 10: proto.ShortURLGetByUserIDResponse.Item
-}
-var file_api_short_url_proto_depIdxs = []int32{
-	8,  // 0: proto.ShortURLCreateBatchRequest.items:type_name -> proto.ShortURLCreateBatchRequest.Item
-	9,  // 1: proto.ShortURLCreateBatchResponse.items:type_name -> proto.ShortURLCreateBatchResponse.Item
-	10, // 2: proto.ShortURLGetByUserIDResponse.items:type_name -> proto.ShortURLGetByUserIDResponse.Item
-	0,  // 3: proto.ShortURL.Create:input_type -> proto.ShortURLCreateRequest
-	2,  // 4: proto.ShortURL.CreateBatch:input_type -> proto.ShortURLCreateBatchRequest
-	4,  // 5: proto.ShortURL.DeleteBatch:input_type -> proto.ShortURLDeleteBatchRequest
-	6,  // 6: proto.ShortURL.GetByUserID:input_type -> proto.ShortURLGetByUserIDRequest
-	1,  // 7: proto.ShortURL.Create:output_type -> proto.ShortURLCreateResponse
-	3,  // 8: proto.ShortURL.CreateBatch:output_type -> proto.ShortURLCreateBatchResponse
-	5,  // 9: proto.ShortURL.DeleteBatch:output_type -> proto.ShortURLDeleteBatchResponse
-	7,  // 10: proto.ShortURL.GetByUserID:output_type -> proto.ShortURLGetByUserIDResponse
-	7,  // [7:11] is the sub-list for method output_type
-	3,  // [3:7] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+// Deprecated: Use ShortURLQuotaRequest.ProtoReflect.Descriptor instead.
+func (*ShortURLQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{13}
 }
 
-func init() { file_api_short_url_proto_init() }
-func file_api_short_url_proto_init() {
+// ShortURLQuotaResponse - квоты пользователя и их использование.
+// Значение 0 в квотах означает отсутствие ограничения.
+type ShortURLQuotaResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Plan          string `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`                                           // тарифный план, если назначен
+	MaxActiveUrls uint32 `protobuf:"varint,2,opt,name=max_active_urls,json=maxActiveUrls,proto3" json:"max_active_urls,omitempty"` // максимальное количество неудаленных ссылок
+	UrlsPerDay    uint32 `protobuf:"varint,3,opt,name=urls_per_day,json=urlsPerDay,proto3" json:"urls_per_day,omitempty"`          // максимальное количество ссылок за последние 24 часа
+	MaxBatchSize  uint32 `protobuf:"varint,4,opt,name=max_batch_size,json=maxBatchSize,proto3" json:"max_batch_size,omitempty"`    // максимальное количество ссылок в пакетном запросе
+	ActiveUrls    uint32 `protobuf:"varint,5,opt,name=active_urls,json=activeUrls,proto3" json:"active_urls,omitempty"`
+	UrlsLastDay   uint32 `protobuf:"varint,6,opt,name=urls_last_day,json=urlsLastDay,proto3" json:"urls_last_day,omitempty"`
+}
+
+func (x *ShortURLQuotaResponse) Reset() {
+	*x = ShortURLQuotaResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLQuotaResponse) ProtoMessage() {}
+
+func (x *ShortURLQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLQuotaResponse.ProtoReflect.Descriptor instead.
+func (*ShortURLQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ShortURLQuotaResponse) GetPlan() string {
+	if x != nil {
+		return x.Plan
+	}
+	return ""
+}
+
+func (x *ShortURLQuotaResponse) GetMaxActiveUrls() uint32 {
+	if x != nil {
+		return x.MaxActiveUrls
+	}
+	return 0
+}
+
+func (x *ShortURLQuotaResponse) GetUrlsPerDay() uint32 {
+	if x != nil {
+		return x.UrlsPerDay
+	}
+	return 0
+}
+
+func (x *ShortURLQuotaResponse) GetMaxBatchSize() uint32 {
+	if x != nil {
+		return x.MaxBatchSize
+	}
+	return 0
+}
+
+func (x *ShortURLQuotaResponse) GetActiveUrls() uint32 {
+	if x != nil {
+		return x.ActiveUrls
+	}
+	return 0
+}
+
+func (x *ShortURLQuotaResponse) GetUrlsLastDay() uint32 {
+	if x != nil {
+		return x.UrlsLastDay
+	}
+	return 0
+}
+
+// RedirectRule - правило условного перенаправления.
+// Правило срабатывает, если выполнены все заданные в нем условия.
+type RedirectRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url       string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Devices   []string               `protobuf:"bytes,2,rep,name=devices,proto3" json:"devices,omitempty"`     // ios, android, desktop
+	Languages []string               `protobuf:"bytes,3,rep,name=languages,proto3" json:"languages,omitempty"` // ru, en-US, ...
+	Schedule  *RedirectRule_Schedule `protobuf:"bytes,4,opt,name=schedule,proto3" json:"schedule,omitempty"`
+}
+
+func (x *RedirectRule) Reset() {
+	*x = RedirectRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RedirectRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedirectRule) ProtoMessage() {}
+
+func (x *RedirectRule) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedirectRule.ProtoReflect.Descriptor instead.
+func (*RedirectRule) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RedirectRule) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *RedirectRule) GetDevices() []string {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *RedirectRule) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *RedirectRule) GetSchedule() *RedirectRule_Schedule {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+// ShortURLGetRulesRequest - запрос на получение правил перенаправления ссылки
+type ShortURLGetRulesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ShortURLGetRulesRequest) Reset() {
+	*x = ShortURLGetRulesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLGetRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLGetRulesRequest) ProtoMessage() {}
+
+func (x *ShortURLGetRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLGetRulesRequest.ProtoReflect.Descriptor instead.
+func (*ShortURLGetRulesRequest) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ShortURLGetRulesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ShortURLSetRulesRequest - запрос на замену правил перенаправления ссылки
+type ShortURLSetRulesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    string          `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Rules []*RedirectRule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *ShortURLSetRulesRequest) Reset() {
+	*x = ShortURLSetRulesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLSetRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLSetRulesRequest) ProtoMessage() {}
+
+func (x *ShortURLSetRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLSetRulesRequest.ProtoReflect.Descriptor instead.
+func (*ShortURLSetRulesRequest) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ShortURLSetRulesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ShortURLSetRulesRequest) GetRules() []*RedirectRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// ShortURLRulesResponse - список правил перенаправления ссылки
+type ShortURLRulesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rules []*RedirectRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+}
+
+func (x *ShortURLRulesResponse) Reset() {
+	*x = ShortURLRulesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLRulesResponse) ProtoMessage() {}
+
+func (x *ShortURLRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLRulesResponse.ProtoReflect.Descriptor instead.
+func (*ShortURLRulesResponse) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ShortURLRulesResponse) GetRules() []*RedirectRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// ShortURLSetQueryTemplateRequest - запрос на замену шаблона query-параметров ссылки.
+// Незаданный шаблон удаляет шаблон ссылки.
+type ShortURLSetQueryTemplateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id            string         `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	QueryTemplate *QueryTemplate `protobuf:"bytes,2,opt,name=query_template,json=queryTemplate,proto3" json:"query_template,omitempty"`
+}
+
+func (x *ShortURLSetQueryTemplateRequest) Reset() {
+	*x = ShortURLSetQueryTemplateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLSetQueryTemplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLSetQueryTemplateRequest) ProtoMessage() {}
+
+func (x *ShortURLSetQueryTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLSetQueryTemplateRequest.ProtoReflect.Descriptor instead.
+func (*ShortURLSetQueryTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ShortURLSetQueryTemplateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ShortURLSetQueryTemplateRequest) GetQueryTemplate() *QueryTemplate {
+	if x != nil {
+		return x.QueryTemplate
+	}
+	return nil
+}
+
+// ShortURLSetQueryTemplateResponse - сохраненный шаблон query-параметров ссылки
+type ShortURLSetQueryTemplateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QueryTemplate *QueryTemplate `protobuf:"bytes,1,opt,name=query_template,json=queryTemplate,proto3" json:"query_template,omitempty"`
+}
+
+func (x *ShortURLSetQueryTemplateResponse) Reset() {
+	*x = ShortURLSetQueryTemplateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLSetQueryTemplateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLSetQueryTemplateResponse) ProtoMessage() {}
+
+func (x *ShortURLSetQueryTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLSetQueryTemplateResponse.ProtoReflect.Descriptor instead.
+func (*ShortURLSetQueryTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ShortURLSetQueryTemplateResponse) GetQueryTemplate() *QueryTemplate {
+	if x != nil {
+		return x.QueryTemplate
+	}
+	return nil
+}
+
+// ShortURLSetPreviewRequest - запрос на изменение заголовка ссылки и показа страницы-предупреждения.
+// Пустой заголовок удаляет заголовок ссылки.
+type ShortURLSetPreviewRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title        string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Interstitial bool   `protobuf:"varint,3,opt,name=interstitial,proto3" json:"interstitial,omitempty"`
+}
+
+func (x *ShortURLSetPreviewRequest) Reset() {
+	*x = ShortURLSetPreviewRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLSetPreviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLSetPreviewRequest) ProtoMessage() {}
+
+func (x *ShortURLSetPreviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLSetPreviewRequest.ProtoReflect.Descriptor instead.
+func (*ShortURLSetPreviewRequest) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ShortURLSetPreviewRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ShortURLSetPreviewRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ShortURLSetPreviewRequest) GetInterstitial() bool {
+	if x != nil {
+		return x.Interstitial
+	}
+	return false
+}
+
+// ShortURLSetPreviewResponse - сохраненные заголовок ссылки и показ страницы-предупреждения
+type ShortURLSetPreviewResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Title        string `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Interstitial bool   `protobuf:"varint,2,opt,name=interstitial,proto3" json:"interstitial,omitempty"`
+}
+
+func (x *ShortURLSetPreviewResponse) Reset() {
+	*x = ShortURLSetPreviewResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLSetPreviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLSetPreviewResponse) ProtoMessage() {}
+
+func (x *ShortURLSetPreviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLSetPreviewResponse.ProtoReflect.Descriptor instead.
+func (*ShortURLSetPreviewResponse) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ShortURLSetPreviewResponse) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ShortURLSetPreviewResponse) GetInterstitial() bool {
+	if x != nil {
+		return x.Interstitial
+	}
+	return false
+}
+
+// ShortURLSetActiveWindowRequest - запрос на изменение периода действия ссылки.
+// Незаданная граница снимает соответствующее ограничение.
+type ShortURLSetActiveWindowRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ActiveFrom  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=active_from,json=activeFrom,proto3" json:"active_from,omitempty"`
+	ActiveUntil *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=active_until,json=activeUntil,proto3" json:"active_until,omitempty"`
+}
+
+func (x *ShortURLSetActiveWindowRequest) Reset() {
+	*x = ShortURLSetActiveWindowRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLSetActiveWindowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLSetActiveWindowRequest) ProtoMessage() {}
+
+func (x *ShortURLSetActiveWindowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLSetActiveWindowRequest.ProtoReflect.Descriptor instead.
+func (*ShortURLSetActiveWindowRequest) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ShortURLSetActiveWindowRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ShortURLSetActiveWindowRequest) GetActiveFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveFrom
+	}
+	return nil
+}
+
+func (x *ShortURLSetActiveWindowRequest) GetActiveUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveUntil
+	}
+	return nil
+}
+
+// ShortURLSetActiveWindowResponse - сохраненный период действия ссылки
+type ShortURLSetActiveWindowResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ActiveFrom  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=active_from,json=activeFrom,proto3" json:"active_from,omitempty"`
+	ActiveUntil *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=active_until,json=activeUntil,proto3" json:"active_until,omitempty"`
+}
+
+func (x *ShortURLSetActiveWindowResponse) Reset() {
+	*x = ShortURLSetActiveWindowResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLSetActiveWindowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLSetActiveWindowResponse) ProtoMessage() {}
+
+func (x *ShortURLSetActiveWindowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLSetActiveWindowResponse.ProtoReflect.Descriptor instead.
+func (*ShortURLSetActiveWindowResponse) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ShortURLSetActiveWindowResponse) GetActiveFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveFrom
+	}
+	return nil
+}
+
+func (x *ShortURLSetActiveWindowResponse) GetActiveUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveUntil
+	}
+	return nil
+}
+
+// ShortURLVariantStatsRequest - запрос на получение статистики переходов по вариантам сплит-ссылки
+type ShortURLVariantStatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ShortURLVariantStatsRequest) Reset() {
+	*x = ShortURLVariantStatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLVariantStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLVariantStatsRequest) ProtoMessage() {}
+
+func (x *ShortURLVariantStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLVariantStatsRequest.ProtoReflect.Descriptor instead.
+func (*ShortURLVariantStatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ShortURLVariantStatsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ShortURLVariantStatsResponse - статистика переходов по вариантам сплит-ссылки
+type ShortURLVariantStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*ShortURLVariantStatsResponse_Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *ShortURLVariantStatsResponse) Reset() {
+	*x = ShortURLVariantStatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLVariantStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLVariantStatsResponse) ProtoMessage() {}
+
+func (x *ShortURLVariantStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLVariantStatsResponse.ProtoReflect.Descriptor instead.
+func (*ShortURLVariantStatsResponse) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ShortURLVariantStatsResponse) GetItems() []*ShortURLVariantStatsResponse_Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// ShortURLStatsRequest - запрос на получение статистики переходов по ссылке за период
+type ShortURLStatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	From        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`                                   // по умолчанию - в зависимости от интервала
+	Until       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`                                 // по умолчанию - текущее время
+	Interval    string                 `protobuf:"bytes,4,opt,name=interval,proto3" json:"interval,omitempty"`                           // hour, day (по умолчанию)
+	IncludeBots bool                   `protobuf:"varint,5,opt,name=include_bots,json=includeBots,proto3" json:"include_bots,omitempty"` // учитывать переходы ботов
+}
+
+func (x *ShortURLStatsRequest) Reset() {
+	*x = ShortURLStatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLStatsRequest) ProtoMessage() {}
+
+func (x *ShortURLStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLStatsRequest.ProtoReflect.Descriptor instead.
+func (*ShortURLStatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ShortURLStatsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ShortURLStatsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ShortURLStatsRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *ShortURLStatsRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *ShortURLStatsRequest) GetIncludeBots() bool {
+	if x != nil {
+		return x.IncludeBots
+	}
+	return false
+}
+
+// ShortURLStatsResponse - статистика переходов по ссылке за период
+type ShortURLStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From              *timestamppb.Timestamp          `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Until             *timestamppb.Timestamp          `protobuf:"bytes,2,opt,name=until,proto3" json:"until,omitempty"`
+	Interval          string                          `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	Total             int64                           `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	Unique            int64                           `protobuf:"varint,5,opt,name=unique,proto3" json:"unique,omitempty"`
+	Buckets           []*ShortURLStatsResponse_Bucket `protobuf:"bytes,6,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	Referrers         []*ShortURLStatsResponse_Count  `protobuf:"bytes,7,rep,name=referrers,proto3" json:"referrers,omitempty"`
+	Countries         []*ShortURLStatsResponse_Count  `protobuf:"bytes,8,rep,name=countries,proto3" json:"countries,omitempty"`
+	Devices           []*ShortURLStatsResponse_Count  `protobuf:"bytes,9,rep,name=devices,proto3" json:"devices,omitempty"`
+	ApproxUnique      int64                           `protobuf:"varint,10,opt,name=approx_unique,json=approxUnique,proto3" json:"approx_unique,omitempty"`                   // оценка уникальных посетителей по HyperLogLog-скетчам за сутки периода
+	ApproxUniqueError float64                         `protobuf:"fixed64,11,opt,name=approx_unique_error,json=approxUniqueError,proto3" json:"approx_unique_error,omitempty"` // относительная стандартная ошибка approx_unique
+	Bots              int64                           `protobuf:"varint,12,opt,name=bots,proto3" json:"bots,omitempty"`                                                       // количество переходов ботов за период
+	IncludeBots       bool                            `protobuf:"varint,13,opt,name=include_bots,json=includeBots,proto3" json:"include_bots,omitempty"`                      // переходы ботов учтены в статистике
+}
+
+func (x *ShortURLStatsResponse) Reset() {
+	*x = ShortURLStatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLStatsResponse) ProtoMessage() {}
+
+func (x *ShortURLStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLStatsResponse.ProtoReflect.Descriptor instead.
+func (*ShortURLStatsResponse) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ShortURLStatsResponse) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ShortURLStatsResponse) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *ShortURLStatsResponse) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *ShortURLStatsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ShortURLStatsResponse) GetUnique() int64 {
+	if x != nil {
+		return x.Unique
+	}
+	return 0
+}
+
+func (x *ShortURLStatsResponse) GetBuckets() []*ShortURLStatsResponse_Bucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *ShortURLStatsResponse) GetReferrers() []*ShortURLStatsResponse_Count {
+	if x != nil {
+		return x.Referrers
+	}
+	return nil
+}
+
+func (x *ShortURLStatsResponse) GetCountries() []*ShortURLStatsResponse_Count {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+func (x *ShortURLStatsResponse) GetDevices() []*ShortURLStatsResponse_Count {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *ShortURLStatsResponse) GetApproxUnique() int64 {
+	if x != nil {
+		return x.ApproxUnique
+	}
+	return 0
+}
+
+func (x *ShortURLStatsResponse) GetApproxUniqueError() float64 {
+	if x != nil {
+		return x.ApproxUniqueError
+	}
+	return 0
+}
+
+func (x *ShortURLStatsResponse) GetBots() int64 {
+	if x != nil {
+		return x.Bots
+	}
+	return 0
+}
+
+func (x *ShortURLStatsResponse) GetIncludeBots() bool {
+	if x != nil {
+		return x.IncludeBots
+	}
+	return false
+}
+
+// ShortURLClickExportRequest - запрос на выгрузку событий переходов по ссылке за период
+type ShortURLClickExportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	From *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"` // по умолчанию - без ограничения
+	To   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`     // по умолчанию - без ограничения
+}
+
+func (x *ShortURLClickExportRequest) Reset() {
+	*x = ShortURLClickExportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLClickExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLClickExportRequest) ProtoMessage() {}
+
+func (x *ShortURLClickExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLClickExportRequest.ProtoReflect.Descriptor instead.
+func (*ShortURLClickExportRequest) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ShortURLClickExportRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ShortURLClickExportRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ShortURLClickExportRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+// ShortURLClick - событие перехода по короткой ссылке
+type ShortURLClick struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ShortUrlId  string                 `protobuf:"bytes,1,opt,name=short_url_id,json=shortUrlId,proto3" json:"short_url_id,omitempty"`
+	Time        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	RefererHost string                 `protobuf:"bytes,3,opt,name=referer_host,json=refererHost,proto3" json:"referer_host,omitempty"`
+	Device      string                 `protobuf:"bytes,4,opt,name=device,proto3" json:"device,omitempty"`
+	Ip          string                 `protobuf:"bytes,5,opt,name=ip,proto3" json:"ip,omitempty"` // анонимизированный IP-адрес
+	Country     string                 `protobuf:"bytes,6,opt,name=country,proto3" json:"country,omitempty"`
+	Visitor     string                 `protobuf:"bytes,7,opt,name=visitor,proto3" json:"visitor,omitempty"` // хеш IP-адреса и User-Agent
+	Bot         bool                   `protobuf:"varint,8,opt,name=bot,proto3" json:"bot,omitempty"`        // переход выполнен ботом
+}
+
+func (x *ShortURLClick) Reset() {
+	*x = ShortURLClick{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLClick) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLClick) ProtoMessage() {}
+
+func (x *ShortURLClick) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLClick.ProtoReflect.Descriptor instead.
+func (*ShortURLClick) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ShortURLClick) GetShortUrlId() string {
+	if x != nil {
+		return x.ShortUrlId
+	}
+	return ""
+}
+
+func (x *ShortURLClick) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *ShortURLClick) GetRefererHost() string {
+	if x != nil {
+		return x.RefererHost
+	}
+	return ""
+}
+
+func (x *ShortURLClick) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *ShortURLClick) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *ShortURLClick) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *ShortURLClick) GetVisitor() string {
+	if x != nil {
+		return x.Visitor
+	}
+	return ""
+}
+
+func (x *ShortURLClick) GetBot() bool {
+	if x != nil {
+		return x.Bot
+	}
+	return false
+}
+
+type Split_Variant struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url    string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Weight uint32 `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+}
+
+func (x *Split_Variant) Reset() {
+	*x = Split_Variant{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Split_Variant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Split_Variant) ProtoMessage() {}
+
+func (x *Split_Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Split_Variant.ProtoReflect.Descriptor instead.
+func (*Split_Variant) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *Split_Variant) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Split_Variant) GetWeight() uint32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type ShortURLCreateBatchRequest_Item struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CorrelationId string         `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	OriginalUrl   string         `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	QueryTemplate *QueryTemplate `protobuf:"bytes,3,opt,name=query_template,json=queryTemplate,proto3" json:"query_template,omitempty"`
+}
+
+func (x *ShortURLCreateBatchRequest_Item) Reset() {
+	*x = ShortURLCreateBatchRequest_Item{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLCreateBatchRequest_Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLCreateBatchRequest_Item) ProtoMessage() {}
+
+func (x *ShortURLCreateBatchRequest_Item) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLCreateBatchRequest_Item.ProtoReflect.Descriptor instead.
+func (*ShortURLCreateBatchRequest_Item) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *ShortURLCreateBatchRequest_Item) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *ShortURLCreateBatchRequest_Item) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *ShortURLCreateBatchRequest_Item) GetQueryTemplate() *QueryTemplate {
+	if x != nil {
+		return x.QueryTemplate
+	}
+	return nil
+}
+
+type ShortURLCreateBatchResponse_Item struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CorrelationId string `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	ShortUrl      string `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+}
+
+func (x *ShortURLCreateBatchResponse_Item) Reset() {
+	*x = ShortURLCreateBatchResponse_Item{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLCreateBatchResponse_Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLCreateBatchResponse_Item) ProtoMessage() {}
+
+func (x *ShortURLCreateBatchResponse_Item) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLCreateBatchResponse_Item.ProtoReflect.Descriptor instead.
+func (*ShortURLCreateBatchResponse_Item) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *ShortURLCreateBatchResponse_Item) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *ShortURLCreateBatchResponse_Item) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+type ShortURLGetByUserIDResponse_Item struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OriginalUrl   string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	QueryTemplate *QueryTemplate         `protobuf:"bytes,3,opt,name=query_template,json=queryTemplate,proto3" json:"query_template,omitempty"`
+	Split         *Split                 `protobuf:"bytes,4,opt,name=split,proto3" json:"split,omitempty"`
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Interstitial  bool                   `protobuf:"varint,7,opt,name=interstitial,proto3" json:"interstitial,omitempty"`
+	ActiveFrom    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=active_from,json=activeFrom,proto3" json:"active_from,omitempty"`
+	ActiveUntil   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=active_until,json=activeUntil,proto3" json:"active_until,omitempty"`
+	SubmittedUrl  string                 `protobuf:"bytes,10,opt,name=submitted_url,json=submittedUrl,proto3" json:"submitted_url,omitempty"`
+	TeamId        uint32                 `protobuf:"varint,11,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"` // если ссылка принадлежит команде
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) Reset() {
+	*x = ShortURLGetByUserIDResponse_Item{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLGetByUserIDResponse_Item) ProtoMessage() {}
+
+func (x *ShortURLGetByUserIDResponse_Item) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLGetByUserIDResponse_Item.ProtoReflect.Descriptor instead.
+func (*ShortURLGetByUserIDResponse_Item) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{9, 0}
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) GetQueryTemplate() *QueryTemplate {
+	if x != nil {
+		return x.QueryTemplate
+	}
+	return nil
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) GetSplit() *Split {
+	if x != nil {
+		return x.Split
+	}
+	return nil
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) GetInterstitial() bool {
+	if x != nil {
+		return x.Interstitial
+	}
+	return false
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) GetActiveFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveFrom
+	}
+	return nil
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) GetActiveUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ActiveUntil
+	}
+	return nil
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) GetSubmittedUrl() string {
+	if x != nil {
+		return x.SubmittedUrl
+	}
+	return ""
+}
+
+func (x *ShortURLGetByUserIDResponse_Item) GetTeamId() uint32 {
+	if x != nil {
+		return x.TeamId
+	}
+	return 0
+}
+
+// Schedule - расписание действия правила
+type RedirectRule_Schedule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Weekdays []string `protobuf:"bytes,1,rep,name=weekdays,proto3" json:"weekdays,omitempty"`                 // mon, tue, wed, thu, fri, sat, sun
+	From     string   `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`                         // 15:04
+	Until    string   `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`                       // 15:04
+	TimeZone string   `protobuf:"bytes,4,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // IANA, например Europe/Moscow
+}
+
+func (x *RedirectRule_Schedule) Reset() {
+	*x = RedirectRule_Schedule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RedirectRule_Schedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedirectRule_Schedule) ProtoMessage() {}
+
+func (x *RedirectRule_Schedule) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedirectRule_Schedule.ProtoReflect.Descriptor instead.
+func (*RedirectRule_Schedule) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{15, 0}
+}
+
+func (x *RedirectRule_Schedule) GetWeekdays() []string {
+	if x != nil {
+		return x.Weekdays
+	}
+	return nil
+}
+
+func (x *RedirectRule_Schedule) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *RedirectRule_Schedule) GetUntil() string {
+	if x != nil {
+		return x.Until
+	}
+	return ""
+}
+
+func (x *RedirectRule_Schedule) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+type ShortURLVariantStatsResponse_Item struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Variant int32  `protobuf:"varint,1,opt,name=variant,proto3" json:"variant,omitempty"`
+	Url     string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Weight  uint32 `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	Clicks  int64  `protobuf:"varint,4,opt,name=clicks,proto3" json:"clicks,omitempty"`
+}
+
+func (x *ShortURLVariantStatsResponse_Item) Reset() {
+	*x = ShortURLVariantStatsResponse_Item{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLVariantStatsResponse_Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLVariantStatsResponse_Item) ProtoMessage() {}
+
+func (x *ShortURLVariantStatsResponse_Item) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLVariantStatsResponse_Item.ProtoReflect.Descriptor instead.
+func (*ShortURLVariantStatsResponse_Item) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{26, 0}
+}
+
+func (x *ShortURLVariantStatsResponse_Item) GetVariant() int32 {
+	if x != nil {
+		return x.Variant
+	}
+	return 0
+}
+
+func (x *ShortURLVariantStatsResponse_Item) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ShortURLVariantStatsResponse_Item) GetWeight() uint32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *ShortURLVariantStatsResponse_Item) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+type ShortURLStatsResponse_Bucket struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Total  int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Unique int64                  `protobuf:"varint,3,opt,name=unique,proto3" json:"unique,omitempty"`
+}
+
+func (x *ShortURLStatsResponse_Bucket) Reset() {
+	*x = ShortURLStatsResponse_Bucket{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLStatsResponse_Bucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLStatsResponse_Bucket) ProtoMessage() {}
+
+func (x *ShortURLStatsResponse_Bucket) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLStatsResponse_Bucket.ProtoReflect.Descriptor instead.
+func (*ShortURLStatsResponse_Bucket) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{28, 0}
+}
+
+func (x *ShortURLStatsResponse_Bucket) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *ShortURLStatsResponse_Bucket) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ShortURLStatsResponse_Bucket) GetUnique() int64 {
+	if x != nil {
+		return x.Unique
+	}
+	return 0
+}
+
+type ShortURLStatsResponse_Count struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key    string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Clicks int64  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+}
+
+func (x *ShortURLStatsResponse_Count) Reset() {
+	*x = ShortURLStatsResponse_Count{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_short_url_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShortURLStatsResponse_Count) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShortURLStatsResponse_Count) ProtoMessage() {}
+
+func (x *ShortURLStatsResponse_Count) ProtoReflect() protoreflect.Message {
+	mi := &file_api_short_url_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShortURLStatsResponse_Count.ProtoReflect.Descriptor instead.
+func (*ShortURLStatsResponse_Count) Descriptor() ([]byte, []int) {
+	return file_api_short_url_proto_rawDescGZIP(), []int{28, 1}
+}
+
+func (x *ShortURLStatsResponse_Count) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ShortURLStatsResponse_Count) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+var File_api_short_url_proto protoreflect.FileDescriptor
+
+var file_api_short_url_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9c, 0x01,
+	0x0a, 0x0d, 0x51, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12,
+	0x38, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x1a, 0x39, 0x0a, 0x0b, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x86, 0x01, 0x0a,
+	0x05, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x12, 0x30, 0x0a, 0x08, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x2e, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x52, 0x08,
+	0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x69, 0x63,
+	0x6b, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x74, 0x69, 0x63, 0x6b, 0x79,
+	0x1a, 0x33, 0x0a, 0x07, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75,
+	0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x16, 0x0a,
+	0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x77,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0xd9, 0x02, 0x0a, 0x15, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
+	0x52, 0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72,
+	0x6c, 0x12, 0x3b, 0x0a, 0x0e, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52,
+	0x0d, 0x71, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x22,
+	0x0a, 0x05, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x05, 0x73, 0x70, 0x6c,
+	0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x73, 0x74, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x74, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x12, 0x3b, 0x0a, 0x0b,
+	0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x61,
+	0x63, 0x74, 0x69, 0x76, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x3d, 0x0a, 0x0c, 0x61, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x61, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x65, 0x61, 0x6d,
+	0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x74, 0x65, 0x61, 0x6d, 0x49,
+	0x64, 0x22, 0x30, 0x0a, 0x16, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x22, 0xea, 0x01, 0x0a, 0x1a, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x3c, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
+	0x52, 0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73,
+	0x1a, 0x8d, 0x01, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72,
+	0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x75, 0x72, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c,
+	0x55, 0x72, 0x6c, 0x12, 0x3b, 0x0a, 0x0e, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x74, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x52, 0x0d, 0x71, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
+	0x22, 0xa8, 0x01, 0x0a, 0x1b, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3d, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x1a,
+	0x4a, 0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1b,
+	0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x72, 0x6c, 0x22, 0x32, 0x0a, 0x1a, 0x53,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22,
+	0x1d, 0x0a, 0x1b, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1c,
+	0x0a, 0x1a, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x47, 0x65, 0x74, 0x42, 0x79, 0x55,
+	0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xb5, 0x04, 0x0a,
+	0x1b, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x47, 0x65, 0x74, 0x42, 0x79, 0x55, 0x73,
+	0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x05,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x47, 0x65, 0x74, 0x42,
+	0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e,
+	0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x1a, 0xd6, 0x03, 0x0a, 0x04,
+	0x49, 0x74, 0x65, 0x6d, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c,
+	0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x72, 0x69, 0x67,
+	0x69, 0x6e, 0x61, 0x6c, 0x55, 0x72, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x6f, 0x72, 0x74,
+	0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x72, 0x6c, 0x12, 0x3b, 0x0a, 0x0e, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x74, 0x65,
+	0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x52, 0x0d, 0x71, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x12, 0x22, 0x0a, 0x05, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x05,
+	0x73, 0x70, 0x6c, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x73,
+	0x74, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x73, 0x74, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x12, 0x3b, 0x0a, 0x0b, 0x61, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x61, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x3d, 0x0a, 0x0c, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x74, 0x65, 0x64, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x55, 0x72, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x74,
+	0x65, 0x61, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x74, 0x65,
+	0x61, 0x6d, 0x49, 0x64, 0x22, 0x35, 0x0a, 0x1a, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x47, 0x65, 0x74, 0x42, 0x79, 0x54, 0x65, 0x61, 0x6d, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x06, 0x74, 0x65, 0x61, 0x6d, 0x49, 0x64, 0x22, 0x41, 0x0a, 0x16, 0x53,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x74, 0x65, 0x61, 0x6d, 0x49, 0x64, 0x22, 0x19,
+	0x0a, 0x17, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x54, 0x65, 0x61,
+	0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x16, 0x0a, 0x14, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x22, 0xe0, 0x01, 0x0a, 0x15, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x51, 0x75,
+	0x6f, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70,
+	0x6c, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6c, 0x61, 0x6e, 0x12,
+	0x26, 0x0a, 0x0f, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x75, 0x72,
+	0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x41, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x55, 0x72, 0x6c, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x75, 0x72, 0x6c, 0x73, 0x5f,
+	0x70, 0x65, 0x72, 0x5f, 0x64, 0x61, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x75,
+	0x72, 0x6c, 0x73, 0x50, 0x65, 0x72, 0x44, 0x61, 0x79, 0x12, 0x24, 0x0a, 0x0e, 0x6d, 0x61, 0x78,
+	0x5f, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x75, 0x72, 0x6c, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x55, 0x72, 0x6c, 0x73,
+	0x12, 0x22, 0x0a, 0x0d, 0x75, 0x72, 0x6c, 0x73, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x64, 0x61,
+	0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x75, 0x72, 0x6c, 0x73, 0x4c, 0x61, 0x73,
+	0x74, 0x44, 0x61, 0x79, 0x22, 0x81, 0x02, 0x0a, 0x0c, 0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x73, 0x12,
+	0x38, 0x0a, 0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x2e, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52,
+	0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x1a, 0x6d, 0x0a, 0x08, 0x53, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x77, 0x65, 0x65, 0x6b, 0x64, 0x61, 0x79,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x77, 0x65, 0x65, 0x6b, 0x64, 0x61, 0x79,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x5f, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x74, 0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x22, 0x29, 0x0a, 0x17, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x52, 0x4c, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x22, 0x54, 0x0a, 0x17, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53,
+	0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x29,
+	0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x52, 0x75,
+	0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0x42, 0x0a, 0x15, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0x6e, 0x0a,
+	0x1f, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x3b, 0x0a, 0x0e, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x0d,
+	0x71, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0x5f, 0x0a,
+	0x20, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x3b, 0x0a, 0x0e, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52,
+	0x0d, 0x71, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0x65,
+	0x0a, 0x19, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x50, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c,
+	0x65, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x74, 0x69, 0x74, 0x69, 0x61,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x74,
+	0x69, 0x74, 0x69, 0x61, 0x6c, 0x22, 0x56, 0x0a, 0x1a, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x53, 0x65, 0x74, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x73, 0x74, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x73, 0x74, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x22, 0xac, 0x01,
+	0x0a, 0x1e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x41, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x3b, 0x0a, 0x0b, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0a, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x3d, 0x0a,
+	0x0c, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x0b, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x22, 0x9d, 0x01, 0x0a,
+	0x1f, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3b, 0x0a, 0x0b, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x0a, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x3d, 0x0a,
+	0x0c, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x0b, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x22, 0x2d, 0x0a, 0x1b,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0xc2, 0x01, 0x0a, 0x1c,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x05,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x56, 0x61, 0x72, 0x69,
+	0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x1a, 0x62, 0x0a, 0x04,
+	0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x10,
+	0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c,
+	0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x63,
+	0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73,
+	0x22, 0xc7, 0x01, 0x0a, 0x14, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x30, 0x0a, 0x05, 0x75, 0x6e, 0x74,
+	0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x5f, 0x62, 0x6f, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x69,
+	0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x42, 0x6f, 0x74, 0x73, 0x22, 0xeb, 0x05, 0x0a, 0x15, 0x53,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04,
+	0x66, 0x72, 0x6f, 0x6d, 0x12, 0x30, 0x0a, 0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x05, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x69, 0x71,
+	0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65,
+	0x12, 0x3d, 0x0a, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
+	0x52, 0x4c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e,
+	0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x52, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12,
+	0x40, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x72, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x52, 0x4c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x72,
+	0x73, 0x12, 0x40, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x09, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72,
+	0x69, 0x65, 0x73, 0x12, 0x3c, 0x0a, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x09,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x78, 0x5f, 0x75, 0x6e, 0x69, 0x71,
+	0x75, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x78,
+	0x55, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x12, 0x2e, 0x0a, 0x13, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x78,
+	0x5f, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x11, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x78, 0x55, 0x6e, 0x69, 0x71, 0x75,
+	0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x74, 0x73, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x62, 0x6f, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e,
+	0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x62, 0x6f, 0x74, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0b, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x42, 0x6f, 0x74, 0x73, 0x1a, 0x66, 0x0a,
+	0x06, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x16, 0x0a,
+	0x06, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75,
+	0x6e, 0x69, 0x71, 0x75, 0x65, 0x1a, 0x31, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x63, 0x6c, 0x69, 0x63, 0x6b, 0x73, 0x22, 0x88, 0x01, 0x0a, 0x1a, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x43, 0x6c, 0x69, 0x63, 0x6b, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x02, 0x74, 0x6f, 0x22, 0xf2, 0x01, 0x0a, 0x0d, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x43, 0x6c, 0x69, 0x63, 0x6b, 0x12, 0x20, 0x0a, 0x0c, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x75,
+	0x72, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x72, 0x6c, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x72, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72,
+	0x65, 0x66, 0x65, 0x72, 0x65, 0x72, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x69, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76,
+	0x69, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x6f, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x03, 0x62, 0x6f, 0x74, 0x32, 0xf4, 0x09, 0x0a, 0x08, 0x53, 0x68, 0x6f,
+	0x72, 0x74, 0x55, 0x52, 0x4c, 0x12, 0x47, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12,
+	0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x56,
+	0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x21, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x56, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68,
+	0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x56,
+	0x0a, 0x0b, 0x47, 0x65, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x21, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x47, 0x65,
+	0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x47, 0x65, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x56, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x42, 0x79, 0x54,
+	0x65, 0x61, 0x6d, 0x49, 0x44, 0x12, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68,
+	0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x47, 0x65, 0x74, 0x42, 0x79, 0x54, 0x65, 0x61, 0x6d, 0x49,
+	0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x47, 0x65, 0x74, 0x42, 0x79, 0x55, 0x73,
+	0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4a,
+	0x0a, 0x07, 0x53, 0x65, 0x74, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x54, 0x65, 0x61,
+	0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x54, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x08, 0x47, 0x65,
+	0x74, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53,
+	0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72,
+	0x74, 0x55, 0x52, 0x4c, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12,
+	0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x47, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x52, 0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x4a, 0x0a, 0x08, 0x53, 0x65, 0x74, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x52,
+	0x75, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x52, 0x75, 0x6c, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x65, 0x0a, 0x10, 0x53,
+	0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12,
+	0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c,
+	0x53, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x53, 0x0a, 0x0a, 0x53, 0x65, 0x74, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x12, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52,
+	0x4c, 0x53, 0x65, 0x74, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x62, 0x0a, 0x0f, 0x53, 0x65, 0x74, 0x41, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x25, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x65, 0x74, 0x41, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x26, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55,
+	0x52, 0x4c, 0x53, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5c, 0x0a, 0x0f, 0x47,
+	0x65, 0x74, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x22,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x56,
+	0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x52, 0x4c, 0x56, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x08, 0x47, 0x65, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68,
+	0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x52, 0x4c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0c, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x6c, 0x69, 0x63,
+	0x6b, 0x73, 0x12, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68, 0x6f, 0x72, 0x74,
+	0x55, 0x52, 0x4c, 0x43, 0x6c, 0x69, 0x63, 0x6b, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x68,
+	0x6f, 0x72, 0x74, 0x55, 0x52, 0x4c, 0x43, 0x6c, 0x69, 0x63, 0x6b, 0x22, 0x00, 0x30, 0x01, 0x42,
+	0x0c, 0x5a, 0x0a, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_short_url_proto_rawDescOnce sync.Once
+	file_api_short_url_proto_rawDescData = file_api_short_url_proto_rawDesc
+)
+
+func file_api_short_url_proto_rawDescGZIP() []byte {
+	file_api_short_url_proto_rawDescOnce.Do(func() {
+		file_api_short_url_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_short_url_proto_rawDescData)
+	})
+	return file_api_short_url_proto_rawDescData
+}
+
+var file_api_short_url_proto_msgTypes = make([]protoimpl.MessageInfo, 40)
+var file_api_short_url_proto_goTypes = []interface{}{
+	(*QueryTemplate)(nil),                     // 0: proto.QueryTemplate
+	(*Split)(nil),                             // 1: proto.Split
+	(*ShortURLCreateRequest)(nil),             // 2: proto.ShortURLCreateRequest
+	(*ShortURLCreateResponse)(nil),            // 3: proto.ShortURLCreateResponse
+	(*ShortURLCreateBatchRequest)(nil),        // 4: proto.ShortURLCreateBatchRequest
+	(*ShortURLCreateBatchResponse)(nil),       // 5: proto.ShortURLCreateBatchResponse
+	(*ShortURLDeleteBatchRequest)(nil),        // 6: proto.ShortURLDeleteBatchRequest
+	(*ShortURLDeleteBatchResponse)(nil),       // 7: proto.ShortURLDeleteBatchResponse
+	(*ShortURLGetByUserIDRequest)(nil),        // 8: proto.ShortURLGetByUserIDRequest
+	(*ShortURLGetByUserIDResponse)(nil),       // 9: proto.ShortURLGetByUserIDResponse
+	(*ShortURLGetByTeamIDRequest)(nil),        // 10: proto.ShortURLGetByTeamIDRequest
+	(*ShortURLSetTeamRequest)(nil),            // 11: proto.ShortURLSetTeamRequest
+	(*ShortURLSetTeamResponse)(nil),           // 12: proto.ShortURLSetTeamResponse
+	(*ShortURLQuotaRequest)(nil),              // 13: proto.ShortURLQuotaRequest
+	(*ShortURLQuotaResponse)(nil),             // 14: proto.ShortURLQuotaResponse
+	(*RedirectRule)(nil),                      // 15: proto.RedirectRule
+	(*ShortURLGetRulesRequest)(nil),           // 16: proto.ShortURLGetRulesRequest
+	(*ShortURLSetRulesRequest)(nil),           // 17: proto.ShortURLSetRulesRequest
+	(*ShortURLRulesResponse)(nil),             // 18: proto.ShortURLRulesResponse
+	(*ShortURLSetQueryTemplateRequest)(nil),   // 19: proto.ShortURLSetQueryTemplateRequest
+	(*ShortURLSetQueryTemplateResponse)(nil),  // 20: proto.ShortURLSetQueryTemplateResponse
+	(*ShortURLSetPreviewRequest)(nil),         // 21: proto.ShortURLSetPreviewRequest
+	(*ShortURLSetPreviewResponse)(nil),        // 22: proto.ShortURLSetPreviewResponse
+	(*ShortURLSetActiveWindowRequest)(nil),    // 23: proto.ShortURLSetActiveWindowRequest
+	(*ShortURLSetActiveWindowResponse)(nil),   // 24: proto.ShortURLSetActiveWindowResponse
+	(*ShortURLVariantStatsRequest)(nil),       // 25: proto.ShortURLVariantStatsRequest
+	(*ShortURLVariantStatsResponse)(nil),      // 26: proto.ShortURLVariantStatsResponse
+	(*ShortURLStatsRequest)(nil),              // 27: proto.ShortURLStatsRequest
+	(*ShortURLStatsResponse)(nil),             // 28: proto.ShortURLStatsResponse
+	(*ShortURLClickExportRequest)(nil),        // 29: proto.ShortURLClickExportRequest
+	(*ShortURLClick)(nil),                     // 30: proto.ShortURLClick
+	nil,                                       // 31: proto.QueryTemplate.ParamsEntry
+	(*Split_Variant)(nil),                     // 32: proto.Split.Variant
+	(*ShortURLCreateBatchRequest_Item)(nil),   // 33: proto.ShortURLCreateBatchRequest.Item
+	(*ShortURLCreateBatchResponse_Item)(nil),  // 34: proto.ShortURLCreateBatchResponse.Item
+	(*ShortURLGetByUserIDResponse_Item)(nil),  // 35: proto.ShortURLGetByUserIDResponse.Item
+	(*RedirectRule_Schedule)(nil),             // 36: proto.RedirectRule.Schedule
+	(*ShortURLVariantStatsResponse_Item)(nil), // 37: proto.ShortURLVariantStatsResponse.Item
+	(*ShortURLStatsResponse_Bucket)(nil),      // 38: proto.ShortURLStatsResponse.Bucket
+	(*ShortURLStatsResponse_Count)(nil),       // 39: proto.ShortURLStatsResponse.Count
+	(*timestamppb.Timestamp)(nil),             // 40: google.protobuf.Timestamp
+}
+var file_api_short_url_proto_depIdxs = []int32{
+	31, // 0: proto.QueryTemplate.params:type_name -> proto.QueryTemplate.ParamsEntry
+	32, // 1: proto.Split.variants:type_name -> proto.Split.Variant
+	0,  // 2: proto.ShortURLCreateRequest.query_template:type_name -> proto.QueryTemplate
+	1,  // 3: proto.ShortURLCreateRequest.split:type_name -> proto.Split
+	40, // 4: proto.ShortURLCreateRequest.active_from:type_name -> google.protobuf.Timestamp
+	40, // 5: proto.ShortURLCreateRequest.active_until:type_name -> google.protobuf.Timestamp
+	33, // 6: proto.ShortURLCreateBatchRequest.items:type_name -> proto.ShortURLCreateBatchRequest.Item
+	34, // 7: proto.ShortURLCreateBatchResponse.items:type_name -> proto.ShortURLCreateBatchResponse.Item
+	35, // 8: proto.ShortURLGetByUserIDResponse.items:type_name -> proto.ShortURLGetByUserIDResponse.Item
+	36, // 9: proto.RedirectRule.schedule:type_name -> proto.RedirectRule.Schedule
+	15, // 10: proto.ShortURLSetRulesRequest.rules:type_name -> proto.RedirectRule
+	15, // 11: proto.ShortURLRulesResponse.rules:type_name -> proto.RedirectRule
+	0,  // 12: proto.ShortURLSetQueryTemplateRequest.query_template:type_name -> proto.QueryTemplate
+	0,  // 13: proto.ShortURLSetQueryTemplateResponse.query_template:type_name -> proto.QueryTemplate
+	40, // 14: proto.ShortURLSetActiveWindowRequest.active_from:type_name -> google.protobuf.Timestamp
+	40, // 15: proto.ShortURLSetActiveWindowRequest.active_until:type_name -> google.protobuf.Timestamp
+	40, // 16: proto.ShortURLSetActiveWindowResponse.active_from:type_name -> google.protobuf.Timestamp
+	40, // 17: proto.ShortURLSetActiveWindowResponse.active_until:type_name -> google.protobuf.Timestamp
+	37, // 18: proto.ShortURLVariantStatsResponse.items:type_name -> proto.ShortURLVariantStatsResponse.Item
+	40, // 19: proto.ShortURLStatsRequest.from:type_name -> google.protobuf.Timestamp
+	40, // 20: proto.ShortURLStatsRequest.until:type_name -> google.protobuf.Timestamp
+	40, // 21: proto.ShortURLStatsResponse.from:type_name -> google.protobuf.Timestamp
+	40, // 22: proto.ShortURLStatsResponse.until:type_name -> google.protobuf.Timestamp
+	38, // 23: proto.ShortURLStatsResponse.buckets:type_name -> proto.ShortURLStatsResponse.Bucket
+	39, // 24: proto.ShortURLStatsResponse.referrers:type_name -> proto.ShortURLStatsResponse.Count
+	39, // 25: proto.ShortURLStatsResponse.countries:type_name -> proto.ShortURLStatsResponse.Count
+	39, // 26: proto.ShortURLStatsResponse.devices:type_name -> proto.ShortURLStatsResponse.Count
+	40, // 27: proto.ShortURLClickExportRequest.from:type_name -> google.protobuf.Timestamp
+	40, // 28: proto.ShortURLClickExportRequest.to:type_name -> google.protobuf.Timestamp
+	40, // 29: proto.ShortURLClick.time:type_name -> google.protobuf.Timestamp
+	0,  // 30: proto.ShortURLCreateBatchRequest.Item.query_template:type_name -> proto.QueryTemplate
+	0,  // 31: proto.ShortURLGetByUserIDResponse.Item.query_template:type_name -> proto.QueryTemplate
+	1,  // 32: proto.ShortURLGetByUserIDResponse.Item.split:type_name -> proto.Split
+	40, // 33: proto.ShortURLGetByUserIDResponse.Item.created_at:type_name -> google.protobuf.Timestamp
+	40, // 34: proto.ShortURLGetByUserIDResponse.Item.active_from:type_name -> google.protobuf.Timestamp
+	40, // 35: proto.ShortURLGetByUserIDResponse.Item.active_until:type_name -> google.protobuf.Timestamp
+	40, // 36: proto.ShortURLStatsResponse.Bucket.time:type_name -> google.protobuf.Timestamp
+	2,  // 37: proto.ShortURL.Create:input_type -> proto.ShortURLCreateRequest
+	4,  // 38: proto.ShortURL.CreateBatch:input_type -> proto.ShortURLCreateBatchRequest
+	6,  // 39: proto.ShortURL.DeleteBatch:input_type -> proto.ShortURLDeleteBatchRequest
+	8,  // 40: proto.ShortURL.GetByUserID:input_type -> proto.ShortURLGetByUserIDRequest
+	10, // 41: proto.ShortURL.GetByTeamID:input_type -> proto.ShortURLGetByTeamIDRequest
+	11, // 42: proto.ShortURL.SetTeam:input_type -> proto.ShortURLSetTeamRequest
+	13, // 43: proto.ShortURL.GetQuota:input_type -> proto.ShortURLQuotaRequest
+	16, // 44: proto.ShortURL.GetRules:input_type -> proto.ShortURLGetRulesRequest
+	17, // 45: proto.ShortURL.SetRules:input_type -> proto.ShortURLSetRulesRequest
+	19, // 46: proto.ShortURL.SetQueryTemplate:input_type -> proto.ShortURLSetQueryTemplateRequest
+	21, // 47: proto.ShortURL.SetPreview:input_type -> proto.ShortURLSetPreviewRequest
+	23, // 48: proto.ShortURL.SetActiveWindow:input_type -> proto.ShortURLSetActiveWindowRequest
+	25, // 49: proto.ShortURL.GetVariantStats:input_type -> proto.ShortURLVariantStatsRequest
+	27, // 50: proto.ShortURL.GetStats:input_type -> proto.ShortURLStatsRequest
+	29, // 51: proto.ShortURL.ExportClicks:input_type -> proto.ShortURLClickExportRequest
+	3,  // 52: proto.ShortURL.Create:output_type -> proto.ShortURLCreateResponse
+	5,  // 53: proto.ShortURL.CreateBatch:output_type -> proto.ShortURLCreateBatchResponse
+	7,  // 54: proto.ShortURL.DeleteBatch:output_type -> proto.ShortURLDeleteBatchResponse
+	9,  // 55: proto.ShortURL.GetByUserID:output_type -> proto.ShortURLGetByUserIDResponse
+	9,  // 56: proto.ShortURL.GetByTeamID:output_type -> proto.ShortURLGetByUserIDResponse
+	12, // 57: proto.ShortURL.SetTeam:output_type -> proto.ShortURLSetTeamResponse
+	14, // 58: proto.ShortURL.GetQuota:output_type -> proto.ShortURLQuotaResponse
+	18, // 59: proto.ShortURL.GetRules:output_type -> proto.ShortURLRulesResponse
+	18, // 60: proto.ShortURL.SetRules:output_type -> proto.ShortURLRulesResponse
+	20, // 61: proto.ShortURL.SetQueryTemplate:output_type -> proto.ShortURLSetQueryTemplateResponse
+	22, // 62: proto.ShortURL.SetPreview:output_type -> proto.ShortURLSetPreviewResponse
+	24, // 63: proto.ShortURL.SetActiveWindow:output_type -> proto.ShortURLSetActiveWindowResponse
+	26, // 64: proto.ShortURL.GetVariantStats:output_type -> proto.ShortURLVariantStatsResponse
+	28, // 65: proto.ShortURL.GetStats:output_type -> proto.ShortURLStatsResponse
+	30, // 66: proto.ShortURL.ExportClicks:output_type -> proto.ShortURLClick
+	52, // [52:67] is the sub-list for method output_type
+	37, // [37:52] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
+}
+
+func init() { file_api_short_url_proto_init() }
+func file_api_short_url_proto_init() {
 	if File_api_short_url_proto != nil {
 		return
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_api_short_url_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryTemplate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Split); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ShortURLCreateRequest); i {
 			case 0:
 				return &v.state
@@ -691,8 +3026,44 @@ func file_api_short_url_proto_init() {
 				return nil
 			}
 		}
-		file_api_short_url_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShortURLCreateResponse); i {
+		file_api_short_url_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLCreateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLCreateBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLCreateBatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLDeleteBatchRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -703,8 +3074,8 @@ func file_api_short_url_proto_init() {
 				return nil
 			}
 		}
-		file_api_short_url_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShortURLCreateBatchRequest); i {
+		file_api_short_url_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLDeleteBatchResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -715,8 +3086,8 @@ func file_api_short_url_proto_init() {
 				return nil
 			}
 		}
-		file_api_short_url_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShortURLCreateBatchResponse); i {
+		file_api_short_url_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLGetByUserIDRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -727,8 +3098,8 @@ func file_api_short_url_proto_init() {
 				return nil
 			}
 		}
-		file_api_short_url_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShortURLDeleteBatchRequest); i {
+		file_api_short_url_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLGetByUserIDResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -739,8 +3110,8 @@ func file_api_short_url_proto_init() {
 				return nil
 			}
 		}
-		file_api_short_url_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShortURLDeleteBatchResponse); i {
+		file_api_short_url_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLGetByTeamIDRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -751,8 +3122,8 @@ func file_api_short_url_proto_init() {
 				return nil
 			}
 		}
-		file_api_short_url_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShortURLGetByUserIDRequest); i {
+		file_api_short_url_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLSetTeamRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -763,8 +3134,8 @@ func file_api_short_url_proto_init() {
 				return nil
 			}
 		}
-		file_api_short_url_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ShortURLGetByUserIDResponse); i {
+		file_api_short_url_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLSetTeamResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -775,7 +3146,235 @@ func file_api_short_url_proto_init() {
 				return nil
 			}
 		}
-		file_api_short_url_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_api_short_url_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLQuotaRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLQuotaResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RedirectRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLGetRulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLSetRulesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLRulesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLSetQueryTemplateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLSetQueryTemplateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLSetPreviewRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLSetPreviewResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLSetActiveWindowRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLSetActiveWindowResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLVariantStatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLVariantStatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLStatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLStatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLClickExportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLClick); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Split_Variant); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ShortURLCreateBatchRequest_Item); i {
 			case 0:
 				return &v.state
@@ -787,7 +3386,7 @@ func file_api_short_url_proto_init() {
 				return nil
 			}
 		}
-		file_api_short_url_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_api_short_url_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ShortURLCreateBatchResponse_Item); i {
 			case 0:
 				return &v.state
@@ -799,7 +3398,7 @@ func file_api_short_url_proto_init() {
 				return nil
 			}
 		}
-		file_api_short_url_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_api_short_url_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ShortURLGetByUserIDResponse_Item); i {
 			case 0:
 				return &v.state
@@ -811,6 +3410,54 @@ func file_api_short_url_proto_init() {
 				return nil
 			}
 		}
+		file_api_short_url_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RedirectRule_Schedule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLVariantStatsResponse_Item); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLStatsResponse_Bucket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_short_url_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShortURLStatsResponse_Count); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -818,7 +3465,7 @@ func file_api_short_url_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_short_url_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   40,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -26,6 +26,17 @@ type ShortURLClient interface {
 	CreateBatch(ctx context.Context, in *ShortURLCreateBatchRequest, opts ...grpc.CallOption) (*ShortURLCreateBatchResponse, error)
 	DeleteBatch(ctx context.Context, in *ShortURLDeleteBatchRequest, opts ...grpc.CallOption) (*ShortURLDeleteBatchResponse, error)
 	GetByUserID(ctx context.Context, in *ShortURLGetByUserIDRequest, opts ...grpc.CallOption) (*ShortURLGetByUserIDResponse, error)
+	GetByTeamID(ctx context.Context, in *ShortURLGetByTeamIDRequest, opts ...grpc.CallOption) (*ShortURLGetByUserIDResponse, error)
+	SetTeam(ctx context.Context, in *ShortURLSetTeamRequest, opts ...grpc.CallOption) (*ShortURLSetTeamResponse, error)
+	GetQuota(ctx context.Context, in *ShortURLQuotaRequest, opts ...grpc.CallOption) (*ShortURLQuotaResponse, error)
+	GetRules(ctx context.Context, in *ShortURLGetRulesRequest, opts ...grpc.CallOption) (*ShortURLRulesResponse, error)
+	SetRules(ctx context.Context, in *ShortURLSetRulesRequest, opts ...grpc.CallOption) (*ShortURLRulesResponse, error)
+	SetQueryTemplate(ctx context.Context, in *ShortURLSetQueryTemplateRequest, opts ...grpc.CallOption) (*ShortURLSetQueryTemplateResponse, error)
+	SetPreview(ctx context.Context, in *ShortURLSetPreviewRequest, opts ...grpc.CallOption) (*ShortURLSetPreviewResponse, error)
+	SetActiveWindow(ctx context.Context, in *ShortURLSetActiveWindowRequest, opts ...grpc.CallOption) (*ShortURLSetActiveWindowResponse, error)
+	GetVariantStats(ctx context.Context, in *ShortURLVariantStatsRequest, opts ...grpc.CallOption) (*ShortURLVariantStatsResponse, error)
+	GetStats(ctx context.Context, in *ShortURLStatsRequest, opts ...grpc.CallOption) (*ShortURLStatsResponse, error)
+	ExportClicks(ctx context.Context, in *ShortURLClickExportRequest, opts ...grpc.CallOption) (ShortURL_ExportClicksClient, error)
 }
 
 type shortURLClient struct {
@@ -72,6 +83,128 @@ func (c *shortURLClient) GetByUserID(ctx context.Context, in *ShortURLGetByUserI
 	return out, nil
 }
 
+func (c *shortURLClient) GetByTeamID(ctx context.Context, in *ShortURLGetByTeamIDRequest, opts ...grpc.CallOption) (*ShortURLGetByUserIDResponse, error) {
+	out := new(ShortURLGetByUserIDResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShortURL/GetByTeamID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLClient) SetTeam(ctx context.Context, in *ShortURLSetTeamRequest, opts ...grpc.CallOption) (*ShortURLSetTeamResponse, error) {
+	out := new(ShortURLSetTeamResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShortURL/SetTeam", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLClient) GetQuota(ctx context.Context, in *ShortURLQuotaRequest, opts ...grpc.CallOption) (*ShortURLQuotaResponse, error) {
+	out := new(ShortURLQuotaResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShortURL/GetQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLClient) GetRules(ctx context.Context, in *ShortURLGetRulesRequest, opts ...grpc.CallOption) (*ShortURLRulesResponse, error) {
+	out := new(ShortURLRulesResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShortURL/GetRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLClient) SetRules(ctx context.Context, in *ShortURLSetRulesRequest, opts ...grpc.CallOption) (*ShortURLRulesResponse, error) {
+	out := new(ShortURLRulesResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShortURL/SetRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLClient) SetQueryTemplate(ctx context.Context, in *ShortURLSetQueryTemplateRequest, opts ...grpc.CallOption) (*ShortURLSetQueryTemplateResponse, error) {
+	out := new(ShortURLSetQueryTemplateResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShortURL/SetQueryTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLClient) SetPreview(ctx context.Context, in *ShortURLSetPreviewRequest, opts ...grpc.CallOption) (*ShortURLSetPreviewResponse, error) {
+	out := new(ShortURLSetPreviewResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShortURL/SetPreview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLClient) SetActiveWindow(ctx context.Context, in *ShortURLSetActiveWindowRequest, opts ...grpc.CallOption) (*ShortURLSetActiveWindowResponse, error) {
+	out := new(ShortURLSetActiveWindowResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShortURL/SetActiveWindow", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLClient) GetVariantStats(ctx context.Context, in *ShortURLVariantStatsRequest, opts ...grpc.CallOption) (*ShortURLVariantStatsResponse, error) {
+	out := new(ShortURLVariantStatsResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShortURL/GetVariantStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLClient) GetStats(ctx context.Context, in *ShortURLStatsRequest, opts ...grpc.CallOption) (*ShortURLStatsResponse, error) {
+	out := new(ShortURLStatsResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShortURL/GetStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortURLClient) ExportClicks(ctx context.Context, in *ShortURLClickExportRequest, opts ...grpc.CallOption) (ShortURL_ExportClicksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ShortURL_ServiceDesc.Streams[0], "/proto.ShortURL/ExportClicks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shortURLExportClicksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ShortURL_ExportClicksClient interface {
+	Recv() (*ShortURLClick, error)
+	grpc.ClientStream
+}
+
+type shortURLExportClicksClient struct {
+	grpc.ClientStream
+}
+
+func (x *shortURLExportClicksClient) Recv() (*ShortURLClick, error) {
+	m := new(ShortURLClick)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ShortURLServer is the server API for ShortURL service.
 // All implementations must embed UnimplementedShortURLServer
 // for forward compatibility
@@ -80,6 +213,17 @@ type ShortURLServer interface {
 	CreateBatch(context.Context, *ShortURLCreateBatchRequest) (*ShortURLCreateBatchResponse, error)
 	DeleteBatch(context.Context, *ShortURLDeleteBatchRequest) (*ShortURLDeleteBatchResponse, error)
 	GetByUserID(context.Context, *ShortURLGetByUserIDRequest) (*ShortURLGetByUserIDResponse, error)
+	GetByTeamID(context.Context, *ShortURLGetByTeamIDRequest) (*ShortURLGetByUserIDResponse, error)
+	SetTeam(context.Context, *ShortURLSetTeamRequest) (*ShortURLSetTeamResponse, error)
+	GetQuota(context.Context, *ShortURLQuotaRequest) (*ShortURLQuotaResponse, error)
+	GetRules(context.Context, *ShortURLGetRulesRequest) (*ShortURLRulesResponse, error)
+	SetRules(context.Context, *ShortURLSetRulesRequest) (*ShortURLRulesResponse, error)
+	SetQueryTemplate(context.Context, *ShortURLSetQueryTemplateRequest) (*ShortURLSetQueryTemplateResponse, error)
+	SetPreview(context.Context, *ShortURLSetPreviewRequest) (*ShortURLSetPreviewResponse, error)
+	SetActiveWindow(context.Context, *ShortURLSetActiveWindowRequest) (*ShortURLSetActiveWindowResponse, error)
+	GetVariantStats(context.Context, *ShortURLVariantStatsRequest) (*ShortURLVariantStatsResponse, error)
+	GetStats(context.Context, *ShortURLStatsRequest) (*ShortURLStatsResponse, error)
+	ExportClicks(*ShortURLClickExportRequest, ShortURL_ExportClicksServer) error
 	mustEmbedUnimplementedShortURLServer()
 }
 
@@ -99,6 +243,39 @@ func (UnimplementedShortURLServer) DeleteBatch(context.Context, *ShortURLDeleteB
 func (UnimplementedShortURLServer) GetByUserID(context.Context, *ShortURLGetByUserIDRequest) (*ShortURLGetByUserIDResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetByUserID not implemented")
 }
+func (UnimplementedShortURLServer) GetByTeamID(context.Context, *ShortURLGetByTeamIDRequest) (*ShortURLGetByUserIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetByTeamID not implemented")
+}
+func (UnimplementedShortURLServer) SetTeam(context.Context, *ShortURLSetTeamRequest) (*ShortURLSetTeamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTeam not implemented")
+}
+func (UnimplementedShortURLServer) GetQuota(context.Context, *ShortURLQuotaRequest) (*ShortURLQuotaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuota not implemented")
+}
+func (UnimplementedShortURLServer) GetRules(context.Context, *ShortURLGetRulesRequest) (*ShortURLRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRules not implemented")
+}
+func (UnimplementedShortURLServer) SetRules(context.Context, *ShortURLSetRulesRequest) (*ShortURLRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRules not implemented")
+}
+func (UnimplementedShortURLServer) SetQueryTemplate(context.Context, *ShortURLSetQueryTemplateRequest) (*ShortURLSetQueryTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetQueryTemplate not implemented")
+}
+func (UnimplementedShortURLServer) SetPreview(context.Context, *ShortURLSetPreviewRequest) (*ShortURLSetPreviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPreview not implemented")
+}
+func (UnimplementedShortURLServer) SetActiveWindow(context.Context, *ShortURLSetActiveWindowRequest) (*ShortURLSetActiveWindowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetActiveWindow not implemented")
+}
+func (UnimplementedShortURLServer) GetVariantStats(context.Context, *ShortURLVariantStatsRequest) (*ShortURLVariantStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVariantStats not implemented")
+}
+func (UnimplementedShortURLServer) GetStats(context.Context, *ShortURLStatsRequest) (*ShortURLStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedShortURLServer) ExportClicks(*ShortURLClickExportRequest, ShortURL_ExportClicksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportClicks not implemented")
+}
 func (UnimplementedShortURLServer) mustEmbedUnimplementedShortURLServer() {}
 
 // UnsafeShortURLServer may be embedded to opt out of forward compatibility for this service.
@@ -184,6 +361,207 @@ func _ShortURL_GetByUserID_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortURL_GetByTeamID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortURLGetByTeamIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServer).GetByTeamID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShortURL/GetByTeamID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServer).GetByTeamID(ctx, req.(*ShortURLGetByTeamIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURL_SetTeam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortURLSetTeamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServer).SetTeam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShortURL/SetTeam",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServer).SetTeam(ctx, req.(*ShortURLSetTeamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURL_GetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortURLQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServer).GetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShortURL/GetQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServer).GetQuota(ctx, req.(*ShortURLQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURL_GetRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortURLGetRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServer).GetRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShortURL/GetRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServer).GetRules(ctx, req.(*ShortURLGetRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURL_SetRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortURLSetRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServer).SetRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShortURL/SetRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServer).SetRules(ctx, req.(*ShortURLSetRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURL_SetQueryTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortURLSetQueryTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServer).SetQueryTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShortURL/SetQueryTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServer).SetQueryTemplate(ctx, req.(*ShortURLSetQueryTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURL_SetPreview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortURLSetPreviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServer).SetPreview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShortURL/SetPreview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServer).SetPreview(ctx, req.(*ShortURLSetPreviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURL_SetActiveWindow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortURLSetActiveWindowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServer).SetActiveWindow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShortURL/SetActiveWindow",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServer).SetActiveWindow(ctx, req.(*ShortURLSetActiveWindowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURL_GetVariantStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortURLVariantStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServer).GetVariantStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShortURL/GetVariantStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServer).GetVariantStats(ctx, req.(*ShortURLVariantStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURL_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShortURLStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortURLServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShortURL/GetStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortURLServer).GetStats(ctx, req.(*ShortURLStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortURL_ExportClicks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ShortURLClickExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShortURLServer).ExportClicks(m, &shortURLExportClicksServer{stream})
+}
+
+type ShortURL_ExportClicksServer interface {
+	Send(*ShortURLClick) error
+	grpc.ServerStream
+}
+
+type shortURLExportClicksServer struct {
+	grpc.ServerStream
+}
+
+func (x *shortURLExportClicksServer) Send(m *ShortURLClick) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // ShortURL_ServiceDesc is the grpc.ServiceDesc for ShortURL service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -207,7 +585,53 @@ var ShortURL_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetByUserID",
 			Handler:    _ShortURL_GetByUserID_Handler,
 		},
+		{
+			MethodName: "GetByTeamID",
+			Handler:    _ShortURL_GetByTeamID_Handler,
+		},
+		{
+			MethodName: "SetTeam",
+			Handler:    _ShortURL_SetTeam_Handler,
+		},
+		{
+			MethodName: "GetQuota",
+			Handler:    _ShortURL_GetQuota_Handler,
+		},
+		{
+			MethodName: "GetRules",
+			Handler:    _ShortURL_GetRules_Handler,
+		},
+		{
+			MethodName: "SetRules",
+			Handler:    _ShortURL_SetRules_Handler,
+		},
+		{
+			MethodName: "SetQueryTemplate",
+			Handler:    _ShortURL_SetQueryTemplate_Handler,
+		},
+		{
+			MethodName: "SetPreview",
+			Handler:    _ShortURL_SetPreview_Handler,
+		},
+		{
+			MethodName: "SetActiveWindow",
+			Handler:    _ShortURL_SetActiveWindow_Handler,
+		},
+		{
+			MethodName: "GetVariantStats",
+			Handler:    _ShortURL_GetVariantStats_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _ShortURL_GetStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportClicks",
+			Handler:       _ShortURL_ExportClicks_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/short_url.proto",
 }
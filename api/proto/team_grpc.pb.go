@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: api/team.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// TeamsClient is the client API for Teams service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TeamsClient interface {
+	Create(ctx context.Context, in *TeamCreateRequest, opts ...grpc.CallOption) (*Team, error)
+	List(ctx context.Context, in *TeamListRequest, opts ...grpc.CallOption) (*TeamListResponse, error)
+	Members(ctx context.Context, in *TeamMembersRequest, opts ...grpc.CallOption) (*TeamMembersResponse, error)
+	SetMember(ctx context.Context, in *TeamSetMemberRequest, opts ...grpc.CallOption) (*TeamMember, error)
+	RemoveMember(ctx context.Context, in *TeamRemoveMemberRequest, opts ...grpc.CallOption) (*TeamRemoveMemberResponse, error)
+}
+
+type teamsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTeamsClient(cc grpc.ClientConnInterface) TeamsClient {
+	return &teamsClient{cc}
+}
+
+func (c *teamsClient) Create(ctx context.Context, in *TeamCreateRequest, opts ...grpc.CallOption) (*Team, error) {
+	out := new(Team)
+	err := c.cc.Invoke(ctx, "/proto.Teams/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamsClient) List(ctx context.Context, in *TeamListRequest, opts ...grpc.CallOption) (*TeamListResponse, error) {
+	out := new(TeamListResponse)
+	err := c.cc.Invoke(ctx, "/proto.Teams/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamsClient) Members(ctx context.Context, in *TeamMembersRequest, opts ...grpc.CallOption) (*TeamMembersResponse, error) {
+	out := new(TeamMembersResponse)
+	err := c.cc.Invoke(ctx, "/proto.Teams/Members", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamsClient) SetMember(ctx context.Context, in *TeamSetMemberRequest, opts ...grpc.CallOption) (*TeamMember, error) {
+	out := new(TeamMember)
+	err := c.cc.Invoke(ctx, "/proto.Teams/SetMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamsClient) RemoveMember(ctx context.Context, in *TeamRemoveMemberRequest, opts ...grpc.CallOption) (*TeamRemoveMemberResponse, error) {
+	out := new(TeamRemoveMemberResponse)
+	err := c.cc.Invoke(ctx, "/proto.Teams/RemoveMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TeamsServer is the server API for Teams service.
+// All implementations must embed UnimplementedTeamsServer
+// for forward compatibility
+type TeamsServer interface {
+	Create(context.Context, *TeamCreateRequest) (*Team, error)
+	List(context.Context, *TeamListRequest) (*TeamListResponse, error)
+	Members(context.Context, *TeamMembersRequest) (*TeamMembersResponse, error)
+	SetMember(context.Context, *TeamSetMemberRequest) (*TeamMember, error)
+	RemoveMember(context.Context, *TeamRemoveMemberRequest) (*TeamRemoveMemberResponse, error)
+	mustEmbedUnimplementedTeamsServer()
+}
+
+// UnimplementedTeamsServer must be embedded to have forward compatible implementations.
+type UnimplementedTeamsServer struct {
+}
+
+func (UnimplementedTeamsServer) Create(context.Context, *TeamCreateRequest) (*Team, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedTeamsServer) List(context.Context, *TeamListRequest) (*TeamListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedTeamsServer) Members(context.Context, *TeamMembersRequest) (*TeamMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Members not implemented")
+}
+func (UnimplementedTeamsServer) SetMember(context.Context, *TeamSetMemberRequest) (*TeamMember, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMember not implemented")
+}
+func (UnimplementedTeamsServer) RemoveMember(context.Context, *TeamRemoveMemberRequest) (*TeamRemoveMemberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMember not implemented")
+}
+func (UnimplementedTeamsServer) mustEmbedUnimplementedTeamsServer() {}
+
+// UnsafeTeamsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TeamsServer will
+// result in compilation errors.
+type UnsafeTeamsServer interface {
+	mustEmbedUnimplementedTeamsServer()
+}
+
+func RegisterTeamsServer(s grpc.ServiceRegistrar, srv TeamsServer) {
+	s.RegisterService(&Teams_ServiceDesc, srv)
+}
+
+func _Teams_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TeamCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamsServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Teams/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamsServer).Create(ctx, req.(*TeamCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Teams_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TeamListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Teams/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamsServer).List(ctx, req.(*TeamListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Teams_Members_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TeamMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamsServer).Members(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Teams/Members",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamsServer).Members(ctx, req.(*TeamMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Teams_SetMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TeamSetMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamsServer).SetMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Teams/SetMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamsServer).SetMember(ctx, req.(*TeamSetMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Teams_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TeamRemoveMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamsServer).RemoveMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.Teams/RemoveMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamsServer).RemoveMember(ctx, req.(*TeamRemoveMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Teams_ServiceDesc is the grpc.ServiceDesc for Teams service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Teams_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.Teams",
+	HandlerType: (*TeamsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _Teams_Create_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Teams_List_Handler,
+		},
+		{
+			MethodName: "Members",
+			Handler:    _Teams_Members_Handler,
+		},
+		{
+			MethodName: "SetMember",
+			Handler:    _Teams_SetMember_Handler,
+		},
+		{
+			MethodName: "RemoveMember",
+			Handler:    _Teams_RemoveMember_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/team.proto",
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/ofstudio/go-shortener/internal/app"
 	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/models"
 )
 
 var (
@@ -59,7 +60,11 @@ func main() {
 	defer cancel()
 
 	// Создаем и запускаем приложение
-	a := app.NewApp(cfg)
+	a := app.NewApp(cfg).WithBuildInfo(models.BuildInfo{
+		Version: buildVersion,
+		Date:    buildDate,
+		Commit:  buildCommit,
+	})
 	if err = a.Start(ctx); err != nil {
 		log.Fatal().Err(err).Msg("Application fatal error")
 	}
@@ -3,21 +3,34 @@ package app
 import (
 	"context"
 	"fmt"
+	"syscall"
+	"time"
 
+	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/models"
 	"github.com/ofstudio/go-shortener/internal/providers"
 	"github.com/ofstudio/go-shortener/internal/providers/auth"
+	"github.com/ofstudio/go-shortener/internal/providers/botdetect"
 	"github.com/ofstudio/go-shortener/internal/providers/ipcheck"
+	"github.com/ofstudio/go-shortener/internal/providers/metrics"
+	"github.com/ofstudio/go-shortener/internal/providers/oidc"
+	"github.com/ofstudio/go-shortener/internal/providers/policy"
 	"github.com/ofstudio/go-shortener/internal/providers/tlsconf"
+	"github.com/ofstudio/go-shortener/internal/providers/tracing"
 	"github.com/ofstudio/go-shortener/internal/repo"
 	"github.com/ofstudio/go-shortener/internal/usecases"
 )
 
+// traceShutdownTimeout - время на отправку накопленных span при завершении приложения
+const traceShutdownTimeout = 5 * time.Second
+
 // App - приложение.
 type App struct {
-	cfg *config.Config
+	cfg   *config.Config
+	build models.BuildInfo
 }
 
 // NewApp - конструктор App.
@@ -25,8 +38,19 @@ func NewApp(cfg *config.Config) *App {
 	return &App{cfg: cfg}
 }
 
+// WithBuildInfo - задает информацию о сборке приложения для статистики внутреннего API.
+func (a *App) WithBuildInfo(build models.BuildInfo) *App {
+	a.build = build
+	return a
+}
+
 // Start - запускает приложение.
 func (a *App) Start(ctx context.Context) error {
+	// Хеши посетителей сохраняются вместе с данными, поэтому их ключ не должен меняться при перезапуске
+	if err := a.cfg.ValidateVisitorSecret(); err != nil {
+		return err
+	}
+
 	// Создаём репозиторий
 	repository, err := repo.Fabric(a.cfg)
 	if err != nil {
@@ -35,14 +59,100 @@ func (a *App) Start(ctx context.Context) error {
 	//goland:noinspection GoUnhandledErrorResult
 	defer repository.Close()
 
-	// Создаем юзкейсы
-	u := usecases.NewContainer(ctx, a.cfg, repository)
+	// Подключаем трассировку.
+	// Накопленные span отправляются при завершении приложения.
+	exporter, err := tracing.NewExporter(ctx, a.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+	t := tracing.NewOTel(exporter)
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), traceShutdownTimeout)
+		defer cancel()
+		if err := t.Shutdown(shutdownCtx); err != nil {
+			log.Err(err).Msg("failed to shutdown tracing")
+		}
+	}()
+
+	// Учитываем длительность операций репозитория в метриках и трассировке
+	m := metrics.NewPrometheus()
+	backend := repo.BackendName(repository)
+	repository = repo.NewObservedRepo(repository, repo.ChainObserve(m.ObserveRepo(backend), t.ObserveRepo(backend)))
+
+	// Создаем юзкейсы.
+	// Фоновые задачи юзкейсов останавливаются и при штатном завершении, и при ошибке запуска серверов.
+	stopCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	u := usecases.NewContainer(stopCtx, a.cfg, repository)
+	u.ShortURL.UseMetrics(m)
+	if a.build != (models.BuildInfo{}) {
+		u.Stats.UseBuildInfo(a.build)
+	}
+
+	// Подключаем списки доменов для адресов назначения, если они заданы.
+	// Списки перезагружаются по сигналу SIGHUP.
+	if a.cfg.PolicyFile != "" {
+		domains, err := policy.NewDomainList(a.cfg.PolicyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load policy file: %w", err)
+		}
+		u.ShortURL.UsePolicies(domains)
+		ReloadOnSignal(ctx, domains.Reload, syscall.SIGHUP)
+	}
+
+	// Подключаем дополнительные шаблоны ботов, если они заданы.
+	// Шаблоны перезагружаются по сигналу SIGHUP.
+	if a.cfg.BotRulesFile != "" {
+		bots, err := botdetect.NewRules(a.cfg.BotRulesFile)
+		if err != nil {
+			return fmt.Errorf("failed to load bot rules file: %w", err)
+		}
+		u.ShortURL.UseBotClassifier(bots)
+		ReloadOnSignal(ctx, bots.Reload, syscall.SIGHUP)
+	}
+
+	// Подключаем набор ключей подписи токенов, если он задан.
+	// Ключи перезагружаются по сигналу SIGHUP.
+	keys := auth.NewStaticKeyring(a.cfg.AuthSecret)
+	if a.cfg.AuthKeysFile != "" {
+		if keys, err = auth.NewKeyring(a.cfg.AuthKeysFile, a.cfg.AuthSecret); err != nil {
+			return fmt.Errorf("failed to load auth keys file: %w", err)
+		}
+		ReloadOnSignal(ctx, keys.Reload, syscall.SIGHUP)
+	}
+
+	// Создаём провайдер аутентификации
+	var authProvider auth.Provider
+	if a.cfg.AuthProvider == config.AuthProviderJWT {
+		jwtProvider, err := auth.NewJWTProvider(a.cfg, u.User)
+		if err != nil {
+			return fmt.Errorf("failed to create JWT auth provider: %w", err)
+		}
+		jwtProvider.UseKeyring(keys)
+		authProvider = jwtProvider
+	} else {
+		sha256Provider := auth.NewSHA256Provider(a.cfg, u.User)
+		sha256Provider.UseKeyring(keys)
+		authProvider = sha256Provider
+	}
+	authProvider.UseAPIKeys(u.APIKey)
 
 	// Создаём провайдеры
 	p := &providers.Container{
-		Auth:    auth.NewSHA256Provider(a.cfg, u.User),
+		Auth:    authProvider,
 		IPCheck: ipcheck.NewWhitelist(a.cfg.TrustedSubnet),
 		TLSConf: tlsconf.NewSelfSignedProvider(a.cfg.Cert),
+		Metrics: m,
+		Tracing: t,
+	}
+
+	// Подключаем вход через OpenID Connect, если задан провайдер
+	if a.cfg.OIDCIssuer != "" {
+		client, err := oidc.NewClient(ctx, a.cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create OIDC client: %w", err)
+		}
+		p.OIDC = client
 	}
 
 	// Создаём серверы
@@ -55,6 +165,10 @@ func (a *App) Start(ctx context.Context) error {
 	g, ctx := errgroup.WithContext(ctx)
 	g.Go(func() error { return httpServer.Start(ctx) })
 	g.Go(func() error { return grpcServer.Start(ctx) })
+	err = g.Wait()
 
-	return g.Wait()
+	// Дожидаемся записи событий переходов до закрытия репозитория
+	stop()
+	u.ShortURL.Wait()
+	return err
 }
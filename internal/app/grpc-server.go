@@ -34,14 +34,31 @@ func (s *GRPCServer) Start(ctx context.Context) error {
 	// Создаём gRPC-сервер
 	server := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
+			s.p.Tracing.Interceptor,
 			logging.UnaryServerInterceptor(grpczerolog.InterceptorLogger(log.Logger)),
+			s.p.Metrics.Interceptor,
 			s.p.Auth.Interceptor,
+			services.ShortURLMethods.RequireUnary,
+			services.APIKeyMethods.RequireUnary,
+			services.TeamMethods.RequireUnary,
+			services.ShortURLScopes.RequireUnary,
 			interceptors.With("proto.Internal", s.p.IPCheck.Interceptor),
 		),
+		grpc.ChainStreamInterceptor(
+			s.p.Tracing.StreamInterceptor,
+			logging.StreamServerInterceptor(grpczerolog.InterceptorLogger(log.Logger)),
+			s.p.Metrics.StreamInterceptor,
+			s.p.Auth.StreamInterceptor,
+			services.ShortURLMethods.RequireStream,
+			services.ShortURLScopes.RequireStream,
+		),
 	)
 	// Регистрируем сервисы
 	proto.RegisterShortURLServer(server, services.NewShortURLService(s.u))
 	proto.RegisterInternalServer(server, services.NewInternalService(s.u))
+	proto.RegisterAPIKeysServer(server, services.NewAPIKeyService(s.u))
+	proto.RegisterAccountServer(server, services.NewAccountService(s.u))
+	proto.RegisterTeamsServer(server, services.NewTeamService(s.u))
 
 	// Горутина для остановки gRPC-сервера
 	stop := make(chan struct{})
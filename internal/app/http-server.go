@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html/template"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -35,7 +36,9 @@ func (s *HTTPServer) Start(ctx context.Context) error {
 
 	// Создаём маршрутизатор
 	r := chi.NewRouter()
+	r.Use(s.p.Tracing.Handler)
 	r.Use(httplog.RequestLogger(log.Logger))
+	r.Use(s.p.Metrics.Handler)
 
 	// Middleware для декомпрессии и компрессии ответов.
 	// Параметр minSize рекомендуется равным middleware.MTUSize.
@@ -44,22 +47,40 @@ func (s *HTTPServer) Start(ctx context.Context) error {
 	r.Use(middleware.NewCompressor(0, gzip.BestSpeed).
 		AddType("application/json").
 		AddType("text/plain").
-		AddType("text/html").Handler)
+		AddType("text/html").
+		AddType("text/csv").
+		AddType("application/x-ndjson").Handler)
 
 	// Middleware аутентификационной куки.
+	// Только определяет пользователя по токену: требования маршрутов объявляются в хендлерах.
 	r.Use(s.p.Auth.Handler)
 
 	// Публичные HTTP-запросы
-	r.Mount("/", handlers.NewHTTPHandlers(s.u).Routes())
+	opts := []handlers.HTTPOpt{handlers.WithMetrics(s.p.Metrics)}
+	if len(s.cfg.TrustedProxies) > 0 {
+		opts = append(opts, handlers.WithTrustedProxies(s.cfg.TrustedProxies...))
+	}
+	if s.cfg.PlaceholderPage != "" {
+		placeholder, err := template.ParseFiles(s.cfg.PlaceholderPage)
+		if err != nil {
+			return fmt.Errorf("failed to load placeholder page: %w", err)
+		}
+		opts = append(opts, handlers.WithPlaceholder(placeholder))
+	}
+	r.Mount("/", handlers.NewHTTPHandlers(s.u, opts...).Routes())
 
 	// Публичный API
 	apiHandlers := handlers.NewAPIHandlers(s.u)
+	if s.p.OIDC != nil {
+		r.Mount("/api/auth/oidc/", handlers.NewOIDCHandlers(s.u, s.p.OIDC, s.cfg.BaseURL).Routes())
+	}
 	r.Mount("/api/", apiHandlers.PublicRoutes())
 
 	// Внутренний API
 	r.Group(func(r chi.Router) {
 		// Middleware для проверки IP-адреса
 		r.Use(s.p.IPCheck.Handler)
+		r.Handle("/api/internal/metrics", s.p.Metrics.ExportHandler())
 		r.Mount("/api/internal/", apiHandlers.InternalRoutes())
 	})
 
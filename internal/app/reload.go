@@ -0,0 +1,30 @@
+package app
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ReloadOnSignal - вызывает reload при получении любого из сигналов sig до завершения контекста ctx.
+// Ошибки перезагрузки записываются в лог.
+func ReloadOnSignal(ctx context.Context, reload func() error, sig ...os.Signal) {
+	go func() {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, sig...)
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s := <-ch:
+				log.Info().Msgf("Received signal: %s, reloading", s)
+				if err := reload(); err != nil {
+					log.Err(err).Msg("failed to reload")
+				}
+			}
+		}
+	}()
+}
@@ -16,6 +16,16 @@ import (
 //		-f <path>      - файл для хранения данных
 //		-t <cidr>      - подсеть, из которой разрешено обращение к внутреннему API
 //		-d <dsn>       - строка с адресом подключения к БД
+//		-i             - показывать страницу-предупреждение перед переходом по любой ссылке
+//		-p <path>      - файл HTML-шаблона страницы-заглушки для еще не активных ссылок
+//		-q             - сортировать query-параметры при приведении URL к каноническому виду
+//		-l <path>      - файл со списками разрешенных и запрещенных доменов для адресов назначения
+//		-e <exporter>  - экспортер трассировки: none, stdout, otlp или file:<path>
+//		-r <path>      - файл с дополнительными шаблонами User-Agent ботов
+//		-n             - отдавать ботам легкую страницу вместо перенаправления, не засчитывая переход
+//		-y <path>      - файл с набором ключей для подписи авторизационных токенов
+//		-u <provider>  - провайдер аутентификации: sha256 или jwt
+//		-k <path>      - файл с закрытым ключом для подписи JWT
 //
 // Если какие-либо значения не заданы в командной строке, то используются значения переданные в cfg.
 func FromCLI(args ...string) CfgFunc {
@@ -43,5 +53,15 @@ func flagSet(cfg *Config) *flag.FlagSet {
 	f.StringVar(&cfg.FileStoragePath, "f", cfg.FileStoragePath, "File storage path")
 	f.StringVar(&cfg.DatabaseDSN, "d", cfg.DatabaseDSN, "Database DSN")
 	f.StringVar(&cfg.TrustedSubnet, "t", cfg.TrustedSubnet, "Trusted IP subnet for internal API access")
+	f.BoolVar(&cfg.Interstitial, "i", cfg.Interstitial, "Show interstitial page before redirecting to any short URL")
+	f.BoolVar(&cfg.SortQueryParams, "q", cfg.SortQueryParams, "Sort query parameters when canonicalizing URLs")
+	f.StringVar(&cfg.PlaceholderPage, "p", cfg.PlaceholderPage, "HTML template file of placeholder page for not yet active short URLs")
+	f.StringVar(&cfg.PolicyFile, "l", cfg.PolicyFile, "Domain allow/deny lists file for destination URLs")
+	f.StringVar(&cfg.TraceExporter, "e", cfg.TraceExporter, "Trace exporter: none, stdout, otlp or file:<path>")
+	f.StringVar(&cfg.BotRulesFile, "r", cfg.BotRulesFile, "Additional bot User-Agent patterns file")
+	f.BoolVar(&cfg.BotPreview, "n", cfg.BotPreview, "Serve bots a lightweight page instead of redirect without counting a click")
+	f.StringVar(&cfg.AuthKeysFile, "y", cfg.AuthKeysFile, "Auth token signing keys file")
+	f.StringVar(&cfg.AuthProvider, "u", cfg.AuthProvider, "Auth provider: sha256 or jwt")
+	f.StringVar(&cfg.JWTKeyFile, "k", cfg.JWTKeyFile, "Private key PEM file for JWT signing")
 	return f
 }
@@ -8,6 +8,29 @@ import (
 	"time"
 
 	"golang.org/x/sync/errgroup"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+)
+
+// Экспортеры трассировки
+const (
+	TraceExporterNone       = "none"   // Трассировка не экспортируется
+	TraceExporterStdout     = "stdout" // Трассировка выводится в stdout
+	TraceExporterOTLP       = "otlp"   // Трассировка отправляется по протоколу OTLP/HTTP (настраивается переменными OTEL_EXPORTER_OTLP_*)
+	traceExporterFilePrefix = "file:"  // Трассировка записывается в файл: file:<path>
+)
+
+// Провайдеры аутентификации
+const (
+	AuthProviderSHA256 = "sha256" // Собственный формат токена с подписью HMAC-SHA256
+	AuthProviderJWT    = "jwt"    // Стандартные JWT (RFC 7519)
+)
+
+// Алгоритмы подписи JWT
+const (
+	JWTAlgorithmHS256 = "HS256" // HMAC-SHA256 с ключом AuthSecret
+	JWTAlgorithmRS256 = "RS256" // RSA-SHA256 с закрытым ключом из JWTKeyFile
+	JWTAlgorithmEdDSA = "EdDSA" // Ed25519 с закрытым ключом из JWTKeyFile
 )
 
 // Config - конфигурация приложения
@@ -30,9 +53,20 @@ type Config struct {
 	// AuthSecret - секретный ключ для подписи авторизационного токена
 	AuthSecret string `env:"AUTH_SECRET,unset"`
 
+	// VisitorSecret - секретный ключ для хеша посетителей в событиях переходов.
+	// Должен сохраняться между перезапусками вместе с данными, иначе посетители учитываются заново.
+	// Обязателен при хранении данных в файле или БД (см. ValidateVisitorSecret).
+	// Если не задан, то используется AuthSecret
+	VisitorSecret string `env:"VISITOR_SECRET,unset"`
+
 	// TrustedSubnet - подсеть, из которой разрешено обращение к внутреннему API
 	TrustedSubnet string `env:"TRUSTED_SUBNET"`
 
+	// TrustedProxies - адреса и подсети прокси-серверов, которым разрешено передавать
+	// IP-адрес и страну посетителя в заголовках X-Real-IP, CF-IPCountry и X-Country-Code.
+	// Если список пуст, заголовки игнорируются и используется адрес соединения.
+	TrustedProxies []string `env:"TRUSTED_PROXIES" envSeparator:","`
+
 	// configFName - имя файла конфигурации
 	configFName string
 
@@ -43,15 +77,102 @@ type Config struct {
 
 	// AuthTTL - время жизни авторизационного токена
 	AuthTTL time.Duration `env:"AUTH_TTL"`
+
+	// AuthRenewBefore - за сколько до окончания срока действия токен активного пользователя выдается заново.
+	// Не больше половины AuthTTL. Если 0, то токены не продлеваются
+	AuthRenewBefore time.Duration `env:"AUTH_RENEW_BEFORE"`
+
+	// AuthMaxAge - максимальная продолжительность сессии пользователя с учетом продлений токена.
+	// Если 0, то не ограничена
+	AuthMaxAge time.Duration `env:"AUTH_MAX_AGE"`
+
+	// Interstitial - перед переходом по любой короткой ссылке показывать страницу-предупреждение
+	Interstitial bool `env:"INTERSTITIAL"`
+
+	// PlaceholderPage - файл HTML-шаблона страницы-заглушки для ссылок, период действия которых еще не начался
+	PlaceholderPage string `env:"PLACEHOLDER_PAGE"`
+
+	// SortQueryParams - сортировать query-параметры при приведении URL к каноническому виду
+	SortQueryParams bool `env:"SORT_QUERY_PARAMS"`
+
+	// PolicyFile - JSON-файл со списками разрешенных и запрещенных доменов для адресов назначения
+	PolicyFile string `env:"POLICY_FILE"`
+
+	// TraceExporter - экспортер трассировки: none, stdout, otlp или file:<path>
+	TraceExporter string `env:"TRACE_EXPORTER"`
+
+	// BotRulesFile - JSON-файл с дополнительными шаблонами User-Agent ботов
+	BotRulesFile string `env:"BOT_RULES_FILE"`
+
+	// BotPreview - отдавать ботам легкую страницу со ссылкой вместо перенаправления, не засчитывая переход
+	BotPreview bool `env:"BOT_PREVIEW"`
+
+	// AuthKeysFile - JSON-файл с набором ключей для подписи авторизационных токенов.
+	// Если не задан, то токены подписываются ключом AuthSecret
+	AuthKeysFile string `env:"AUTH_KEYS_FILE"`
+
+	// AuthProvider - провайдер аутентификации: sha256 или jwt
+	AuthProvider string `env:"AUTH_PROVIDER"`
+
+	// JWTAlgorithm - алгоритм подписи JWT: HS256, RS256 или EdDSA
+	JWTAlgorithm string `env:"JWT_ALGORITHM"`
+
+	// JWTKeyFile - PEM-файл с закрытым ключом для подписи JWT алгоритмами RS256 и EdDSA
+	JWTKeyFile string `env:"JWT_KEY_FILE"`
+
+	// JWTIssuer - издатель JWT (iss). Если задан, то токены других издателей не принимаются
+	JWTIssuer string `env:"JWT_ISSUER"`
+
+	// JWTAudience - получатель JWT (aud). Если задан, то токены для других получателей не принимаются
+	JWTAudience string `env:"JWT_AUDIENCE"`
+
+	// JWTLeeway - допустимое расхождение часов при проверке времени действия JWT
+	JWTLeeway time.Duration `env:"JWT_LEEWAY"`
+
+	// OIDCIssuer - адрес провайдера OpenID Connect (издатель).
+	// Если задан, то включается вход пользователей через провайдера
+	OIDCIssuer string `env:"OIDC_ISSUER"`
+
+	// OIDCClientID - идентификатор клиента, зарегистрированного у провайдера OpenID Connect
+	OIDCClientID string `env:"OIDC_CLIENT_ID"`
+
+	// OIDCClientSecret - секрет клиента, зарегистрированного у провайдера OpenID Connect
+	OIDCClientSecret string `env:"OIDC_CLIENT_SECRET,unset"`
+
+	// OIDCRedirectURL - адрес возврата от провайдера OpenID Connect.
+	// Если не задан, то используется <BaseURL>api/auth/oidc/callback
+	OIDCRedirectURL string `env:"OIDC_REDIRECT_URL"`
+
+	// QuotaMaxActiveURLs - максимальное количество неудаленных ссылок пользователя по умолчанию.
+	// Если 0, то не ограничено
+	QuotaMaxActiveURLs int `env:"QUOTA_MAX_ACTIVE_URLS"`
+
+	// QuotaURLsPerDay - максимальное количество ссылок, создаваемых пользователем за сутки, по умолчанию.
+	// Если 0, то не ограничено
+	QuotaURLsPerDay int `env:"QUOTA_URLS_PER_DAY"`
+
+	// QuotaMaxBatchSize - максимальное количество ссылок в одном пакетном запросе по умолчанию.
+	// Если 0, то не ограничено
+	QuotaMaxBatchSize int `env:"QUOTA_MAX_BATCH_SIZE"`
+
+	// QuotaPlans - квоты тарифных планов по их названиям. Задаются только в JSON-файле конфигурации.
+	// Пользователям без плана или с неизвестным планом назначаются квоты по умолчанию
+	QuotaPlans map[string]models.Quota
 }
 
 // validate - проверяет конфигурацию на валидность
 func (c *Config) validate() error {
 	g := &errgroup.Group{}
 	g.Go(c.validateAuthSecret)
+	g.Go(c.validateAuthSession)
+	g.Go(c.validateAuthProvider)
+	g.Go(c.validateOIDC)
 	g.Go(c.validateBaseURL)
 	g.Go(c.validateServerAddr)
 	g.Go(c.Cert.validate)
+	g.Go(c.validateTraceExporter)
+	g.Go(c.validateQuota)
+	g.Go(c.validateTrustedProxies)
 	return g.Wait()
 }
 
@@ -99,3 +220,139 @@ func (c *Config) validateAuthSecret() error {
 	}
 	return nil
 }
+
+// ValidateVisitorSecret - проверяет, что ключ хеша посетителей задан, если данные хранятся в файле или БД.
+// По умолчанию AuthSecret генерируется случайно при каждом запуске, поэтому не подходит для хешей,
+// которые сохраняются между перезапусками.
+// Вызывается при запуске приложения, после чтения конфигурации из всех источников.
+func (c *Config) ValidateVisitorSecret() error {
+	if c.VisitorSecret == "" && (c.FileStoragePath != "" || c.DatabaseDSN != "") {
+		return fmt.Errorf("visitor secret must be set when data is stored in a file or database")
+	}
+	return nil
+}
+
+// validateAuthSession - проверяет время продления токена и максимальную продолжительность сессии.
+func (c *Config) validateAuthSession() error {
+	if c.AuthRenewBefore < 0 {
+		return fmt.Errorf("invalid auth renew before: %v", c.AuthRenewBefore)
+	}
+	if c.AuthMaxAge < 0 {
+		return fmt.Errorf("invalid auth max age: %v", c.AuthMaxAge)
+	}
+	return nil
+}
+
+// validateAuthProvider - проверяет провайдер аутентификации и параметры JWT.
+// Пустое значение провайдера означает sha256.
+func (c *Config) validateAuthProvider() error {
+	switch c.AuthProvider {
+	case "", AuthProviderSHA256:
+		return nil
+	case AuthProviderJWT:
+	default:
+		return fmt.Errorf("invalid auth provider: %s", c.AuthProvider)
+	}
+	switch c.JWTAlgorithm {
+	case "", JWTAlgorithmHS256:
+	case JWTAlgorithmRS256, JWTAlgorithmEdDSA:
+		if c.JWTKeyFile == "" {
+			return fmt.Errorf("JWT key file not set for %s", c.JWTAlgorithm)
+		}
+	default:
+		return fmt.Errorf("invalid JWT algorithm: %s", c.JWTAlgorithm)
+	}
+	if c.JWTLeeway < 0 {
+		return fmt.Errorf("invalid JWT leeway: %v", c.JWTLeeway)
+	}
+	return nil
+}
+
+// validateOIDC - проверяет параметры OpenID Connect, если задан провайдер.
+// Адреса провайдера и возврата должны быть абсолютными http(s)-адресами.
+func (c *Config) validateOIDC() error {
+	if c.OIDCIssuer == "" {
+		return nil
+	}
+	if u, err := url.Parse(c.OIDCIssuer); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid OIDC issuer: %s", c.OIDCIssuer)
+	}
+	if c.OIDCClientID == "" {
+		return fmt.Errorf("OIDC client id not set")
+	}
+	if c.OIDCRedirectURL == "" {
+		return nil
+	}
+	if u, err := url.Parse(c.OIDCRedirectURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid OIDC redirect URL: %s", c.OIDCRedirectURL)
+	}
+	return nil
+}
+
+// validateTraceExporter - проверяет экспортер трассировки.
+// Допустимые значения: пустая строка, none, stdout, otlp или file:<path>.
+func (c *Config) validateTraceExporter() error {
+	switch c.TraceExporter {
+	case "", TraceExporterNone, TraceExporterStdout, TraceExporterOTLP:
+		return nil
+	}
+	if path, ok := c.TraceFile(); ok && path != "" {
+		return nil
+	}
+	return fmt.Errorf("invalid trace exporter: %s", c.TraceExporter)
+}
+
+// validateTrustedProxies - проверяет, что адреса прокси-серверов заданы в формате IP или CIDR.
+func (c *Config) validateTrustedProxies() error {
+	for _, addr := range c.TrustedProxies {
+		if net.ParseIP(addr) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(addr); err != nil {
+			return fmt.Errorf("invalid trusted proxy: %s", addr)
+		}
+	}
+	return nil
+}
+
+// validateQuota - проверяет квоты по умолчанию и квоты тарифных планов.
+// Квоты не могут быть отрицательными, названия планов не могут быть пустыми.
+func (c *Config) validateQuota() error {
+	if err := validateQuotaValues("default", c.DefaultQuota()); err != nil {
+		return err
+	}
+	for name, q := range c.QuotaPlans {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("empty quota plan name")
+		}
+		if err := validateQuotaValues(name, q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateQuotaValues - проверяет, что квоты плана не отрицательные
+func validateQuotaValues(plan string, q models.Quota) error {
+	if q.MaxActiveURLs < 0 || q.URLsPerDay < 0 || q.MaxBatchSize < 0 {
+		return fmt.Errorf("invalid %s quota: %+v", plan, q)
+	}
+	return nil
+}
+
+// DefaultQuota - возвращает квоты по умолчанию
+func (c *Config) DefaultQuota() models.Quota {
+	return models.Quota{
+		MaxActiveURLs: c.QuotaMaxActiveURLs,
+		URLsPerDay:    c.QuotaURLsPerDay,
+		MaxBatchSize:  c.QuotaMaxBatchSize,
+	}
+}
+
+// TraceFile - возвращает путь к файлу, если экспортер трассировки задан в виде file:<path>
+func (c *Config) TraceFile() (string, bool) {
+	if !strings.HasPrefix(c.TraceExporter, traceExporterFilePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(c.TraceExporter, traceExporterFilePrefix), true
+}
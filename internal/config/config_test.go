@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/suite"
+
+	"github.com/ofstudio/go-shortener/internal/models"
 )
 
 type configSuite struct {
@@ -25,6 +27,7 @@ func (suite *configSuite) TestNewFromEnv_all() {
 		"BASE_URL":          "https://example.com/",
 		"SERVER_ADDRESS":    "localhost:8888",
 		"FILE_STORAGE_PATH": "/tmp/shortener.aof",
+		"INTERSTITIAL":      "true",
 	})
 
 	actualCfg, err := FromEnv(suite.defaultCfg())
@@ -36,6 +39,7 @@ func (suite *configSuite) TestNewFromEnv_all() {
 	suite.Equal("https://example.com/", actualCfg.BaseURL.String())
 	suite.Equal("localhost:8888", actualCfg.HTTPServerAddress)
 	suite.Equal("/tmp/shortener.aof", actualCfg.FileStoragePath)
+	suite.True(actualCfg.Interstitial)
 }
 
 func (suite *configSuite) TestNewFromEnv_partial() {
@@ -67,6 +71,7 @@ func (suite *configSuite) TestNewFromEnvAndCLI_all() {
 		"-b", "https://example.com/",
 		"-f", "/tmp/shortener.aof",
 		"-t", "192.168.0.0/16",
+		"-i",
 	}
 
 	defaultCfg := suite.defaultCfg()
@@ -78,6 +83,7 @@ func (suite *configSuite) TestNewFromEnvAndCLI_all() {
 	suite.Equal("https://example.com/", actualCfg.BaseURL.String())
 	suite.Equal("/tmp/shortener.aof", actualCfg.FileStoragePath)
 	suite.Equal("192.168.0.0/16", actualCfg.TrustedSubnet)
+	suite.True(actualCfg.Interstitial)
 
 	// Проверяем, что остальные параметры установлены в значения по умолчанию
 	suite.Equal(defaultCfg.AuthSecret, actualCfg.AuthSecret)
@@ -164,6 +170,161 @@ func (suite *configSuite) TestValidateServerAddress() {
 	suite.Error(err)
 }
 
+func (suite *configSuite) TestValidateTraceExporter() {
+	for _, exporter := range []string{"none", "stdout", "otlp", "file:/tmp/trace.json"} {
+		suite.setenv(map[string]string{"TRACE_EXPORTER": exporter})
+		actualCfg, err := FromEnv(suite.defaultCfg())
+		suite.NoError(err)
+		suite.Equal(exporter, actualCfg.TraceExporter)
+	}
+	path, ok := (&Config{TraceExporter: "file:/tmp/trace.json"}).TraceFile()
+	suite.True(ok)
+	suite.Equal("/tmp/trace.json", path)
+
+	// Проверяем на неизвестный экспортер и пустой путь к файлу
+	_, err := FromCLI("-e", "jaeger")(suite.defaultCfg())
+	suite.Error(err)
+	_, err = FromCLI("-e", "file:")(suite.defaultCfg())
+	suite.Error(err)
+}
+
+func (suite *configSuite) TestValidateAuthProvider() {
+	suite.setenv(map[string]string{
+		"AUTH_PROVIDER":  "jwt",
+		"AUTH_KEYS_FILE": "/path/to/auth-keys.json",
+		"JWT_ALGORITHM":  "EdDSA",
+		"JWT_KEY_FILE":   "/path/to/jwt.pem",
+		"JWT_ISSUER":     "https://short.example.com",
+		"JWT_AUDIENCE":   "shortener",
+		"JWT_LEEWAY":     "1m",
+	})
+	actualCfg, err := FromEnv(suite.defaultCfg())
+	suite.Require().NoError(err)
+	suite.Equal(AuthProviderJWT, actualCfg.AuthProvider)
+	suite.Equal("/path/to/auth-keys.json", actualCfg.AuthKeysFile)
+	suite.Equal(JWTAlgorithmEdDSA, actualCfg.JWTAlgorithm)
+	suite.Equal("/path/to/jwt.pem", actualCfg.JWTKeyFile)
+	suite.Equal("https://short.example.com", actualCfg.JWTIssuer)
+	suite.Equal("shortener", actualCfg.JWTAudience)
+	suite.Equal(time.Minute, actualCfg.JWTLeeway)
+
+	// Проверяем неизвестные провайдер и алгоритм, а также отсутствие файла ключа
+	_, err = FromCLI("-u", "oauth")(suite.defaultCfg())
+	suite.Error(err)
+	cfg := suite.defaultCfg()
+	cfg.AuthProvider, cfg.JWTAlgorithm = AuthProviderJWT, "ES256"
+	suite.Error(cfg.validate())
+	cfg.JWTAlgorithm = JWTAlgorithmRS256
+	suite.Error(cfg.validate())
+	cfg.JWTKeyFile = "/path/to/jwt.pem"
+	suite.NoError(cfg.validate())
+}
+
+func (suite *configSuite) TestValidateAuthSession() {
+	suite.setenv(map[string]string{
+		"AUTH_RENEW_BEFORE": "24h",
+		"AUTH_MAX_AGE":      "720h",
+	})
+	actualCfg, err := FromEnv(suite.defaultCfg())
+	suite.Require().NoError(err)
+	suite.Equal(24*time.Hour, actualCfg.AuthRenewBefore)
+	suite.Equal(720*time.Hour, actualCfg.AuthMaxAge)
+
+	// Проверяем отрицательные значения
+	cfg := suite.defaultCfg()
+	cfg.AuthRenewBefore = -time.Hour
+	suite.Error(cfg.validate())
+	cfg.AuthRenewBefore, cfg.AuthMaxAge = 0, -time.Hour
+	suite.Error(cfg.validate())
+	cfg.AuthMaxAge = 0
+	suite.NoError(cfg.validate())
+}
+
+func (suite *configSuite) TestValidateQuota() {
+	suite.setenv(map[string]string{
+		"QUOTA_MAX_ACTIVE_URLS": "1000",
+		"QUOTA_URLS_PER_DAY":    "100",
+		"QUOTA_MAX_BATCH_SIZE":  "50",
+	})
+	actualCfg, err := FromEnv(suite.defaultCfg())
+	suite.Require().NoError(err)
+	suite.Equal(models.Quota{MaxActiveURLs: 1000, URLsPerDay: 100, MaxBatchSize: 50}, actualCfg.DefaultQuota())
+
+	// Планы задаются в JSON-файле, нулевое значение снимает ограничение по умолчанию
+	os.Clearenv()
+	actualCfg, err = FromJSONFile("-c", "testdata/cfg-quota.json")(suite.defaultCfg())
+	suite.Require().NoError(err)
+	suite.Zero(actualCfg.QuotaMaxBatchSize)
+	suite.Equal(models.Quota{MaxActiveURLs: 100000, URLsPerDay: 10000, MaxBatchSize: 1000}, actualCfg.QuotaPlans["pro"])
+
+	// Проверяем отрицательные значения и пустое название плана
+	cfg := suite.defaultCfg()
+	cfg.QuotaURLsPerDay = -1
+	suite.Error(cfg.validate())
+	cfg.QuotaURLsPerDay = 0
+	cfg.QuotaPlans = map[string]models.Quota{"pro": {MaxActiveURLs: -1}}
+	suite.Error(cfg.validate())
+	cfg.QuotaPlans = map[string]models.Quota{" ": {}}
+	suite.Error(cfg.validate())
+	cfg.QuotaPlans = map[string]models.Quota{"pro": {MaxActiveURLs: 10}}
+	suite.NoError(cfg.validate())
+}
+
+func (suite *configSuite) TestValidateTrustedProxies() {
+	suite.setenv(map[string]string{"TRUSTED_PROXIES": "10.0.0.0/8,127.0.0.1,::1"})
+	actualCfg, err := FromEnv(suite.defaultCfg())
+	suite.Require().NoError(err)
+	suite.Equal([]string{"10.0.0.0/8", "127.0.0.1", "::1"}, actualCfg.TrustedProxies)
+
+	os.Clearenv()
+	suite.setenv(map[string]string{"TRUSTED_PROXIES": "10.0.0.0/8,localhost"})
+	_, err = FromEnv(suite.defaultCfg())
+	suite.Error(err)
+}
+
+func (suite *configSuite) TestValidateOIDC() {
+	suite.setenv(map[string]string{
+		"OIDC_ISSUER":        "https://idp.example.com",
+		"OIDC_CLIENT_ID":     "shortener",
+		"OIDC_CLIENT_SECRET": "secret",
+		"OIDC_REDIRECT_URL":  "https://short.example.com/api/auth/oidc/callback",
+	})
+	actualCfg, err := FromEnv(suite.defaultCfg())
+	suite.Require().NoError(err)
+	suite.Equal("https://idp.example.com", actualCfg.OIDCIssuer)
+	suite.Equal("shortener", actualCfg.OIDCClientID)
+	suite.Equal("secret", actualCfg.OIDCClientSecret)
+	suite.Equal("https://short.example.com/api/auth/oidc/callback", actualCfg.OIDCRedirectURL)
+
+	// Проверяем адреса провайдера и возврата, а также отсутствие идентификатора клиента
+	cfg := suite.defaultCfg()
+	cfg.OIDCIssuer = "idp.example.com"
+	suite.Error(cfg.validate())
+	cfg.OIDCIssuer = "https://idp.example.com"
+	suite.Error(cfg.validate())
+	cfg.OIDCClientID = "shortener"
+	suite.NoError(cfg.validate())
+	cfg.OIDCRedirectURL = "/callback"
+	suite.Error(cfg.validate())
+}
+
+func (suite *configSuite) TestValidateVisitorSecret() {
+	suite.setenv(map[string]string{"VISITOR_SECRET": "visitor secret"})
+	actualCfg, err := FromEnv(suite.defaultCfg())
+	suite.Require().NoError(err)
+	suite.Equal("visitor secret", actualCfg.VisitorSecret)
+
+	// Без ключа данные можно хранить только в памяти
+	cfg := suite.defaultCfg()
+	suite.NoError(cfg.ValidateVisitorSecret())
+	cfg.FileStoragePath = "/path/to/file.db"
+	suite.Error(cfg.ValidateVisitorSecret())
+	cfg.FileStoragePath, cfg.DatabaseDSN = "", "postgres://localhost/shortener"
+	suite.Error(cfg.ValidateVisitorSecret())
+	cfg.VisitorSecret = "visitor secret"
+	suite.NoError(cfg.ValidateVisitorSecret())
+}
+
 func (suite *configSuite) TestFromJSONFile() {
 	suite.Run("valid from cli", func() {
 		cfg, err := FromJSONFile("-c", "testdata/cfg-valid.json")(suite.defaultCfg())
@@ -184,8 +345,11 @@ func (suite *configSuite) TestFromJSONFile() {
 		suite.Equal("http://localhost/", cfg.BaseURL.String())
 		suite.Equal("/path/to/file.db", cfg.FileStoragePath)
 		suite.Equal("", cfg.DatabaseDSN)
+		suite.Equal("visitor secret", cfg.VisitorSecret)
 		suite.Equal(true, cfg.EnableHTTPS)
 		suite.Equal("192.168.0.0/16", cfg.TrustedSubnet)
+		suite.Equal([]string{"10.0.0.0/8", "127.0.0.1"}, cfg.TrustedProxies)
+		suite.True(cfg.Interstitial)
 	})
 	suite.Run("mistype", func() {
 		os.Setenv("CONFIG", "testdata/cfg-mistype.json")
@@ -19,8 +19,14 @@ func Default(_ *Config) (*Config, error) {
 		EnableHTTPS:       false,
 		Cert:              defaultCert,
 		AuthTTL:           time.Minute * 60 * 24 * 30,
+		AuthRenewBefore:   time.Minute * 60 * 24 * 7,
+		AuthMaxAge:        time.Minute * 60 * 24 * 90,
 		AuthSecret:        secret,
+		AuthProvider:      AuthProviderSHA256,
+		JWTAlgorithm:      JWTAlgorithmHS256,
+		JWTLeeway:         time.Second * 30,
 		DatabaseDSN:       "",
+		QuotaMaxBatchSize: 1000,
 	}
 	if err = cfg.validate(); err != nil {
 		return nil, err
@@ -12,8 +12,33 @@ import "github.com/caarlos0/env/v6"
 //	USE_TLS             - использовать Cert с самоподписанным сертификатом
 //	FILE_STORAGE_PATH   - файл для хранения данных
 //	AUTH_TTL            - время жизни авторизационного токена
+//	AUTH_RENEW_BEFORE   - за сколько до окончания срока действия токен выдается заново
+//	AUTH_MAX_AGE        - максимальная продолжительность сессии пользователя
 //	AUTH_SECRET         - секретный ключ для подписи авторизационного токена
+//	VISITOR_SECRET      - секретный ключ для хеша посетителей в событиях переходов
 //	TRUSTED_SUBNET     - подсеть, из которой разрешено обращение к внутреннему API
+//	TRUSTED_PROXIES     - адреса и подсети прокси-серверов через запятую, которым доверяются заголовки X-Real-IP и страны
+//	INTERSTITIAL        - показывать страницу-предупреждение перед переходом по любой ссылке
+//	PLACEHOLDER_PAGE    - файл HTML-шаблона страницы-заглушки для еще не активных ссылок
+//	SORT_QUERY_PARAMS   - сортировать query-параметры при приведении URL к каноническому виду
+//	POLICY_FILE         - файл со списками разрешенных и запрещенных доменов для адресов назначения
+//	TRACE_EXPORTER      - экспортер трассировки: none, stdout, otlp или file:<path>
+//	BOT_RULES_FILE      - файл с дополнительными шаблонами User-Agent ботов
+//	BOT_PREVIEW         - отдавать ботам легкую страницу вместо перенаправления, не засчитывая переход
+//	AUTH_KEYS_FILE      - файл с набором ключей для подписи авторизационных токенов
+//	AUTH_PROVIDER       - провайдер аутентификации: sha256 или jwt
+//	JWT_ALGORITHM       - алгоритм подписи JWT: HS256, RS256 или EdDSA
+//	JWT_KEY_FILE        - файл с закрытым ключом для подписи JWT алгоритмами RS256 и EdDSA
+//	JWT_ISSUER          - издатель JWT (iss)
+//	JWT_AUDIENCE        - получатель JWT (aud)
+//	JWT_LEEWAY          - допустимое расхождение часов при проверке времени действия JWT
+//	OIDC_ISSUER         - адрес провайдера OpenID Connect
+//	OIDC_CLIENT_ID      - идентификатор клиента у провайдера OpenID Connect
+//	OIDC_CLIENT_SECRET  - секрет клиента у провайдера OpenID Connect
+//	OIDC_REDIRECT_URL   - адрес возврата от провайдера OpenID Connect
+//	QUOTA_MAX_ACTIVE_URLS - максимальное количество неудаленных ссылок пользователя
+//	QUOTA_URLS_PER_DAY    - максимальное количество ссылок, создаваемых пользователем за сутки
+//	QUOTA_MAX_BATCH_SIZE  - максимальное количество ссылок в одном пакетном запросе
 //
 // Если какие-либо переменные окружения не заданы, то используются значения переданные в cfg.
 func FromEnv(cfg *Config) (*Config, error) {
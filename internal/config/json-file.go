@@ -4,17 +4,46 @@ import (
 	"encoding/json"
 	"net/url"
 	"os"
+	"time"
+
+	"github.com/ofstudio/go-shortener/internal/models"
 )
 
 // jsonDTO - структура для считывания конфигурации из JSON-файла.
 type jsonDTO struct {
-	HTTPServerAddress string `json:"server_address"`
-	GRPCServerAddress string `json:"grpc_server_address"`
-	BaseURL           string `json:"base_url"`
-	FileStoragePath   string `json:"file_storage_path"`
-	DatabaseDSN       string `json:"database_dsn"`
-	TrustedSubnet     string `json:"trusted_subnet"`
-	EnableHTTPS       bool   `json:"enable_https"`
+	HTTPServerAddress  string                  `json:"server_address"`
+	GRPCServerAddress  string                  `json:"grpc_server_address"`
+	BaseURL            string                  `json:"base_url"`
+	FileStoragePath    string                  `json:"file_storage_path"`
+	DatabaseDSN        string                  `json:"database_dsn"`
+	VisitorSecret      string                  `json:"visitor_secret"`
+	TrustedSubnet      string                  `json:"trusted_subnet"`
+	TrustedProxies     []string                `json:"trusted_proxies"`
+	EnableHTTPS        bool                    `json:"enable_https"`
+	Interstitial       bool                    `json:"interstitial"`
+	PlaceholderPage    string                  `json:"placeholder_page"`
+	SortQueryParams    bool                    `json:"sort_query_params"`
+	PolicyFile         string                  `json:"policy_file"`
+	TraceExporter      string                  `json:"trace_exporter"`
+	BotRulesFile       string                  `json:"bot_rules_file"`
+	BotPreview         bool                    `json:"bot_preview"`
+	AuthRenewBefore    string                  `json:"auth_renew_before"`
+	AuthMaxAge         string                  `json:"auth_max_age"`
+	AuthKeysFile       string                  `json:"auth_keys_file"`
+	AuthProvider       string                  `json:"auth_provider"`
+	JWTAlgorithm       string                  `json:"jwt_algorithm"`
+	JWTKeyFile         string                  `json:"jwt_key_file"`
+	JWTIssuer          string                  `json:"jwt_issuer"`
+	JWTAudience        string                  `json:"jwt_audience"`
+	JWTLeeway          string                  `json:"jwt_leeway"`
+	OIDCIssuer         string                  `json:"oidc_issuer"`
+	OIDCClientID       string                  `json:"oidc_client_id"`
+	OIDCClientSecret   string                  `json:"oidc_client_secret"`
+	OIDCRedirectURL    string                  `json:"oidc_redirect_url"`
+	QuotaMaxActiveURLs *int                    `json:"quota_max_active_urls"`
+	QuotaURLsPerDay    *int                    `json:"quota_urls_per_day"`
+	QuotaMaxBatchSize  *int                    `json:"quota_max_batch_size"`
+	QuotaPlans         map[string]models.Quota `json:"quota_plans"`
 }
 
 // FromJSONFile - конфигурационная функция, которая считывает конфигурацию приложения из JSON-файла.
@@ -27,9 +56,40 @@ type jsonDTO struct {
 //		"base_url": "http://localhost",
 //		"file_storage_path": "/path/to/file.db",
 //		"database_dsn": "",
-//		"enable_https": true
+//		"visitor_secret": "<secret>",
+//		"enable_https": true,
+//		"trusted_proxies": ["10.0.0.0/8", "127.0.0.1"],
+//		"interstitial": false,
+//		"placeholder_page": "/path/to/placeholder.html",
+//		"sort_query_params": false,
+//		"policy_file": "/path/to/policy.json",
+//		"trace_exporter": "stdout",
+//		"bot_rules_file": "/path/to/bots.json",
+//		"bot_preview": false,
+//		"auth_renew_before": "168h",
+//		"auth_max_age": "2160h",
+//		"auth_keys_file": "/path/to/auth-keys.json",
+//		"auth_provider": "jwt",
+//		"jwt_algorithm": "EdDSA",
+//		"jwt_key_file": "/path/to/jwt.pem",
+//		"jwt_issuer": "https://short.example.com",
+//		"jwt_audience": "shortener",
+//		"jwt_leeway": "30s",
+//		"oidc_issuer": "https://idp.example.com",
+//		"oidc_client_id": "shortener",
+//		"oidc_client_secret": "<secret>",
+//		"oidc_redirect_url": "https://short.example.com/api/auth/oidc/callback",
+//		"quota_max_active_urls": 1000,
+//		"quota_urls_per_day": 100,
+//		"quota_max_batch_size": 100,
+//		"quota_plans": {
+//			"pro": {"max_active_urls": 100000, "urls_per_day": 10000, "max_batch_size": 1000},
+//			"unlimited": {"max_active_urls": 0, "urls_per_day": 0, "max_batch_size": 0}
+//		}
 //	}
 //
+// Значение 0 в квотах означает отсутствие ограничения.
+//
 // Имя файла конфигурации можно задать (в порядке приоритета):
 //  1. через переменную окружения CONFIG
 //  2. через флаг -c командной строки
@@ -83,12 +143,102 @@ func FromJSONFile(args ...string) CfgFunc {
 			if dto.DatabaseDSN != "" {
 				cfg.DatabaseDSN = dto.DatabaseDSN
 			}
+			if dto.VisitorSecret != "" {
+				cfg.VisitorSecret = dto.VisitorSecret
+			}
 			if dto.EnableHTTPS {
 				cfg.EnableHTTPS = dto.EnableHTTPS
 			}
 			if dto.TrustedSubnet != "" {
 				cfg.TrustedSubnet = dto.TrustedSubnet
 			}
+			if len(dto.TrustedProxies) > 0 {
+				cfg.TrustedProxies = dto.TrustedProxies
+			}
+			if dto.Interstitial {
+				cfg.Interstitial = dto.Interstitial
+			}
+			if dto.PlaceholderPage != "" {
+				cfg.PlaceholderPage = dto.PlaceholderPage
+			}
+			if dto.SortQueryParams {
+				cfg.SortQueryParams = dto.SortQueryParams
+			}
+			if dto.PolicyFile != "" {
+				cfg.PolicyFile = dto.PolicyFile
+			}
+			if dto.TraceExporter != "" {
+				cfg.TraceExporter = dto.TraceExporter
+			}
+			if dto.BotRulesFile != "" {
+				cfg.BotRulesFile = dto.BotRulesFile
+			}
+			if dto.BotPreview {
+				cfg.BotPreview = dto.BotPreview
+			}
+			if dto.AuthRenewBefore != "" {
+				renewBefore, err := time.ParseDuration(dto.AuthRenewBefore)
+				if err != nil {
+					return nil, err
+				}
+				cfg.AuthRenewBefore = renewBefore
+			}
+			if dto.AuthMaxAge != "" {
+				maxAge, err := time.ParseDuration(dto.AuthMaxAge)
+				if err != nil {
+					return nil, err
+				}
+				cfg.AuthMaxAge = maxAge
+			}
+			if dto.AuthKeysFile != "" {
+				cfg.AuthKeysFile = dto.AuthKeysFile
+			}
+			if dto.AuthProvider != "" {
+				cfg.AuthProvider = dto.AuthProvider
+			}
+			if dto.JWTAlgorithm != "" {
+				cfg.JWTAlgorithm = dto.JWTAlgorithm
+			}
+			if dto.JWTKeyFile != "" {
+				cfg.JWTKeyFile = dto.JWTKeyFile
+			}
+			if dto.JWTIssuer != "" {
+				cfg.JWTIssuer = dto.JWTIssuer
+			}
+			if dto.JWTAudience != "" {
+				cfg.JWTAudience = dto.JWTAudience
+			}
+			if dto.JWTLeeway != "" {
+				leeway, err := time.ParseDuration(dto.JWTLeeway)
+				if err != nil {
+					return nil, err
+				}
+				cfg.JWTLeeway = leeway
+			}
+			if dto.OIDCIssuer != "" {
+				cfg.OIDCIssuer = dto.OIDCIssuer
+			}
+			if dto.OIDCClientID != "" {
+				cfg.OIDCClientID = dto.OIDCClientID
+			}
+			if dto.OIDCClientSecret != "" {
+				cfg.OIDCClientSecret = dto.OIDCClientSecret
+			}
+			if dto.OIDCRedirectURL != "" {
+				cfg.OIDCRedirectURL = dto.OIDCRedirectURL
+			}
+			if dto.QuotaMaxActiveURLs != nil {
+				cfg.QuotaMaxActiveURLs = *dto.QuotaMaxActiveURLs
+			}
+			if dto.QuotaURLsPerDay != nil {
+				cfg.QuotaURLsPerDay = *dto.QuotaURLsPerDay
+			}
+			if dto.QuotaMaxBatchSize != nil {
+				cfg.QuotaMaxBatchSize = *dto.QuotaMaxBatchSize
+			}
+			if dto.QuotaPlans != nil {
+				cfg.QuotaPlans = dto.QuotaPlans
+			}
 
 			// Проверяем конфигурацию.
 			if err := cfg.validate(); err != nil {
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ofstudio/go-shortener/api/proto"
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/providers/auth"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+// AccountService - реализация gRPC сервиса регистрации и входа пользователей.
+// Токен пользователя передается клиенту в метаданных ответа auth_token.
+type AccountService struct {
+	proto.UnimplementedAccountServer
+	u *usecases.Container
+}
+
+// NewAccountService - конструктор AccountService.
+func NewAccountService(u *usecases.Container) *AccountService {
+	return &AccountService{u: u}
+}
+
+// Register - регистрация пользователя.
+// Если вызов выполнен с токеном анонимного пользователя, то регистрируется он сам.
+func (s AccountService) Register(ctx context.Context, request *proto.AccountCredentials) (*proto.AccountResponse, error) {
+	claimID, _ := auth.FromContext(ctx)
+	user, err := s.u.User.Register(ctx, claimID, request.Email, request.Password)
+	if err != nil {
+		return nil, Error(err)
+	}
+	return s.respondWithAccount(ctx, user)
+}
+
+// Login - вход пользователя по email и паролю.
+func (s AccountService) Login(ctx context.Context, request *proto.AccountCredentials) (*proto.AccountResponse, error) {
+	user, err := s.u.User.Login(ctx, request.Email, request.Password)
+	if err != nil {
+		return nil, Error(err)
+	}
+	return s.respondWithAccount(ctx, user)
+}
+
+// Logout - выход пользователя. Клиент удаляет токен самостоятельно.
+// Если задан all, то все токены пользователя отзываются.
+func (s AccountService) Logout(ctx context.Context, request *proto.AccountLogoutRequest) (*proto.AccountLogoutResponse, error) {
+	if request.All {
+		userID, ok := auth.FromContext(ctx)
+		if !ok {
+			return nil, Error(pkgerrors.ErrAuth)
+		}
+		if _, err := s.u.User.RevokeTokens(ctx, userID); err != nil {
+			return nil, Error(err)
+		}
+	}
+	return &proto.AccountLogoutResponse{}, nil
+}
+
+// respondWithAccount - выдает токен пользователя и возвращает ответ с пользователем
+func (s AccountService) respondWithAccount(ctx context.Context, user *models.User) (*proto.AccountResponse, error) {
+	if err := auth.LoginGRPC(ctx, user.ID); err != nil {
+		log.Err(err).Msg("failed to issue auth token")
+		return nil, Error(pkgerrors.ErrInternal)
+	}
+	return &proto.AccountResponse{Id: uint32(user.ID), Email: user.Email}, nil
+}
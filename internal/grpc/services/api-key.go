@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ofstudio/go-shortener/api/proto"
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/providers/auth"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+// APIKeyMethods - требования методов сервиса APIKeys к аутентификации.
+// Методы сервиса не указаны в ShortURLScopes, поэтому недоступны по API-ключу.
+var APIKeyMethods = auth.Methods{
+	"/proto.APIKeys/Create": auth.Required,
+	"/proto.APIKeys/List":   auth.Required,
+	"/proto.APIKeys/Revoke": auth.Required,
+}
+
+// APIKeyService - реализация gRPC сервиса для управления API-ключами пользователя.
+type APIKeyService struct {
+	proto.UnimplementedAPIKeysServer
+	u *usecases.Container
+}
+
+// NewAPIKeyService - конструктор APIKeyService.
+func NewAPIKeyService(u *usecases.Container) *APIKeyService {
+	return &APIKeyService{u: u}
+}
+
+// Create - создание API-ключа. Сам ключ возвращается только в ответе на этот вызов.
+func (s APIKeyService) Create(ctx context.Context, request *proto.APIKeyCreateRequest) (*proto.APIKey, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+
+	// Создаем ключ
+	scopes := make([]models.APIScope, 0, len(request.Scopes))
+	for _, scope := range request.Scopes {
+		scopes = append(scopes, models.APIScope(scope))
+	}
+	key, plain, err := s.u.APIKey.Create(ctx, userID, request.Name, scopes)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	// Возвращаем результат
+	res := apiKeyToProto(key)
+	res.Key = plain
+	return res, nil
+}
+
+// List - получение API-ключей пользователя, в тч отозванных.
+func (s APIKeyService) List(ctx context.Context, _ *proto.APIKeyListRequest) (*proto.APIKeyListResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+
+	// Получаем ключи
+	keys, err := s.u.APIKey.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	// Возвращаем результат
+	res := &proto.APIKeyListResponse{Keys: make([]*proto.APIKey, 0, len(keys))}
+	for i := range keys {
+		res.Keys = append(res.Keys, apiKeyToProto(&keys[i]))
+	}
+	return res, nil
+}
+
+// Revoke - отзыв API-ключа пользователя.
+func (s APIKeyService) Revoke(ctx context.Context, request *proto.APIKeyRevokeRequest) (*proto.APIKeyRevokeResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+
+	// Отзываем ключ
+	if err := s.u.APIKey.Revoke(ctx, userID, request.Id); err != nil {
+		return nil, Error(err)
+	}
+	return &proto.APIKeyRevokeResponse{}, nil
+}
+
+// apiKeyToProto - преобразует models.APIKey в proto.APIKey без самого ключа
+func apiKeyToProto(key *models.APIKey) *proto.APIKey {
+	res := &proto.APIKey{
+		Id:        key.ID,
+		Name:      key.Name,
+		Scopes:    make([]string, 0, len(key.Scopes)),
+		CreatedAt: timestamppb.New(key.CreatedAt),
+		RevokedAt: timeToProto(key.RevokedAt),
+	}
+	for _, scope := range key.Scopes {
+		res.Scopes = append(res.Scopes, string(scope))
+	}
+	return res
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ofstudio/go-shortener/api/proto"
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/providers/auth"
+	"github.com/ofstudio/go-shortener/internal/repo"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+type APIKeyServiceSuite struct {
+	suite.Suite
+	u *usecases.Container
+	s *APIKeyService
+}
+
+func TestAPIKeyServiceSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyServiceSuite))
+}
+
+func (suite *APIKeyServiceSuite) SetupTest() {
+	cfg, _ := config.Default(nil)
+	suite.u = usecases.NewContainer(context.Background(), cfg, repo.NewMemoryRepo())
+	suite.s = NewAPIKeyService(suite.u)
+}
+
+func (suite *APIKeyServiceSuite) TestCreateListRevoke() {
+	suite.Run("unauthenticated", func() {
+		_, err := suite.s.List(context.Background(), &proto.APIKeyListRequest{})
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	ctx := auth.ToContext(context.Background(), 1)
+	suite.Run("invalid scope", func() {
+		_, err := suite.s.Create(ctx, &proto.APIKeyCreateRequest{Name: "CI", Scopes: []string{"admin"}})
+		suite.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	created, err := suite.s.Create(ctx, &proto.APIKeyCreateRequest{Name: "CI", Scopes: []string{"read", "delete"}})
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(created.Key, usecases.APIKeyPrefix))
+	suite.Equal([]string{"read", "delete"}, created.Scopes)
+	suite.Nil(created.RevokedAt)
+
+	// Ключ возвращается только при создании
+	list, err := suite.s.List(ctx, &proto.APIKeyListRequest{})
+	suite.Require().NoError(err)
+	suite.Require().Len(list.Keys, 1)
+	suite.Equal(created.Id, list.Keys[0].Id)
+	suite.Empty(list.Keys[0].Key)
+
+	// Чужой ключ отозвать нельзя
+	_, err = suite.s.Revoke(auth.ToContext(context.Background(), 2), &proto.APIKeyRevokeRequest{Id: created.Id})
+	suite.Equal(codes.NotFound, status.Code(err))
+	_, err = suite.s.Revoke(ctx, &proto.APIKeyRevokeRequest{Id: created.Id})
+	suite.Require().NoError(err)
+	list, err = suite.s.List(ctx, &proto.APIKeyListRequest{})
+	suite.Require().NoError(err)
+	suite.NotNil(list.Keys[0].RevokedAt)
+	_, err = suite.u.APIKey.Verify(context.Background(), created.Key)
+	suite.Error(err)
+}
@@ -3,7 +3,10 @@ package services
 import (
 	"context"
 
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	"github.com/ofstudio/go-shortener/api/proto"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
 	"github.com/ofstudio/go-shortener/internal/usecases"
 )
 
@@ -20,16 +23,52 @@ func NewInternalService(u *usecases.Container) *InternalService {
 
 // Stats - возвращает статистику сервиса.
 func (s *InternalService) Stats(ctx context.Context, _ *proto.StatsRequest) (*proto.StatsResponse, error) {
-	usersCount, err := s.u.User.Count(ctx)
+	stats, err := s.u.Stats.Get(ctx)
 	if err != nil {
 		return nil, Error(err)
 	}
-	shortURLCount, err := s.u.ShortURL.Count(ctx)
+	return &proto.StatsResponse{
+		Urls:                uint32(stats.URLs),
+		Users:               uint32(stats.Users),
+		ActiveUrls:          uint32(stats.ActiveURLs),
+		DeletedUrls:         uint32(stats.DeletedURLs),
+		UrlsLastHour:        uint32(stats.URLsLastHour),
+		UrlsLastDay:         uint32(stats.URLsLastDay),
+		ActiveUsers:         uint32(stats.ActiveUsers),
+		UniqueVisitorsDay:   stats.UniqueVisitorsDay,
+		UniqueVisitorsMonth: stats.UniqueVisitorsMonth,
+		UniqueVisitorsError: stats.UniqueVisitorsError,
+		Backend:             stats.Backend,
+		StorageSize:         stats.Size,
+		StartedAt:           timestamppb.New(stats.StartedAt),
+		UptimeSeconds:       int64(stats.Uptime.Seconds()),
+		Build: &proto.StatsResponse_Build{
+			Version: stats.Build.Version,
+			Date:    stats.Build.Date,
+			Commit:  stats.Build.Commit,
+		},
+	}, nil
+}
+
+// RevokeUserTokens - отзывает все токены пользователя.
+func (s *InternalService) RevokeUserTokens(ctx context.Context, request *proto.RevokeUserTokensRequest) (*proto.RevokeUserTokensResponse, error) {
+	if request.UserId == 0 {
+		return nil, Error(pkgerrors.ErrValidation)
+	}
+	generation, err := s.u.User.RevokeTokens(ctx, uint(request.UserId))
 	if err != nil {
 		return nil, Error(err)
 	}
-	return &proto.StatsResponse{
-		Users: uint32(usersCount),
-		Urls:  uint32(shortURLCount),
-	}, nil
+	return &proto.RevokeUserTokensResponse{Generation: uint32(generation)}, nil
+}
+
+// SetUserPlan - назначает пользователю тарифный план.
+func (s *InternalService) SetUserPlan(ctx context.Context, request *proto.SetUserPlanRequest) (*proto.SetUserPlanResponse, error) {
+	if request.UserId == 0 {
+		return nil, Error(pkgerrors.ErrValidation)
+	}
+	if err := s.u.Quota.SetPlan(ctx, uint(request.UserId), request.Plan); err != nil {
+		return nil, Error(err)
+	}
+	return &proto.SetUserPlanResponse{}, nil
 }
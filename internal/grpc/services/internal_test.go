@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/ofstudio/go-shortener/api/proto"
 	"github.com/ofstudio/go-shortener/internal/config"
@@ -28,18 +30,82 @@ func (suite *InternalServiceSuite) SetupTest() {
 func (suite *InternalServiceSuite) TestStats() {
 
 	suite.Run("should return stats", func() {
+		suite.NoError(suite.u.User.Create(context.Background(), &models.User{}))
 		suite.NoError(suite.u.User.Create(context.Background(), &models.User{}))
 		_, err := suite.u.ShortURL.Create(context.Background(), 1, "https://google.com")
 		suite.NoError(err)
+		deleted, err := suite.u.ShortURL.Create(context.Background(), 2, "https://example.com")
+		suite.NoError(err)
+		suite.NoError(suite.u.ShortURL.DeleteBatch(context.Background(), 2, []string{deleted.ID}))
 
 		res, err := suite.s.Stats(context.Background(), &proto.StatsRequest{})
 		suite.NoError(err)
-		suite.Equal(uint32(1), res.Users)
-		suite.Equal(uint32(1), res.Urls)
+		suite.Equal(uint32(2), res.Users)
+		suite.Equal(uint32(1), res.ActiveUsers)
+		suite.Equal(uint32(2), res.Urls)
+		suite.Equal(uint32(1), res.ActiveUrls)
+		suite.Equal(uint32(1), res.DeletedUrls)
+		suite.Equal(uint32(2), res.UrlsLastHour)
+		suite.Equal(uint32(2), res.UrlsLastDay)
+		suite.Equal("memory", res.Backend)
+		suite.NotNil(res.StartedAt)
+		suite.Equal("N/A", res.Build.Version)
 	})
 
 }
 
+func (suite *InternalServiceSuite) TestRevokeUserTokens() {
+	user := &models.User{}
+	suite.Require().NoError(suite.u.User.Create(context.Background(), user))
+
+	suite.Run("should revoke user tokens", func() {
+		res, err := suite.s.RevokeUserTokens(context.Background(), &proto.RevokeUserTokensRequest{UserId: uint32(user.ID)})
+		suite.NoError(err)
+		suite.Equal(uint32(1), res.Generation)
+		res, err = suite.s.RevokeUserTokens(context.Background(), &proto.RevokeUserTokensRequest{UserId: uint32(user.ID)})
+		suite.NoError(err)
+		suite.Equal(uint32(2), res.Generation)
+	})
+
+	suite.Run("should return NotFound for unknown user", func() {
+		_, err := suite.s.RevokeUserTokens(context.Background(), &proto.RevokeUserTokensRequest{UserId: 100500})
+		suite.Equal(codes.NotFound, status.Code(err))
+	})
+
+	suite.Run("should return InvalidArgument for empty user id", func() {
+		_, err := suite.s.RevokeUserTokens(context.Background(), &proto.RevokeUserTokensRequest{})
+		suite.Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func (suite *InternalServiceSuite) TestSetUserPlan() {
+	cfg, _ := config.Default(nil)
+	cfg.QuotaPlans = map[string]models.Quota{"pro": {MaxActiveURLs: 100}}
+	suite.u = usecases.NewContainer(context.Background(), cfg, repo.NewMemoryRepo())
+	suite.s = NewInternalService(suite.u)
+	user := &models.User{}
+	suite.Require().NoError(suite.u.User.Create(context.Background(), user))
+
+	suite.Run("should set user plan", func() {
+		_, err := suite.s.SetUserPlan(context.Background(), &proto.SetUserPlanRequest{UserId: uint32(user.ID), Plan: "pro"})
+		suite.NoError(err)
+		usage, err := suite.u.Quota.Usage(context.Background(), user.ID)
+		suite.NoError(err)
+		suite.Equal("pro", usage.Plan)
+		suite.Equal(100, usage.MaxActiveURLs)
+	})
+
+	suite.Run("should return InvalidArgument for unknown plan", func() {
+		_, err := suite.s.SetUserPlan(context.Background(), &proto.SetUserPlanRequest{UserId: uint32(user.ID), Plan: "enterprise"})
+		suite.Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("should return NotFound for unknown user", func() {
+		_, err := suite.s.SetUserPlan(context.Background(), &proto.SetUserPlanRequest{UserId: 100500, Plan: "pro"})
+		suite.Equal(codes.NotFound, status.Code(err))
+	})
+}
+
 func TestInternalServerSuite(t *testing.T) {
 	suite.Run(t, new(InternalServiceSuite))
 }
@@ -3,15 +3,58 @@ package services
 import (
 	"context"
 	"errors"
+	"time"
 
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/ofstudio/go-shortener/api/proto"
+	"github.com/ofstudio/go-shortener/internal/models"
 	"github.com/ofstudio/go-shortener/internal/pkgerrors"
 	"github.com/ofstudio/go-shortener/internal/providers/auth"
 	"github.com/ofstudio/go-shortener/internal/usecases"
 )
 
+// ShortURLMethods - требования методов сервиса ShortURL к аутентификации.
+// Новый пользователь создается только методами создания, получения и удаления ссылок.
+var ShortURLMethods = auth.Methods{
+	"/proto.ShortURL/Create":           auth.CreateUser,
+	"/proto.ShortURL/CreateBatch":      auth.CreateUser,
+	"/proto.ShortURL/DeleteBatch":      auth.CreateUser,
+	"/proto.ShortURL/GetByUserID":      auth.CreateUser,
+	"/proto.ShortURL/GetByTeamID":      auth.Required,
+	"/proto.ShortURL/SetTeam":          auth.Required,
+	"/proto.ShortURL/GetQuota":         auth.Required,
+	"/proto.ShortURL/GetRules":         auth.Required,
+	"/proto.ShortURL/SetRules":         auth.Required,
+	"/proto.ShortURL/SetQueryTemplate": auth.Required,
+	"/proto.ShortURL/SetPreview":       auth.Required,
+	"/proto.ShortURL/SetActiveWindow":  auth.Required,
+	"/proto.ShortURL/GetVariantStats":  auth.Required,
+	"/proto.ShortURL/GetStats":         auth.Required,
+	"/proto.ShortURL/ExportClicks":     auth.Required,
+}
+
+// ShortURLScopes - права доступа API-ключа, необходимые методам сервиса ShortURL.
+// Методы, не указанные здесь, в тч методы других сервисов, недоступны по API-ключу.
+var ShortURLScopes = auth.Scopes{
+	"/proto.ShortURL/Create":           models.APIScopeCreate,
+	"/proto.ShortURL/CreateBatch":      models.APIScopeCreate,
+	"/proto.ShortURL/DeleteBatch":      models.APIScopeDelete,
+	"/proto.ShortURL/GetByUserID":      models.APIScopeRead,
+	"/proto.ShortURL/GetByTeamID":      models.APIScopeRead,
+	"/proto.ShortURL/SetTeam":          models.APIScopeCreate,
+	"/proto.ShortURL/GetQuota":         models.APIScopeRead,
+	"/proto.ShortURL/GetRules":         models.APIScopeRead,
+	"/proto.ShortURL/SetRules":         models.APIScopeCreate,
+	"/proto.ShortURL/SetQueryTemplate": models.APIScopeCreate,
+	"/proto.ShortURL/SetPreview":       models.APIScopeCreate,
+	"/proto.ShortURL/SetActiveWindow":  models.APIScopeCreate,
+	"/proto.ShortURL/GetVariantStats":  models.APIScopeRead,
+	"/proto.ShortURL/GetStats":         models.APIScopeRead,
+	"/proto.ShortURL/ExportClicks":     models.APIScopeRead,
+}
+
 // ShortURLService - реализация gRPC сервиса для работы с короткими ссылками.
 type ShortURLService struct {
 	proto.UnimplementedShortURLServer
@@ -32,7 +75,13 @@ func (s ShortURLService) Create(ctx context.Context, request *proto.ShortURLCrea
 	}
 
 	// Создаем короткую ссылку
-	shortURL, err := s.u.ShortURL.Create(ctx, userID, request.Url)
+	shortURL, err := s.u.ShortURL.Create(ctx, userID, request.Url,
+		usecases.WithTitle(request.Title),
+		usecases.WithInterstitial(request.Interstitial),
+		usecases.WithActiveWindow(timeFromProto(request.ActiveFrom), timeFromProto(request.ActiveUntil)),
+		usecases.WithQueryTemplate(queryTemplateFromProto(request.QueryTemplate)),
+		usecases.WithSplit(splitFromProto(request.Split)),
+		usecases.WithTeam(uint(request.TeamId)))
 	if err != nil && !errors.Is(err, pkgerrors.ErrDuplicate) {
 		return nil, Error(err)
 	}
@@ -51,18 +100,25 @@ func (s ShortURLService) CreateBatch(ctx context.Context, request *proto.ShortUR
 		return nil, Error(pkgerrors.ErrAuth)
 	}
 
-	// Создаем короткие ссылки
+	// Создаем короткие ссылки: размер пакета и квоты пользователя проверяются для всего пакета
+	items := make([]usecases.BatchItem, len(request.Items))
+	for i, item := range request.Items {
+		items[i] = usecases.BatchItem{
+			OriginalURL: item.OriginalUrl,
+			Opts:        []usecases.CreateOpt{usecases.WithQueryTemplate(queryTemplateFromProto(item.QueryTemplate))},
+		}
+	}
+	shortURLs, err := s.u.ShortURL.CreateBatch(ctx, userID, items)
+	if err != nil {
+		return nil, Error(err)
+	}
 	res := &proto.ShortURLCreateBatchResponse{
 		Items: make([]*proto.ShortURLCreateBatchResponse_Item, 0, len(request.Items)),
 	}
-	for _, item := range request.Items {
-		shortURL, err := s.u.ShortURL.Create(ctx, userID, item.OriginalUrl)
-		if err != nil && !errors.Is(err, pkgerrors.ErrDuplicate) {
-			return nil, Error(err)
-		}
+	for i, item := range request.Items {
 		res.Items = append(res.Items, &proto.ShortURLCreateBatchResponse_Item{
 			CorrelationId: item.CorrelationId,
-			ShortUrl:      s.u.ShortURL.Resolve(shortURL.ID),
+			ShortUrl:      s.u.ShortURL.Resolve(shortURLs[i].ID),
 		})
 	}
 	return res, nil
@@ -100,20 +156,395 @@ func (s ShortURLService) GetByUserID(ctx context.Context, _ *proto.ShortURLGetBy
 	if err != nil {
 		return nil, Error(err)
 	}
-	// Если коротких ссылок нет, возвращаем no content
+	return s.shortURLsToProto(shortURLs)
+}
+
+// GetByTeamID - получение коротких ссылок команды. Доступно любому участнику команды.
+func (s ShortURLService) GetByTeamID(ctx context.Context, request *proto.ShortURLGetByTeamIDRequest) (*proto.ShortURLGetByUserIDResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+
+	// Получаем короткие ссылки команды
+	shortURLs, err := s.u.ShortURL.GetByTeamID(ctx, userID, uint(request.TeamId))
+	if err != nil {
+		return nil, Error(err)
+	}
+	return s.shortURLsToProto(shortURLs)
+}
+
+// SetTeam - передача короткой ссылки команде либо возврат ее создавшему пользователю.
+func (s ShortURLService) SetTeam(ctx context.Context, request *proto.ShortURLSetTeamRequest) (*proto.ShortURLSetTeamResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+
+	// Передаем ссылку
+	if _, err := s.u.ShortURL.SetTeam(ctx, userID, request.Id, uint(request.TeamId)); err != nil {
+		return nil, Error(err)
+	}
+	return &proto.ShortURLSetTeamResponse{}, nil
+}
+
+// GetQuota - квоты пользователя и их использование.
+func (s ShortURLService) GetQuota(ctx context.Context, _ *proto.ShortURLQuotaRequest) (*proto.ShortURLQuotaResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+
+	usage, err := s.u.Quota.Usage(ctx, userID)
+	if err != nil {
+		return nil, Error(err)
+	}
+	return &proto.ShortURLQuotaResponse{
+		Plan:          usage.Plan,
+		MaxActiveUrls: uint32(usage.MaxActiveURLs),
+		UrlsPerDay:    uint32(usage.URLsPerDay),
+		MaxBatchSize:  uint32(usage.MaxBatchSize),
+		ActiveUrls:    uint32(usage.ActiveURLs),
+		UrlsLastDay:   uint32(usage.URLsLastDay),
+	}, nil
+}
+
+// shortURLsToProto - формирует ответ со списком коротких ссылок.
+// Если коротких ссылок нет, возвращает no content.
+func (s ShortURLService) shortURLsToProto(shortURLs []models.ShortURL) (*proto.ShortURLGetByUserIDResponse, error) {
 	if len(shortURLs) == 0 {
 		return nil, status.Error(pkgerrors.GRPCNoContent, "no content")
 	}
-
-	// Формируем ответ
 	res := &proto.ShortURLGetByUserIDResponse{
 		Items: make([]*proto.ShortURLGetByUserIDResponse_Item, 0, len(shortURLs)),
 	}
 	for _, shortURL := range shortURLs {
 		res.Items = append(res.Items, &proto.ShortURLGetByUserIDResponse_Item{
-			OriginalUrl: shortURL.OriginalURL,
-			ShortUrl:    s.u.ShortURL.Resolve(shortURL.ID),
+			OriginalUrl:   shortURL.OriginalURL,
+			ShortUrl:      s.u.ShortURL.Resolve(shortURL.ID),
+			SubmittedUrl:  shortURL.SubmittedURL,
+			Title:         shortURL.Title,
+			CreatedAt:     timestamppb.New(shortURL.CreatedAt),
+			Interstitial:  shortURL.Interstitial,
+			ActiveFrom:    timeToProto(shortURL.ActiveFrom),
+			ActiveUntil:   timeToProto(shortURL.ActiveUntil),
+			QueryTemplate: queryTemplateToProto(shortURL.QueryTemplate),
+			Split:         splitToProto(shortURL.Split),
+			TeamId:        uint32(shortURL.TeamID),
 		})
 	}
 	return res, nil
 }
+
+// GetRules - получение правил перенаправления короткой ссылки пользователя.
+func (s ShortURLService) GetRules(ctx context.Context, request *proto.ShortURLGetRulesRequest) (*proto.ShortURLRulesResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+	// Получаем короткую ссылку пользователя
+	shortURL, err := s.u.ShortURL.GetOwned(ctx, userID, request.Id)
+	if err != nil {
+		return nil, Error(err)
+	}
+	return &proto.ShortURLRulesResponse{Rules: rulesToProto(shortURL.Rules)}, nil
+}
+
+// SetRules - замена правил перенаправления короткой ссылки пользователя.
+func (s ShortURLService) SetRules(ctx context.Context, request *proto.ShortURLSetRulesRequest) (*proto.ShortURLRulesResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+	// Сохраняем правила
+	shortURL, err := s.u.ShortURL.SetRules(ctx, userID, request.Id, rulesFromProto(request.Rules))
+	if err != nil {
+		return nil, Error(err)
+	}
+	return &proto.ShortURLRulesResponse{Rules: rulesToProto(shortURL.Rules)}, nil
+}
+
+// SetQueryTemplate - замена шаблона query-параметров короткой ссылки пользователя.
+func (s ShortURLService) SetQueryTemplate(ctx context.Context, request *proto.ShortURLSetQueryTemplateRequest) (*proto.ShortURLSetQueryTemplateResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+	// Сохраняем шаблон
+	shortURL, err := s.u.ShortURL.SetQueryTemplate(ctx, userID, request.Id, queryTemplateFromProto(request.QueryTemplate))
+	if err != nil {
+		return nil, Error(err)
+	}
+	return &proto.ShortURLSetQueryTemplateResponse{QueryTemplate: queryTemplateToProto(shortURL.QueryTemplate)}, nil
+}
+
+// SetPreview - изменение заголовка короткой ссылки пользователя и показа страницы-предупреждения.
+func (s ShortURLService) SetPreview(ctx context.Context, request *proto.ShortURLSetPreviewRequest) (*proto.ShortURLSetPreviewResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+	// Сохраняем заголовок и страницу-предупреждение
+	shortURL, err := s.u.ShortURL.SetPreview(ctx, userID, request.Id, request.Title, request.Interstitial)
+	if err != nil {
+		return nil, Error(err)
+	}
+	return &proto.ShortURLSetPreviewResponse{Title: shortURL.Title, Interstitial: shortURL.Interstitial}, nil
+}
+
+// SetActiveWindow - изменение периода действия короткой ссылки пользователя.
+func (s ShortURLService) SetActiveWindow(ctx context.Context, request *proto.ShortURLSetActiveWindowRequest) (*proto.ShortURLSetActiveWindowResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+	// Сохраняем период действия
+	shortURL, err := s.u.ShortURL.SetActiveWindow(ctx, userID, request.Id,
+		timeFromProto(request.ActiveFrom), timeFromProto(request.ActiveUntil))
+	if err != nil {
+		return nil, Error(err)
+	}
+	return &proto.ShortURLSetActiveWindowResponse{
+		ActiveFrom:  timeToProto(shortURL.ActiveFrom),
+		ActiveUntil: timeToProto(shortURL.ActiveUntil),
+	}, nil
+}
+
+// GetVariantStats - получение статистики переходов по вариантам сплит-ссылки пользователя.
+func (s ShortURLService) GetVariantStats(ctx context.Context, request *proto.ShortURLVariantStatsRequest) (*proto.ShortURLVariantStatsResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+	// Получаем статистику
+	stats, err := s.u.ShortURL.VariantStats(ctx, userID, request.Id)
+	if err != nil {
+		return nil, Error(err)
+	}
+	res := &proto.ShortURLVariantStatsResponse{Items: make([]*proto.ShortURLVariantStatsResponse_Item, 0, len(stats))}
+	for _, stat := range stats {
+		res.Items = append(res.Items, &proto.ShortURLVariantStatsResponse_Item{
+			Variant: int32(stat.Variant),
+			Url:     stat.URL,
+			Weight:  uint32(stat.Weight),
+			Clicks:  stat.Clicks,
+		})
+	}
+	return res, nil
+}
+
+// GetStats - получение статистики переходов по ссылке пользователя за период.
+func (s ShortURLService) GetStats(ctx context.Context, request *proto.ShortURLStatsRequest) (*proto.ShortURLStatsResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+	// Получаем статистику
+	var from, until time.Time
+	if t := timeFromProto(request.From); t != nil {
+		from = *t
+	}
+	if t := timeFromProto(request.Until); t != nil {
+		until = *t
+	}
+	stats, err := s.u.ShortURL.Stats(ctx, userID, request.Id, from, until, models.StatsInterval(request.Interval), request.IncludeBots)
+	if err != nil {
+		return nil, Error(err)
+	}
+	res := &proto.ShortURLStatsResponse{
+		From:      timestamppb.New(stats.From),
+		Until:     timestamppb.New(stats.Until),
+		Interval:  string(stats.Interval),
+		Total:     stats.Total,
+		Unique:    stats.Unique,
+		Buckets:   make([]*proto.ShortURLStatsResponse_Bucket, 0, len(stats.Buckets)),
+		Referrers: statsCountsToProto(stats.Referrers),
+		Countries: statsCountsToProto(stats.Countries),
+		Devices:   statsCountsToProto(stats.Devices),
+
+		ApproxUnique:      stats.ApproxUnique,
+		ApproxUniqueError: stats.ApproxUniqueError,
+		Bots:              stats.Bots,
+		IncludeBots:       stats.IncludeBots,
+	}
+	for _, b := range stats.Buckets {
+		res.Buckets = append(res.Buckets, &proto.ShortURLStatsResponse_Bucket{
+			Time:   timestamppb.New(b.Time),
+			Total:  b.Total,
+			Unique: b.Unique,
+		})
+	}
+	return res, nil
+}
+
+// ExportClicks - потоковая выгрузка событий переходов по ссылке пользователя за период, включая переходы ботов.
+// События передаются клиенту по мере чтения из хранилища.
+func (s ShortURLService) ExportClicks(request *proto.ShortURLClickExportRequest, stream proto.ShortURL_ExportClicksServer) error {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(stream.Context())
+	if !ok {
+		return Error(pkgerrors.ErrAuth)
+	}
+	// Выгружаем события
+	var from, until time.Time
+	if t := timeFromProto(request.From); t != nil {
+		from = *t
+	}
+	if t := timeFromProto(request.To); t != nil {
+		until = *t
+	}
+	err := s.u.ShortURL.ExportClicks(stream.Context(), userID, request.Id, from, until, func(click models.Click) error {
+		return stream.Send(clickToProto(click))
+	})
+	if err != nil {
+		return Error(err)
+	}
+	return nil
+}
+
+// clickToProto - преобразует models.Click в proto.ShortURLClick
+func clickToProto(click models.Click) *proto.ShortURLClick {
+	return &proto.ShortURLClick{
+		ShortUrlId:  click.ShortURLID,
+		Time:        timestamppb.New(click.Time),
+		RefererHost: click.RefererHost,
+		Device:      string(click.Device),
+		Ip:          click.IP,
+		Country:     click.Country,
+		Visitor:     click.Visitor,
+		Bot:         click.Bot,
+	}
+}
+
+// queryTemplateFromProto - преобразует proto.QueryTemplate в models.QueryTemplate
+func queryTemplateFromProto(t *proto.QueryTemplate) *models.QueryTemplate {
+	if t == nil {
+		return nil
+	}
+	return &models.QueryTemplate{
+		Params: t.Params,
+		Policy: models.QueryMergePolicy(t.Policy),
+	}
+}
+
+// queryTemplateToProto - преобразует models.QueryTemplate в proto.QueryTemplate
+func queryTemplateToProto(t *models.QueryTemplate) *proto.QueryTemplate {
+	if t == nil {
+		return nil
+	}
+	return &proto.QueryTemplate{
+		Params: t.Params,
+		Policy: string(t.Policy),
+	}
+}
+
+// rulesFromProto - преобразует список proto.RedirectRule в список models.RedirectRule
+func rulesFromProto(rules []*proto.RedirectRule) []models.RedirectRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make([]models.RedirectRule, 0, len(rules))
+	for _, r := range rules {
+		rule := models.RedirectRule{
+			URL:       r.Url,
+			Languages: r.Languages,
+		}
+		for _, d := range r.Devices {
+			rule.Devices = append(rule.Devices, models.Device(d))
+		}
+		if r.Schedule != nil {
+			rule.Schedule = &models.Schedule{
+				Weekdays: r.Schedule.Weekdays,
+				From:     r.Schedule.From,
+				Until:    r.Schedule.Until,
+				TimeZone: r.Schedule.TimeZone,
+			}
+		}
+		result = append(result, rule)
+	}
+	return result
+}
+
+// rulesToProto - преобразует список models.RedirectRule в список proto.RedirectRule
+func rulesToProto(rules []models.RedirectRule) []*proto.RedirectRule {
+	result := make([]*proto.RedirectRule, 0, len(rules))
+	for _, r := range rules {
+		rule := &proto.RedirectRule{
+			Url:       r.URL,
+			Languages: r.Languages,
+		}
+		for _, d := range r.Devices {
+			rule.Devices = append(rule.Devices, string(d))
+		}
+		if r.Schedule != nil {
+			rule.Schedule = &proto.RedirectRule_Schedule{
+				Weekdays: r.Schedule.Weekdays,
+				From:     r.Schedule.From,
+				Until:    r.Schedule.Until,
+				TimeZone: r.Schedule.TimeZone,
+			}
+		}
+		result = append(result, rule)
+	}
+	return result
+}
+
+// splitFromProto - преобразует proto.Split в models.Split
+func splitFromProto(split *proto.Split) *models.Split {
+	if split == nil {
+		return nil
+	}
+	result := &models.Split{Sticky: split.Sticky}
+	for _, v := range split.Variants {
+		result.Variants = append(result.Variants, models.Variant{URL: v.Url, Weight: uint(v.Weight)})
+	}
+	return result
+}
+
+// splitToProto - преобразует models.Split в proto.Split
+func splitToProto(split *models.Split) *proto.Split {
+	if split == nil {
+		return nil
+	}
+	result := &proto.Split{Sticky: split.Sticky}
+	for _, v := range split.Variants {
+		result.Variants = append(result.Variants, &proto.Split_Variant{Url: v.URL, Weight: uint32(v.Weight)})
+	}
+	return result
+}
+
+// timeFromProto - преобразует необязательное время из timestamppb.Timestamp
+func timeFromProto(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	result := t.AsTime()
+	return &result
+}
+
+// timeToProto - преобразует необязательное время в timestamppb.Timestamp
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// statsCountsToProto - преобразует []models.StatsCount в []*proto.ShortURLStatsResponse_Count
+func statsCountsToProto(counts []models.StatsCount) []*proto.ShortURLStatsResponse_Count {
+	result := make([]*proto.ShortURLStatsResponse_Count, 0, len(counts))
+	for _, c := range counts {
+		result = append(result, &proto.ShortURLStatsResponse_Count{Key: c.Key, Clicks: c.Clicks})
+	}
+	return result
+}
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/ofstudio/go-shortener/api/proto"
 	"github.com/ofstudio/go-shortener/internal/config"
@@ -20,13 +22,15 @@ import (
 
 type ShortURLServiceSuite struct {
 	suite.Suite
+	r *repo.MemoryRepo
 	u *usecases.Container
 	s *ShortURLService
 }
 
 func (suite *ShortURLServiceSuite) SetupTest() {
 	cfg, _ := config.Default(nil)
-	suite.u = usecases.NewContainer(context.Background(), cfg, repo.NewMemoryRepo())
+	suite.r = repo.NewMemoryRepo()
+	suite.u = usecases.NewContainer(context.Background(), cfg, suite.r)
 	// Создаем двух тестовых пользователей
 	suite.Require().NoError(suite.u.User.Create(context.Background(), &models.User{ID: 1}))
 	suite.Require().NoError(suite.u.User.Create(context.Background(), &models.User{ID: 2}))
@@ -58,6 +62,43 @@ func (suite *ShortURLServiceSuite) TestCreate() {
 		suite.Equal(codes.InvalidArgument, st.Code())
 	})
 
+	suite.Run("should return error with reason if url points to private network", func() {
+		ctx := auth.ToContext(context.Background(), 1)
+		_, err := suite.s.Create(ctx, &proto.ShortURLCreateRequest{Url: "http://127.0.0.1:8080/"})
+		suite.Require().Error(err)
+		st, ok := status.FromError(err)
+		suite.Require().True(ok)
+		suite.Equal(codes.InvalidArgument, st.Code())
+		suite.Contains(st.Message(), "127.0.0.1")
+	})
+
+	suite.Run("should create short url with query template", func() {
+		ctx := auth.ToContext(context.Background(), 2)
+		res, err := suite.s.Create(ctx, &proto.ShortURLCreateRequest{
+			Url: "https://example.com",
+			QueryTemplate: &proto.QueryTemplate{
+				Params: map[string]string{"utm_source": "{referrer_host}"},
+				Policy: "override",
+			},
+		})
+		suite.Require().NoError(err)
+		suite.NotEmpty(res.Result)
+		list, err := suite.s.GetByUserID(ctx, &proto.ShortURLGetByUserIDRequest{})
+		suite.Require().NoError(err)
+		suite.Require().Len(list.Items, 1)
+		suite.Require().NotNil(list.Items[0].QueryTemplate)
+		suite.Equal("{referrer_host}", list.Items[0].QueryTemplate.Params["utm_source"])
+		suite.Equal("override", list.Items[0].QueryTemplate.Policy)
+	})
+	suite.Run("should return error if query template is invalid", func() {
+		ctx := auth.ToContext(context.Background(), 1)
+		_, err := suite.s.Create(ctx, &proto.ShortURLCreateRequest{
+			Url:           "https://example.org",
+			QueryTemplate: &proto.QueryTemplate{Params: map[string]string{"a": "{unknown}"}},
+		})
+		suite.Require().Error(err)
+		suite.Equal(codes.InvalidArgument, status.Code(err))
+	})
 	suite.Run("should same short return url if duplicate", func() {
 		ctx := auth.ToContext(context.Background(), 1)
 		res, err := suite.s.Create(ctx, &proto.ShortURLCreateRequest{Url: "https://google.com"})
@@ -204,6 +245,304 @@ func (suite *ShortURLServiceSuite) TestGetByUserID() {
 	})
 }
 
+func (suite *ShortURLServiceSuite) TestRules() {
+	suite.Run("unauthenticated", func() {
+		_, err := suite.s.SetRules(context.Background(), &proto.ShortURLSetRulesRequest{})
+		suite.Require().Error(err)
+		st, ok := status.FromError(err)
+		suite.Require().True(ok)
+		suite.Equal(codes.Unauthenticated, st.Code())
+	})
+
+	suite.Run("should set and get rules", func() {
+		ctx := auth.ToContext(context.Background(), 1)
+		shortURL, err := suite.u.ShortURL.Create(ctx, 1, "https://example.com/rules")
+		suite.Require().NoError(err)
+		rules := []*proto.RedirectRule{
+			{Url: "https://apps.apple.com/app", Devices: []string{"ios"}},
+			{Url: "https://example.com/office", Schedule: &proto.RedirectRule_Schedule{
+				Weekdays: []string{"mon", "fri"},
+				From:     "09:00",
+				Until:    "18:00",
+				TimeZone: "Europe/Moscow",
+			}},
+		}
+		res, err := suite.s.SetRules(ctx, &proto.ShortURLSetRulesRequest{Id: shortURL.ID, Rules: rules})
+		suite.Require().NoError(err)
+		suite.Len(res.Rules, 2)
+
+		res, err = suite.s.GetRules(ctx, &proto.ShortURLGetRulesRequest{Id: shortURL.ID})
+		suite.Require().NoError(err)
+		suite.Require().Len(res.Rules, 2)
+		suite.Equal([]string{"ios"}, res.Rules[0].Devices)
+		suite.Equal("Europe/Moscow", res.Rules[1].Schedule.TimeZone)
+	})
+
+	suite.Run("should return error if not owner", func() {
+		shortURL, err := suite.u.ShortURL.Create(context.Background(), 1, "https://example.com/owned")
+		suite.Require().NoError(err)
+		ctx := auth.ToContext(context.Background(), 2)
+		_, err = suite.s.GetRules(ctx, &proto.ShortURLGetRulesRequest{Id: shortURL.ID})
+		suite.Require().Error(err)
+		st, ok := status.FromError(err)
+		suite.Require().True(ok)
+		suite.Equal(codes.NotFound, st.Code())
+	})
+
+	suite.Run("should return error if rules are invalid", func() {
+		ctx := auth.ToContext(context.Background(), 1)
+		shortURL, err := suite.u.ShortURL.Create(ctx, 1, "https://example.com/invalid")
+		suite.Require().NoError(err)
+		_, err = suite.s.SetRules(ctx, &proto.ShortURLSetRulesRequest{
+			Id:    shortURL.ID,
+			Rules: []*proto.RedirectRule{{Url: "https://example.com/", Devices: []string{"tv"}}},
+		})
+		suite.Require().Error(err)
+		st, ok := status.FromError(err)
+		suite.Require().True(ok)
+		suite.Equal(codes.InvalidArgument, st.Code())
+	})
+}
+
+func (suite *ShortURLServiceSuite) TestSetQueryTemplate() {
+	suite.Run("unauthenticated", func() {
+		_, err := suite.s.SetQueryTemplate(context.Background(), &proto.ShortURLSetQueryTemplateRequest{})
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	suite.Run("should set and remove query template", func() {
+		ctx := auth.ToContext(context.Background(), 1)
+		shortURL, err := suite.u.ShortURL.Create(ctx, 1, "https://example.com/template")
+		suite.Require().NoError(err)
+		template := &proto.QueryTemplate{Params: map[string]string{"utm_content": "{id}"}, Policy: "append"}
+		res, err := suite.s.SetQueryTemplate(ctx, &proto.ShortURLSetQueryTemplateRequest{Id: shortURL.ID, QueryTemplate: template})
+		suite.Require().NoError(err)
+		suite.Equal(template.Params, res.QueryTemplate.Params)
+		suite.Equal("append", res.QueryTemplate.Policy)
+
+		res, err = suite.s.SetQueryTemplate(ctx, &proto.ShortURLSetQueryTemplateRequest{Id: shortURL.ID})
+		suite.Require().NoError(err)
+		suite.Nil(res.QueryTemplate)
+	})
+
+	suite.Run("should return error if template is invalid", func() {
+		ctx := auth.ToContext(context.Background(), 1)
+		shortURL, err := suite.u.ShortURL.Create(ctx, 1, "https://example.com/invalid-template")
+		suite.Require().NoError(err)
+		_, err = suite.s.SetQueryTemplate(ctx, &proto.ShortURLSetQueryTemplateRequest{
+			Id:            shortURL.ID,
+			QueryTemplate: &proto.QueryTemplate{Params: map[string]string{"a": "b"}, Policy: "merge"},
+		})
+		suite.Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func (suite *ShortURLServiceSuite) TestSetPreview() {
+	suite.Run("unauthenticated", func() {
+		_, err := suite.s.SetPreview(context.Background(), &proto.ShortURLSetPreviewRequest{})
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	suite.Run("should set title and interstitial", func() {
+		ctx := auth.ToContext(context.Background(), 1)
+		shortURL, err := suite.u.ShortURL.Create(ctx, 1, "https://example.com/preview")
+		suite.Require().NoError(err)
+		res, err := suite.s.SetPreview(ctx, &proto.ShortURLSetPreviewRequest{Id: shortURL.ID, Title: "Отчет", Interstitial: true})
+		suite.Require().NoError(err)
+		suite.Equal("Отчет", res.Title)
+		suite.True(res.Interstitial)
+	})
+
+	suite.Run("should return error if not owner", func() {
+		shortURL, err := suite.u.ShortURL.Create(context.Background(), 1, "https://example.com/preview-owned")
+		suite.Require().NoError(err)
+		_, err = suite.s.SetPreview(auth.ToContext(context.Background(), 2), &proto.ShortURLSetPreviewRequest{Id: shortURL.ID})
+		suite.Equal(codes.NotFound, status.Code(err))
+	})
+}
+
+func (suite *ShortURLServiceSuite) TestSetActiveWindow() {
+	suite.Run("unauthenticated", func() {
+		_, err := suite.s.SetActiveWindow(context.Background(), &proto.ShortURLSetActiveWindowRequest{})
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	suite.Run("should set active window", func() {
+		ctx := auth.ToContext(context.Background(), 1)
+		shortURL, err := suite.u.ShortURL.Create(ctx, 1, "https://example.com/window")
+		suite.Require().NoError(err)
+		from := timestamppb.New(time.Now().Add(time.Hour).Truncate(time.Second))
+		res, err := suite.s.SetActiveWindow(ctx, &proto.ShortURLSetActiveWindowRequest{Id: shortURL.ID, ActiveFrom: from})
+		suite.Require().NoError(err)
+		suite.Equal(from.AsTime(), res.ActiveFrom.AsTime())
+		suite.Nil(res.ActiveUntil)
+		_, err = suite.u.ShortURL.GetByID(ctx, shortURL.ID)
+		suite.Equal(pkgerrors.ErrNotActive, err)
+	})
+
+	suite.Run("should return error if window is invalid", func() {
+		ctx := auth.ToContext(context.Background(), 1)
+		shortURL, err := suite.u.ShortURL.Create(ctx, 1, "https://example.com/invalid-window")
+		suite.Require().NoError(err)
+		now := timestamppb.Now()
+		_, err = suite.s.SetActiveWindow(ctx, &proto.ShortURLSetActiveWindowRequest{Id: shortURL.ID, ActiveFrom: now, ActiveUntil: now})
+		suite.Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func (suite *ShortURLServiceSuite) TestGetStats() {
+	suite.Run("unauthenticated", func() {
+		_, err := suite.s.GetStats(context.Background(), &proto.ShortURLStatsRequest{})
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	shortURL, err := suite.u.ShortURL.Create(context.Background(), 1, "https://example.com/stats")
+	suite.Require().NoError(err)
+	day := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	suite.Require().NoError(suite.r.ClickAddBatch(context.Background(), []models.Click{
+		{ShortURLID: shortURL.ID, Time: day.Add(time.Hour), RefererHost: "t.co", Device: models.DeviceIOS, Country: "DE", Visitor: "a"},
+		{ShortURLID: shortURL.ID, Time: day.Add(30 * time.Hour), Device: models.DeviceDesktop, Visitor: "b"},
+	}))
+
+	suite.Run("should return stats", func() {
+		ctx := auth.ToContext(context.Background(), 1)
+		res, err := suite.s.GetStats(ctx, &proto.ShortURLStatsRequest{
+			Id:    shortURL.ID,
+			From:  timestamppb.New(day),
+			Until: timestamppb.New(day.AddDate(0, 0, 2)),
+		})
+		suite.Require().NoError(err)
+		suite.Equal("day", res.Interval)
+		suite.Equal(int64(2), res.Total)
+		suite.Equal(int64(2), res.Unique)
+		suite.Require().Len(res.Buckets, 2)
+		suite.Equal(day, res.Buckets[0].Time.AsTime())
+		suite.Equal(int64(1), res.Buckets[1].Total)
+		suite.Require().Len(res.Referrers, 1)
+		suite.Equal("t.co", res.Referrers[0].Key)
+		suite.Require().Len(res.Countries, 1)
+		suite.Len(res.Devices, 2)
+	})
+
+	suite.Run("should return error if not owner", func() {
+		ctx := auth.ToContext(context.Background(), 2)
+		_, err := suite.s.GetStats(ctx, &proto.ShortURLStatsRequest{Id: shortURL.ID})
+		suite.Equal(codes.NotFound, status.Code(err))
+	})
+
+	suite.Run("should return error if interval is invalid", func() {
+		ctx := auth.ToContext(context.Background(), 1)
+		_, err := suite.s.GetStats(ctx, &proto.ShortURLStatsRequest{Id: shortURL.ID, Interval: "week"})
+		suite.Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func (suite *ShortURLServiceSuite) TestExportClicks() {
+	shortURL, err := suite.u.ShortURL.Create(context.Background(), 1, "https://example.com/export")
+	suite.Require().NoError(err)
+	day := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	suite.Require().NoError(suite.r.ClickAddBatch(context.Background(), []models.Click{
+		{ShortURLID: shortURL.ID, Time: day.Add(time.Hour), RefererHost: "t.co", Device: models.DeviceIOS, IP: "203.0.113.0", Country: "DE", Visitor: "a"},
+		{ShortURLID: shortURL.ID, Time: day.Add(30 * time.Hour), Device: models.DeviceDesktop, Visitor: "b", Bot: true},
+	}))
+
+	suite.Run("unauthenticated", func() {
+		stream := &clickStream{ctx: context.Background()}
+		err := suite.s.ExportClicks(&proto.ShortURLClickExportRequest{Id: shortURL.ID}, stream)
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	suite.Run("should stream clicks", func() {
+		stream := &clickStream{ctx: auth.ToContext(context.Background(), 1)}
+		suite.Require().NoError(suite.s.ExportClicks(&proto.ShortURLClickExportRequest{Id: shortURL.ID}, stream))
+		suite.Require().Len(stream.sent, 2)
+		suite.Equal(shortURL.ID, stream.sent[0].ShortUrlId)
+		suite.Equal(day.Add(time.Hour), stream.sent[0].Time.AsTime())
+		suite.Equal("t.co", stream.sent[0].RefererHost)
+		suite.Equal("ios", stream.sent[0].Device)
+		suite.Equal("203.0.113.0", stream.sent[0].Ip)
+		suite.Equal("DE", stream.sent[0].Country)
+		suite.Equal("a", stream.sent[0].Visitor)
+		suite.False(stream.sent[0].Bot)
+		suite.True(stream.sent[1].Bot)
+	})
+
+	suite.Run("should filter by period", func() {
+		stream := &clickStream{ctx: auth.ToContext(context.Background(), 1)}
+		suite.Require().NoError(suite.s.ExportClicks(&proto.ShortURLClickExportRequest{
+			Id:   shortURL.ID,
+			From: timestamppb.New(day.Add(24 * time.Hour)),
+			To:   timestamppb.New(day.Add(48 * time.Hour)),
+		}, stream))
+		suite.Require().Len(stream.sent, 1)
+		suite.Equal("b", stream.sent[0].Visitor)
+	})
+
+	suite.Run("should return error if not owner", func() {
+		stream := &clickStream{ctx: auth.ToContext(context.Background(), 2)}
+		err := suite.s.ExportClicks(&proto.ShortURLClickExportRequest{Id: shortURL.ID}, stream)
+		suite.Equal(codes.NotFound, status.Code(err))
+		suite.Empty(stream.sent)
+	})
+}
+
+func (suite *ShortURLServiceSuite) TestQuota() {
+	cfg, _ := config.Default(nil)
+	cfg.QuotaMaxActiveURLs = 2
+	cfg.QuotaMaxBatchSize = 2
+	s := NewShortURLService(usecases.NewContainer(context.Background(), cfg, suite.r))
+	ctx := auth.ToContext(context.Background(), 1)
+
+	suite.Run("should reject too large batch", func() {
+		items := []*proto.ShortURLCreateBatchRequest_Item{
+			{CorrelationId: "1", OriginalUrl: "https://quota.com/1"},
+			{CorrelationId: "2", OriginalUrl: "https://quota.com/2"},
+			{CorrelationId: "3", OriginalUrl: "https://quota.com/3"},
+		}
+		_, err := s.CreateBatch(ctx, &proto.ShortURLCreateBatchRequest{Items: items})
+		suite.Equal(codes.ResourceExhausted, status.Code(err))
+	})
+
+	suite.Run("should reject links over quota", func() {
+		items := []*proto.ShortURLCreateBatchRequest_Item{
+			{CorrelationId: "1", OriginalUrl: "https://quota.com/1"},
+			{CorrelationId: "2", OriginalUrl: "https://quota.com/2"},
+		}
+		_, err := s.CreateBatch(ctx, &proto.ShortURLCreateBatchRequest{Items: items})
+		suite.Require().NoError(err)
+		_, err = s.Create(ctx, &proto.ShortURLCreateRequest{Url: "https://quota.com/3"})
+		suite.Equal(codes.ResourceExhausted, status.Code(err))
+	})
+
+	suite.Run("should return quota usage", func() {
+		res, err := s.GetQuota(ctx, &proto.ShortURLQuotaRequest{})
+		suite.Require().NoError(err)
+		suite.Equal(uint32(2), res.MaxActiveUrls)
+		suite.Equal(uint32(2), res.MaxBatchSize)
+		suite.Zero(res.UrlsPerDay)
+		suite.Equal(uint32(2), res.ActiveUrls)
+		suite.Equal(uint32(2), res.UrlsLastDay)
+		_, err = s.GetQuota(context.Background(), &proto.ShortURLQuotaRequest{})
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+	})
+}
+
 func TestShortURLServiceSuite(t *testing.T) {
 	suite.Run(t, new(ShortURLServiceSuite))
 }
+
+// clickStream - тестовый поток выгрузки событий переходов
+type clickStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*proto.ShortURLClick
+}
+
+func (s *clickStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *clickStream) Send(click *proto.ShortURLClick) error {
+	s.sent = append(s.sent, click)
+	return nil
+}
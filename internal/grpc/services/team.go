@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ofstudio/go-shortener/api/proto"
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/providers/auth"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+// TeamMethods - требования методов сервиса Teams к аутентификации.
+// Методы сервиса не указаны в ShortURLScopes, поэтому недоступны по API-ключу.
+var TeamMethods = auth.Methods{
+	"/proto.Teams/Create":       auth.Required,
+	"/proto.Teams/List":         auth.Required,
+	"/proto.Teams/Members":      auth.Required,
+	"/proto.Teams/SetMember":    auth.Required,
+	"/proto.Teams/RemoveMember": auth.Required,
+}
+
+// TeamService - реализация gRPC сервиса для управления командами пользователя.
+type TeamService struct {
+	proto.UnimplementedTeamsServer
+	u *usecases.Container
+}
+
+// NewTeamService - конструктор TeamService.
+func NewTeamService(u *usecases.Container) *TeamService {
+	return &TeamService{u: u}
+}
+
+// Create - создание команды. Текущий пользователь становится ее владельцем.
+func (s TeamService) Create(ctx context.Context, request *proto.TeamCreateRequest) (*proto.Team, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+
+	// Создаем команду
+	team, err := s.u.Team.Create(ctx, userID, request.Name)
+	if err != nil {
+		return nil, Error(err)
+	}
+	return teamToProto(&models.TeamMembership{Team: *team, Role: models.TeamRoleOwner}), nil
+}
+
+// List - получение команд пользователя и его ролей в них.
+func (s TeamService) List(ctx context.Context, _ *proto.TeamListRequest) (*proto.TeamListResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+
+	// Получаем команды
+	teams, err := s.u.Team.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, Error(err)
+	}
+	res := &proto.TeamListResponse{Teams: make([]*proto.Team, 0, len(teams))}
+	for i := range teams {
+		res.Teams = append(res.Teams, teamToProto(&teams[i]))
+	}
+	return res, nil
+}
+
+// Members - получение участников команды. Доступно любому участнику команды.
+func (s TeamService) Members(ctx context.Context, request *proto.TeamMembersRequest) (*proto.TeamMembersResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+
+	// Получаем участников
+	members, err := s.u.Team.Members(ctx, userID, uint(request.TeamId))
+	if err != nil {
+		return nil, Error(err)
+	}
+	res := &proto.TeamMembersResponse{Members: make([]*proto.TeamMember, 0, len(members))}
+	for i := range members {
+		res.Members = append(res.Members, teamMemberToProto(&members[i]))
+	}
+	return res, nil
+}
+
+// SetMember - добавление участника команды или изменение его роли. Доступно только владельцам команды.
+func (s TeamService) SetMember(ctx context.Context, request *proto.TeamSetMemberRequest) (*proto.TeamMember, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+
+	// Добавляем участника
+	member, err := s.u.Team.SetMember(ctx, userID, uint(request.TeamId), request.Email, models.TeamRole(request.Role))
+	if err != nil {
+		return nil, Error(err)
+	}
+	return teamMemberToProto(member), nil
+}
+
+// RemoveMember - удаление участника команды.
+// Владелец может удалить любого участника, остальные участники - только себя.
+func (s TeamService) RemoveMember(ctx context.Context, request *proto.TeamRemoveMemberRequest) (*proto.TeamRemoveMemberResponse, error) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, Error(pkgerrors.ErrAuth)
+	}
+
+	// Удаляем участника
+	if err := s.u.Team.RemoveMember(ctx, userID, uint(request.TeamId), uint(request.UserId)); err != nil {
+		return nil, Error(err)
+	}
+	return &proto.TeamRemoveMemberResponse{}, nil
+}
+
+// teamToProto - преобразует models.TeamMembership в proto.Team
+func teamToProto(team *models.TeamMembership) *proto.Team {
+	return &proto.Team{
+		Id:        uint32(team.ID),
+		Name:      team.Name,
+		CreatedAt: timestamppb.New(team.CreatedAt),
+		Role:      string(team.Role),
+	}
+}
+
+// teamMemberToProto - преобразует models.TeamMember в proto.TeamMember
+func teamMemberToProto(member *models.TeamMember) *proto.TeamMember {
+	return &proto.TeamMember{
+		TeamId: uint32(member.TeamID),
+		UserId: uint32(member.UserID),
+		Role:   string(member.Role),
+	}
+}
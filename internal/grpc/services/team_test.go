@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ofstudio/go-shortener/api/proto"
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/providers/auth"
+	"github.com/ofstudio/go-shortener/internal/repo"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+type TeamServiceSuite struct {
+	suite.Suite
+	u *usecases.Container
+	s *TeamService
+	l *ShortURLService
+}
+
+func TestTeamServiceSuite(t *testing.T) {
+	suite.Run(t, new(TeamServiceSuite))
+}
+
+func (suite *TeamServiceSuite) SetupTest() {
+	cfg, _ := config.Default(nil)
+	suite.u = usecases.NewContainer(context.Background(), cfg, repo.NewMemoryRepo())
+	suite.s = NewTeamService(suite.u)
+	suite.l = NewShortURLService(suite.u)
+	for _, email := range []string{"owner@example.com", "viewer@example.com"} {
+		_, err := suite.u.User.Register(context.Background(), 0, email, "password")
+		suite.Require().NoError(err)
+	}
+}
+
+func (suite *TeamServiceSuite) TestTeams() {
+	suite.Run("unauthenticated", func() {
+		_, err := suite.s.List(context.Background(), &proto.TeamListRequest{})
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	owner, viewer := auth.ToContext(context.Background(), 1), auth.ToContext(context.Background(), 2)
+	team, err := suite.s.Create(owner, &proto.TeamCreateRequest{Name: "Marketing"})
+	suite.Require().NoError(err)
+	suite.Equal(string(models.TeamRoleOwner), team.Role)
+	_, err = suite.s.Create(owner, &proto.TeamCreateRequest{})
+	suite.Equal(codes.InvalidArgument, status.Code(err))
+
+	member, err := suite.s.SetMember(owner, &proto.TeamSetMemberRequest{TeamId: team.Id, Email: "viewer@example.com", Role: "viewer"})
+	suite.Require().NoError(err)
+	suite.Equal(uint32(2), member.UserId)
+	_, err = suite.s.SetMember(viewer, &proto.TeamSetMemberRequest{TeamId: team.Id, Email: "viewer@example.com", Role: "owner"})
+	suite.Equal(codes.PermissionDenied, status.Code(err))
+
+	members, err := suite.s.Members(viewer, &proto.TeamMembersRequest{TeamId: team.Id})
+	suite.Require().NoError(err)
+	suite.Len(members.Members, 2)
+	list, err := suite.s.List(viewer, &proto.TeamListRequest{})
+	suite.Require().NoError(err)
+	suite.Require().Len(list.Teams, 1)
+	suite.Equal(string(models.TeamRoleViewer), list.Teams[0].Role)
+
+	suite.Run("team links", func() {
+		_, err := suite.l.Create(viewer, &proto.ShortURLCreateRequest{Url: "https://example.com/viewer", TeamId: team.Id})
+		suite.Equal(codes.PermissionDenied, status.Code(err))
+		_, err = suite.l.Create(owner, &proto.ShortURLCreateRequest{Url: "https://example.com/team", TeamId: team.Id})
+		suite.Require().NoError(err)
+		res, err := suite.l.GetByTeamID(viewer, &proto.ShortURLGetByTeamIDRequest{TeamId: team.Id})
+		suite.Require().NoError(err)
+		suite.Require().Len(res.Items, 1)
+		suite.Equal(team.Id, res.Items[0].TeamId)
+		_, err = suite.l.GetByTeamID(auth.ToContext(context.Background(), 3), &proto.ShortURLGetByTeamIDRequest{TeamId: team.Id})
+		suite.Equal(codes.NotFound, status.Code(err))
+
+		shortURLs, err := suite.u.ShortURL.GetByTeamID(context.Background(), 1, uint(team.Id))
+		suite.Require().NoError(err)
+		suite.Require().Len(shortURLs, 1)
+		shortURL := shortURLs[0]
+		_, err = suite.l.SetTeam(viewer, &proto.ShortURLSetTeamRequest{Id: shortURL.ID})
+		suite.Equal(codes.PermissionDenied, status.Code(err))
+		_, err = suite.l.SetTeam(owner, &proto.ShortURLSetTeamRequest{Id: shortURL.ID})
+		suite.Require().NoError(err)
+		_, err = suite.l.GetByTeamID(viewer, &proto.ShortURLGetByTeamIDRequest{TeamId: team.Id})
+		suite.Equal(pkgerrors.GRPCNoContent, status.Code(err))
+	})
+
+	suite.Run("remove member", func() {
+		_, err := suite.s.RemoveMember(owner, &proto.TeamRemoveMemberRequest{TeamId: team.Id, UserId: 1})
+		suite.Equal(codes.InvalidArgument, status.Code(err))
+		_, err = suite.s.RemoveMember(viewer, &proto.TeamRemoveMemberRequest{TeamId: team.Id, UserId: 2})
+		suite.Require().NoError(err)
+		_, err = suite.s.Members(viewer, &proto.TeamMembersRequest{TeamId: team.Id})
+		suite.Equal(codes.NotFound, status.Code(err))
+	})
+}
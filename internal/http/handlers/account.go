@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/providers/auth"
+)
+
+// accountReqType - учетные данные пользователя в запросе
+type accountReqType struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// accountResType - зарегистрированный пользователь в ответе
+type accountResType struct {
+	ID    uint   `json:"id"`
+	Email string `json:"email"`
+}
+
+// accountRegister - регистрирует пользователя с email и паролем:
+//
+//	{"email": "user@example.com", "password": "<password>"}
+//
+// Пароль - от 8 символов до 72 байт. Email регистрируется в нижнем регистре.
+// Если запрос отправлен с токеном анонимного пользователя, то регистрируется он сам
+// и все его ссылки сохраняются за ним. Иначе создается новый пользователь.
+//
+// Возвращает ответ http.StatusCreated (201) и устанавливает новый токен в куку auth_token:
+//
+//	{"id": 42, "email": "user@example.com"}
+//
+// @Tags account
+// @Summary Регистрирует пользователя
+// @Security cookieAuth
+// @ID accountRegister
+// @Accept  json
+// @Produce json
+// @Param   request body handlers.accountReqType true "Запрос"
+// @Success 201 {object} handlers.accountResType
+// @Failure 400
+// @Failure 403
+// @Failure 409
+// @Failure 500
+// @Router /user/register [post]
+func (h APIHandlers) accountRegister(w http.ResponseWriter, r *http.Request) {
+	// Читаем body запроса
+	reqJSON := &accountReqType{}
+	if err := parseJSONRequest(r, reqJSON); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Регистрируем текущего анонимного пользователя, если он есть
+	claimID, _ := auth.FromContext(r.Context())
+	user, err := h.u.User.Register(r.Context(), claimID, reqJSON.Email, reqJSON.Password)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Выдаем токен и возвращаем ответ
+	h.respondWithAccount(w, r, http.StatusCreated, user)
+}
+
+// accountLogin - выполняет вход пользователя по email и паролю:
+//
+//	{"email": "user@example.com", "password": "<password>"}
+//
+// Возвращает ответ http.StatusOK (200) и устанавливает токен пользователя в куку auth_token:
+//
+//	{"id": 42, "email": "user@example.com"}
+//
+// @Tags account
+// @Summary Выполняет вход пользователя
+// @ID accountLogin
+// @Accept  json
+// @Produce json
+// @Param   request body handlers.accountReqType true "Запрос"
+// @Success 200 {object} handlers.accountResType
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 500
+// @Router /user/login [post]
+func (h APIHandlers) accountLogin(w http.ResponseWriter, r *http.Request) {
+	// Читаем body запроса
+	reqJSON := &accountReqType{}
+	if err := parseJSONRequest(r, reqJSON); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Проверяем учетные данные
+	user, err := h.u.User.Login(r.Context(), reqJSON.Email, reqJSON.Password)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Выдаем токен и возвращаем ответ
+	h.respondWithAccount(w, r, http.StatusOK, user)
+}
+
+// accountLogout - выполняет выход пользователя: удаляет куку auth_token.
+// С параметром all=true дополнительно отзывает все токены текущего пользователя:
+// выход выполняется на всех устройствах, в том числе из gRPC-клиентов.
+// Возвращает ответ http.StatusNoContent (204).
+//
+// @Tags account
+// @Summary Выполняет выход пользователя
+// @Security cookieAuth
+// @ID accountLogout
+// @Param   all query bool false "Отозвать все токены пользователя"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 500
+// @Router /user/logout [post]
+func (h APIHandlers) accountLogout(w http.ResponseWriter, r *http.Request) {
+	all, err := parseBoolParam(r.URL.Query().Get("all"))
+	if err != nil {
+		respondWithError(w, pkgerrors.ErrValidation)
+		return
+	}
+
+	// Отзываем все токены пользователя
+	if all {
+		userID, ok := auth.FromContext(r.Context())
+		if !ok {
+			respondWithError(w, pkgerrors.ErrAuth)
+			return
+		}
+		if _, err = h.u.User.RevokeTokens(r.Context(), userID); err != nil {
+			respondWithError(w, err)
+			return
+		}
+	}
+
+	auth.Logout(w, r)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondWithAccount - выдает токен пользователя и возвращает ответ с пользователем
+func (h APIHandlers) respondWithAccount(w http.ResponseWriter, r *http.Request, status int, user *models.User) {
+	if err := auth.Login(w, r, user.ID); err != nil {
+		log.Err(err).Msg("failed to issue auth token")
+		respondWithError(w, pkgerrors.ErrInternal)
+		return
+	}
+	respondWithJSON(w, status, accountResType{ID: user.ID, Email: user.Email})
+}
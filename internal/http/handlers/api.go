@@ -5,9 +5,13 @@ import (
 	"errors"
 	"mime"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
 
+	"github.com/ofstudio/go-shortener/internal/models"
 	"github.com/ofstudio/go-shortener/internal/pkgerrors"
 	"github.com/ofstudio/go-shortener/internal/providers/auth"
 	"github.com/ofstudio/go-shortener/internal/usecases"
@@ -24,6 +28,11 @@ import (
 // @In cookie
 // @Name auth_token
 
+// @securityDefinitions.apikey BearerAuth
+// @In header
+// @Name Authorization
+// @Description API-ключ в формате: Bearer shk_<id>_<secret>
+
 // APIHandlers - HTTP-хендлеры для JSON API
 type APIHandlers struct {
 	u *usecases.Container
@@ -34,13 +43,56 @@ func NewAPIHandlers(u *usecases.Container) *APIHandlers {
 	return &APIHandlers{u}
 }
 
-// PublicRoutes - возвращает роутер с хендлерами
+// PublicRoutes - возвращает роутер с хендлерами.
+// Требования маршрутов к аутентификации объявляются с помощью auth.Require,
+// а права доступа API-ключей - с помощью auth.RequireScope.
 func (h APIHandlers) PublicRoutes() chi.Router {
 	r := chi.NewRouter()
-	r.Post("/shorten", h.shortURLCreate)
-	r.Post("/shorten/batch", h.shortURLCreateBatch)
-	r.Get("/user/urls", h.shortURLGetByUserID)
-	r.Delete("/user/urls", h.shortURLDeleteBatch)
+	read := auth.RequireScope(models.APIScopeRead)
+	create := auth.RequireScope(models.APIScopeCreate)
+	remove := auth.RequireScope(models.APIScopeDelete)
+	// Создание, получение и удаление ссылок: при необходимости создается новый пользователь
+	r.Group(func(r chi.Router) {
+		r.Use(auth.Require(auth.CreateUser))
+		r.With(create).Post("/shorten", h.shortURLCreate)
+		r.With(create).Post("/shorten/batch", h.shortURLCreateBatch)
+		r.With(read).Get("/user/urls", h.shortURLGetByUserID)
+		r.With(remove).Delete("/user/urls", h.shortURLDeleteBatch)
+	})
+	// Работа с существующими ссылками: требуется валидный токен
+	r.Group(func(r chi.Router) {
+		r.Use(auth.Require(auth.Required))
+		r.With(read).Get("/user/urls/{id}/rules", h.shortURLGetRules)
+		r.With(create).Put("/user/urls/{id}/rules", h.shortURLSetRules)
+		r.With(create).Put("/user/urls/{id}/query-template", h.shortURLSetQueryTemplate)
+		r.With(create).Put("/user/urls/{id}/preview", h.shortURLSetPreview)
+		r.With(create).Put("/user/urls/{id}/active-window", h.shortURLSetActiveWindow)
+		r.With(read).Get("/user/urls/{id}/variants", h.shortURLVariantStats)
+		r.With(read).Get("/user/urls/{id}/stats", h.shortURLStats)
+		r.With(read).Get("/user/urls/{id}/clicks/export", h.shortURLClicksExport)
+		r.With(create).Put("/user/urls/{id}/team", h.shortURLSetTeam)
+		r.With(read).Get("/user/teams/{id}/urls", h.teamShortURLs)
+		r.With(read).Get("/user/quota", h.quotaGet)
+	})
+	// Регистрация, вход и выход пользователя: API-ключи не принимаются
+	r.Group(func(r chi.Router) {
+		r.Use(auth.NoAPIKey)
+		r.Post("/user/register", h.accountRegister)
+		r.Post("/user/login", h.accountLogin)
+		r.Post("/user/logout", h.accountLogout)
+	})
+	// Управление API-ключами и командами: требуется токен пользователя, API-ключи не принимаются
+	r.Group(func(r chi.Router) {
+		r.Use(auth.Require(auth.Required), auth.NoAPIKey)
+		r.Post("/user/keys", h.apiKeyCreate)
+		r.Get("/user/keys", h.apiKeyList)
+		r.Delete("/user/keys/{id}", h.apiKeyRevoke)
+		r.Post("/user/teams", h.teamCreate)
+		r.Get("/user/teams", h.teamList)
+		r.Get("/user/teams/{id}/members", h.teamMembers)
+		r.Put("/user/teams/{id}/members", h.teamSetMember)
+		r.Delete("/user/teams/{id}/members/{user_id}", h.teamRemoveMember)
+	})
 	return r
 }
 
@@ -48,6 +100,8 @@ func (h APIHandlers) PublicRoutes() chi.Router {
 func (h APIHandlers) InternalRoutes() chi.Router {
 	r := chi.NewRouter()
 	r.Get("/stats", h.stats)
+	r.Post("/users/{id}/revoke", h.userRevokeTokens)
+	r.Put("/users/{id}/plan", h.userSetPlan)
 	return r
 }
 
@@ -55,13 +109,63 @@ func (h APIHandlers) InternalRoutes() chi.Router {
 //
 //	{"url":"<url>"}
 //
+// Опционально можно передать шаблон query-параметров, которые будут добавлены к URL при переходе:
+//
+//	{
+//	    "url": "<url>",
+//	    "query_template": {
+//	        "params": {"utm_source": "{referrer_host}", "utm_content": "{id}"},
+//	        "policy": "keep"
+//	    }
+//	}
+//
+// Доступные плейсхолдеры: {id}, {referrer_host}, {date}.
+// Политики слияния с параметрами URL: keep (по умолчанию), override, append.
+//
+// Опционально можно создать сплит-ссылку, переходы по которой распределяются между вариантами по их весам:
+//
+//	{
+//	    "url": "<url>",
+//	    "split": {
+//	        "variants": [
+//	            {"url": "https://example.com/a", "weight": 70},
+//	            {"url": "https://example.com/b", "weight": 30}
+//	        ],
+//	        "sticky": true
+//	    }
+//	}
+//
+// Если задан sticky, то выбранный вариант закрепляется за посетителем с помощью cookie.
+//
+// Также можно задать заголовок ссылки для страницы предпросмотра (title)
+// и включить показ страницы-предупреждения перед переходом (interstitial):
+//
+//	{"url": "<url>", "title": "Отчет за квартал", "interstitial": true}
+//
+// Период действия ссылки задается полями active_from и active_until (RFC 3339), любое из них необязательно:
+//
+//	{"url": "<url>", "active_from": "2023-03-08T09:00:00+03:00", "active_until": "2023-03-31T00:00:00+03:00"}
+//
+// До начала периода ссылка возвращает 404 (или страницу-заглушку), после окончания - 410.
+//
+// Ссылку можно сразу передать команде, в которой пользователь является владельцем или редактором:
+//
+//	{"url": "<url>", "team_id": 1}
+//
 // Возвращает ответ http.StatusCreated (201) и сокращенный URL в виде JSON:
 //
 //	{"result":"<shorten_url>"}
 //
+// Если у пользователя уже есть простая ссылка на этот URL, возвращает http.StatusConflict (409) и существующий
+// сокращенный URL. Ссылки с шаблоном query-параметров, заголовком, страницей-предупреждения или периодом действия,
+// сплит-ссылки и ссылки команды всегда создаются новыми.
+//
+// Если создание ссылки превысит квоты пользователя (см. quotaGet), возвращает http.StatusTooManyRequests (429).
+//
 // @Tags shorten
 // @Summary Создает сокращенную ссылку
 // @Security cookieAuth
+// @Security BearerAuth
 // @ID shortURLCreate
 // @Accept  json
 // @Produce json
@@ -69,14 +173,24 @@ func (h APIHandlers) InternalRoutes() chi.Router {
 // @Success 201 {object} handlers.shortURLCreate.resType
 // @Failure 400
 // @Failure 401
+// @Failure 403
+// @Failure 404
 // @Failure 409 {object} handlers.shortURLCreate.resType
 // @Failure 410
+// @Failure 429
 // @Failure 500
 // @Router /shorten [post]
 func (h APIHandlers) shortURLCreate(w http.ResponseWriter, r *http.Request) {
 	// Структура запроса
 	type reqType struct {
-		URL string `json:"url"`
+		URL           string                `json:"url"`
+		Title         string                `json:"title,omitempty"`
+		Interstitial  bool                  `json:"interstitial,omitempty"`
+		ActiveFrom    *time.Time            `json:"active_from,omitempty"`
+		ActiveUntil   *time.Time            `json:"active_until,omitempty"`
+		QueryTemplate *models.QueryTemplate `json:"query_template,omitempty"`
+		Split         *models.Split         `json:"split,omitempty"`
+		TeamID        uint                  `json:"team_id,omitempty"`
 	}
 	// Структура ответа
 	type resType struct {
@@ -99,7 +213,13 @@ func (h APIHandlers) shortURLCreate(w http.ResponseWriter, r *http.Request) {
 
 	// Создаем сокращенную ссылку
 	statusCode := http.StatusCreated
-	shortURL, err := h.u.ShortURL.Create(r.Context(), userID, reqJSON.URL)
+	shortURL, err := h.u.ShortURL.Create(r.Context(), userID, reqJSON.URL,
+		usecases.WithTitle(reqJSON.Title),
+		usecases.WithInterstitial(reqJSON.Interstitial),
+		usecases.WithActiveWindow(reqJSON.ActiveFrom, reqJSON.ActiveUntil),
+		usecases.WithQueryTemplate(reqJSON.QueryTemplate),
+		usecases.WithSplit(reqJSON.Split),
+		usecases.WithTeam(reqJSON.TeamID))
 
 	if err != nil && !errors.Is(err, pkgerrors.ErrDuplicate) {
 		respondWithError(w, err)
@@ -122,7 +242,8 @@ func (h APIHandlers) shortURLCreate(w http.ResponseWriter, r *http.Request) {
 //	[
 //	    {
 //	        "correlation_id": "<строковый идентификатор>",
-//	        "original_url": "<URL для сокращения>"
+//	        "original_url": "<URL для сокращения>",
+//	        "query_template": {...} // необязательно, см. shortURLCreate
 //	    },
 //	    ...
 //	]
@@ -137,9 +258,13 @@ func (h APIHandlers) shortURLCreate(w http.ResponseWriter, r *http.Request) {
 //	    ...
 //	]
 //
+// Размер пакета и квоты пользователя проверяются до создания ссылок: если они будут превышены,
+// ни одна ссылка не создается и возвращается http.StatusTooManyRequests (429).
+//
 // @Tags shorten
 // @Summary Создает несколько сокращенных ссылок
 // @Security cookieAuth
+// @Security BearerAuth
 // @ID shortURLCreateBatch
 // @Accept  json
 // @Produce json
@@ -148,13 +273,15 @@ func (h APIHandlers) shortURLCreate(w http.ResponseWriter, r *http.Request) {
 // @Failure 400 {string} Bad Request
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 410 {string} string "Gone"
+// @Failure 429 {string} string "Too Many Requests"
 // @Failure 500 {string} string "Internal server Error"
 // @Router /shorten/batch [post]
 func (h APIHandlers) shortURLCreateBatch(w http.ResponseWriter, r *http.Request) {
 	// Структура элемента запроса
 	type reqType struct {
-		CorrelationID string `json:"correlation_id"`
-		OriginalURL   string `json:"original_url"`
+		CorrelationID string                `json:"correlation_id"`
+		OriginalURL   string                `json:"original_url"`
+		QueryTemplate *models.QueryTemplate `json:"query_template,omitempty"`
 	}
 	// Структура элемента ответа
 	type resType struct {
@@ -176,17 +303,24 @@ func (h APIHandlers) shortURLCreateBatch(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Создаем сокращенные ссылки
-	resJSON := make([]resType, len(reqJSON))
+	// Создаем сокращенные ссылки: размер пакета и квоты пользователя проверяются для всего пакета
+	items := make([]usecases.BatchItem, len(reqJSON))
 	for i, item := range reqJSON {
-		shortURL, err := h.u.ShortURL.Create(r.Context(), userID, item.OriginalURL)
-		if err != nil && !errors.Is(err, pkgerrors.ErrDuplicate) {
-			respondWithError(w, err)
-			return
+		items[i] = usecases.BatchItem{
+			OriginalURL: item.OriginalURL,
+			Opts:        []usecases.CreateOpt{usecases.WithQueryTemplate(item.QueryTemplate)},
 		}
+	}
+	shortURLs, err := h.u.ShortURL.CreateBatch(r.Context(), userID, items)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	resJSON := make([]resType, len(reqJSON))
+	for i, item := range reqJSON {
 		resJSON[i] = resType{
 			CorrelationID: item.CorrelationID,
-			ShortURL:      h.u.ShortURL.Resolve(shortURL.ID),
+			ShortURL:      h.u.ShortURL.Resolve(shortURLs[i].ID),
 		}
 	}
 
@@ -204,6 +338,7 @@ func (h APIHandlers) shortURLCreateBatch(w http.ResponseWriter, r *http.Request)
 // @Tags user
 // @Summary Удаляет несколько сокращенных ссылок
 // @Security cookieAuth
+// @Security BearerAuth
 // @ID shortURLDeleteBatch
 // @Accept  json
 // @Produce json
@@ -248,28 +383,34 @@ func (h APIHandlers) shortURLDeleteBatch(w http.ResponseWriter, r *http.Request)
 //	[
 //	    {
 //	        "short_url": "http://...",
-//	        "original_url": "http://..."
+//	        "original_url": "http://...",  // в каноническом виде
+//	        "submitted_url": "http://...", // в том виде, в котором URL был передан
+//	        "title": "...",          // если задан
+//	        "created_at": "2023-03-08T10:30:00Z",
+//	        "interstitial": true,    // если включено
+//	        "active_from": "...",    // если задано
+//	        "active_until": "...",   // если задано
+//	        "query_template": {...}, // если задан
+//	        "split": {...},          // если задан
+//	        "team_id": 1             // если ссылка принадлежит команде
 //	    },
 //	    ...
 //	]
 //
+// Ссылки команд, из которых пользователь вышел, не возвращаются.
+//
 // @Tags user
 // @Summary Возвращает список сокращенных ссылок пользователя
 // @Security cookieAuth
+// @Security BearerAuth
 // @ID shortURLGetByUserID
 // @Produce json
-// @Success 200 {array} handlers.shortURLGetByUserID.resType
+// @Success 200 {array} handlers.shortURLResType
 // @Failure 400
 // @Failure 401
 // @Failure 500
 // @Router /user/urls [get]
 func (h APIHandlers) shortURLGetByUserID(w http.ResponseWriter, r *http.Request) {
-	// Структура ответа
-	type resType struct {
-		ShortURL    string `json:"short_url"`
-		OriginalURL string `json:"original_url"`
-	}
-
 	// Проверяем аутентифицирован ли пользователь
 	userID, ok := auth.FromContext(r.Context())
 	if !ok {
@@ -284,23 +425,547 @@ func (h APIHandlers) shortURLGetByUserID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Если пользователь не имеет сокращенных ссылок, возвращаем 204 No Content
+	// Возвращаем ответ
+	h.respondWithShortURLs(w, shortURLs)
+}
+
+// shortURLResType - сокращенная ссылка в списке ссылок
+type shortURLResType struct {
+	ShortURL      string                `json:"short_url"`
+	OriginalURL   string                `json:"original_url"`
+	SubmittedURL  string                `json:"submitted_url,omitempty"`
+	Title         string                `json:"title,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+	Interstitial  bool                  `json:"interstitial,omitempty"`
+	ActiveFrom    *time.Time            `json:"active_from,omitempty"`
+	ActiveUntil   *time.Time            `json:"active_until,omitempty"`
+	QueryTemplate *models.QueryTemplate `json:"query_template,omitempty"`
+	Split         *models.Split         `json:"split,omitempty"`
+	TeamID        uint                  `json:"team_id,omitempty"`
+}
+
+// respondWithShortURLs - возвращает список сокращенных ссылок.
+// Если список пуст, возвращает http.StatusNoContent (204).
+func (h APIHandlers) respondWithShortURLs(w http.ResponseWriter, shortURLs []models.ShortURL) {
 	if len(shortURLs) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
-
-	// Формируем ответ
-	res := make([]resType, len(shortURLs))
+	res := make([]shortURLResType, len(shortURLs))
 	for i := range shortURLs {
-		res[i] = resType{
-			ShortURL:    h.u.ShortURL.Resolve(shortURLs[i].ID),
-			OriginalURL: shortURLs[i].OriginalURL,
+		res[i] = shortURLResType{
+			ShortURL:      h.u.ShortURL.Resolve(shortURLs[i].ID),
+			OriginalURL:   shortURLs[i].OriginalURL,
+			SubmittedURL:  shortURLs[i].SubmittedURL,
+			Title:         shortURLs[i].Title,
+			CreatedAt:     shortURLs[i].CreatedAt,
+			Interstitial:  shortURLs[i].Interstitial,
+			ActiveFrom:    shortURLs[i].ActiveFrom,
+			ActiveUntil:   shortURLs[i].ActiveUntil,
+			QueryTemplate: shortURLs[i].QueryTemplate,
+			Split:         shortURLs[i].Split,
+			TeamID:        shortURLs[i].TeamID,
 		}
 	}
+	respondWithJSON(w, http.StatusOK, res)
+}
+
+// shortURLGetRules - возвращает список правил условного перенаправления ссылки пользователя.
+// Формат ответа:
+//
+//	[
+//	    {
+//	        "url": "https://apps.apple.com/...",
+//	        "devices": ["ios"]
+//	    },
+//	    ...
+//	]
+//
+// @Tags user
+// @Summary Возвращает правила перенаправления сокращенной ссылки
+// @Security cookieAuth
+// @Security BearerAuth
+// @ID shortURLGetRules
+// @Produce json
+// @Param   id path string true "Идентификатор сокращенной ссылки"
+// @Success 200 {array} models.RedirectRule
+// @Failure 401
+// @Failure 404
+// @Failure 410
+// @Failure 500
+// @Router /user/urls/{id}/rules [get]
+func (h APIHandlers) shortURLGetRules(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Получаем ссылку пользователя
+	shortURL, err := h.u.ShortURL.GetOwned(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
 
 	// Возвращаем ответ
-	respondWithJSON(w, http.StatusOK, res)
+	rules := shortURL.Rules
+	if rules == nil {
+		rules = []models.RedirectRule{}
+	}
+	respondWithJSON(w, http.StatusOK, rules)
+}
+
+// shortURLSetRules - заменяет список правил условного перенаправления ссылки пользователя.
+// Правила проверяются по порядку, срабатывает первое правило, все условия которого выполнены.
+// Если ни одно правило не сработало, то посетитель перенаправляется на оригинальный URL.
+// Формат запроса:
+//
+//	[
+//	    {"url": "https://apps.apple.com/...", "devices": ["ios"]},
+//	    {"url": "https://play.google.com/...", "devices": ["android"]},
+//	    {"url": "https://example.com/ru/", "languages": ["ru"]},
+//	    {
+//	        "url": "https://example.com/office-hours/",
+//	        "schedule": {
+//	            "weekdays": ["mon", "tue", "wed", "thu", "fri"],
+//	            "from": "09:00",
+//	            "until": "18:00",
+//	            "time_zone": "Europe/Moscow"
+//	        }
+//	    }
+//	]
+//
+// Пустой список удаляет все правила. Если у пользователя уже есть ссылка без правил с тем же URL,
+// то правила не удаляются и возвращается ответ http.StatusConflict (409).
+// Возвращает ответ http.StatusOK (200) и сохраненный список правил.
+//
+// @Tags user
+// @Summary Заменяет правила перенаправления сокращенной ссылки
+// @Security cookieAuth
+// @Security BearerAuth
+// @ID shortURLSetRules
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Идентификатор сокращенной ссылки"
+// @Param   request body []models.RedirectRule true "Запрос"
+// @Success 200 {array} models.RedirectRule
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Failure 410
+// @Failure 500
+// @Router /user/urls/{id}/rules [put]
+func (h APIHandlers) shortURLSetRules(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Читаем body запроса
+	reqJSON := make([]models.RedirectRule, 0)
+	if err := parseJSONRequest(r, &reqJSON); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Сохраняем правила
+	shortURL, err := h.u.ShortURL.SetRules(r.Context(), userID, chi.URLParam(r, "id"), reqJSON)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Возвращаем ответ
+	rules := shortURL.Rules
+	if rules == nil {
+		rules = []models.RedirectRule{}
+	}
+	respondWithJSON(w, http.StatusOK, rules)
+}
+
+// shortURLSetQueryTemplate - заменяет шаблон query-параметров ссылки пользователя. Формат запроса:
+//
+//	{"params": {"utm_source": "{referrer_host}", "utm_content": "{id}"}, "policy": "override"}
+//
+// Запрос null удаляет шаблон. Если у пользователя уже есть ссылка без шаблона и собственной маршрутизации
+// с тем же URL, то шаблон не удаляется и возвращается ответ http.StatusConflict (409).
+// Возвращает ответ http.StatusOK (200) и сохраненный шаблон.
+//
+// @Tags user
+// @Summary Заменяет шаблон query-параметров сокращенной ссылки
+// @Security cookieAuth
+// @Security BearerAuth
+// @ID shortURLSetQueryTemplate
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Идентификатор сокращенной ссылки"
+// @Param   request body models.QueryTemplate true "Запрос"
+// @Success 200 {object} models.QueryTemplate
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Failure 410
+// @Failure 500
+// @Router /user/urls/{id}/query-template [put]
+func (h APIHandlers) shortURLSetQueryTemplate(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Читаем body запроса
+	var reqJSON *models.QueryTemplate
+	if err := parseJSONRequest(r, &reqJSON); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Сохраняем шаблон
+	shortURL, err := h.u.ShortURL.SetQueryTemplate(r.Context(), userID, chi.URLParam(r, "id"), reqJSON)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Возвращаем ответ
+	respondWithJSON(w, http.StatusOK, shortURL.QueryTemplate)
+}
+
+// shortURLSetPreview - задает заголовок ссылки пользователя для страницы предпросмотра
+// и показ страницы-предупреждения перед переходом. Формат запроса:
+//
+//	{"title": "Отчет за квартал", "interstitial": true}
+//
+// Пустой заголовок удаляет заголовок ссылки. Если у пользователя уже есть ссылка без заголовка,
+// страницы-предупреждения и собственной маршрутизации с тем же URL, то возвращается ответ http.StatusConflict (409).
+// Возвращает ответ http.StatusOK (200) и сохраненные значения.
+//
+// @Tags user
+// @Summary Задает заголовок и страницу-предупреждение сокращенной ссылки
+// @Security cookieAuth
+// @Security BearerAuth
+// @ID shortURLSetPreview
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Идентификатор сокращенной ссылки"
+// @Param   request body handlers.shortURLSetPreview.preview true "Запрос"
+// @Success 200 {object} handlers.shortURLSetPreview.preview
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Failure 410
+// @Failure 500
+// @Router /user/urls/{id}/preview [put]
+func (h APIHandlers) shortURLSetPreview(w http.ResponseWriter, r *http.Request) {
+	// Структура запроса и ответа
+	type preview struct {
+		Title        string `json:"title"`
+		Interstitial bool   `json:"interstitial"`
+	}
+
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Читаем body запроса
+	reqJSON := preview{}
+	if err := parseJSONRequest(r, &reqJSON); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Сохраняем заголовок и страницу-предупреждение
+	shortURL, err := h.u.ShortURL.SetPreview(r.Context(), userID, chi.URLParam(r, "id"), reqJSON.Title, reqJSON.Interstitial)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Возвращаем ответ
+	respondWithJSON(w, http.StatusOK, preview{Title: shortURL.Title, Interstitial: shortURL.Interstitial})
+}
+
+// shortURLSetActiveWindow - задает период действия ссылки пользователя. Формат запроса:
+//
+//	{"active_from": "2023-03-08T09:00:00+03:00", "active_until": "2023-03-31T00:00:00+03:00"}
+//
+// Любое из полей необязательно, незаданное поле снимает соответствующее ограничение.
+// Если у пользователя уже есть ссылка без периода действия и других дополнительных параметров с тем же URL,
+// то возвращается ответ http.StatusConflict (409).
+// Возвращает ответ http.StatusOK (200) и сохраненный период действия.
+//
+// @Tags user
+// @Summary Задает период действия сокращенной ссылки
+// @Security cookieAuth
+// @Security BearerAuth
+// @ID shortURLSetActiveWindow
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Идентификатор сокращенной ссылки"
+// @Param   request body handlers.shortURLSetActiveWindow.activeWindow true "Запрос"
+// @Success 200 {object} handlers.shortURLSetActiveWindow.activeWindow
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 409
+// @Failure 410
+// @Failure 500
+// @Router /user/urls/{id}/active-window [put]
+func (h APIHandlers) shortURLSetActiveWindow(w http.ResponseWriter, r *http.Request) {
+	// Структура запроса и ответа
+	type activeWindow struct {
+		ActiveFrom  *time.Time `json:"active_from,omitempty"`
+		ActiveUntil *time.Time `json:"active_until,omitempty"`
+	}
+
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Читаем body запроса
+	reqJSON := activeWindow{}
+	if err := parseJSONRequest(r, &reqJSON); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Сохраняем период действия
+	shortURL, err := h.u.ShortURL.SetActiveWindow(r.Context(), userID, chi.URLParam(r, "id"), reqJSON.ActiveFrom, reqJSON.ActiveUntil)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Возвращаем ответ
+	respondWithJSON(w, http.StatusOK, activeWindow{ActiveFrom: shortURL.ActiveFrom, ActiveUntil: shortURL.ActiveUntil})
+}
+
+// shortURLVariantStats - возвращает статистику переходов по вариантам сплит-ссылки пользователя.
+// Формат ответа:
+//
+//	[
+//	    {"variant": 1, "url": "https://example.com/a", "weight": 70, "clicks": 712},
+//	    {"variant": 2, "url": "https://example.com/b", "weight": 30, "clicks": 288}
+//	]
+//
+// Если ссылка не является сплит-ссылкой, возвращает пустой список.
+//
+// @Tags user
+// @Summary Возвращает статистику переходов по вариантам сплит-ссылки
+// @Security cookieAuth
+// @Security BearerAuth
+// @ID shortURLVariantStats
+// @Produce json
+// @Param   id path string true "Идентификатор сокращенной ссылки"
+// @Success 200 {array} models.VariantStat
+// @Failure 401
+// @Failure 404
+// @Failure 410
+// @Failure 500
+// @Router /user/urls/{id}/variants [get]
+func (h APIHandlers) shortURLVariantStats(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Получаем статистику
+	stats, err := h.u.ShortURL.VariantStats(r.Context(), userID, chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Возвращаем ответ
+	if stats == nil {
+		stats = []models.VariantStat{}
+	}
+	respondWithJSON(w, http.StatusOK, stats)
+}
+
+// shortURLStats - возвращает статистику переходов по ссылке пользователя за период.
+// Параметры запроса (все необязательные):
+//
+//	from     - начало периода в формате RFC 3339
+//	until    - конец периода в формате RFC 3339 (по умолчанию - текущее время)
+//	interval - интервал агрегации: hour или day (по умолчанию)
+//	bots     - учитывать переходы ботов: true или false (по умолчанию)
+//
+// Формат ответа:
+//
+//	{
+//	    "from": "2022-12-01T00:00:00Z",
+//	    "until": "2022-12-03T00:00:00Z",
+//	    "interval": "day",
+//	    "total": 15,
+//	    "unique": 7,
+//	    "bots": 3,
+//	    "include_bots": false,
+//	    "buckets": [
+//	        {"time": "2022-12-01T00:00:00Z", "total": 10, "unique": 5},
+//	        {"time": "2022-12-02T00:00:00Z", "total": 5, "unique": 3}
+//	    ],
+//	    "referrers": [{"key": "news.example.com", "clicks": 9}],
+//	    "countries": [{"key": "DE", "clicks": 4}],
+//	    "devices": [{"key": "ios", "clicks": 8}, {"key": "desktop", "clicks": 7}]
+//	}
+//
+// @Tags user
+// @Summary Возвращает статистику переходов по ссылке
+// @Security cookieAuth
+// @Security BearerAuth
+// @ID shortURLStats
+// @Produce json
+// @Param   id       path  string true  "Идентификатор сокращенной ссылки"
+// @Param   from     query string false "Начало периода (RFC 3339)"
+// @Param   until    query string false "Конец периода (RFC 3339)"
+// @Param   interval query string false "Интервал агрегации" Enums(hour, day)
+// @Param   bots     query bool   false "Учитывать переходы ботов"
+// @Success 200 {object} models.LinkStats
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 410
+// @Failure 500
+// @Router /user/urls/{id}/stats [get]
+func (h APIHandlers) shortURLStats(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Разбираем параметры запроса
+	query := r.URL.Query()
+	from, err1 := parseTimeParam(query.Get("from"))
+	until, err2 := parseTimeParam(query.Get("until"))
+	includeBots, err3 := parseBoolParam(query.Get("bots"))
+	if err1 != nil || err2 != nil || err3 != nil {
+		respondWithError(w, pkgerrors.ErrValidation)
+		return
+	}
+
+	// Получаем статистику
+	stats, err := h.u.ShortURL.Stats(r.Context(), userID, chi.URLParam(r, "id"),
+		from, until, models.StatsInterval(query.Get("interval")), includeBots)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, stats)
+}
+
+// shortURLClicksExport - выгружает события переходов по ссылке пользователя за период, включая переходы ботов.
+// События передаются потоком по мере чтения из хранилища и не загружаются в память целиком.
+// Параметры запроса:
+//
+//	format - формат выгрузки: csv (по умолчанию) или jsonl
+//	from   - начало периода в формате RFC 3339 (необязательный)
+//	to     - конец периода в формате RFC 3339 (необязательный)
+//
+// Формат CSV (с заголовком):
+//
+//	short_url_id,time,referer_host,device,ip,country,visitor,bot
+//	xyz,2022-12-01T10:00:00Z,news.example.com,ios,203.0.113.0,DE,3f2a9c0d1e4b5a68,false
+//
+// Формат JSON Lines (одно событие в строке):
+//
+//	{"short_url_id":"xyz","time":"2022-12-01T10:00:00Z","referer_host":"news.example.com","device":"ios",...}
+//
+// Ответ сжимается middleware.Compressor, если клиент поддерживает gzip.
+//
+// @Tags user
+// @Summary Выгружает события переходов по ссылке
+// @Security cookieAuth
+// @Security BearerAuth
+// @ID shortURLClicksExport
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param   id     path  string true  "Идентификатор сокращенной ссылки"
+// @Param   format query string false "Формат выгрузки" Enums(csv, jsonl)
+// @Param   from   query string false "Начало периода (RFC 3339)"
+// @Param   to     query string false "Конец периода (RFC 3339)"
+// @Success 200 {string} string
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 410
+// @Failure 500
+// @Router /user/urls/{id}/clicks/export [get]
+func (h APIHandlers) shortURLClicksExport(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Разбираем параметры запроса
+	id := chi.URLParam(r, "id")
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+	enc, ok := newClickEncoder(w, format)
+	from, err1 := parseTimeParam(query.Get("from"))
+	until, err2 := parseTimeParam(query.Get("to"))
+	if !ok || err1 != nil || err2 != nil {
+		respondWithError(w, pkgerrors.ErrValidation)
+		return
+	}
+
+	// Заголовки ответа устанавливаются перед первым событием:
+	// до этого момента еще можно вернуть ошибку с соответствующим кодом
+	exported := 0
+	setHeaders := func() {
+		w.Header().Set("Content-Type", exportContentTypes[format])
+		w.Header().Set("Content-Disposition", `attachment; filename="`+id+`-clicks.`+format+`"`)
+	}
+	err := h.u.ShortURL.ExportClicks(r.Context(), userID, id, from, until, func(click models.Click) error {
+		if exported == 0 {
+			setHeaders()
+		}
+		exported++
+		return enc.Encode(click)
+	})
+	if err != nil && exported == 0 {
+		respondWithError(w, err)
+		return
+	}
+	if err != nil {
+		// Часть ответа уже отправлена: прерываем выгрузку
+		log.Err(err).Str("id", id).Int("exported", exported).Msg("failed to export clicks")
+		return
+	}
+	if exported == 0 {
+		setHeaders()
+	}
+	if err = enc.Flush(); err != nil {
+		log.Err(err).Str("id", id).Msg("failed to flush clicks export")
+	}
 }
 
 // stats - возвращает статистику сервиса.
@@ -308,7 +973,17 @@ func (h APIHandlers) shortURLGetByUserID(w http.ResponseWriter, r *http.Request)
 //
 //	{
 //	    "urls": 100,
-//	    "users": 10
+//	    "active_urls": 90,
+//	    "deleted_urls": 10,
+//	    "urls_last_hour": 2,
+//	    "urls_last_day": 15,
+//	    "users": 10,
+//	    "active_users": 8,
+//	    "storage_size": 40960,
+//	    "backend": "postgres",
+//	    "started_at": "2022-12-01T10:00:00Z",
+//	    "uptime_seconds": 3600,
+//	    "build": {"version": "v1.0.0", "date": "2022-12-01", "commit": "abcdef"}
 //	}
 //
 // @Tags internal
@@ -323,30 +998,22 @@ func (h APIHandlers) shortURLGetByUserID(w http.ResponseWriter, r *http.Request)
 func (h APIHandlers) stats(w http.ResponseWriter, r *http.Request) {
 	// Структура ответа
 	type resType struct {
-		Users int `json:"users"`
-		URLs  int `json:"urls"`
+		models.ServiceStats
+		UptimeSeconds int64 `json:"uptime_seconds"`
 	}
 
 	// Получаем статистику
-	userCount, err := h.u.User.Count(r.Context())
-	if err != nil {
-		respondWithError(w, err)
-		return
-	}
-	shortURLCount, err := h.u.ShortURL.Count(r.Context())
+	stats, err := h.u.Stats.Get(r.Context())
 	if err != nil {
 		respondWithError(w, err)
 		return
 	}
 
-	// Формируем ответ
-	res := resType{
-		Users: userCount,
-		URLs:  shortURLCount,
-	}
-
 	// Возвращаем ответ
-	respondWithJSON(w, http.StatusOK, res)
+	respondWithJSON(w, http.StatusOK, resType{
+		ServiceStats:  *stats,
+		UptimeSeconds: int64(stats.Uptime.Seconds()),
+	})
 }
 
 // parseJSONRequest - парсит запрос в теле запроса в структуру.
@@ -375,3 +1042,21 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.WriteHeader(code)
 	_, _ = w.Write(response)
 }
+
+// parseTimeParam - разбирает необязательный параметр запроса в формате RFC 3339.
+// Для пустого значения возвращает нулевое время.
+func parseTimeParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// parseBoolParam - разбирает необязательный логический параметр запроса.
+// Для пустого значения возвращает false.
+func parseBoolParam(v string) (bool, error) {
+	if v == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(v)
+}
@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	. "github.com/onsi/ginkgo/v2"
@@ -15,6 +19,7 @@ import (
 	"github.com/onsi/gomega/ghttp"
 
 	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/http/middleware"
 	"github.com/ofstudio/go-shortener/internal/models"
 	"github.com/ofstudio/go-shortener/internal/providers/auth"
 	"github.com/ofstudio/go-shortener/internal/repo"
@@ -74,6 +79,16 @@ var _ = Describe("POST /shorten ", func() {
 			Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
 		})
 	})
+	When("private network url sent", func() {
+		It("should return 400 with rejection reason", func() {
+			res := testHTTPRequest("POST", server.URL()+"/shorten", "application/json", `{"url":"http://192.168.0.1/admin"}`)
+			Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+			resBody, err := io.ReadAll(res.Body)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+			Expect(string(resBody)).Should(ContainSubstring("192.168.0.1"))
+		})
+	})
 	When("wrong json sent", func() {
 		It("should return 400", func() {
 			res := testHTTPRequest("POST", server.URL()+"/shorten", "application/json", `{"wrong":true}`)
@@ -106,7 +121,9 @@ var _ = Describe("POST /shorten ", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 			_ = res1.Body.Close()
 
-			res2 := testHTTPRequest("POST", server.URL()+"/shorten", "application/json", `{"url":"https://www.duplicate.com"}`)
+			// Повторно сокращает URL тот же пользователь
+			Expect(res1.Cookies()).ShouldNot(BeEmpty())
+			res2 := testHTTPRequest("POST", server.URL()+"/shorten", "application/json", `{"url":"https://www.duplicate.com"}`, res1.Cookies()[0])
 			Expect(res2.StatusCode).Should(Equal(http.StatusConflict))
 			resBody2, err := io.ReadAll(res2.Body)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -122,6 +139,7 @@ var _ = Describe("POST /shorten/batch", func() {
 	repository := repo.NewMemoryRepo()
 	u := usecases.NewContainer(context.Background(), cfg, repository)
 	var duplicateID string
+	var cookie *http.Cookie
 
 	BeforeEach(func() {
 		server = ghttp.NewServer()
@@ -145,6 +163,8 @@ var _ = Describe("POST /shorten/batch", func() {
 			res := testHTTPRequest("POST", server.URL()+"/shorten/batch", "application/json", body)
 			Expect(res.StatusCode).Should(Equal(http.StatusCreated))
 			Expect(res.Header.Get("Content-Type")).Should(Equal("application/json"))
+			Expect(res.Cookies()).ShouldNot(BeEmpty())
+			cookie = res.Cookies()[0]
 			resBody, err := io.ReadAll(res.Body)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
@@ -171,7 +191,8 @@ var _ = Describe("POST /shorten/batch", func() {
 				{"correlation_id":"100","original_url":"https://www.facebook.com"},
 				{"correlation_id":"101","original_url":"https://www.vk.com"}
 			]`
-			res := testHTTPRequest("POST", server.URL()+"/shorten/batch", "application/json", body)
+			// Повторно сокращает URL тот же пользователь
+			res := testHTTPRequest("POST", server.URL()+"/shorten/batch", "application/json", body, cookie)
 			Expect(res.StatusCode).Should(Equal(http.StatusCreated))
 			Expect(res.Header.Get("Content-Type")).Should(Equal("application/json"))
 			resBody, err := io.ReadAll(res.Body)
@@ -332,6 +353,118 @@ var _ = Describe("DELETE /user/urls", func() {
 
 })
 
+var _ = Describe("GET /user/urls/{id}/stats", func() {
+	var server *ghttp.Server
+	var cookie *http.Cookie
+	var id string
+	cfg, _ := config.Default(nil)
+	repository := repo.NewMemoryRepo()
+	u := usecases.NewContainer(context.Background(), cfg, repository)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		cfg.BaseURL = testParseURL(server.URL() + "/")
+		r := chi.NewRouter()
+		r.Use(auth.NewSHA256Provider(cfg, u.User).Handler)
+		// Страна посетителя принимается только от доверенного прокси-сервера
+		r.Mount("/", NewHTTPHandlers(u, WithTrustedProxies("127.0.0.1")).Routes())
+		r.Mount("/api", NewAPIHandlers(u).PublicRoutes())
+		// Статистика запрашивается повторно, пока события переходов не будут сохранены
+		server.RouteToHandler("GET", regexp.MustCompile(`.*`), r.ServeHTTP)
+		server.RouteToHandler("POST", regexp.MustCompile(`.*`), r.ServeHTTP)
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should create short url", func() {
+		res := testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json", `{"url":"https://example.com/stats"}`)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+		cookie = res.Cookies()[0]
+		resBody, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+		resJSON := &struct {
+			Result string `json:"result"`
+		}{}
+		Expect(json.Unmarshal(resBody, resJSON)).Should(Succeed())
+		su, err := url.Parse(resJSON.Result)
+		Expect(err).ShouldNot(HaveOccurred())
+		id = su.Path[1:]
+	})
+	It("should record clicks on redirect", func() {
+		c := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+		for i := 0; i < 2; i++ {
+			req, err := http.NewRequest("GET", server.URL()+"/"+id, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			req.Header.Set("Referer", "https://news.example.org/")
+			req.Header.Set("CF-IPCountry", "de")
+			req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64)")
+			res, err := c.Do(req)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+			Expect(res.StatusCode).Should(Equal(http.StatusTemporaryRedirect))
+		}
+		// Переход бота тоже перенаправляется, но отмечается как переход бота
+		req, err := http.NewRequest("GET", server.URL()+"/"+id, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		req.Header.Set("User-Agent", "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)")
+		res, err := c.Do(req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+		Expect(res.StatusCode).Should(Equal(http.StatusTemporaryRedirect))
+	})
+	It("should return stats to owner", func() {
+		stats := &models.LinkStats{}
+		// События переходов сохраняются в фоне
+		Eventually(func() int64 {
+			res := testHTTPRequest("GET", server.URL()+"/api/user/urls/"+id+"/stats?interval=hour", "", "", cookie)
+			Expect(res.StatusCode).Should(Equal(http.StatusOK))
+			Expect(json.NewDecoder(res.Body).Decode(stats)).Should(Succeed())
+			Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+			return stats.Total + stats.Bots
+		}, 5*time.Second, 100*time.Millisecond).Should(Equal(int64(3)))
+		Expect(stats.Total).Should(Equal(int64(2)))
+		Expect(stats.Bots).Should(Equal(int64(1)))
+		Expect(stats.Unique).Should(Equal(int64(1)))
+		Expect(stats.Interval).Should(Equal(models.StatsIntervalHour))
+		Expect(stats.Buckets).Should(HaveLen(25))
+		Expect(stats.Referrers).Should(Equal([]models.StatsCount{{Key: "news.example.org", Clicks: 2}}))
+		Expect(stats.Countries).Should(Equal([]models.StatsCount{{Key: "DE", Clicks: 2}}))
+	})
+	It("should include bots on request", func() {
+		stats := &models.LinkStats{}
+		res := testHTTPRequest("GET", server.URL()+"/api/user/urls/"+id+"/stats?interval=hour&bots=true", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		Expect(json.NewDecoder(res.Body).Decode(stats)).Should(Succeed())
+		Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+		Expect(stats.IncludeBots).Should(BeTrue())
+		Expect(stats.Total).Should(Equal(int64(3)))
+		Expect(stats.Bots).Should(Equal(int64(1)))
+		Expect(stats.Unique).Should(Equal(int64(2)))
+	})
+	It("should return 400 for invalid params", func() {
+		res := testHTTPRequest("GET", server.URL()+"/api/user/urls/"+id+"/stats?from=yesterday", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+		res = testHTTPRequest("GET", server.URL()+"/api/user/urls/"+id+"/stats?interval=week", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+		res = testHTTPRequest("GET", server.URL()+"/api/user/urls/"+id+"/stats?bots=maybe", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+	})
+	It("should return 401 without token", func() {
+		res := testHTTPRequest("GET", server.URL()+"/api/user/urls/"+id+"/stats", "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusUnauthorized))
+		Expect(res.Cookies()).Should(BeEmpty())
+	})
+	It("should return 404 to other user", func() {
+		res := testHTTPRequest("GET", server.URL()+"/api/user/urls", "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+		Expect(res.Cookies()).Should(HaveLen(1))
+		res = testHTTPRequest("GET", server.URL()+"/api/user/urls/"+id+"/stats", "", "", res.Cookies()[0])
+		Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+	})
+})
+
 var _ = Describe("GET /internal/stats", func() {
 	var server *ghttp.Server
 	cfg, _ := config.Default(nil)
@@ -356,6 +489,684 @@ var _ = Describe("GET /internal/stats", func() {
 		resBody, err := io.ReadAll(res.Body)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
-		Expect(resBody).Should(MatchJSON(`{"users":0,"urls":0}`))
+		var stats map[string]interface{}
+		Expect(json.Unmarshal(resBody, &stats)).Should(Succeed())
+		Expect(stats).Should(HaveKeyWithValue("users", BeEquivalentTo(0)))
+		Expect(stats).Should(HaveKeyWithValue("urls", BeEquivalentTo(0)))
+		Expect(stats).Should(HaveKeyWithValue("backend", "memory"))
+		Expect(stats).Should(HaveKey("uptime_seconds"))
+		Expect(stats).Should(HaveKeyWithValue("build", HaveKeyWithValue("version", "N/A")))
+	})
+
+	It("should count active and deleted urls", func() {
+		user := &models.User{}
+		Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+		shortURL, err := u.ShortURL.Create(context.Background(), user.ID, "https://example.com/internal-stats")
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = u.ShortURL.Create(context.Background(), user.ID, "https://example.com/internal-stats-active")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(u.ShortURL.DeleteBatch(context.Background(), user.ID, []string{shortURL.ID})).Should(Succeed())
+
+		res := testHTTPRequest("GET", server.URL()+"/stats", "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		stats := &models.ServiceStats{}
+		Expect(json.NewDecoder(res.Body).Decode(stats)).Should(Succeed())
+		Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+		Expect(stats.URLs).Should(Equal(2))
+		Expect(stats.ActiveURLs).Should(Equal(1))
+		Expect(stats.DeletedURLs).Should(Equal(1))
+		Expect(stats.URLsLastDay).Should(Equal(2))
+		Expect(stats.ActiveUsers).Should(Equal(1))
+	})
+})
+
+var _ = Describe("GET /user/urls/{id}/clicks/export", func() {
+	var server *ghttp.Server
+	var cookie *http.Cookie
+	var shortURL *models.ShortURL
+	cfg, _ := config.Default(nil)
+	repository := repo.NewMemoryRepo()
+	u := usecases.NewContainer(context.Background(), cfg, repository)
+	p := auth.NewSHA256Provider(cfg, u.User)
+	day := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		r := chi.NewRouter()
+		r.Use(middleware.NewCompressor(0, gzip.BestSpeed).AddType("text/csv").AddType("application/x-ndjson").Handler)
+		r.Use(p.Handler)
+		r.Mount("/api", NewAPIHandlers(u).PublicRoutes())
+		server.RouteToHandler("GET", regexp.MustCompile(`.*`), r.ServeHTTP)
+		if shortURL != nil {
+			return
+		}
+		user := &models.User{}
+		Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+		token, err := p.CreateToken(auth.Claims{UserID: user.ID})
+		Expect(err).ShouldNot(HaveOccurred())
+		cookie = &http.Cookie{Name: "auth_token", Value: token}
+		shortURL, err = u.ShortURL.Create(context.Background(), user.ID, "https://example.com/export")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(repository.ClickAddBatch(context.Background(), []models.Click{
+			{ShortURLID: shortURL.ID, Time: day.Add(time.Hour), RefererHost: "t.co", Device: models.DeviceIOS, IP: "203.0.113.0", Country: "DE", Visitor: "a"},
+			{ShortURLID: shortURL.ID, Time: day.Add(25 * time.Hour), Device: models.DeviceDesktop, Visitor: "b", Bot: true},
+		})).Should(Succeed())
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should export clicks in CSV by default", func() {
+		res := testHTTPRequest("GET", server.URL()+"/api/user/urls/"+shortURL.ID+"/clicks/export", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		Expect(res.Header.Get("Content-Type")).Should(Equal("text/csv"))
+		Expect(res.Header.Get("Content-Disposition")).Should(ContainSubstring(shortURL.ID + "-clicks.csv"))
+		// Ответ сжат: клиент распаковал его автоматически
+		Expect(res.Uncompressed).Should(BeTrue())
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+		Expect(string(body)).Should(Equal(
+			"short_url_id,time,referer_host,device,ip,country,visitor,bot\n" +
+				shortURL.ID + ",2022-12-01T01:00:00Z,t.co,ios,203.0.113.0,DE,a,false\n" +
+				shortURL.ID + ",2022-12-02T01:00:00Z,,desktop,,,b,true\n"))
+	})
+
+	It("should export clicks in JSON Lines for period", func() {
+		res := testHTTPRequest("GET", server.URL()+"/api/user/urls/"+shortURL.ID+"/clicks/export?format=jsonl&from=2022-12-02T00:00:00Z&to=2022-12-03T00:00:00Z", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		Expect(res.Header.Get("Content-Type")).Should(Equal("application/x-ndjson"))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		Expect(lines).Should(HaveLen(1))
+		click := models.Click{}
+		Expect(json.Unmarshal([]byte(lines[0]), &click)).Should(Succeed())
+		Expect(click.Visitor).Should(Equal("b"))
+		Expect(click.Bot).Should(BeTrue())
+	})
+
+	It("should export only CSV header if there are no clicks", func() {
+		res := testHTTPRequest("GET", server.URL()+"/api/user/urls/"+shortURL.ID+"/clicks/export?from=2023-01-01T00:00:00Z", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+		Expect(string(body)).Should(Equal("short_url_id,time,referer_host,device,ip,country,visitor,bot\n"))
+	})
+
+	It("should return 400 for invalid params", func() {
+		res := testHTTPRequest("GET", server.URL()+"/api/user/urls/"+shortURL.ID+"/clicks/export?format=xml", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+		res = testHTTPRequest("GET", server.URL()+"/api/user/urls/"+shortURL.ID+"/clicks/export?to=tomorrow", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+	})
+
+	It("should return 404 to other user", func() {
+		other := &models.User{}
+		Expect(u.User.Create(context.Background(), other)).Should(Succeed())
+		token, err := p.CreateToken(auth.Claims{UserID: other.ID})
+		Expect(err).ShouldNot(HaveOccurred())
+		res := testHTTPRequest("GET", server.URL()+"/api/user/urls/"+shortURL.ID+"/clicks/export", "", "",
+			&http.Cookie{Name: "auth_token", Value: token})
+		Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+		Expect(res.Header.Get("Content-Disposition")).Should(BeEmpty())
+	})
+})
+
+var _ = Describe("/user/keys", func() {
+	var server *ghttp.Server
+	var cookie *http.Cookie
+	cfg, _ := config.Default(nil)
+	repository := repo.NewMemoryRepo()
+	u := usecases.NewContainer(context.Background(), cfg, repository)
+	p := auth.NewSHA256Provider(cfg, u.User)
+	p.UseAPIKeys(u.APIKey)
+
+	// bearerRequest - выполняет запрос с API-ключом в заголовке Authorization
+	bearerRequest := func(method, url, body, key string) *http.Response {
+		req, err := http.NewRequest(method, url, strings.NewReader(body))
+		Expect(err).ShouldNot(HaveOccurred())
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+key)
+		res, err := http.DefaultClient.Do(req)
+		Expect(err).ShouldNot(HaveOccurred())
+		return res
+	}
+
+	// createKey - создает API-ключ с правами scopes и возвращает ответ
+	createKey := func(scopes string) map[string]interface{} {
+		res := testHTTPRequest("POST", server.URL()+"/api/user/keys", "application/json",
+			`{"name":"CI","scopes":`+scopes+`}`, cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+		resJSON := map[string]interface{}{}
+		Expect(json.NewDecoder(res.Body).Decode(&resJSON)).Should(Succeed())
+		Expect(res.Body.Close()).Should(Succeed())
+		return resJSON
+	}
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		cfg.BaseURL = testParseURL(server.URL() + "/")
+		r := chi.NewRouter()
+		r.Use(p.Handler)
+		r.Mount("/api", NewAPIHandlers(u).PublicRoutes())
+		server.RouteToHandler("GET", regexp.MustCompile(`.*`), r.ServeHTTP)
+		server.RouteToHandler("POST", regexp.MustCompile(`.*`), r.ServeHTTP)
+		server.RouteToHandler("DELETE", regexp.MustCompile(`.*`), r.ServeHTTP)
+		if cookie != nil {
+			return
+		}
+		user := &models.User{}
+		Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+		token, err := p.CreateToken(auth.Claims{UserID: user.ID})
+		Expect(err).ShouldNot(HaveOccurred())
+		cookie = &http.Cookie{Name: "auth_token", Value: token}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should create API key and return it only once", func() {
+		key := createKey(`["read","create"]`)
+		Expect(key["key"]).Should(HavePrefix(usecases.APIKeyPrefix))
+		Expect(key["scopes"]).Should(Equal([]interface{}{"read", "create"}))
+		Expect(key).ShouldNot(HaveKey("hash"))
+
+		res := testHTTPRequest("GET", server.URL()+"/api/user/keys", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Should(Succeed())
+		Expect(string(body)).Should(ContainSubstring(key["id"].(string)))
+		Expect(string(body)).ShouldNot(ContainSubstring(key["key"].(string)))
+		Expect(string(body)).ShouldNot(ContainSubstring("hash"))
+	})
+
+	It("should return 400 for invalid scopes", func() {
+		res := testHTTPRequest("POST", server.URL()+"/api/user/keys", "application/json", `{"name":"CI","scopes":["admin"]}`, cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+	})
+
+	It("should authorize requests by API key scopes", func() {
+		key := createKey(`["read"]`)["key"].(string)
+		res := bearerRequest("GET", server.URL()+"/api/user/urls", "", key)
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+		res = bearerRequest("POST", server.URL()+"/api/shorten", `{"url":"https://example.com/key"}`, key)
+		Expect(res.StatusCode).Should(Equal(http.StatusForbidden))
+		// API-ключом нельзя управлять ключами
+		res = bearerRequest("GET", server.URL()+"/api/user/keys", "", key)
+		Expect(res.StatusCode).Should(Equal(http.StatusForbidden))
+
+		key = createKey(`["create"]`)["key"].(string)
+		res = bearerRequest("POST", server.URL()+"/api/shorten", `{"url":"https://example.com/key"}`, key)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+		Expect(res.Cookies()).Should(BeEmpty())
+	})
+
+	It("should revoke API key", func() {
+		created := createKey(`["read"]`)
+		res := testHTTPRequest("DELETE", server.URL()+"/api/user/keys/"+created["id"].(string), "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+		res = bearerRequest("GET", server.URL()+"/api/user/urls", "", created["key"].(string))
+		Expect(res.StatusCode).Should(Equal(http.StatusUnauthorized))
+		res = testHTTPRequest("DELETE", server.URL()+"/api/user/keys/"+created["id"].(string), "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+	})
+
+	It("should return 401 without token", func() {
+		res := testHTTPRequest("GET", server.URL()+"/api/user/keys", "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusUnauthorized))
+	})
+})
+
+var _ = Describe("/user/register, /user/login, /user/logout", func() {
+	var server *ghttp.Server
+	cfg, _ := config.Default(nil)
+	repository := repo.NewMemoryRepo()
+	u := usecases.NewContainer(context.Background(), cfg, repository)
+
+	// authCookie - возвращает куку auth_token из ответа
+	authCookie := func(res *http.Response) *http.Cookie {
+		for _, c := range res.Cookies() {
+			if c.Name == "auth_token" {
+				return c
+			}
+		}
+		return nil
+	}
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		cfg.BaseURL = testParseURL(server.URL() + "/")
+		r := chi.NewRouter()
+		r.Use(auth.NewSHA256Provider(cfg, u.User).Handler)
+		r.Mount("/api", NewAPIHandlers(u).PublicRoutes())
+		server.RouteToHandler("GET", regexp.MustCompile(`.*`), r.ServeHTTP)
+		server.RouteToHandler("POST", regexp.MustCompile(`.*`), r.ServeHTTP)
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should claim anonymous links on registration and restore them on login", func() {
+		// Анонимный пользователь создает ссылку
+		res := testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json", `{"url":"https://example.com/claim"}`)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+		anonymous := authCookie(res)
+		Expect(anonymous).ShouldNot(BeNil())
+
+		// Регистрируется с текущим токеном
+		res = testHTTPRequest("POST", server.URL()+"/api/user/register", "application/json",
+			`{"email":"User@Example.com","password":"password"}`, anonymous)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+		resJSON := &struct {
+			ID    uint   `json:"id"`
+			Email string `json:"email"`
+		}{}
+		Expect(json.NewDecoder(res.Body).Decode(resJSON)).Should(Succeed())
+		Expect(res.Body.Close()).Should(Succeed())
+		Expect(resJSON.Email).Should(Equal("user@example.com"))
+		Expect(authCookie(res)).ShouldNot(BeNil())
+
+		// Выходит: кука удаляется
+		res = testHTTPRequest("POST", server.URL()+"/api/user/logout", "", "", authCookie(res))
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+		Expect(authCookie(res)).ShouldNot(BeNil())
+		Expect(authCookie(res).MaxAge).Should(BeNumerically("<", 0))
+
+		// Входит снова и получает свои ссылки
+		res = testHTTPRequest("POST", server.URL()+"/api/user/login", "application/json",
+			`{"email":"user@example.com","password":"password"}`)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		cookie := authCookie(res)
+		Expect(cookie).ShouldNot(BeNil())
+		res = testHTTPRequest("GET", server.URL()+"/api/user/urls", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Should(Succeed())
+		Expect(string(body)).Should(ContainSubstring("https://example.com/claim"))
+	})
+
+	It("should return 409 for registered email", func() {
+		res := testHTTPRequest("POST", server.URL()+"/api/user/register", "application/json",
+			`{"email":"user@example.com","password":"password"}`)
+		Expect(res.StatusCode).Should(Equal(http.StatusConflict))
+	})
+
+	It("should return 400 for invalid credentials", func() {
+		res := testHTTPRequest("POST", server.URL()+"/api/user/register", "application/json",
+			`{"email":"other@example.com","password":"short"}`)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+	})
+
+	It("should return 401 for wrong password", func() {
+		res := testHTTPRequest("POST", server.URL()+"/api/user/login", "application/json",
+			`{"email":"user@example.com","password":"wrong password"}`)
+		Expect(res.StatusCode).Should(Equal(http.StatusUnauthorized))
+		Expect(authCookie(res)).Should(BeNil())
+	})
+
+	It("should revoke all tokens on logout with all=true", func() {
+		login := func() *http.Cookie {
+			res := testHTTPRequest("POST", server.URL()+"/api/user/login", "application/json",
+				`{"email":"user@example.com","password":"password"}`)
+			Expect(res.StatusCode).Should(Equal(http.StatusOK))
+			return authCookie(res)
+		}
+		first, second := login(), login()
+		res := testHTTPRequest("GET", server.URL()+"/api/user/keys", "", "", second)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+
+		res = testHTTPRequest("POST", server.URL()+"/api/user/logout?all=maybe", "", "", first)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+		res = testHTTPRequest("POST", server.URL()+"/api/user/logout?all=true", "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusUnauthorized))
+		res = testHTTPRequest("POST", server.URL()+"/api/user/logout?all=true", "", "", first)
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+		Expect(authCookie(res).MaxAge).Should(BeNumerically("<", 0))
+
+		// Все ранее выданные токены отозваны, новый токен принимается
+		res = testHTTPRequest("GET", server.URL()+"/api/user/keys", "", "", second)
+		Expect(res.StatusCode).Should(Equal(http.StatusUnauthorized))
+		res = testHTTPRequest("GET", server.URL()+"/api/user/keys", "", "", login())
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+	})
+})
+
+var _ = Describe("POST /internal/users/{id}/revoke", func() {
+	var server *ghttp.Server
+	cfg, _ := config.Default(nil)
+	repository := repo.NewMemoryRepo()
+	u := usecases.NewContainer(context.Background(), cfg, repository)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		r := chi.NewRouter()
+		r.Mount("/", NewAPIHandlers(u).InternalRoutes())
+		server.AppendHandlers(r.ServeHTTP)
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should revoke user tokens", func() {
+		user := &models.User{}
+		Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+		res := testHTTPRequest("POST", server.URL()+"/users/"+strconv.Itoa(int(user.ID))+"/revoke", "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+		Expect(u.User.TokenGeneration(context.Background(), user.ID)).Should(Equal(uint(1)))
+	})
+
+	It("should return 404 for unknown user", func() {
+		res := testHTTPRequest("POST", server.URL()+"/users/100500/revoke", "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+	})
+
+	It("should return 400 for invalid id", func() {
+		res := testHTTPRequest("POST", server.URL()+"/users/abc/revoke", "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+	})
+})
+
+var _ = Describe("/user/teams", func() {
+	var server *ghttp.Server
+	cfg, _ := config.Default(nil)
+	repository := repo.NewMemoryRepo()
+	u := usecases.NewContainer(context.Background(), cfg, repository)
+	p := auth.NewSHA256Provider(cfg, u.User)
+
+	// userCookie - регистрирует пользователя с адресом email и возвращает его куку
+	userCookie := func(email string) *http.Cookie {
+		user, err := u.User.Register(context.Background(), 0, email, "password")
+		Expect(err).ShouldNot(HaveOccurred())
+		token, err := p.CreateToken(auth.Claims{UserID: user.ID})
+		Expect(err).ShouldNot(HaveOccurred())
+		return &http.Cookie{Name: "auth_token", Value: token}
+	}
+	owner, viewer, stranger := userCookie("owner@example.com"), userCookie("viewer@example.com"), userCookie("stranger@example.com")
+	var teamPath, teamID string
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		cfg.BaseURL = testParseURL(server.URL() + "/")
+		r := chi.NewRouter()
+		r.Use(p.Handler)
+		r.Mount("/api", NewAPIHandlers(u).PublicRoutes())
+		server.RouteToHandler("GET", regexp.MustCompile(`.*`), r.ServeHTTP)
+		server.RouteToHandler("POST", regexp.MustCompile(`.*`), r.ServeHTTP)
+		server.RouteToHandler("PUT", regexp.MustCompile(`.*`), r.ServeHTTP)
+		server.RouteToHandler("DELETE", regexp.MustCompile(`.*`), r.ServeHTTP)
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should create team and add members", func() {
+		res := testHTTPRequest("GET", server.URL()+"/api/user/teams", "", "", owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+
+		res = testHTTPRequest("POST", server.URL()+"/api/user/teams", "application/json", `{"name":"Marketing"}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+		team := &models.TeamMembership{}
+		Expect(json.NewDecoder(res.Body).Decode(team)).Should(Succeed())
+		Expect(res.Body.Close()).Should(Succeed())
+		Expect(team.Name).Should(Equal("Marketing"))
+		Expect(team.Role).Should(Equal(models.TeamRoleOwner))
+		teamID = strconv.Itoa(int(team.ID))
+		teamPath = "/api/user/teams/" + teamID
+
+		res = testHTTPRequest("PUT", server.URL()+teamPath+"/members", "application/json", `{"email":"viewer@example.com","role":"viewer"}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		res = testHTTPRequest("PUT", server.URL()+teamPath+"/members", "application/json", `{"email":"stranger@example.com","role":"viewer"}`, viewer)
+		Expect(res.StatusCode).Should(Equal(http.StatusForbidden))
+		res = testHTTPRequest("PUT", server.URL()+teamPath+"/members", "application/json", `{"email":"stranger@example.com","role":"admin"}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+
+		res = testHTTPRequest("GET", server.URL()+teamPath+"/members", "", "", viewer)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		var members []models.TeamMember
+		Expect(json.NewDecoder(res.Body).Decode(&members)).Should(Succeed())
+		Expect(res.Body.Close()).Should(Succeed())
+		Expect(members).Should(HaveLen(2))
+		res = testHTTPRequest("GET", server.URL()+teamPath+"/members", "", "", stranger)
+		Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+	})
+
+	It("should share links with team members according to their roles", func() {
+		res := testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json",
+			`{"url":"https://example.com/viewer","team_id":`+teamID+`}`, viewer)
+		Expect(res.StatusCode).Should(Equal(http.StatusForbidden))
+		res = testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json",
+			`{"url":"https://example.com/team","team_id":`+teamID+`}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+		resJSON := &struct {
+			Result string `json:"result"`
+		}{}
+		Expect(json.NewDecoder(res.Body).Decode(resJSON)).Should(Succeed())
+		Expect(res.Body.Close()).Should(Succeed())
+		id := resJSON.Result[strings.LastIndex(resJSON.Result, "/")+1:]
+
+		// Наблюдатель видит ссылки команды и их правила, но не может их изменять
+		res = testHTTPRequest("GET", server.URL()+teamPath+"/urls", "", "", viewer)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Should(Succeed())
+		Expect(string(body)).Should(ContainSubstring(`"team_id":` + teamID))
+		res = testHTTPRequest("GET", server.URL()+"/api/user/urls/"+id+"/rules", "", "", viewer)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		res = testHTTPRequest("PUT", server.URL()+"/api/user/urls/"+id+"/rules", "application/json", `[]`, viewer)
+		Expect(res.StatusCode).Should(Equal(http.StatusForbidden))
+		res = testHTTPRequest("GET", server.URL()+teamPath+"/urls", "", "", stranger)
+		Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+
+		// Сделать ссылку личной может только создавший ее пользователь
+		res = testHTTPRequest("PUT", server.URL()+"/api/user/urls/"+id+"/team", "application/json", `{"team_id":0}`, viewer)
+		Expect(res.StatusCode).Should(Equal(http.StatusForbidden))
+		res = testHTTPRequest("PUT", server.URL()+"/api/user/urls/"+id+"/team", "application/json", `{"team_id":0}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+		res = testHTTPRequest("GET", server.URL()+teamPath+"/urls", "", "", viewer)
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+	})
+
+	It("should remove team members", func() {
+		res := testHTTPRequest("DELETE", server.URL()+teamPath+"/members/1", "", "", viewer)
+		Expect(res.StatusCode).Should(Equal(http.StatusForbidden))
+		res = testHTTPRequest("DELETE", server.URL()+teamPath+"/members/abc", "", "", owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+		// Участник может выйти из команды сам
+		res = testHTTPRequest("DELETE", server.URL()+teamPath+"/members/2", "", "", viewer)
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+		res = testHTTPRequest("GET", server.URL()+"/api/user/teams", "", "", viewer)
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+	})
+})
+
+var _ = Describe("PUT /user/urls/{id}/...", func() {
+	var server *ghttp.Server
+	cfg, _ := config.Default(nil)
+	repository := repo.NewMemoryRepo()
+	u := usecases.NewContainer(context.Background(), cfg, repository)
+	p := auth.NewSHA256Provider(cfg, u.User)
+
+	// userCookie - создает пользователя и возвращает его куку
+	userCookie := func() *http.Cookie {
+		user := &models.User{}
+		Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+		token, err := p.CreateToken(auth.Claims{UserID: user.ID})
+		Expect(err).ShouldNot(HaveOccurred())
+		return &http.Cookie{Name: "auth_token", Value: token}
+	}
+	owner, stranger := userCookie(), userCookie()
+	var path string
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		cfg.BaseURL = testParseURL(server.URL() + "/")
+		r := chi.NewRouter()
+		r.Use(p.Handler)
+		r.Mount("/api", NewAPIHandlers(u).PublicRoutes())
+		server.RouteToHandler("POST", regexp.MustCompile(`.*`), r.ServeHTTP)
+		server.RouteToHandler("PUT", regexp.MustCompile(`.*`), r.ServeHTTP)
+
+		res := testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json", `{"url":"https://example.com/settings"}`, owner)
+		Expect(res.StatusCode).Should(BeElementOf(http.StatusCreated, http.StatusConflict))
+		resJSON := &struct {
+			Result string `json:"result"`
+		}{}
+		Expect(json.NewDecoder(res.Body).Decode(resJSON)).Should(Succeed())
+		Expect(res.Body.Close()).Should(Succeed())
+		path = "/api/user/urls/" + resJSON.Result[strings.LastIndex(resJSON.Result, "/")+1:]
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should set and remove query template", func() {
+		res := testHTTPRequest("PUT", server.URL()+path+"/query-template", "application/json",
+			`{"params":{"utm_content":"{id}"},"policy":"override"}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Should(Succeed())
+		Expect(body).Should(MatchJSON(`{"params":{"utm_content":"{id}"},"policy":"override"}`))
+
+		res = testHTTPRequest("PUT", server.URL()+path+"/query-template", "application/json", `{"params":{"a":"{unknown}"}}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+		res = testHTTPRequest("PUT", server.URL()+path+"/query-template", "application/json", `null`, stranger)
+		Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+
+		// Ссылка с шаблоном не используется повторно: без шаблона она совпала бы с новой ссылкой на тот же URL
+		res = testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json", `{"url":"https://example.com/settings"}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+		res = testHTTPRequest("PUT", server.URL()+path+"/query-template", "application/json", `null`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusConflict))
+	})
+
+	It("should set title and interstitial", func() {
+		res := testHTTPRequest("PUT", server.URL()+path+"/preview", "application/json",
+			`{"title":"Отчет за квартал","interstitial":true}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Should(Succeed())
+		Expect(body).Should(MatchJSON(`{"title":"Отчет за квартал","interstitial":true}`))
+
+		res = testHTTPRequest("PUT", server.URL()+path+"/preview", "application/json",
+			`{"title":"`+strings.Repeat("я", models.TitleMaxLen+1)+`"}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+		res = testHTTPRequest("PUT", server.URL()+path+"/preview", "application/json", `{"title":""}`, stranger)
+		Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+	})
+
+	It("should set active window", func() {
+		res := testHTTPRequest("PUT", server.URL()+path+"/active-window", "application/json",
+			`{"active_from":"2023-03-08T09:00:00Z","active_until":"2023-03-31T00:00:00Z"}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Should(Succeed())
+		Expect(body).Should(MatchJSON(`{"active_from":"2023-03-08T09:00:00Z","active_until":"2023-03-31T00:00:00Z"}`))
+
+		res = testHTTPRequest("PUT", server.URL()+path+"/active-window", "application/json",
+			`{"active_from":"2023-03-31T00:00:00Z","active_until":"2023-03-08T09:00:00Z"}`, owner)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+		res = testHTTPRequest("PUT", server.URL()+path+"/active-window", "application/json", `{}`, stranger)
+		Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+	})
+})
+
+var _ = Describe("quotas", func() {
+	var server *ghttp.Server
+	cfg, _ := config.Default(nil)
+	cfg.QuotaMaxActiveURLs = 2
+	cfg.QuotaMaxBatchSize = 2
+	cfg.QuotaPlans = map[string]models.Quota{"pro": {MaxActiveURLs: 100, MaxBatchSize: 10}}
+	repository := repo.NewMemoryRepo()
+	u := usecases.NewContainer(context.Background(), cfg, repository)
+	p := auth.NewSHA256Provider(cfg, u.User)
+
+	user := &models.User{}
+	Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+	token, err := p.CreateToken(auth.Claims{UserID: user.ID})
+	Expect(err).ShouldNot(HaveOccurred())
+	cookie := &http.Cookie{Name: "auth_token", Value: token}
+	planPath := "/internal/users/" + strconv.Itoa(int(user.ID)) + "/plan"
+
+	// usage - возвращает квоты пользователя и их использование
+	usage := func() *models.QuotaUsage {
+		res := testHTTPRequest("GET", server.URL()+"/api/user/quota", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		result := &models.QuotaUsage{}
+		Expect(json.NewDecoder(res.Body).Decode(result)).Should(Succeed())
+		Expect(res.Body.Close()).Should(Succeed())
+		return result
+	}
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		cfg.BaseURL = testParseURL(server.URL() + "/")
+		r := chi.NewRouter()
+		r.Route("/api", func(r chi.Router) {
+			r.Use(p.Handler)
+			r.Mount("/", NewAPIHandlers(u).PublicRoutes())
+		})
+		r.Mount("/internal", NewAPIHandlers(u).InternalRoutes())
+		server.RouteToHandler("GET", regexp.MustCompile(`.*`), r.ServeHTTP)
+		server.RouteToHandler("POST", regexp.MustCompile(`.*`), r.ServeHTTP)
+		server.RouteToHandler("PUT", regexp.MustCompile(`.*`), r.ServeHTTP)
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should enforce default quotas", func() {
+		batch := `[
+			{"correlation_id":"1","original_url":"https://quota.com/1"},
+			{"correlation_id":"2","original_url":"https://quota.com/2"},
+			{"correlation_id":"3","original_url":"https://quota.com/3"}
+		]`
+		res := testHTTPRequest("POST", server.URL()+"/api/shorten/batch", "application/json", batch, cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusTooManyRequests))
+		Expect(usage().ActiveURLs).Should(BeZero())
+
+		res = testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json", `{"url":"https://quota.com/1"}`, cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+		res = testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json", `{"url":"https://quota.com/2"}`, cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+		res = testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json", `{"url":"https://quota.com/3"}`, cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusTooManyRequests))
+
+		// Существующая ссылка возвращается с кодом 409 и при исчерпанной квоте
+		res = testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json", `{"url":"https://quota.com/1"}`, cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusConflict))
+		batch = `[
+			{"correlation_id":"1","original_url":"https://quota.com/1"},
+			{"correlation_id":"2","original_url":"https://quota.com/2"}
+		]`
+		res = testHTTPRequest("POST", server.URL()+"/api/shorten/batch", "application/json", batch, cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+
+		Expect(usage()).Should(Equal(&models.QuotaUsage{
+			Quota:       models.Quota{MaxActiveURLs: 2, MaxBatchSize: 2},
+			ActiveURLs:  2,
+			URLsLastDay: 2,
+		}))
+	})
+
+	It("should set user plan", func() {
+		res := testHTTPRequest("PUT", server.URL()+planPath, "application/json", `{"plan":"enterprise"}`)
+		Expect(res.StatusCode).Should(Equal(http.StatusBadRequest))
+		res = testHTTPRequest("PUT", server.URL()+"/internal/users/100500/plan", "application/json", `{"plan":"pro"}`)
+		Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+		res = testHTTPRequest("PUT", server.URL()+planPath, "application/json", `{"plan":"pro"}`)
+		Expect(res.StatusCode).Should(Equal(http.StatusNoContent))
+
+		Expect(usage().Plan).Should(Equal("pro"))
+		res = testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json", `{"url":"https://quota.com/3"}`, cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
 	})
 })
@@ -6,10 +6,15 @@ import (
 	"github.com/ofstudio/go-shortener/internal/pkgerrors"
 )
 
-// respondWithError - возвращает клиенту http-ошибку, соответствующую ошибке приложения
+// respondWithError - возвращает клиенту http-ошибку, соответствующую ошибке приложения.
+// Если у ошибки есть уточняющее описание, то оно добавляется к тексту ответа.
 func respondWithError(w http.ResponseWriter, err error) {
 	if appError, ok := err.(*pkgerrors.Error); ok {
-		http.Error(w, http.StatusText(appError.HTTPStatus), appError.HTTPStatus)
+		text := http.StatusText(appError.HTTPStatus)
+		if appError.Detail != "" {
+			text += ": " + appError.Detail
+		}
+		http.Error(w, text, appError.HTTPStatus)
 	} else {
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 	}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+)
+
+// Форматы выгрузки событий переходов
+const (
+	exportFormatCSV   = "csv"
+	exportFormatJSONL = "jsonl"
+)
+
+// exportContentTypes - Content-Type ответа для каждого формата выгрузки
+var exportContentTypes = map[string]string{
+	exportFormatCSV:   "text/csv",
+	exportFormatJSONL: "application/x-ndjson",
+}
+
+// exportBufferSize - размер буфера выгрузки: данные отправляются клиенту частями по мере накопления
+const exportBufferSize = 32 * 1024
+
+// clickEncoder - кодировщик событий переходов для выгрузки
+type clickEncoder interface {
+	// Encode - записывает событие перехода
+	Encode(click models.Click) error
+	// Flush - отправляет накопленные данные
+	Flush() error
+}
+
+// newClickEncoder - создает кодировщик событий переходов в формате format.
+// Если формат не поддерживается, возвращает false.
+func newClickEncoder(w io.Writer, format string) (clickEncoder, bool) {
+	bw := bufio.NewWriterSize(w, exportBufferSize)
+	switch format {
+	case exportFormatCSV:
+		return newCSVClickEncoder(bw), true
+	case exportFormatJSONL:
+		return &jsonlClickEncoder{w: bw, enc: json.NewEncoder(bw)}, true
+	default:
+		return nil, false
+	}
+}
+
+// csvColumns - колонки выгрузки событий переходов в формате CSV
+var csvColumns = []string{"short_url_id", "time", "referer_host", "device", "ip", "country", "visitor", "bot"}
+
+// csvClickEncoder - кодировщик событий переходов в формате CSV с заголовком
+type csvClickEncoder struct {
+	w      *bufio.Writer
+	csv    *csv.Writer
+	header bool
+}
+
+// newCSVClickEncoder - конструктор csvClickEncoder
+func newCSVClickEncoder(w *bufio.Writer) *csvClickEncoder {
+	return &csvClickEncoder{w: w, csv: csv.NewWriter(w)}
+}
+
+// Encode - записывает событие перехода строкой CSV.
+// Перед первым событием записывается заголовок.
+func (e *csvClickEncoder) Encode(click models.Click) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.csv.Write([]string{
+		click.ShortURLID,
+		click.Time.UTC().Format(time.RFC3339),
+		click.RefererHost,
+		string(click.Device),
+		click.IP,
+		click.Country,
+		click.Visitor,
+		strconv.FormatBool(click.Bot),
+	})
+}
+
+// Flush - см. clickEncoder.Flush.
+// Заголовок записывается и при выгрузке без событий.
+func (e *csvClickEncoder) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// writeHeader - записывает заголовок CSV, если он еще не записан
+func (e *csvClickEncoder) writeHeader() error {
+	if e.header {
+		return nil
+	}
+	e.header = true
+	return e.csv.Write(csvColumns)
+}
+
+// jsonlClickEncoder - кодировщик событий переходов в формате JSON Lines: одно событие в строке
+type jsonlClickEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// Encode - записывает событие перехода строкой JSON
+func (e *jsonlClickEncoder) Encode(click models.Click) error {
+	return e.enc.Encode(click)
+}
+
+// Flush - см. clickEncoder.Flush
+func (e *jsonlClickEncoder) Flush() error {
+	return e.w.Flush()
+}
@@ -2,46 +2,191 @@ package handlers
 
 import (
 	"errors"
+	"html/template"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/ofstudio/go-shortener/internal/models"
 	"github.com/ofstudio/go-shortener/internal/pkgerrors"
 	"github.com/ofstudio/go-shortener/internal/providers/auth"
+	"github.com/ofstudio/go-shortener/internal/providers/ipcheck"
+	"github.com/ofstudio/go-shortener/internal/providers/metrics"
 	"github.com/ofstudio/go-shortener/internal/usecases"
 )
 
+// Параметры cookie для закрепления варианта сплит-ссылки за посетителем
+const (
+	splitCookiePrefix = "split_"
+	splitCookieTTL    = 30 * 24 * time.Hour
+)
+
 // HTTPHandlers - HTTP-хендлеры приложения
 type HTTPHandlers struct {
-	u *usecases.Container
+	u           *usecases.Container
+	placeholder *template.Template
+	metrics     metrics.Recorder
+	proxies     *ipcheck.Whitelist
+}
+
+// HTTPOpt - дополнительный параметр HTTPHandlers
+type HTTPOpt func(*HTTPHandlers)
+
+// WithPlaceholder - задает шаблон страницы-заглушки, которая показывается
+// при переходе по ссылке, период действия которой еще не начался.
+// В шаблоне доступны поля {{.ShortURL}}, {{.Title}} и {{.ActiveFrom}}.
+// Если шаблон не задан, то возвращается ответ http.StatusNotFound (404).
+func WithPlaceholder(t *template.Template) HTTPOpt {
+	return func(h *HTTPHandlers) {
+		h.placeholder = t
+	}
+}
+
+// WithMetrics - задает учет попыток перехода по коротким ссылкам.
+func WithMetrics(m metrics.Recorder) HTTPOpt {
+	return func(h *HTTPHandlers) {
+		h.metrics = m
+	}
+}
+
+// WithTrustedProxies - задает адреса и подсети прокси-серверов в формате IP или CIDR,
+// от которых принимаются IP-адрес и страна посетителя в заголовках X-Real-IP и countryHeaders.
+// Если параметр не задан, заголовки игнорируются и используется адрес соединения.
+func WithTrustedProxies(addrs ...string) HTTPOpt {
+	return func(h *HTTPHandlers) {
+		h.proxies = ipcheck.NewWhitelist(addrs...)
+	}
 }
 
 // NewHTTPHandlers - конструктор HTTPHandlers
-func NewHTTPHandlers(u *usecases.Container) *HTTPHandlers {
-	return &HTTPHandlers{u: u}
+func NewHTTPHandlers(u *usecases.Container, opts ...HTTPOpt) *HTTPHandlers {
+	h := &HTTPHandlers{u: u, metrics: metrics.Nop{}}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
-// Routes - возвращает роутер для HTTP-хендлеров
+// Routes - возвращает роутер для HTTP-хендлеров.
+// Переходы по ссылкам и проверка доступности не создают новых пользователей.
 func (h HTTPHandlers) Routes() chi.Router {
 	r := chi.NewRouter()
 	r.Get("/ping", h.ping)
 	r.Get("/{id}", h.shortURLRedirectToOriginal)
-	r.Post("/", h.shortURLCreate)
+	r.Head("/{id}", h.shortURLRedirectToOriginal)
+	r.Get("/{id}+", h.shortURLPreview)
+	r.With(auth.Require(auth.CreateUser), auth.RequireScope(models.APIScopeCreate)).Post("/", h.shortURLCreate)
 	return r
 }
 
 // shortURLRedirectToOriginal - принимает в качестве URL-параметра идентификатор сокращённого URL
 // и возвращает ответ с кодом http.StatusTemporaryRedirect (307) и оригинальным URL
 // в HTTP-заголовке Location.
+// Если задан параметр ?preview=1, то вместо перенаправления возвращает страницу предпросмотра (см. shortURLPreview).
+// Если период действия ссылки еще не начался, возвращает http.StatusNotFound (404) либо страницу-заглушку,
+// если она задана. Если период действия ссылки закончился, возвращает http.StatusGone (410).
+// Если для ссылки или для всего сервиса включен режим страницы-предупреждения,
+// то вместо перенаправления возвращает страницу со ссылкой для продолжения перехода.
+// Если у ссылки заданы правила перенаправления, то URL выбирается по первому сработавшему правилу.
+// Если ссылка является сплит-ссылкой, то URL выбирается из вариантов по их весам.
+// Для сплит-ссылок с закреплением выбранный вариант сохраняется в cookie.
+// Если у ссылки задан шаблон query-параметров, то они добавляются к URL.
+// Переходы ботов (см. usecases.ShortURL.DetectBot) сохраняются с отметкой bot.
+// Если включен режим BotPreview, то ботам вместо перенаправления возвращается легкая страница
+// со ссылкой на URL назначения, а переход не засчитывается.
 func (h HTTPHandlers) shortURLRedirectToOriginal(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
+	if r.URL.Query().Get("preview") == "1" {
+		h.shortURLPreview(w, r)
+		return
+	}
 	shortURL, err := h.u.ShortURL.GetByID(r.Context(), id)
+	h.metrics.Redirect(err == nil)
+	if errors.Is(err, pkgerrors.ErrNotActive) && h.placeholder != nil {
+		h.shortURLPlaceholder(w, r, id)
+		return
+	}
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	visit := &models.Visit{
+		Time:           time.Now(),
+		Referer:        r.Referer(),
+		UserAgent:      r.UserAgent(),
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+		IP:             h.remoteIP(r),
+		Country:        h.visitorCountry(r),
+		Method:         r.Method,
+		Purpose:        visitPurpose(r),
+	}
+	if h.u.ShortURL.DetectBot(visit) && h.u.ShortURL.BotPreview() {
+		respondWithPage(w, tmplBot, pageData{
+			ShortURL:    h.u.ShortURL.Resolve(shortURL.ID),
+			Destination: h.u.ShortURL.Destination(shortURL, visit),
+			Title:       shortURL.Title,
+		})
+		return
+	}
+	if c, err := r.Cookie(splitCookiePrefix + id); err == nil {
+		visit.Variant, _ = strconv.Atoi(c.Value)
+	}
+	dest, variant := h.u.ShortURL.Redirect(r.Context(), shortURL, visit)
+	if variant > 0 && shortURL.Split.Sticky {
+		http.SetCookie(w, &http.Cookie{
+			Name:     splitCookiePrefix + id,
+			Value:    strconv.Itoa(variant),
+			Path:     "/" + id,
+			MaxAge:   int(splitCookieTTL / time.Second),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	if h.u.ShortURL.Interstitial(shortURL) {
+		respondWithPage(w, tmplInterstitial, pageData{
+			ShortURL:    h.u.ShortURL.Resolve(shortURL.ID),
+			Destination: dest,
+			Title:       shortURL.Title,
+		})
+		return
+	}
+	http.Redirect(w, r, dest, http.StatusTemporaryRedirect)
+}
+
+// shortURLPlaceholder - возвращает страницу-заглушку для ссылки id, период действия которой еще не начался
+func (h HTTPHandlers) shortURLPlaceholder(w http.ResponseWriter, r *http.Request, id string) {
+	shortURL, err := h.u.ShortURL.GetNotActive(r.Context(), id)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondWithPage(w, h.placeholder, pageData{
+		ShortURL:   h.u.ShortURL.Resolve(shortURL.ID),
+		Title:      shortURL.Title,
+		ActiveFrom: *shortURL.ActiveFrom,
+	})
+}
+
+// shortURLPreview - возвращает HTML-страницу предпросмотра короткой ссылки:
+// оригинальный URL, заголовок и дату создания ссылки.
+// Переход по ссылке при этом не засчитывается.
+func (h HTTPHandlers) shortURLPreview(w http.ResponseWriter, r *http.Request) {
+	shortURL, err := h.u.ShortURL.GetByID(r.Context(), chi.URLParam(r, "id"))
 	if err != nil {
 		respondWithError(w, err)
 		return
 	}
-	http.Redirect(w, r, shortURL.OriginalURL, http.StatusTemporaryRedirect)
+	respondWithPage(w, tmplPreview, pageData{
+		ShortURL:    h.u.ShortURL.Resolve(shortURL.ID),
+		Destination: shortURL.OriginalURL,
+		Title:       shortURL.Title,
+		CreatedAt:   shortURL.CreatedAt,
+	})
 }
 
 // shortURLCreate - принимает в теле запроса строку URL для сокращения
@@ -93,3 +238,58 @@ func (h HTTPHandlers) ping(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusOK)
 }
+
+// remoteIP - возвращает IP-адрес посетителя из заголовка X-Real-IP, если запрос пришел
+// от доверенного прокси-сервера (см. WithTrustedProxies), иначе - адрес соединения.
+func (h HTTPHandlers) remoteIP(r *http.Request) net.IP {
+	ip := connIP(r)
+	if !h.fromTrustedProxy(ip) {
+		return ip
+	}
+	if realIP := net.ParseIP(r.Header.Get("X-Real-IP")); realIP != nil {
+		return realIP
+	}
+	return ip
+}
+
+// connIP - возвращает IP-адрес соединения
+func connIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
+
+// fromTrustedProxy - проверяет, что IP-адрес соединения принадлежит доверенному прокси-серверу
+func (h HTTPHandlers) fromTrustedProxy(ip net.IP) bool {
+	return h.proxies != nil && ip != nil && h.proxies.IsAllowed(ip)
+}
+
+// countryHeaders - заголовки, в которых прокси-сервер или CDN передает код страны посетителя
+var countryHeaders = []string{"CF-IPCountry", "X-Country-Code"}
+
+// visitorCountry - возвращает код страны посетителя (ISO 3166-1 alpha-2) из заголовков countryHeaders
+// либо пустую строку, если код страны неизвестен или запрос пришел не от доверенного прокси-сервера.
+func (h HTTPHandlers) visitorCountry(r *http.Request) string {
+	if !h.fromTrustedProxy(connIP(r)) {
+		return ""
+	}
+	for _, header := range countryHeaders {
+		code := strings.ToUpper(strings.TrimSpace(r.Header.Get(header)))
+		if len(code) == 2 && code != "XX" &&
+			code[0] >= 'A' && code[0] <= 'Z' && code[1] >= 'A' && code[1] <= 'Z' {
+			return code
+		}
+	}
+	return ""
+}
+
+// visitPurpose - возвращает назначение запроса из заголовка Sec-Purpose, а если он не задан - из заголовка Purpose.
+// Браузеры отмечают так предварительную загрузку страниц (prefetch).
+func visitPurpose(r *http.Request) string {
+	if purpose := r.Header.Get("Sec-Purpose"); purpose != "" {
+		return purpose
+	}
+	return r.Header.Get("Purpose")
+}
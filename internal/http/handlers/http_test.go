@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"context"
+	"html/template"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
@@ -52,10 +54,14 @@ var _ = Describe("shortURL handlers", func() {
 			shortURLPath = testParseURL(string(resBody)).Path
 		})
 		It("successfully retrieve short url", func() {
+			n, err := u.User.Count(context.Background())
+			Expect(err).ShouldNot(HaveOccurred())
 			res := testHTTPRequest("GET", server.URL()+shortURLPath, "", "")
 			Expect(res.StatusCode).Should(Equal(http.StatusTemporaryRedirect))
 			Expect(res.Header.Get("Location")).Should(Equal("https://www.google.com"))
-
+			// Анонимный переход не создает нового пользователя
+			Expect(res.Cookies()).Should(BeEmpty())
+			Expect(u.User.Count(context.Background())).Should(Equal(n))
 		})
 	})
 
@@ -101,6 +107,85 @@ var _ = Describe("shortURL handlers", func() {
 		})
 	})
 
+	When("sticky split link", func() {
+		It("redirects to the same variant", func() {
+			user := &models.User{}
+			Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+			shortURL, err := u.ShortURL.Create(context.Background(), user.ID, "https://www.split.com",
+				usecases.WithSplit(&models.Split{
+					Variants: []models.Variant{
+						{URL: "https://www.split.com/a", Weight: 1},
+						{URL: "https://www.split.com/b", Weight: 1},
+					},
+					Sticky: true,
+				}))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			res1 := testHTTPRequest("GET", server.URL()+"/"+shortURL.ID, "", "")
+			Expect(res1.StatusCode).Should(Equal(http.StatusTemporaryRedirect))
+			var cookie *http.Cookie
+			for _, c := range res1.Cookies() {
+				if c.Name == "split_"+shortURL.ID {
+					cookie = c
+				}
+			}
+			Expect(cookie).ShouldNot(BeNil())
+
+			res2 := testHTTPRequest("GET", server.URL()+"/"+shortURL.ID, "", "", cookie)
+			Expect(res2.StatusCode).Should(Equal(http.StatusTemporaryRedirect))
+			Expect(res2.Header.Get("Location")).Should(Equal(res1.Header.Get("Location")))
+		})
+	})
+
+	When("preview requested", func() {
+		var shortURL *models.ShortURL
+		BeforeEach(func() {
+			user := &models.User{}
+			Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+			var err error
+			shortURL, err = u.ShortURL.Create(context.Background(), user.ID, "https://www.preview.com/?q=<b>",
+				usecases.WithTitle("Quarterly <report>"))
+			if err != nil {
+				shortURL, err = u.ShortURL.GetByOriginalURL(context.Background(), user.ID, "https://www.preview.com/?q=<b>")
+			}
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+		It("renders preview page by /{id}+", func() {
+			res := testHTTPRequest("GET", server.URL()+"/"+shortURL.ID+"+", "", "")
+			Expect(res.StatusCode).Should(Equal(http.StatusOK))
+			Expect(res.Header.Get("Content-Type")).Should(HavePrefix("text/html"))
+			body, err := io.ReadAll(res.Body)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(body)).Should(ContainSubstring("Quarterly &lt;report&gt;"))
+			Expect(string(body)).Should(ContainSubstring("https://www.preview.com/?q=%3cb%3e"))
+			Expect(string(body)).Should(ContainSubstring(shortURL.CreatedAt.Format("02.01.2006")))
+		})
+		It("renders preview page by ?preview=1", func() {
+			res := testHTTPRequest("GET", server.URL()+"/"+shortURL.ID+"?preview=1", "", "")
+			Expect(res.StatusCode).Should(Equal(http.StatusOK))
+			Expect(res.Header.Get("Location")).Should(BeEmpty())
+		})
+		It("returns 404 for unknown id", func() {
+			res := testHTTPRequest("GET", server.URL()+"/unknown+", "", "")
+			Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+		})
+	})
+
+	When("interstitial enabled for short url", func() {
+		It("renders interstitial page", func() {
+			user := &models.User{}
+			Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+			shortURL, err := u.ShortURL.Create(context.Background(), user.ID, "https://www.interstitial.com",
+				usecases.WithInterstitial(true))
+			Expect(err).ShouldNot(HaveOccurred())
+			res := testHTTPRequest("GET", server.URL()+"/"+shortURL.ID, "", "")
+			Expect(res.StatusCode).Should(Equal(http.StatusOK))
+			body, err := io.ReadAll(res.Body)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(body)).Should(ContainSubstring(`href="https://www.interstitial.com"`))
+		})
+	})
+
 	When("duplicate url sent", func() {
 		It("returns 409 error", func() {
 			res1 := testHTTPRequest("POST", server.URL()+"/", "", "https://www.duplicate.com")
@@ -108,7 +193,9 @@ var _ = Describe("shortURL handlers", func() {
 			resBody1, err := io.ReadAll(res1.Body)
 			Expect(err).ShouldNot(HaveOccurred())
 			_ = res1.Body.Close()
-			res2 := testHTTPRequest("POST", server.URL()+"/", "", "https://www.duplicate.com")
+			// Повторно сокращает URL тот же пользователь
+			Expect(res1.Cookies()).ShouldNot(BeEmpty())
+			res2 := testHTTPRequest("POST", server.URL()+"/", "", "https://www.duplicate.com", res1.Cookies()[0])
 			Expect(res2.StatusCode).Should(Equal(http.StatusConflict))
 			resBody2, err := io.ReadAll(res2.Body)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -119,6 +206,162 @@ var _ = Describe("shortURL handlers", func() {
 
 })
 
+var _ = Describe("server-wide interstitial", func() {
+	It("renders interstitial page for any short url", func() {
+		cfg, _ := config.Default(nil)
+		cfg.Interstitial = true
+		u := usecases.NewContainer(context.Background(), cfg, repo.NewMemoryRepo())
+		user := &models.User{}
+		Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+		shortURL, err := u.ShortURL.Create(context.Background(), user.ID, "https://www.google.com")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		server := ghttp.NewServer()
+		defer server.Close()
+		server.AppendHandlers(NewHTTPHandlers(u).Routes().ServeHTTP)
+		res := testHTTPRequest("GET", server.URL()+"/"+shortURL.ID, "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(body)).Should(ContainSubstring("Продолжить"))
+	})
+})
+
+var _ = Describe("bot preview", func() {
+	It("renders lightweight page for bots without counting a click", func() {
+		cfg, _ := config.Default(nil)
+		cfg.BotPreview = true
+		stopCtx, stop := context.WithCancel(context.Background())
+		defer stop()
+		r := repo.NewMemoryRepo()
+		u := usecases.NewContainer(stopCtx, cfg, r)
+		user := &models.User{}
+		Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+		shortURL, err := u.ShortURL.Create(context.Background(), user.ID, "https://www.unfurl.com",
+			usecases.WithTitle("Unfurl me"))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		server := ghttp.NewServer()
+		defer server.Close()
+		h := NewHTTPHandlers(u).Routes().ServeHTTP
+		server.AppendHandlers(h, h)
+
+		// Бот получает легкую страницу со ссылкой на URL назначения
+		c := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+		req, err := http.NewRequest("GET", server.URL()+"/"+shortURL.ID, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		req.Header.Set("User-Agent", "TelegramBot (like TwitterBot)")
+		res, err := c.Do(req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+		Expect(string(body)).Should(ContainSubstring(`<meta property="og:url" content="https://www.unfurl.com">`))
+		Expect(string(body)).Should(ContainSubstring(`<meta property="og:title" content="Unfurl me">`))
+
+		// Человек перенаправляется как обычно
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 13_0)")
+		res, err = c.Do(req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Error().ShouldNot(HaveOccurred())
+		Expect(res.StatusCode).Should(Equal(http.StatusTemporaryRedirect))
+
+		// Засчитан только переход человека
+		stop()
+		u.ShortURL.Wait()
+		clicks, err := r.ClickGetByShortURLID(context.Background(), shortURL.ID, time.Time{}, time.Time{})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(clicks).Should(HaveLen(1))
+		Expect(clicks[0].Bot).Should(BeFalse())
+	})
+})
+
+var _ = Describe("activation window", func() {
+	cfg, _ := config.Default(nil)
+	u := usecases.NewContainer(context.Background(), cfg, repo.NewMemoryRepo())
+	var (
+		server *ghttp.Server
+		user   *models.User
+	)
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		user = &models.User{}
+		Expect(u.User.Create(context.Background(), user)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("returns 404 before the window starts", func() {
+		server.AppendHandlers(NewHTTPHandlers(u).Routes().ServeHTTP)
+		shortURL, err := u.ShortURL.Create(context.Background(), user.ID, "https://www.soon.com",
+			usecases.WithActiveWindow(&future, nil))
+		Expect(err).ShouldNot(HaveOccurred())
+		res := testHTTPRequest("GET", server.URL()+"/"+shortURL.ID, "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusNotFound))
+	})
+
+	It("renders placeholder page before the window starts", func() {
+		placeholder := template.Must(template.New("placeholder").Parse(`Starts at {{.ActiveFrom.Format "2006-01-02"}}`))
+		server.AppendHandlers(NewHTTPHandlers(u, WithPlaceholder(placeholder)).Routes().ServeHTTP)
+		shortURL, err := u.ShortURL.Create(context.Background(), user.ID, "https://www.placeholder.com",
+			usecases.WithActiveWindow(&future, nil))
+		Expect(err).ShouldNot(HaveOccurred())
+		res := testHTTPRequest("GET", server.URL()+"/"+shortURL.ID, "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(body)).Should(Equal("Starts at " + future.Format("2006-01-02")))
+	})
+
+	It("returns 410 after the window ends", func() {
+		server.AppendHandlers(NewHTTPHandlers(u).Routes().ServeHTTP)
+		shortURL, err := u.ShortURL.Create(context.Background(), user.ID, "https://www.expired.com",
+			usecases.WithActiveWindow(nil, &past))
+		Expect(err).ShouldNot(HaveOccurred())
+		res := testHTTPRequest("GET", server.URL()+"/"+shortURL.ID, "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusGone))
+	})
+})
+
+var _ = Describe("visitor address", func() {
+	newRequest := func(remoteAddr string) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Real-IP", "203.0.113.7")
+		req.Header.Set("CF-IPCountry", "de")
+		return req
+	}
+
+	It("ignores headers without trusted proxies", func() {
+		h := NewHTTPHandlers(nil)
+		req := newRequest("198.51.100.1:4321")
+		Expect(h.remoteIP(req).String()).Should(Equal("198.51.100.1"))
+		Expect(h.visitorCountry(req)).Should(BeEmpty())
+	})
+	It("ignores headers from untrusted address", func() {
+		h := NewHTTPHandlers(nil, WithTrustedProxies("10.0.0.0/8"))
+		req := newRequest("198.51.100.1:4321")
+		Expect(h.remoteIP(req).String()).Should(Equal("198.51.100.1"))
+		Expect(h.visitorCountry(req)).Should(BeEmpty())
+	})
+	It("accepts headers from trusted proxy", func() {
+		h := NewHTTPHandlers(nil, WithTrustedProxies("10.0.0.0/8", "127.0.0.1"))
+		req := newRequest("10.1.2.3:4321")
+		Expect(h.remoteIP(req).String()).Should(Equal("203.0.113.7"))
+		Expect(h.visitorCountry(req)).Should(Equal("DE"))
+		req = newRequest("127.0.0.1:4321")
+		req.Header.Del("X-Real-IP")
+		Expect(h.remoteIP(req).String()).Should(Equal("127.0.0.1"))
+	})
+})
+
 var _ = Describe("ping handler", func() {
 	When("no sql database configured", func() {
 		It("returns 200", func() {
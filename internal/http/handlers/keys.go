@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/providers/auth"
+)
+
+// apiKeyResType - API-ключ в ответе. Хеш ключа не передается.
+type apiKeyResType struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Scopes    []models.APIScope `json:"scopes"`
+	CreatedAt time.Time         `json:"created_at"`
+	RevokedAt *time.Time        `json:"revoked_at,omitempty"`
+	Key       string            `json:"key,omitempty"` // Сам ключ, только в ответе на создание
+}
+
+// newAPIKeyResType - конструктор apiKeyResType
+func newAPIKeyResType(key *models.APIKey) apiKeyResType {
+	return apiKeyResType{
+		ID:        key.ID,
+		Name:      key.Name,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+// apiKeyCreate - создает API-ключ пользователя для программных клиентов.
+// Формат запроса:
+//
+//	{"name": "CI", "scopes": ["read", "create"]}
+//
+// Права доступа: read - получение ссылок, правил и статистики, create - создание ссылок и изменение правил,
+// delete - удаление ссылок.
+//
+// Возвращает ответ http.StatusCreated (201) и ключ. Ключ возвращается только один раз:
+//
+//	{
+//	    "id": "9f86d081884c7d65",
+//	    "name": "CI",
+//	    "scopes": ["read", "create"],
+//	    "created_at": "2023-03-08T09:00:00Z",
+//	    "key": "shk_9f86d081884c7d65_..."
+//	}
+//
+// Ключ передается в заголовке Authorization: Bearer <key>.
+// Управление ключами доступно только с токеном пользователя.
+//
+// @Tags keys
+// @Summary Создает API-ключ
+// @Security cookieAuth
+// @ID apiKeyCreate
+// @Accept  json
+// @Produce json
+// @Param   request body handlers.apiKeyCreate.reqType true "Запрос"
+// @Success 201 {object} handlers.apiKeyResType
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 500
+// @Router /user/keys [post]
+func (h APIHandlers) apiKeyCreate(w http.ResponseWriter, r *http.Request) {
+	// Структура запроса
+	type reqType struct {
+		Name   string            `json:"name"`
+		Scopes []models.APIScope `json:"scopes"`
+	}
+
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Читаем body запроса
+	reqJSON := &reqType{}
+	if err := parseJSONRequest(r, reqJSON); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Создаем ключ
+	key, plain, err := h.u.APIKey.Create(r.Context(), userID, reqJSON.Name, reqJSON.Scopes)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Возвращаем ответ
+	res := newAPIKeyResType(key)
+	res.Key = plain
+	respondWithJSON(w, http.StatusCreated, res)
+}
+
+// apiKeyList - возвращает API-ключи пользователя, в тч отозванные, без самих ключей.
+//
+// @Tags keys
+// @Summary Возвращает API-ключи пользователя
+// @Security cookieAuth
+// @ID apiKeyList
+// @Produce json
+// @Success 200 {array} handlers.apiKeyResType
+// @Failure 401
+// @Failure 403
+// @Failure 500
+// @Router /user/keys [get]
+func (h APIHandlers) apiKeyList(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Получаем ключи
+	keys, err := h.u.APIKey.GetByUserID(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Возвращаем ответ
+	res := make([]apiKeyResType, 0, len(keys))
+	for i := range keys {
+		res = append(res, newAPIKeyResType(&keys[i]))
+	}
+	respondWithJSON(w, http.StatusOK, res)
+}
+
+// apiKeyRevoke - отзывает API-ключ пользователя.
+// Отозванный ключ больше не принимается, но остается в списке ключей.
+// Возвращает ответ http.StatusNoContent (204).
+//
+// @Tags keys
+// @Summary Отзывает API-ключ
+// @Security cookieAuth
+// @ID apiKeyRevoke
+// @Param   id path string true "Идентификатор ключа"
+// @Success 204
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /user/keys/{id} [delete]
+func (h APIHandlers) apiKeyRevoke(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Отзываем ключ
+	if err := h.u.APIKey.Revoke(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		respondWithError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/providers/auth"
+	"github.com/ofstudio/go-shortener/internal/providers/oidc"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+// oidcFlowCookie - кука с параметрами входа через OpenID Connect
+const oidcFlowCookie = "oidc_flow"
+
+// oidcFlowTTL - время на вход у провайдера OpenID Connect
+const oidcFlowTTL = 10 * time.Minute
+
+// OIDCHandlers - HTTP-хендлеры для входа через провайдера OpenID Connect
+type OIDCHandlers struct {
+	u      *usecases.Container
+	p      oidc.Provider
+	secure bool // Передавать куку только по HTTPS
+}
+
+// NewOIDCHandlers - конструктор OIDCHandlers.
+// Кука с параметрами входа передается только по HTTPS, если baseURL использует протокол https,
+// так же как авторизационная кука: сервер может работать за прокси-сервером, завершающим TLS.
+func NewOIDCHandlers(u *usecases.Container, p oidc.Provider, baseURL url.URL) *OIDCHandlers {
+	return &OIDCHandlers{u: u, p: p, secure: baseURL.Scheme == "https"}
+}
+
+// Routes - возвращает роутер с хендлерами. API-ключи не принимаются.
+func (h OIDCHandlers) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(auth.NoAPIKey)
+	r.Get("/login", h.oidcLogin)
+	r.Get("/callback", h.oidcCallback)
+	return r
+}
+
+// oidcLogin - начинает вход через провайдера OpenID Connect:
+// сохраняет параметры входа в куку oidc_flow и перенаправляет на страницу входа у провайдера.
+// Возвращает ответ http.StatusFound (302).
+//
+// @Tags account
+// @Summary Начинает вход через OpenID Connect
+// @ID oidcLogin
+// @Success 302
+// @Failure 403
+// @Failure 500
+// @Router /auth/oidc/login [get]
+func (h OIDCHandlers) oidcLogin(w http.ResponseWriter, r *http.Request) {
+	authURL, flow, err := h.p.Begin()
+	if err != nil {
+		log.Err(err).Msg("failed to begin oidc login")
+		respondWithError(w, pkgerrors.ErrInternal)
+		return
+	}
+	h.setFlowCookie(w, flow.String(), int(oidcFlowTTL.Seconds()))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// oidcCallback - завершает вход через провайдера OpenID Connect.
+// Проверяет параметр state по куке oidc_flow, обменивает код авторизации на ID-токен
+// и определяет пользователя по идентификатору у провайдера.
+// При первом входе регистрируется текущий анонимный пользователь, если он есть: все его ссылки сохраняются за ним.
+//
+// Возвращает ответ http.StatusFound (302) с перенаправлением на главную страницу
+// и устанавливает токен пользователя в куку auth_token.
+//
+// @Tags account
+// @Summary Завершает вход через OpenID Connect
+// @Security cookieAuth
+// @ID oidcCallback
+// @Param   code  query string true "Код авторизации"
+// @Param   state query string true "Параметр state"
+// @Success 302
+// @Failure 401
+// @Failure 403
+// @Failure 500
+// @Router /auth/oidc/callback [get]
+func (h OIDCHandlers) oidcCallback(w http.ResponseWriter, r *http.Request) {
+	// Параметры входа одноразовые
+	cookie, err := r.Cookie(oidcFlowCookie)
+	h.setFlowCookie(w, "", -1)
+	if err != nil {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+	flow, err := oidc.ParseFlow(cookie.Value)
+	state := r.URL.Query().Get("state")
+	if err != nil || subtle.ConstantTimeCompare([]byte(state), []byte(flow.State)) != 1 {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Провайдер вернул ошибку, например, пользователь отказался от входа
+	if errCode := r.URL.Query().Get("error"); errCode != "" {
+		respondWithError(w, pkgerrors.ErrAuth.WithDetail(errCode))
+		return
+	}
+
+	// Проверяем вход у провайдера
+	identity, err := h.p.Finish(r.Context(), flow, r.URL.Query().Get("code"))
+	if errors.Is(err, oidc.ErrLoginFailed) || errors.Is(err, oidc.ErrInvalidFlow) {
+		log.Warn().Err(err).Msg("oidc login failed")
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	} else if err != nil {
+		log.Err(err).Msg("failed to finish oidc login")
+		respondWithError(w, pkgerrors.ErrInternal)
+		return
+	}
+
+	// Определяем пользователя и выдаем ему токен
+	claimID, _ := auth.FromContext(r.Context())
+	user, err := h.u.User.LoginOIDC(r.Context(), claimID, identity.Issuer, identity.Subject)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	if err = auth.Login(w, r, user.ID); err != nil {
+		log.Err(err).Msg("failed to issue auth token")
+		respondWithError(w, pkgerrors.ErrInternal)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// setFlowCookie - устанавливает куку с параметрами входа и временем жизни maxAge в секундах.
+// Кука должна передаваться при возврате от провайдера, поэтому SameSite=Lax.
+func (h OIDCHandlers) setFlowCookie(w http.ResponseWriter, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcFlowCookie,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   h.secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+
+	"github.com/go-chi/chi/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/providers/auth"
+	"github.com/ofstudio/go-shortener/internal/providers/oidc"
+	"github.com/ofstudio/go-shortener/internal/providers/oidc/oidctest"
+	"github.com/ofstudio/go-shortener/internal/repo"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+var _ = Describe("/auth/oidc/login, /auth/oidc/callback", func() {
+	var server *ghttp.Server
+	var idp *oidctest.Server
+	var client *oidc.Client
+	cfg, _ := config.Default(nil)
+	repository := repo.NewMemoryRepo()
+	u := usecases.NewContainer(context.Background(), cfg, repository)
+
+	// findCookie - возвращает куку name из ответа
+	findCookie := func(res *http.Response, name string) *http.Cookie {
+		for _, c := range res.Cookies() {
+			if c.Name == name {
+				return c
+			}
+		}
+		return nil
+	}
+
+	// login - проходит вход у тестового провайдера с кукой cookie.
+	// Возвращает куку с параметрами входа и адрес возврата от провайдера.
+	login := func(cookie *http.Cookie) (*http.Cookie, string) {
+		res := testHTTPRequest("GET", server.URL()+"/api/auth/oidc/login", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusFound))
+		flow := findCookie(res, "oidc_flow")
+		Expect(flow).ShouldNot(BeNil())
+		Expect(flow.HttpOnly).Should(BeTrue())
+		Expect(flow.Secure).Should(BeFalse())
+
+		// Страница входа провайдера перенаправляет обратно с кодом авторизации
+		res = testHTTPRequest("GET", res.Header.Get("Location"), "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusFound))
+		callback := res.Header.Get("Location")
+		Expect(callback).Should(HavePrefix(server.URL() + "/api/auth/oidc/callback?"))
+		return flow, callback
+	}
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		idp = oidctest.NewServer("shortener", "secret")
+		cfg.BaseURL = testParseURL(server.URL() + "/")
+		cfg.OIDCIssuer, cfg.OIDCClientID, cfg.OIDCClientSecret = idp.URL, "shortener", "secret"
+		var err error
+		client, err = oidc.NewClient(context.Background(), cfg)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		r := chi.NewRouter()
+		r.Use(auth.NewSHA256Provider(cfg, u.User).Handler)
+		r.Mount("/api/auth/oidc/", NewOIDCHandlers(u, client, cfg.BaseURL).Routes())
+		r.Mount("/api", NewAPIHandlers(u).PublicRoutes())
+		server.RouteToHandler("GET", regexp.MustCompile(`.*`), r.ServeHTTP)
+		server.RouteToHandler("POST", regexp.MustCompile(`.*`), r.ServeHTTP)
+	})
+	AfterEach(func() {
+		server.Close()
+		idp.Close()
+	})
+
+	It("should claim anonymous links on first login and restore them on next login", func() {
+		// Анонимный пользователь создает ссылку
+		res := testHTTPRequest("POST", server.URL()+"/api/shorten", "application/json", `{"url":"https://example.com/oidc"}`)
+		Expect(res.StatusCode).Should(Equal(http.StatusCreated))
+		anonymous := findCookie(res, "auth_token")
+		Expect(anonymous).ShouldNot(BeNil())
+
+		// Входит через провайдера с текущим токеном
+		idp.SetSubject("alice")
+		flow, callback := login(anonymous)
+		res = testHTTPRequest("GET", callback, "", "", flow, anonymous)
+		Expect(res.StatusCode).Should(Equal(http.StatusFound))
+		Expect(res.Header.Get("Location")).Should(Equal("/"))
+		Expect(findCookie(res, "oidc_flow").MaxAge).Should(BeNumerically("<", 0))
+		Expect(findCookie(res, "auth_token")).ShouldNot(BeNil())
+
+		// Входит снова без токена и получает свои ссылки
+		flow, callback = login(nil)
+		res = testHTTPRequest("GET", callback, "", "", flow)
+		Expect(res.StatusCode).Should(Equal(http.StatusFound))
+		cookie := findCookie(res, "auth_token")
+		Expect(cookie).ShouldNot(BeNil())
+		res = testHTTPRequest("GET", server.URL()+"/api/user/urls", "", "", cookie)
+		Expect(res.StatusCode).Should(Equal(http.StatusOK))
+		body, err := io.ReadAll(res.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.Body.Close()).Should(Succeed())
+		Expect(string(body)).Should(ContainSubstring("https://example.com/oidc"))
+
+		// Пользователь зарегистрирован через провайдера
+		user, err := repository.UserGetByOIDCSubject(context.Background(), idp.URL+" alice")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(user.Registered()).Should(BeTrue())
+	})
+
+	It("should return 401 without flow cookie or with wrong state", func() {
+		flow, callback := login(nil)
+		res := testHTTPRequest("GET", callback, "", "")
+		Expect(res.StatusCode).Should(Equal(http.StatusUnauthorized))
+
+		other, _ := login(nil)
+		res = testHTTPRequest("GET", callback, "", "", other)
+		Expect(res.StatusCode).Should(Equal(http.StatusUnauthorized))
+		Expect(findCookie(res, "auth_token")).Should(BeNil())
+
+		// Код авторизации принимается с правильными параметрами входа
+		res = testHTTPRequest("GET", callback, "", "", flow)
+		Expect(res.StatusCode).Should(Equal(http.StatusFound))
+	})
+
+	It("should return 401 on provider error", func() {
+		flow, callback := login(nil)
+		res := testHTTPRequest("GET", callback+"&error=access_denied", "", "", flow)
+		Expect(res.StatusCode).Should(Equal(http.StatusUnauthorized))
+	})
+
+	It("should set secure flow cookie for https base URL behind TLS-terminating proxy", func() {
+		h := NewOIDCHandlers(u, client, testParseURL("https://sho.rt/"))
+		rec := httptest.NewRecorder()
+		h.Routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/login", nil))
+		Expect(rec.Code).Should(Equal(http.StatusFound))
+		flow := findCookie(rec.Result(), "oidc_flow")
+		Expect(flow).ShouldNot(BeNil())
+		Expect(flow.Secure).Should(BeTrue())
+	})
+})
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"net/http"
+	"time"
+)
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+// templates - HTML-шаблоны страниц
+var templates = template.Must(template.ParseFS(templatesFS, "templates/*.html"))
+
+// HTML-шаблоны страниц
+var (
+	tmplPreview      = templates.Lookup("preview.html")
+	tmplInterstitial = templates.Lookup("interstitial.html")
+	tmplBot          = templates.Lookup("bot.html")
+)
+
+// pageData - данные для HTML-шаблона страницы короткой ссылки
+type pageData struct {
+	ShortURL    string
+	Destination string
+	Title       string
+	CreatedAt   time.Time
+	ActiveFrom  time.Time
+}
+
+// respondWithPage - HTTP ответ в виде HTML-страницы по шаблону t
+func respondWithPage(w http.ResponseWriter, t *template.Template, data pageData) {
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, data); err != nil {
+		respondWithError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
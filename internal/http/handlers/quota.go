@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/providers/auth"
+)
+
+// quotaGet - возвращает квоты пользователя и их текущее использование.
+// Квоты определяются тарифным планом пользователя, значение 0 означает отсутствие ограничения.
+// Формат ответа:
+//
+//	{
+//	    "plan": "pro",              // если назначен
+//	    "max_active_urls": 1000,    // максимальное количество неудаленных ссылок
+//	    "urls_per_day": 100,        // максимальное количество ссылок за последние 24 часа
+//	    "max_batch_size": 100,      // максимальное количество ссылок в пакетном запросе
+//	    "active_urls": 10,
+//	    "urls_last_day": 2
+//	}
+//
+// @Tags user
+// @Summary Возвращает квоты пользователя и их использование
+// @Security cookieAuth
+// @Security BearerAuth
+// @ID quotaGet
+// @Produce json
+// @Success 200 {object} models.QuotaUsage
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Router /user/quota [get]
+func (h APIHandlers) quotaGet(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Получаем квоты
+	usage, err := h.u.Quota.Usage(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, usage)
+}
@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/providers/auth"
+)
+
+// teamCreate - создает команду. Создавший команду пользователь становится ее владельцем.
+// Управление командами доступно только с токеном пользователя.
+// Формат запроса:
+//
+//	{"name": "Marketing"}
+//
+// Возвращает ответ http.StatusCreated (201) и команду:
+//
+//	{"id": 1, "name": "Marketing", "created_at": "2023-03-08T09:00:00Z", "role": "owner"}
+//
+// @Tags teams
+// @Summary Создает команду
+// @Security cookieAuth
+// @ID teamCreate
+// @Accept  json
+// @Produce json
+// @Param   request body handlers.teamCreate.reqType true "Запрос"
+// @Success 201 {object} models.TeamMembership
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 500
+// @Router /user/teams [post]
+func (h APIHandlers) teamCreate(w http.ResponseWriter, r *http.Request) {
+	// Структура запроса
+	type reqType struct {
+		Name string `json:"name"`
+	}
+
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Читаем body запроса
+	reqJSON := &reqType{}
+	if err := parseJSONRequest(r, reqJSON); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Создаем команду
+	team, err := h.u.Team.Create(r.Context(), userID, reqJSON.Name)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, models.TeamMembership{Team: *team, Role: models.TeamRoleOwner})
+}
+
+// teamList - возвращает команды пользователя и его роли в них.
+// Если пользователь не состоит ни в одной команде, возвращает http.StatusNoContent (204).
+//
+// @Tags teams
+// @Summary Возвращает команды пользователя
+// @Security cookieAuth
+// @ID teamList
+// @Produce json
+// @Success 200 {array} models.TeamMembership
+// @Success 204
+// @Failure 401
+// @Failure 403
+// @Failure 500
+// @Router /user/teams [get]
+func (h APIHandlers) teamList(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Получаем команды
+	teams, err := h.u.Team.GetByUserID(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	if len(teams) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, teams)
+}
+
+// teamMembers - возвращает участников команды. Доступно любому участнику команды.
+// Формат ответа:
+//
+//	[
+//	    {"team_id": 1, "user_id": 1, "role": "owner"},
+//	    {"team_id": 1, "user_id": 2, "role": "viewer"}
+//	]
+//
+// @Tags teams
+// @Summary Возвращает участников команды
+// @Security cookieAuth
+// @ID teamMembers
+// @Produce json
+// @Param   id path int true "Идентификатор команды"
+// @Success 200 {array} models.TeamMember
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /user/teams/{id}/members [get]
+func (h APIHandlers) teamMembers(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+	teamID, err := uintURLParam(r, "id")
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Получаем участников
+	members, err := h.u.Team.Members(r.Context(), userID, teamID)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, members)
+}
+
+// teamSetMember - добавляет в команду зарегистрированного пользователя или изменяет его роль.
+// Доступно только владельцам команды. Формат запроса:
+//
+//	{"email": "user@example.com", "role": "editor"}
+//
+// Роли: owner - управление участниками и ссылками команды, editor - создание, изменение и удаление ссылок,
+// viewer - просмотр ссылок и их статистики. В команде должен остаться хотя бы один владелец.
+//
+// Возвращает ответ http.StatusOK (200) и участника команды:
+//
+//	{"team_id": 1, "user_id": 2, "role": "editor"}
+//
+// @Tags teams
+// @Summary Добавляет участника команды или изменяет его роль
+// @Security cookieAuth
+// @ID teamSetMember
+// @Accept  json
+// @Produce json
+// @Param   id path int true "Идентификатор команды"
+// @Param   request body handlers.teamSetMember.reqType true "Запрос"
+// @Success 200 {object} models.TeamMember
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /user/teams/{id}/members [put]
+func (h APIHandlers) teamSetMember(w http.ResponseWriter, r *http.Request) {
+	// Структура запроса
+	type reqType struct {
+		Email string          `json:"email"`
+		Role  models.TeamRole `json:"role"`
+	}
+
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+	teamID, err := uintURLParam(r, "id")
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Читаем body запроса
+	reqJSON := &reqType{}
+	if err = parseJSONRequest(r, reqJSON); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Добавляем участника
+	member, err := h.u.Team.SetMember(r.Context(), userID, teamID, reqJSON.Email, reqJSON.Role)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, member)
+}
+
+// teamRemoveMember - удаляет участника из команды.
+// Владелец может удалить любого участника, остальные участники - только себя (выйти из команды).
+// Ссылки, созданные участником для команды, остаются у команды.
+// Возвращает ответ http.StatusNoContent (204).
+//
+// @Tags teams
+// @Summary Удаляет участника команды
+// @Security cookieAuth
+// @ID teamRemoveMember
+// @Param   id path int true "Идентификатор команды"
+// @Param   user_id path int true "Идентификатор участника"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /user/teams/{id}/members/{user_id} [delete]
+func (h APIHandlers) teamRemoveMember(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+	teamID, err := uintURLParam(r, "id")
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	memberID, err := uintURLParam(r, "user_id")
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Удаляем участника
+	if err = h.u.Team.RemoveMember(r.Context(), userID, teamID, memberID); err != nil {
+		respondWithError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// teamShortURLs - возвращает ссылки команды в формате списка ссылок пользователя (см. shortURLGetByUserID).
+// Доступно любому участнику команды. Если у команды нет ссылок, возвращает http.StatusNoContent (204).
+//
+// @Tags teams
+// @Summary Возвращает ссылки команды
+// @Security cookieAuth
+// @Security BearerAuth
+// @ID teamShortURLs
+// @Produce json
+// @Param   id path int true "Идентификатор команды"
+// @Success 200 {array} handlers.shortURLResType
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Router /user/teams/{id}/urls [get]
+func (h APIHandlers) teamShortURLs(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+	teamID, err := uintURLParam(r, "id")
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Получаем ссылки команды
+	shortURLs, err := h.u.ShortURL.GetByTeamID(r.Context(), userID, teamID)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+	h.respondWithShortURLs(w, shortURLs)
+}
+
+// shortURLSetTeam - передает ссылку команде либо делает ее личной. Формат запроса:
+//
+//	{"team_id": 1}
+//
+// Если team_id равен 0, ссылка становится личной ссылкой создавшего ее пользователя:
+// это может сделать только он сам.
+// Передать ссылку можно в команду, в которой пользователь является владельцем или редактором.
+//
+// Возвращает ответ http.StatusNoContent (204).
+//
+// @Tags user
+// @Summary Передает сокращенную ссылку команде
+// @Security cookieAuth
+// @Security BearerAuth
+// @ID shortURLSetTeam
+// @Accept  json
+// @Param   id path string true "Идентификатор сокращенной ссылки"
+// @Param   request body handlers.shortURLSetTeam.reqType true "Запрос"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Failure 403
+// @Failure 404
+// @Failure 410
+// @Failure 500
+// @Router /user/urls/{id}/team [put]
+func (h APIHandlers) shortURLSetTeam(w http.ResponseWriter, r *http.Request) {
+	// Структура запроса
+	type reqType struct {
+		TeamID uint `json:"team_id"`
+	}
+
+	// Проверяем аутентифицирован ли пользователь
+	userID, ok := auth.FromContext(r.Context())
+	if !ok {
+		respondWithError(w, pkgerrors.ErrAuth)
+		return
+	}
+
+	// Читаем body запроса
+	reqJSON := &reqType{}
+	if err := parseJSONRequest(r, reqJSON); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Передаем ссылку
+	if _, err := h.u.ShortURL.SetTeam(r.Context(), userID, chi.URLParam(r, "id"), reqJSON.TeamID); err != nil {
+		respondWithError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// uintURLParam - возвращает положительный целочисленный параметр пути запроса либо ErrValidation
+func uintURLParam(r *http.Request, name string) (uint, error) {
+	v, err := strconv.ParseUint(chi.URLParam(r, name), 10, 0)
+	if err != nil || v == 0 {
+		return 0, pkgerrors.ErrValidation
+	}
+	return uint(v), nil
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import "net/http"
+
+// userRevokeTokens - отзывает все токены пользователя: выполняет выход пользователя на всех устройствах.
+// Токены, выданные после отзыва, принимаются.
+// Возвращает ответ http.StatusNoContent (204).
+//
+// @Tags internal
+// @Summary Отзывает все токены пользователя
+// @Security ipAuth
+// @ID userRevokeTokens
+// @Param   id path int true "Идентификатор пользователя"
+// @Success 204
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /internal/users/{id}/revoke [post]
+func (h APIHandlers) userRevokeTokens(w http.ResponseWriter, r *http.Request) {
+	userID, err := uintURLParam(r, "id")
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Отзываем токены
+	if _, err = h.u.User.RevokeTokens(r.Context(), userID); err != nil {
+		respondWithError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userSetPlan - назначает пользователю тарифный план. Формат запроса:
+//
+//	{"plan": "pro"}
+//
+// План должен быть задан в конфигурации. Пустой план означает квоты по умолчанию.
+// Возвращает ответ http.StatusNoContent (204).
+//
+// @Tags internal
+// @Summary Назначает пользователю тарифный план
+// @Security ipAuth
+// @ID userSetPlan
+// @Accept  json
+// @Param   id path int true "Идентификатор пользователя"
+// @Param   request body handlers.userSetPlan.reqType true "Запрос"
+// @Success 204
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Failure 500
+// @Router /internal/users/{id}/plan [put]
+func (h APIHandlers) userSetPlan(w http.ResponseWriter, r *http.Request) {
+	// Структура запроса
+	type reqType struct {
+		Plan string `json:"plan"`
+	}
+
+	userID, err := uintURLParam(r, "id")
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Читаем body запроса
+	reqJSON := &reqType{}
+	if err = parseJSONRequest(r, reqJSON); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Назначаем план
+	if err = h.u.Quota.SetPlan(r.Context(), userID, reqJSON.Plan); err != nil {
+		respondWithError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
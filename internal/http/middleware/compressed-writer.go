@@ -2,8 +2,12 @@ package middleware
 
 import (
 	"compress/gzip"
+	"context"
 	"io"
 	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // MTUSize - оптимизированное под размер сетевого пакета значение для CompressedWriter.minSize.
@@ -23,6 +27,9 @@ const (
 
 const noStatusCode = -1
 
+// tracer - трассировщик сжатия ответов
+var tracer = otel.Tracer("github.com/ofstudio/go-shortener/internal/http/middleware")
+
 // CompressedWriter - компрессор для gzip-сжатия данных.
 // Данные будут сжиматься при соблюдении следующих условий:
 //  1. Размер данных для сжатия больше или равен minSize.
@@ -67,6 +74,12 @@ type CompressedWriter struct {
 
 	// Кол-во данных в буфере.
 	buffered int64
+
+	// Контекст запроса для трассировки сжатия.
+	ctx context.Context
+
+	// Span сжатия данных: начинается при создании compWriter и завершается в Close.
+	span trace.Span
 }
 
 // NewCompressedWriter - создает новый поток для сжатия данных.
@@ -88,6 +101,8 @@ func NewCompressedWriter(responseWriter http.ResponseWriter, minSize int64, leve
 		typeChecked:    false,
 		level:          level,
 		statusCode:     noStatusCode,
+		ctx:            context.Background(),
+		span:           trace.SpanFromContext(context.Background()),
 	}
 }
 
@@ -129,6 +144,10 @@ func (w *CompressedWriter) Write(p []byte) (int, error) {
 			return 0, err
 		}
 		w.state = stateCompress
+		// Трассируем сжатие, только если запрос является частью трассировки
+		if trace.SpanContextFromContext(w.ctx).IsValid() {
+			_, w.span = tracer.Start(w.ctx, "gzip")
+		}
 		w.ResponseWriter.Header().Set("Content-Encoding", "gzip")
 		w.ResponseWriter.Header().Set("Vary", "Accept-Encoding")
 		w.resumeWriteHeader()
@@ -164,6 +183,7 @@ func (w *CompressedWriter) Close() error {
 	// Если установлено состояние "Данные нужно сжимать",
 	// то закрываем поток для сжатия.
 	case w.state == stateCompress && w.compWriter != nil:
+		defer w.span.End()
 		return w.compWriter.Close()
 
 	// Если признак решения о сжатии не установлен и есть данные в буфере,
@@ -42,6 +42,7 @@ func (c *Compressor) Handler(next http.Handler) http.Handler {
 
 		// Создаём CompressedWriter
 		cw := NewCompressedWriter(w, c.minSize, c.level, c.allowedTypes)
+		cw.ctx = r.Context()
 		// Необходимо закрыть компрессор после завершения обработки запроса,
 		// тк в его буфере могут быть неотправленные данные.
 		defer func() {
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// APIScope - право доступа API-ключа
+type APIScope string
+
+// Права доступа API-ключей
+const (
+	APIScopeRead   APIScope = "read"   // Получение ссылок, правил и статистики
+	APIScopeCreate APIScope = "create" // Создание и изменение ссылок
+	APIScopeDelete APIScope = "delete" // Удаление ссылок
+)
+
+// APIScopes - все права доступа API-ключей
+var APIScopes = []APIScope{APIScopeRead, APIScopeCreate, APIScopeDelete}
+
+// APIKeyNameMaxLen - максимальная длина названия API-ключа
+const APIKeyNameMaxLen = 128
+
+// APIKey - API-ключ пользователя для программных клиентов.
+// Сам ключ передается пользователю только при создании, а в хранилище сохраняется его хеш.
+type APIKey struct {
+	ID        string     `json:"id"`                   // Идентификатор ключа, часть самого ключа
+	UserID    uint       `json:"user_id"`              // Владелец ключа
+	Name      string     `json:"name"`                 // Название ключа
+	Scopes    []APIScope `json:"scopes"`               // Права доступа
+	Hash      string     `json:"hash"`                 // SHA-256 ключа в шестнадцатеричном виде
+	CreatedAt time.Time  `json:"created_at"`           // Время создания
+	RevokedAt *time.Time `json:"revoked_at,omitempty"` // Время отзыва
+}
+
+// HasScope - возвращает true, если ключ имеет право доступа scope
+func (k *APIKey) HasScope(scope APIScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Revoked - возвращает true, если ключ отозван
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ofstudio/go-shortener/pkg/hll"
+)
+
+// Click - событие перехода по короткой ссылке
+type Click struct {
+	ShortURLID  string    `json:"short_url_id"`
+	Time        time.Time `json:"time"`
+	RefererHost string    `json:"referer_host,omitempty"` // Хост из заголовка Referer
+	Device      Device    `json:"device"`                 // Класс устройства посетителя
+	IP          string    `json:"ip,omitempty"`           // Анонимизированный IP-адрес посетителя
+	Country     string    `json:"country,omitempty"`      // Код страны посетителя (ISO 3166-1 alpha-2) по заголовку запроса
+	Visitor     string    `json:"visitor,omitempty"`      // Хеш IP-адреса и User-Agent для подсчета уникальных посетителей
+	Bot         bool      `json:"bot,omitempty"`          // Переход выполнен ботом
+}
+
+// VisitorSketchAll - идентификатор скетча посетителей всех ссылок сервиса
+const VisitorSketchAll = ""
+
+// VisitorSketch - HyperLogLog-скетч уникальных посетителей короткой ссылки за сутки (UTC).
+// Скетч с ShortURLID равным VisitorSketchAll учитывает посетителей всех ссылок сервиса.
+type VisitorSketch struct {
+	ShortURLID string      `json:"short_url_id"`
+	Day        time.Time   `json:"day"`
+	Sketch     *hll.Sketch `json:"sketch"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// QueryParamsMaxCount - максимальное количество параметров в шаблоне query-параметров.
+const QueryParamsMaxCount = 32
+
+// QueryMergePolicy - политика слияния параметров шаблона с query-параметрами оригинального URL.
+type QueryMergePolicy string
+
+// Политики слияния query-параметров
+const (
+	// QueryMergeKeep - параметр из оригинального URL имеет приоритет,
+	// параметр шаблона добавляется только если такого ключа нет в оригинальном URL (по умолчанию).
+	QueryMergeKeep QueryMergePolicy = "keep"
+	// QueryMergeOverride - параметр шаблона заменяет все значения параметра с таким же ключом.
+	QueryMergeOverride QueryMergePolicy = "override"
+	// QueryMergeAppend - параметр шаблона добавляется как еще одно значение параметра с таким же ключом.
+	QueryMergeAppend QueryMergePolicy = "append"
+)
+
+// Плейсхолдеры, доступные в значениях шаблона query-параметров
+const (
+	// PlaceholderID - идентификатор короткой ссылки
+	PlaceholderID = "{id}"
+	// PlaceholderReferrerHost - хост страницы, с которой был выполнен переход
+	PlaceholderReferrerHost = "{referrer_host}"
+	// PlaceholderDate - дата перехода в формате YYYY-MM-DD (UTC)
+	PlaceholderDate = "{date}"
+)
+
+// QueryTemplate - шаблон query-параметров, которые добавляются к оригинальному URL при переходе по короткой ссылке.
+// Значения параметров могут содержать плейсхолдеры, например:
+//
+//	{"utm_source": "{referrer_host}", "utm_campaign": "spring", "utm_content": "{id}"}
+type QueryTemplate struct {
+	Params map[string]string `json:"params"`
+	Policy QueryMergePolicy  `json:"policy,omitempty"`
+}
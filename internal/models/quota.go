@@ -0,0 +1,16 @@
+package models
+
+// Quota - квоты пользователя. Нулевое значение означает отсутствие ограничения.
+type Quota struct {
+	MaxActiveURLs int `json:"max_active_urls"` // Максимальное количество неудаленных ссылок
+	URLsPerDay    int `json:"urls_per_day"`    // Максимальное количество ссылок, созданных за последние 24 часа
+	MaxBatchSize  int `json:"max_batch_size"`  // Максимальное количество ссылок в одном пакетном запросе
+}
+
+// QuotaUsage - квоты пользователя и их текущее использование
+type QuotaUsage struct {
+	Plan string `json:"plan,omitempty"` // Тарифный план пользователя
+	Quota
+	ActiveURLs  int `json:"active_urls"`   // Количество неудаленных ссылок
+	URLsLastDay int `json:"urls_last_day"` // Количество ссылок, созданных за последние 24 часа
+}
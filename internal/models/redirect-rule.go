@@ -0,0 +1,43 @@
+package models
+
+// RedirectRulesMaxCount - максимальное количество правил перенаправления у одной ссылки.
+const RedirectRulesMaxCount = 16
+
+// Device - класс устройства посетителя, определяемый по заголовку User-Agent.
+type Device string
+
+// Классы устройств
+const (
+	DeviceIOS     Device = "ios"
+	DeviceAndroid Device = "android"
+	DeviceDesktop Device = "desktop"
+)
+
+// RedirectRule - правило условного перенаправления.
+// Правило срабатывает, если выполнены все заданные в нем условия.
+// Незаданное (пустое) условие считается выполненным.
+type RedirectRule struct {
+	// URL - адрес, на который перенаправляется посетитель при срабатывании правила
+	URL string `json:"url"`
+	// Devices - классы устройств посетителя
+	Devices []Device `json:"devices,omitempty"`
+	// Languages - языки посетителя (по первому языку заголовка Accept-Language), например: "ru", "en-US".
+	// Язык без региона соответствует всем регионам: "en" соответствует "en-US" и "en-GB".
+	Languages []string `json:"languages,omitempty"`
+	// Schedule - расписание, по которому действует правило
+	Schedule *Schedule `json:"schedule,omitempty"`
+}
+
+// Schedule - расписание действия правила перенаправления.
+type Schedule struct {
+	// Weekdays - дни недели: "mon", "tue", "wed", "thu", "fri", "sat", "sun".
+	// Если не заданы, то правило действует во все дни.
+	Weekdays []string `json:"weekdays,omitempty"`
+	// From, Until - интервал времени суток в формате "15:04".
+	// Если Until меньше From, то интервал переходит через полночь. From и Until не могут совпадать.
+	// Если не заданы, то правило действует круглосуточно.
+	From  string `json:"from,omitempty"`
+	Until string `json:"until,omitempty"`
+	// TimeZone - часовой пояс расписания в формате IANA, например "Europe/Moscow". По умолчанию UTC.
+	TimeZone string `json:"time_zone,omitempty"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// StorageStats - статистика хранилища сервиса.
+type StorageStats struct {
+	// URLs - общее количество сокращенных ссылок, включая удаленные
+	URLs int `json:"urls"`
+	// ActiveURLs - количество неудаленных ссылок
+	ActiveURLs int `json:"active_urls"`
+	// DeletedURLs - количество удаленных ссылок
+	DeletedURLs int `json:"deleted_urls"`
+	// URLsLastHour - количество ссылок, созданных за последний час
+	URLsLastHour int `json:"urls_last_hour"`
+	// URLsLastDay - количество ссылок, созданных за последние сутки
+	URLsLastDay int `json:"urls_last_day"`
+	// Users - общее количество пользователей
+	Users int `json:"users"`
+	// ActiveUsers - количество пользователей, у которых есть хотя бы одна неудаленная ссылка
+	ActiveUsers int `json:"active_users"`
+	// Size - размер хранилища в байтах (размер AOF-файлов или таблиц БД). Для хранилища в памяти - 0.
+	Size int64 `json:"storage_size"`
+}
+
+// BuildInfo - информация о сборке приложения.
+type BuildInfo struct {
+	Version string `json:"version"`
+	Date    string `json:"date"`
+	Commit  string `json:"commit"`
+}
+
+// ServiceStats - статистика сервиса для внутреннего API.
+type ServiceStats struct {
+	StorageStats
+	// UniqueVisitorsDay - оценка количества уникальных посетителей сервиса за текущие сутки (UTC)
+	UniqueVisitorsDay int64 `json:"unique_visitors_day"`
+	// UniqueVisitorsMonth - оценка количества уникальных посетителей сервиса за последние 30 суток (UTC), включая текущие
+	UniqueVisitorsMonth int64 `json:"unique_visitors_month"`
+	// UniqueVisitorsError - относительная стандартная ошибка оценок количества уникальных посетителей
+	UniqueVisitorsError float64 `json:"unique_visitors_error"`
+	// Backend - тип хранилища: postgres, aof или memory
+	Backend string `json:"backend"`
+	// StartedAt - время запуска сервиса
+	StartedAt time.Time `json:"started_at"`
+	// Uptime - время работы сервиса
+	Uptime time.Duration `json:"-"`
+	// Build - информация о сборке
+	Build BuildInfo `json:"build"`
+}
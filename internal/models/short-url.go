@@ -1,15 +1,39 @@
 package models
 
+import "time"
+
 // URLMaxLen - максимальная длина исходного URL в байтах.
 // Формально, размер URL ничем не ограничен.
 // Разные версии разных браузеров имеют свои конкретные ограничения: от 2048 байт до нескольких мегабайт.
 // В случае нашего сервиса необходимо некое разумное ограничение.
 const URLMaxLen = 4096
 
+// TitleMaxLen - максимальная длина заголовка ссылки в символах.
+const TitleMaxLen = 256
+
 // ShortURL - модель сокращенной ссылки
 type ShortURL struct {
-	ID          string `json:"id"`
-	OriginalURL string `json:"original_url,omitempty"`
-	UserID      uint   `json:"user_id"`
-	Deleted     bool   `json:"-"`
+	ID            string         `json:"id"`
+	OriginalURL   string         `json:"original_url,omitempty"`  // Канонический вид URL
+	SubmittedURL  string         `json:"submitted_url,omitempty"` // URL в том виде, в котором его передал пользователь
+	UserID        uint           `json:"user_id"`
+	TeamID        uint           `json:"team_id,omitempty"` // Команда, которой принадлежит ссылка. 0 - личная ссылка пользователя
+	Deleted       bool           `json:"-"`
+	Title         string         `json:"title,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Interstitial  bool           `json:"interstitial,omitempty"`
+	ActiveFrom    *time.Time     `json:"active_from,omitempty"`
+	ActiveUntil   *time.Time     `json:"active_until,omitempty"`
+	QueryTemplate *QueryTemplate `json:"query_template,omitempty"`
+	Rules         []RedirectRule `json:"rules,omitempty"`
+	Split         *Split         `json:"split,omitempty"`
+}
+
+// Plain - возвращает true, если у ссылки нет собственной маршрутизации и оформления: правил перенаправления,
+// вариантов сплит-ссылки, шаблона query-параметров, заголовка, страницы-предупреждения, периода действия и команды.
+// Простые ссылки пользователя с одинаковым URL не различаются: при повторном сокращении URL
+// пользователю возвращается его существующая простая ссылка.
+func (s ShortURL) Plain() bool {
+	return s.TeamID == 0 && len(s.Rules) == 0 && s.Split == nil && s.QueryTemplate == nil &&
+		s.Title == "" && !s.Interstitial && s.ActiveFrom == nil && s.ActiveUntil == nil
 }
@@ -0,0 +1,31 @@
+package models
+
+// SplitVariantsMaxCount - максимальное количество вариантов сплит-ссылки.
+const SplitVariantsMaxCount = 10
+
+// SplitWeightMax - максимальный вес варианта сплит-ссылки.
+const SplitWeightMax = 1_000_000
+
+// Split - параметры сплит-ссылки (A/B-тест).
+// Переходы по сплит-ссылке распределяются между вариантами пропорционально их весам.
+type Split struct {
+	// Variants - варианты адресов перехода. Номер варианта - его порядковый номер в списке, начиная с 1.
+	Variants []Variant `json:"variants"`
+	// Sticky - закреплять выбранный вариант за посетителем (с помощью cookie)
+	Sticky bool `json:"sticky,omitempty"`
+}
+
+// Variant - вариант адреса перехода сплит-ссылки.
+type Variant struct {
+	URL string `json:"url"`
+	// Weight - вес варианта. Вариант с нулевым весом не выбирается.
+	Weight uint `json:"weight"`
+}
+
+// VariantStat - статистика переходов по варианту сплит-ссылки.
+type VariantStat struct {
+	Variant int    `json:"variant"`
+	URL     string `json:"url"`
+	Weight  uint   `json:"weight"`
+	Clicks  int64  `json:"clicks"`
+}
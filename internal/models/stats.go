@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// StatsInterval - интервал агрегации статистики переходов по времени.
+type StatsInterval string
+
+// Интервалы агрегации
+const (
+	StatsIntervalHour StatsInterval = "hour"
+	StatsIntervalDay  StatsInterval = "day"
+)
+
+// StatsMaxBuckets - максимальное количество интервалов в статистике за один запрос.
+const StatsMaxBuckets = 1000
+
+// StatsTopCount - количество позиций в списках лидеров (рефереры, страны, устройства).
+const StatsTopCount = 10
+
+// LinkStats - статистика переходов по короткой ссылке за период [From, Until).
+type LinkStats struct {
+	From     time.Time     `json:"from"`
+	Until    time.Time     `json:"until"`
+	Interval StatsInterval `json:"interval"`
+	// IncludeBots - переходы ботов учтены в статистике
+	IncludeBots bool `json:"include_bots"`
+	// Total - общее количество переходов за период
+	Total int64 `json:"total"`
+	// Unique - количество уникальных посетителей за период
+	Unique int64 `json:"unique"`
+	// Bots - количество переходов ботов за период, учтены они в статистике или нет
+	Bots int64 `json:"bots"`
+	// ApproxUnique - оценка количества уникальных посетителей по HyperLogLog-скетчам.
+	// Учитываются полные сутки (UTC), пересекающиеся с периодом.
+	ApproxUnique int64 `json:"approx_unique"`
+	// ApproxUniqueError - относительная стандартная ошибка оценки ApproxUnique
+	ApproxUniqueError float64 `json:"approx_unique_error"`
+	// Buckets - переходы по интервалам времени, включая интервалы без переходов
+	Buckets []StatsBucket `json:"buckets"`
+	// Referrers - хосты, с которых чаще всего переходили по ссылке
+	Referrers []StatsCount `json:"referrers"`
+	// Countries - страны посетителей по заголовку запроса
+	Countries []StatsCount `json:"countries"`
+	// Devices - классы устройств посетителей
+	Devices []StatsCount `json:"devices"`
+}
+
+// StatsBucket - переходы за один интервал времени, начинающийся в Time.
+type StatsBucket struct {
+	Time   time.Time `json:"time"`
+	Total  int64     `json:"total"`
+	Unique int64     `json:"unique"`
+}
+
+// StatsCount - количество переходов для значения Key (хоста, страны, класса устройства).
+type StatsCount struct {
+	Key    string `json:"key"`
+	Clicks int64  `json:"clicks"`
+}
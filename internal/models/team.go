@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// TeamNameMaxLen - максимальная длина названия команды в символах
+const TeamNameMaxLen = 128
+
+// TeamRole - роль участника команды
+type TeamRole string
+
+// Роли участников команды
+const (
+	TeamRoleOwner  TeamRole = "owner"  // Управление участниками, создание, изменение и удаление ссылок команды
+	TeamRoleEditor TeamRole = "editor" // Создание, изменение и удаление ссылок команды
+	TeamRoleViewer TeamRole = "viewer" // Просмотр ссылок команды и их статистики
+)
+
+// Valid - возвращает true, если роль известна
+func (r TeamRole) Valid() bool {
+	switch r {
+	case TeamRoleOwner, TeamRoleEditor, TeamRoleViewer:
+		return true
+	}
+	return false
+}
+
+// CanEdit - возвращает true, если роль позволяет создавать, изменять и удалять ссылки команды
+func (r TeamRole) CanEdit() bool {
+	return r == TeamRoleOwner || r == TeamRoleEditor
+}
+
+// CanManage - возвращает true, если роль позволяет управлять участниками команды
+func (r TeamRole) CanManage() bool {
+	return r == TeamRoleOwner
+}
+
+// Team - модель команды.
+// Ссылки команды доступны ее участникам в соответствии с их ролями
+// и не зависят от того, остается ли в команде создавший их пользователь.
+type Team struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TeamMember - участник команды
+type TeamMember struct {
+	TeamID uint     `json:"team_id"`
+	UserID uint     `json:"user_id"`
+	Role   TeamRole `json:"role"`
+}
+
+// TeamMembership - команда и роль в ней пользователя
+type TeamMembership struct {
+	Team
+	Role TeamRole `json:"role"`
+}
@@ -1,6 +1,29 @@
 package models
 
-// User - модель пользователя
+// Ограничения учетных данных пользователя
+const (
+	EmailMaxLen    = 254 // Максимальная длина email (RFC 5321)
+	PasswordMinLen = 8   // Минимальная длина пароля в символах
+	PasswordMaxLen = 72  // Максимальная длина пароля в байтах (ограничение bcrypt)
+)
+
+// User - модель пользователя.
+// Пользователь создается анонимным: он определяется только по токену.
+// Зарегистрированный пользователь может войти по email и паролю или через OpenID Connect
+// и получить новый токен.
 type User struct {
-	ID uint `json:"id"`
+	ID           uint   `json:"id"`
+	Email        string `json:"email,omitempty"`         // Email в нижнем регистре, пустой у анонимного пользователя
+	PasswordHash string `json:"password_hash,omitempty"` // bcrypt-хеш пароля
+	OIDCSubject  string `json:"oidc_subject,omitempty"`  // Издатель и идентификатор пользователя у провайдера OpenID Connect: <iss> <sub>
+	// TokenGeneration - поколение токенов пользователя.
+	// Принимаются только токены текущего поколения: его увеличение отзывает все выданные токены.
+	TokenGeneration uint `json:"token_generation,omitempty"`
+	// Plan - тарифный план пользователя. Пустой план означает квоты по умолчанию.
+	Plan string `json:"plan,omitempty"`
+}
+
+// Registered - возвращает true, если пользователь зарегистрирован
+func (u *User) Registered() bool {
+	return u.Email != "" || u.OIDCSubject != ""
 }
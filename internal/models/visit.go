@@ -0,0 +1,20 @@
+package models
+
+import (
+	"net"
+	"time"
+)
+
+// Visit - параметры перехода по короткой ссылке
+type Visit struct {
+	Time           time.Time // Время перехода
+	Referer        string    // Значение заголовка Referer
+	UserAgent      string    // Значение заголовка User-Agent
+	AcceptLanguage string    // Значение заголовка Accept-Language
+	Variant        int       // Номер варианта сплит-ссылки, ранее закрепленный за посетителем (0 - не закреплен)
+	IP             net.IP    // IP-адрес посетителя
+	Country        string    // Код страны посетителя (ISO 3166-1 alpha-2), если известен
+	Method         string    // HTTP-метод запроса
+	Purpose        string    // Значение заголовка Purpose или Sec-Purpose (например, prefetch)
+	Bot            bool      // Переход выполнен ботом (см. ShortURL.DetectBot)
+}
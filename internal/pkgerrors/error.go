@@ -3,6 +3,7 @@ package pkgerrors
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"google.golang.org/grpc/codes"
@@ -17,12 +18,24 @@ var ErrValidation = NewError(http.StatusBadRequest, codes.InvalidArgument, "vali
 // ErrAuth - ошибка авторизации
 var ErrAuth = NewError(http.StatusUnauthorized, codes.Unauthenticated, "unauthorized")
 
+// ErrForbidden - недостаточно прав
+var ErrForbidden = NewError(http.StatusForbidden, codes.PermissionDenied, "forbidden")
+
 // ErrDuplicate - дубликат
 var ErrDuplicate = NewError(http.StatusConflict, codes.AlreadyExists, "duplicate")
 
 // ErrDeleted - удалено
 var ErrDeleted = NewError(http.StatusGone, GRPCDeleted, "deleted")
 
+// ErrNotActive - период действия ссылки еще не начался
+var ErrNotActive = NewError(http.StatusNotFound, codes.NotFound, "not active yet")
+
+// ErrExpired - период действия ссылки закончился
+var ErrExpired = NewError(http.StatusGone, GRPCDeleted, "expired")
+
+// ErrQuotaExceeded - превышена квота пользователя
+var ErrQuotaExceeded = NewError(http.StatusTooManyRequests, codes.ResourceExhausted, "quota exceeded")
+
 // ErrInternal - внутренняя ошибка
 var ErrInternal = NewError(http.StatusInternalServerError, codes.Internal, "internal error")
 
@@ -31,6 +44,9 @@ type Error struct {
 	error
 	HTTPStatus int
 	GRPCCode   codes.Code
+	// Detail - уточняющее описание ошибки для клиента
+	Detail string
+	parent *Error
 }
 
 // NewError - конструктор ошибки
@@ -42,6 +58,26 @@ func NewError(httpStatus int, grpcCode codes.Code, message string) *Error {
 	}
 }
 
+// WithDetail - возвращает копию ошибки с уточняющим описанием detail.
+// Для полученной ошибки errors.Is(err, e) возвращает true.
+func (e *Error) WithDetail(detail string) *Error {
+	return &Error{
+		error:      fmt.Errorf("%s: %s", e.error, detail),
+		HTTPStatus: e.HTTPStatus,
+		GRPCCode:   e.GRPCCode,
+		Detail:     detail,
+		parent:     e,
+	}
+}
+
+// Unwrap - возвращает исходную ошибку, из которой была получена ошибка с уточняющим описанием
+func (e *Error) Unwrap() error {
+	if e.parent == nil {
+		return nil
+	}
+	return e.parent
+}
+
 // Кастомные коды ошибок GRPC
 const (
 	// GRPCNoContent - нет контента
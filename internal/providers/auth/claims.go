@@ -0,0 +1,18 @@
+package auth
+
+import "time"
+
+// Claims - данные токена пользователя
+type Claims struct {
+	// UserID - id пользователя
+	UserID uint
+	// Generation - поколение токенов пользователя на момент выдачи токена.
+	// Токен принимается, только если оно совпадает с текущим поколением (см. models.User.TokenGeneration).
+	Generation uint
+	// SessionStart - время начала сессии: создания пользователя или входа.
+	// Не меняется при продлении токена. Нулевое значение - токен выдан без времени начала сессии
+	SessionStart time.Time
+	// ExpiresAt - время окончания действия токена.
+	// Если при создании токена не задано, то токен действует AuthTTL
+	ExpiresAt time.Time
+}
@@ -1,6 +1,13 @@
 package auth
 
-import "context"
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+)
 
 type ctxKey struct {
 	name string
@@ -19,3 +26,69 @@ func FromContext(ctx context.Context) (uint, bool) {
 func ToContext(ctx context.Context, userID uint) context.Context {
 	return context.WithValue(ctx, userIDKey, userID)
 }
+
+// apiKeyKey - ключ для API-ключа в контексте запроса
+var apiKeyKey = &ctxKey{"api_key"}
+
+// APIKeyFromContext - возвращает API-ключ, которым аутентифицирован запрос.
+// Для запросов с токеном пользователя возвращает false.
+func APIKeyFromContext(ctx context.Context) (*models.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyKey).(*models.APIKey)
+	return key, ok
+}
+
+// apiKeyToContext - добавляет API-ключ и id его владельца в контекст
+func apiKeyToContext(ctx context.Context, key *models.APIKey) context.Context {
+	return context.WithValue(ToContext(ctx, key.UserID), apiKeyKey, key)
+}
+
+// HasScope - возвращает true, если запрос имеет право доступа scope.
+// Запросы с токеном пользователя имеют все права, запросы с API-ключом - только права ключа.
+func HasScope(ctx context.Context, scope models.APIScope) bool {
+	key, ok := APIKeyFromContext(ctx)
+	return !ok || key.HasScope(scope)
+}
+
+// creatorKey - ключ для userCreator в контексте запроса
+var creatorKey = &ctxKey{"user_creator"}
+
+// userCreator - создает нового пользователя для запроса без валидного токена
+// и передает клиенту его токен. Также выдает токен при входе и удаляет его при выходе пользователя.
+// Устанавливается в контекст провайдером аутентификации.
+type userCreator interface {
+	// createHTTPUser - создает пользователя и устанавливает токен в http-куку
+	createHTTPUser(w http.ResponseWriter, r *http.Request) (uint, error)
+	// createGRPCUser - создает пользователя и устанавливает токен в заголовок ответа
+	createGRPCUser(ctx context.Context, setHeader func(metadata.MD) error) (uint, error)
+	// issueHTTPToken - устанавливает токен пользователя в http-куку
+	issueHTTPToken(ctx context.Context, w http.ResponseWriter, userID uint) error
+	// clearHTTPToken - удаляет http-куку с токеном
+	clearHTTPToken(w http.ResponseWriter)
+	// issueGRPCToken - устанавливает токен пользователя в заголовок ответа
+	issueGRPCToken(ctx context.Context, userID uint, setHeader func(metadata.MD) error) error
+}
+
+// creatorFromContext - возвращает userCreator из контекста
+func creatorFromContext(ctx context.Context) (userCreator, bool) {
+	c, ok := ctx.Value(creatorKey).(userCreator)
+	return c, ok
+}
+
+// creatorToContext - добавляет userCreator в контекст
+func creatorToContext(ctx context.Context, c userCreator) context.Context {
+	return context.WithValue(ctx, creatorKey, c)
+}
+
+// authFailedKey - ключ для признака ошибки проверки токена в контексте запроса
+var authFailedKey = &ctxKey{"auth_failed"}
+
+// authFailedFromContext - возвращает true, если токен запроса не удалось проверить из-за внутренней ошибки
+func authFailedFromContext(ctx context.Context) bool {
+	failed, _ := ctx.Value(authFailedKey).(bool)
+	return failed
+}
+
+// authFailedToContext - отмечает в контексте, что токен запроса не удалось проверить из-за внутренней ошибки
+func authFailedToContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authFailedKey, true)
+}
@@ -10,3 +10,9 @@ var ErrInvalidToken = errors.New("invalid token")
 
 // ErrExpiredToken - просроченный токен
 var ErrExpiredToken = errors.New("expired token")
+
+// ErrNoProvider - в контексте запроса нет провайдера аутентификации
+var ErrNoProvider = errors.New("auth provider is not set")
+
+// ErrRevokedToken - токен отозван: поколение токена не совпадает с текущим поколением токенов пользователя
+var ErrRevokedToken = errors.New("revoked token")
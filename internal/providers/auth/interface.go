@@ -5,17 +5,24 @@ import (
 	"net/http"
 
 	"google.golang.org/grpc"
+
+	"github.com/ofstudio/go-shortener/internal/usecases"
 )
 
 // Provider - провайдер проверки токена пользователя.
 // Реализует middleware для http и grpc.
 type Provider interface {
 	// CreateToken - создает токен для пользователя.
-	CreateToken(userID uint) (string, error)
-	// VerifyToken - проверяет токен пользователя.
-	VerifyToken(token string) (uint, error)
+	CreateToken(claims Claims) (string, error)
+	// VerifyToken - проверяет подпись и срок действия токена и возвращает его данные.
+	// Поколение токена не проверяется.
+	VerifyToken(token string) (Claims, error)
+	// UseAPIKeys - подключает проверку API-ключей в заголовке Authorization.
+	UseAPIKeys(keys *usecases.APIKey)
 	// Handler - http.HandlerFunc
 	Handler(next http.Handler) http.Handler
 	// Interceptor - grpc.UnaryServerInterceptor
 	Interceptor(context.Context, interface{}, *grpc.UnaryServerInfo, grpc.UnaryHandler) (interface{}, error)
+	// StreamInterceptor - grpc.StreamServerInterceptor
+	StreamInterceptor(interface{}, grpc.ServerStream, *grpc.StreamServerInfo, grpc.StreamHandler) error
 }
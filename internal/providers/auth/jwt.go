@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+// JWTProvider - провайдер аутентификации со стандартными JWT (RFC 7519).
+// Токены подписываются алгоритмом HS256 (ключ AuthSecret), RS256 или EdDSA (закрытый ключ из PEM-файла),
+// поэтому могут быть проверены другими сервисами.
+// Для HS256 можно задать набор ключей (см. UseKeyring): идентификатор ключа подписи передается в заголовке kid.
+// Идентификатор пользователя передается в поле sub, поколение токенов пользователя - в поле gen,
+// время начала сессии - в поле auth_time.
+// Если в конфигурации заданы издатель и получатель, то они устанавливаются в поля iss и aud
+// и проверяются у входящих токенов. При проверке времени действия допускается расхождение часов JWTLeeway.
+//
+// Для перехода с SHA256Provider принимаются также токены SHA256Provider, подписанные ключом AuthSecret.
+// Новые токены всегда выдаются в формате JWT.
+type JWTProvider struct {
+	*transport
+	method    jwt.SigningMethod
+	keys      *Keyring         // Ключи для подписи токена алгоритмом HS256
+	signKey   interface{}      // Ключ для подписи токена алгоритмами RS256 и EdDSA
+	verifyKey interface{}      // Ключ для проверки подписи токена алгоритмами RS256 и EdDSA
+	parser    *jwt.Parser      // Парсер с проверками алгоритма, издателя и получателя
+	legacy    *SHA256Provider  // Провайдер для проверки токенов прежнего формата
+	issuer    string           // Издатель токена
+	audience  jwt.ClaimStrings // Получатель токена
+	ttl       time.Duration    // Время жизни токена
+	now       func() time.Time // Текущее время
+}
+
+// jwtClaims - поля JWT
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Generation uint             `json:"gen,omitempty"`       // Поколение токенов пользователя
+	AuthTime   *jwt.NumericDate `json:"auth_time,omitempty"` // Время начала сессии
+}
+
+// NewJWTProvider - конструктор JWTProvider.
+// Для алгоритмов RS256 и EdDSA загружает закрытый ключ из файла cfg.JWTKeyFile.
+func NewJWTProvider(cfg *config.Config, u *usecases.User) (*JWTProvider, error) {
+	p := &JWTProvider{
+		legacy: NewSHA256Provider(cfg, u),
+		issuer: cfg.JWTIssuer,
+		ttl:    cfg.AuthTTL,
+		now:    time.Now,
+	}
+	if cfg.JWTAudience != "" {
+		p.audience = jwt.ClaimStrings{cfg.JWTAudience}
+	}
+
+	var err error
+	switch cfg.JWTAlgorithm {
+	case "", config.JWTAlgorithmHS256:
+		p.method = jwt.SigningMethodHS256
+		p.keys = NewStaticKeyring(cfg.AuthSecret)
+	case config.JWTAlgorithmRS256:
+		p.method = jwt.SigningMethodRS256
+		p.signKey, p.verifyKey, err = loadKey(cfg.JWTKeyFile, func(pem []byte) (crypto.Signer, error) {
+			return jwt.ParseRSAPrivateKeyFromPEM(pem)
+		})
+	case config.JWTAlgorithmEdDSA:
+		p.method = jwt.SigningMethodEdDSA
+		p.signKey, p.verifyKey, err = loadKey(cfg.JWTKeyFile, func(pem []byte) (crypto.Signer, error) {
+			key, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			edKey, ok := key.(ed25519.PrivateKey)
+			if !ok {
+				return nil, jwt.ErrNotEdPrivateKey
+			}
+			return edKey, nil
+		})
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm: %s", cfg.JWTAlgorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{p.method.Alg()}),
+		jwt.WithLeeway(cfg.JWTLeeway),
+		jwt.WithTimeFunc(func() time.Time { return p.now() }),
+	}
+	if cfg.JWTIssuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+	if cfg.JWTAudience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.JWTAudience))
+	}
+	p.parser = jwt.NewParser(opts...)
+	p.transport = newTransport(cfg, u, p)
+	return p, nil
+}
+
+// UseKeyring - устанавливает набор ключей для подписи и проверки токенов алгоритмом HS256
+// и токенов SHA256Provider.
+func (p *JWTProvider) UseKeyring(keys *Keyring) {
+	if p.method == jwt.SigningMethodHS256 {
+		p.keys = keys
+	}
+	p.legacy.UseKeyring(keys)
+}
+
+// CreateToken - создает JWT для пользователя.
+func (p *JWTProvider) CreateToken(claims Claims) (string, error) {
+	now := p.now()
+	expiresAt := claims.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(p.ttl)
+	}
+	registered := jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(claims.UserID), 10),
+			Issuer:    p.issuer,
+			Audience:  p.audience,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Generation: claims.Generation,
+	}
+	if !claims.SessionStart.IsZero() {
+		registered.AuthTime = jwt.NewNumericDate(claims.SessionStart)
+	}
+	token := jwt.NewWithClaims(p.method, registered)
+	signKey := p.signKey
+	if p.keys != nil {
+		key := p.keys.Active()
+		if key.ID != "" {
+			token.Header["kid"] = key.ID
+		}
+		signKey = key.Secret
+	}
+	signed, err := token.SignedString(signKey)
+	if err != nil {
+		return "", ErrSigningError
+	}
+	return signed, nil
+}
+
+// VerifyToken - проверяет JWT и возвращает его данные.
+// Если токен не является JWT, то он проверяется как токен SHA256Provider.
+func (p *JWTProvider) VerifyToken(token string) (Claims, error) {
+	if token == "" {
+		return Claims{}, ErrInvalidToken
+	}
+	claims := &jwtClaims{}
+	_, err := p.parser.ParseWithClaims(token, claims, p.verifyKeyFunc)
+	switch {
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		// Токен прежнего формата
+		return p.legacy.VerifyToken(token)
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return Claims{}, ErrExpiredToken
+	case err != nil:
+		return Claims{}, ErrInvalidToken
+	}
+	// Время окончания действия токена обязательно
+	if claims.ExpiresAt == nil {
+		return Claims{}, ErrInvalidToken
+	}
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	verified := Claims{
+		UserID:     uint(userID),
+		Generation: claims.Generation,
+		ExpiresAt:  claims.ExpiresAt.Time,
+	}
+	if claims.AuthTime != nil {
+		verified.SessionStart = claims.AuthTime.Time
+	}
+	return verified, nil
+}
+
+// verifyKeyFunc - возвращает ключ для проверки подписи токена.
+// Для HS256 ключ выбирается из набора ключей по заголовку kid.
+func (p *JWTProvider) verifyKeyFunc(token *jwt.Token) (interface{}, error) {
+	if p.keys == nil {
+		return p.verifyKey, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := p.keys.Get(kid)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return key.Secret, nil
+}
+
+// loadKey - загружает закрытый ключ из PEM-файла с помощью функции parse.
+// Возвращает закрытый ключ для подписи и открытый ключ для проверки подписи.
+func loadKey(fileName string, parse func([]byte) (crypto.Signer, error)) (interface{}, interface{}, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read JWT key file: %w", err)
+	}
+	key, err := parse(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse JWT key file %s: %w", fileName, err)
+	}
+	return key, key.Public(), nil
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/repo"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+type jwtProviderSuite struct {
+	suite.Suite
+	cfg *config.Config
+	u   *usecases.User
+}
+
+func TestJWTProviderSuite(t *testing.T) {
+	suite.Run(t, new(jwtProviderSuite))
+}
+
+func (suite *jwtProviderSuite) SetupTest() {
+	suite.cfg = &config.Config{
+		AuthSecret:   "secret",
+		AuthTTL:      time.Hour,
+		AuthProvider: config.AuthProviderJWT,
+		JWTIssuer:    "https://short.example.com",
+		JWTAudience:  "shortener",
+		JWTLeeway:    time.Minute,
+	}
+	suite.u = usecases.NewUser(repo.NewMemoryRepo())
+}
+
+func (suite *jwtProviderSuite) TestAlgorithms() {
+	for alg, keyFile := range map[string]string{
+		config.JWTAlgorithmHS256: "",
+		config.JWTAlgorithmRS256: "testdata/rsa.pem",
+		config.JWTAlgorithmEdDSA: "testdata/ed25519.pem",
+	} {
+		suite.Run(alg, func() {
+			suite.cfg.JWTAlgorithm, suite.cfg.JWTKeyFile = alg, keyFile
+			p, err := NewJWTProvider(suite.cfg, suite.u)
+			suite.Require().NoError(err)
+			token, err := p.CreateToken(Claims{UserID: 42})
+			suite.Require().NoError(err)
+
+			// Проверяем стандартные поля токена
+			claims := &jwt.RegisteredClaims{}
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, claims)
+			suite.Require().NoError(err)
+			suite.Equal(alg, parsed.Method.Alg())
+			suite.Equal("42", claims.Subject)
+			suite.Equal("https://short.example.com", claims.Issuer)
+			suite.Equal(jwt.ClaimStrings{"shortener"}, claims.Audience)
+
+			verified, err := p.VerifyToken(token)
+			suite.NoError(err)
+			suite.Equal(uint(42), verified.UserID)
+			suite.Equal(claims.ExpiresAt.Unix(), verified.ExpiresAt.Unix())
+
+			// Подпись проверяется
+			_, err = p.VerifyToken(token[:len(token)-4] + "AAAA")
+			suite.ErrorIs(err, ErrInvalidToken)
+		})
+	}
+}
+
+func (suite *jwtProviderSuite) TestVerifyToken() {
+	p, err := NewJWTProvider(suite.cfg, suite.u)
+	suite.Require().NoError(err)
+
+	suite.Run("issuer and audience", func() {
+		for _, cfg := range []config.Config{
+			{JWTIssuer: "https://other.example.com", JWTAudience: "shortener"},
+			{JWTIssuer: "https://short.example.com", JWTAudience: "other"},
+		} {
+			other := *suite.cfg
+			other.JWTIssuer, other.JWTAudience = cfg.JWTIssuer, cfg.JWTAudience
+			op, err := NewJWTProvider(&other, suite.u)
+			suite.Require().NoError(err)
+			token, err := op.CreateToken(Claims{UserID: 42})
+			suite.Require().NoError(err)
+			_, err = p.VerifyToken(token)
+			suite.ErrorIs(err, ErrInvalidToken)
+		}
+	})
+
+	suite.Run("clock skew", func() {
+		token, err := p.CreateToken(Claims{UserID: 42})
+		suite.Require().NoError(err)
+		p.now = func() time.Time { return time.Now().Add(suite.cfg.AuthTTL + 30*time.Second) }
+		_, err = p.VerifyToken(token)
+		suite.NoError(err)
+		p.now = func() time.Time { return time.Now().Add(suite.cfg.AuthTTL + 2*time.Minute) }
+		_, err = p.VerifyToken(token)
+		suite.ErrorIs(err, ErrExpiredToken)
+		p.now = time.Now
+	})
+
+	suite.Run("algorithm substitution", func() {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+			Subject:   "42",
+			Issuer:    suite.cfg.JWTIssuer,
+			Audience:  jwt.ClaimStrings{suite.cfg.JWTAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		suite.Require().NoError(err)
+		_, err = p.VerifyToken(token)
+		suite.ErrorIs(err, ErrInvalidToken)
+	})
+
+	suite.Run("expiration required", func() {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Subject:  "42",
+			Issuer:   suite.cfg.JWTIssuer,
+			Audience: jwt.ClaimStrings{suite.cfg.JWTAudience},
+		}).SignedString([]byte(suite.cfg.AuthSecret))
+		suite.Require().NoError(err)
+		_, err = p.VerifyToken(token)
+		suite.ErrorIs(err, ErrInvalidToken)
+	})
+
+	suite.Run("legacy SHA256 token", func() {
+		token, err := NewSHA256Provider(suite.cfg, suite.u).CreateToken(Claims{UserID: 42})
+		suite.Require().NoError(err)
+		suite.False(strings.Contains(token, "."))
+		claims, err := p.VerifyToken(token)
+		suite.NoError(err)
+		suite.Equal(uint(42), claims.UserID)
+	})
+
+	suite.Run("token generation and session", func() {
+		sessionStart, expiresAt := time.Unix(time.Now().Unix()-60, 0), time.Unix(time.Now().Unix()+60, 0)
+		token, err := p.CreateToken(Claims{UserID: 42, Generation: 3, SessionStart: sessionStart, ExpiresAt: expiresAt})
+		suite.Require().NoError(err)
+		claims, err := p.VerifyToken(token)
+		suite.NoError(err)
+		suite.Equal(uint(42), claims.UserID)
+		suite.Equal(uint(3), claims.Generation)
+		suite.True(sessionStart.Equal(claims.SessionStart))
+		suite.True(expiresAt.Equal(claims.ExpiresAt))
+	})
+
+	suite.Run("invalid", func() {
+		_, err := p.VerifyToken("")
+		suite.ErrorIs(err, ErrInvalidToken)
+		_, err = p.VerifyToken("a.b.c")
+		suite.ErrorIs(err, ErrInvalidToken)
+	})
+}
+
+func (suite *jwtProviderSuite) TestNewJWTProvider_invalidKey() {
+	suite.cfg.JWTAlgorithm, suite.cfg.JWTKeyFile = config.JWTAlgorithmRS256, "testdata/not-exists.pem"
+	_, err := NewJWTProvider(suite.cfg, suite.u)
+	suite.Error(err)
+	suite.cfg.JWTAlgorithm, suite.cfg.JWTKeyFile = config.JWTAlgorithmRS256, "testdata/ed25519.pem"
+	_, err = NewJWTProvider(suite.cfg, suite.u)
+	suite.Error(err)
+}
@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// maxKeyIDLen - максимальная длина идентификатора ключа
+const maxKeyIDLen = 64
+
+// keyIDRe - допустимые символы идентификатора ключа
+var keyIDRe = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// ErrNoActiveKey - в наборе ключей не задан действующий ключ подписи
+var ErrNoActiveKey = errors.New("no active signing key")
+
+// Key - секретный ключ для подписи токенов
+type Key struct {
+	ID       string    // Идентификатор ключа, передается в токене
+	Secret   []byte    // Секретный ключ
+	RetireAt time.Time // Время, после которого ключ не принимается. Нулевое значение - ключ не выводится из оборота
+}
+
+// retired - возвращает true, если ключ выведен из оборота на момент now
+func (k Key) retired(now time.Time) bool {
+	return !k.RetireAt.IsZero() && !now.Before(k.RetireAt)
+}
+
+// Keyring - набор ключей для подписи и проверки токенов.
+// Токены подписываются действующим ключом, а его идентификатор передается в токене.
+// Токены, подписанные прежними ключами, принимаются до вывода этих ключей из оборота (retire_at).
+// Это позволяет менять ключ без повторной аутентификации всех пользователей.
+//
+// Ключ с пустым идентификатором - ключ AuthSecret: им проверяются токены без идентификатора ключа,
+// выданные до перехода на набор ключей. Его тоже можно вывести из оборота, указав legacy_retire_at:
+// после этого момента токены без идентификатора ключа не принимаются.
+//
+// Набор ключей загружается из JSON-файла и может быть перезагружен без перезапуска сервиса (см. Reload).
+// Формат файла:
+//
+//	{
+//		"active": "2023-03",
+//		"keys": [
+//			{"id": "2023-03", "secret": "new-secret"},
+//			{"id": "2023-01", "secret": "old-secret", "retire_at": "2023-04-01T00:00:00Z"}
+//		],
+//		"legacy_retire_at": "2023-04-01T00:00:00Z"
+//	}
+type Keyring struct {
+	filePath string
+	legacy   []byte           // Ключ для токенов без идентификатора ключа
+	active   Key              // Действующий ключ подписи
+	keys     map[string]Key   // Ключи для проверки подписи
+	now      func() time.Time // Текущее время
+	mu       sync.RWMutex
+}
+
+// keyringDTO - структура для считывания набора ключей из JSON-файла.
+type keyringDTO struct {
+	Active string `json:"active"`
+	Keys   []struct {
+		ID       string     `json:"id"`
+		Secret   string     `json:"secret"`
+		RetireAt *time.Time `json:"retire_at"`
+	} `json:"keys"`
+	LegacyRetireAt *time.Time `json:"legacy_retire_at"`
+}
+
+// NewStaticKeyring - конструктор Keyring из одного ключа secret с пустым идентификатором.
+// Токены, подписанные таким ключом, совместимы с токенами, выданными до появления набора ключей.
+func NewStaticKeyring(secret string) *Keyring {
+	key := Key{Secret: []byte(secret)}
+	return &Keyring{
+		legacy: key.Secret,
+		active: key,
+		keys:   map[string]Key{"": key},
+		now:    time.Now,
+	}
+}
+
+// NewKeyring - конструктор Keyring. Загружает ключи из файла filePath.
+// Если задан legacySecret, то он используется для проверки токенов без идентификатора ключа.
+func NewKeyring(filePath, legacySecret string) (*Keyring, error) {
+	k := &Keyring{filePath: filePath, legacy: []byte(legacySecret), now: time.Now}
+	if err := k.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Reload - перезагружает ключи из файла.
+// При ошибке загрузки остается действовать предыдущий набор ключей.
+func (k *Keyring) Reload() error {
+	if k.filePath == "" {
+		return nil
+	}
+	f, err := os.Open(k.filePath)
+	if err != nil {
+		return err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer f.Close()
+
+	dto := &keyringDTO{}
+	d := json.NewDecoder(f)
+	d.DisallowUnknownFields() // запрещаем неизвестные поля, чтобы предотвратить опечатки
+	if err = d.Decode(dto); err != nil {
+		return fmt.Errorf("failed to parse auth keys %s: %w", k.filePath, err)
+	}
+
+	keys := make(map[string]Key, len(dto.Keys)+1)
+	if len(k.legacy) > 0 {
+		legacy := Key{Secret: k.legacy}
+		if dto.LegacyRetireAt != nil {
+			legacy.RetireAt = *dto.LegacyRetireAt
+		}
+		keys[""] = legacy
+	}
+	for _, item := range dto.Keys {
+		if len(item.ID) > maxKeyIDLen || !keyIDRe.MatchString(item.ID) {
+			return fmt.Errorf("invalid auth key id %q", item.ID)
+		}
+		if item.Secret == "" {
+			return fmt.Errorf("empty secret of auth key %q", item.ID)
+		}
+		if _, ok := keys[item.ID]; ok {
+			return fmt.Errorf("duplicate auth key id %q", item.ID)
+		}
+		key := Key{ID: item.ID, Secret: []byte(item.Secret)}
+		if item.RetireAt != nil {
+			key.RetireAt = *item.RetireAt
+		}
+		keys[item.ID] = key
+	}
+	// Действующий ключ не может быть выведен из оборота
+	active, ok := keys[dto.Active]
+	if !ok || dto.Active == "" || !active.RetireAt.IsZero() {
+		return fmt.Errorf("%w: %q", ErrNoActiveKey, dto.Active)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.active, k.keys = active, keys
+	return nil
+}
+
+// Active - возвращает действующий ключ подписи
+func (k *Keyring) Active() Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active
+}
+
+// Get - возвращает ключ для проверки подписи по идентификатору.
+// Ключи, выведенные из оборота, не возвращаются.
+func (k *Keyring) Get(id string) (Key, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[id]
+	if !ok || key.retired(k.now()) {
+		return Key{}, false
+	}
+	return key, true
+}
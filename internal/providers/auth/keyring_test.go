@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/repo"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+type keyringSuite struct {
+	suite.Suite
+}
+
+func TestKeyringSuite(t *testing.T) {
+	suite.Run(t, new(keyringSuite))
+}
+
+func (suite *keyringSuite) TestNewKeyring() {
+	k, err := NewKeyring("testdata/keys.json", "legacy-secret")
+	suite.Require().NoError(err)
+	suite.Equal("2023-03", k.Active().ID)
+	suite.Equal([]byte("march-secret"), k.Active().Secret)
+
+	key, ok := k.Get("2023-01")
+	suite.True(ok)
+	suite.Equal([]byte("january-secret"), key.Secret)
+	key, ok = k.Get("")
+	suite.True(ok)
+	suite.Equal([]byte("legacy-secret"), key.Secret)
+
+	// Ключ выведен из оборота
+	_, ok = k.Get("2022-10")
+	suite.False(ok)
+	_, ok = k.Get("unknown")
+	suite.False(ok)
+
+	// Ключ выводится из оборота в момент retire_at
+	k.now = func() time.Time { return time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC) }
+	_, ok = k.Get("2023-01")
+	suite.False(ok)
+	_, ok = k.Get("2023-03")
+	suite.True(ok)
+
+	// Без AuthSecret токены без идентификатора ключа не принимаются
+	k, err = NewKeyring("testdata/keys.json", "")
+	suite.Require().NoError(err)
+	_, ok = k.Get("")
+	suite.False(ok)
+}
+
+func (suite *keyringSuite) TestReload() {
+	fileName := filepath.Join(suite.T().TempDir(), "keys.json")
+	suite.Require().NoError(os.WriteFile(fileName, []byte(`{"active": "a", "keys": [{"id": "a", "secret": "s1"}]}`), 0o600))
+	k, err := NewKeyring(fileName, "")
+	suite.Require().NoError(err)
+	suite.Equal("a", k.Active().ID)
+
+	suite.Require().NoError(os.WriteFile(fileName, []byte(`{"active": "b", "keys": [{"id": "a", "secret": "s1"}, {"id": "b", "secret": "s2"}]}`), 0o600))
+	suite.NoError(k.Reload())
+	suite.Equal("b", k.Active().ID)
+	_, ok := k.Get("a")
+	suite.True(ok)
+
+	// При ошибке загрузки продолжает действовать прежний набор ключей
+	suite.Require().NoError(os.WriteFile(fileName, []byte(`{"active": "c", "keys": [{"id": "b", "secret": "s2"}]}`), 0o600))
+	suite.ErrorIs(k.Reload(), ErrNoActiveKey)
+	suite.Equal("b", k.Active().ID)
+}
+
+func (suite *keyringSuite) TestLegacyRetire() {
+	fileName := filepath.Join(suite.T().TempDir(), "keys.json")
+	suite.Require().NoError(os.WriteFile(fileName, []byte(`{
+		"active": "a",
+		"keys": [{"id": "a", "secret": "s1"}],
+		"legacy_retire_at": "2023-04-01T00:00:00Z"
+	}`), 0o600))
+	k, err := NewKeyring(fileName, "legacy-secret")
+	suite.Require().NoError(err)
+
+	// Ключ AuthSecret принимается до legacy_retire_at
+	k.now = func() time.Time { return time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC) }
+	key, ok := k.Get("")
+	suite.True(ok)
+	suite.Equal([]byte("legacy-secret"), key.Secret)
+
+	// и не принимается после
+	k.now = func() time.Time { return time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC) }
+	_, ok = k.Get("")
+	suite.False(ok)
+	_, ok = k.Get("a")
+	suite.True(ok)
+}
+
+func (suite *keyringSuite) TestInvalid() {
+	_, err := NewKeyring("testdata/keys-no-active.json", "")
+	suite.ErrorIs(err, ErrNoActiveKey)
+	_, err = NewKeyring("testdata/not-exists.json", "")
+	suite.Error(err)
+
+	dir := suite.T().TempDir()
+	for name, data := range map[string]string{
+		"invalid id":   `{"active": "a b", "keys": [{"id": "a b", "secret": "s"}]}`,
+		"empty id":     `{"active": "", "keys": [{"id": "", "secret": "s"}]}`,
+		"empty secret": `{"active": "a", "keys": [{"id": "a", "secret": ""}]}`,
+		"duplicate":    `{"active": "a", "keys": [{"id": "a", "secret": "s1"}, {"id": "a", "secret": "s2"}]}`,
+		"unknown":      `{"active": "a", "keys": [{"id": "a", "secret": "s", "kid": "a"}]}`,
+	} {
+		fileName := filepath.Join(dir, "keys.json")
+		suite.Require().NoError(os.WriteFile(fileName, []byte(data), 0o600))
+		_, err = NewKeyring(fileName, "")
+		suite.Error(err, name)
+	}
+}
+
+func (suite *keyringSuite) TestRotation() {
+	cfg := &config.Config{AuthSecret: "legacy-secret", AuthTTL: time.Hour}
+	u := usecases.NewUser(repo.NewMemoryRepo())
+	keys, err := NewKeyring("testdata/keys.json", cfg.AuthSecret)
+	suite.Require().NoError(err)
+
+	suite.Run("sha256", func() {
+		// Токен, выданный до появления набора ключей
+		legacyToken, err := NewSHA256Provider(cfg, u).CreateToken(Claims{UserID: 42})
+		suite.Require().NoError(err)
+
+		p := NewSHA256Provider(cfg, u)
+		p.UseKeyring(keys)
+		token, err := p.CreateToken(Claims{UserID: 42})
+		suite.Require().NoError(err)
+		for _, t := range []string{token, legacyToken} {
+			claims, err := p.VerifyToken(t)
+			suite.NoError(err)
+			suite.Equal(uint(42), claims.UserID)
+		}
+
+		// Токен с идентификатором ключа, выведенного из оборота
+		old := NewSHA256Provider(cfg, u)
+		old.UseKeyring(&Keyring{active: Key{ID: "2022-10", Secret: []byte("october-secret")}})
+		oldToken, err := old.CreateToken(Claims{UserID: 42})
+		suite.Require().NoError(err)
+		_, err = p.VerifyToken(oldToken)
+		suite.ErrorIs(err, ErrInvalidToken)
+	})
+
+	suite.Run("jwt", func() {
+		p, err := NewJWTProvider(cfg, u)
+		suite.Require().NoError(err)
+		p.UseKeyring(keys)
+		token, err := p.CreateToken(Claims{UserID: 42})
+		suite.Require().NoError(err)
+		claims, err := p.VerifyToken(token)
+		suite.NoError(err)
+		suite.Equal(uint(42), claims.UserID)
+
+		// После смены действующего ключа токен проверяется прежним ключом
+		next := &Keyring{
+			active: Key{ID: "2023-06", Secret: []byte("june-secret")},
+			keys:   map[string]Key{"2023-03": keys.Active()},
+			now:    time.Now,
+		}
+		p.UseKeyring(next)
+		_, err = p.VerifyToken(token)
+		suite.NoError(err)
+		next.keys = map[string]Key{}
+		_, err = p.VerifyToken(token)
+		suite.ErrorIs(err, ErrInvalidToken)
+	})
+}
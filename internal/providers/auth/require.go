@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+)
+
+// Requirement - требование маршрута или gRPC-метода к аутентификации пользователя.
+// Провайдер аутентификации только определяет пользователя по валидному токену,
+// а новых пользователей создают лишь маршруты и методы с требованием CreateUser.
+type Requirement int
+
+const (
+	// Optional - пользователь определяется по валидному токену, если он передан.
+	// Новый пользователь не создается. Если токен не удалось проверить, запрос обрабатывается как анонимный.
+	// Требование по умолчанию.
+	Optional Requirement = iota
+	// Required - требуется валидный токен, иначе возвращается ошибка аутентификации.
+	Required
+	// CreateUser - если валидный токен не передан, то создается новый пользователь
+	// и клиенту возвращается его токен. Используется маршрутами, которым нужен владелец ссылок.
+	CreateUser
+)
+
+// Methods - требования к аутентификации gRPC-методов.
+// Ключ - полное имя метода: /package.Service/Method.
+type Methods map[string]Requirement
+
+// Require - HTTP-middleware, объявляющее требование маршрута к аутентификации.
+// Должно использоваться после middleware провайдера аутентификации (см. Provider.Handler).
+func Require(req Requirement) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := FromContext(r.Context()); ok || req == Optional {
+				next.ServeHTTP(w, r)
+				return
+			}
+			// Токен не удалось проверить: не отклоняем его и не заменяем новым пользователем
+			if authFailedFromContext(r.Context()) {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			creator, ok := creatorFromContext(r.Context())
+			if req == Required || !ok {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			userID, err := creator.createHTTPUser(w, r)
+			if err != nil {
+				log.Err(err).Msg("auth: failed to create new user")
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ToContext(r.Context(), userID)))
+		})
+	}
+}
+
+// RequireUnary - унарный серверный интерцептор, применяющий требования methods к вызовам.
+// Должен использоваться после интерцептора провайдера аутентификации (см. Provider.Interceptor).
+// Для методов, не указанных в methods, действует требование Optional.
+func (methods Methods) RequireUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := methods.require(ctx, info.FullMethod, func(md metadata.MD) error { return grpc.SetHeader(ctx, md) })
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// RequireStream - потоковый серверный интерцептор, применяющий требования methods к вызовам.
+// Должен использоваться после интерцептора провайдера аутентификации (см. Provider.StreamInterceptor).
+// Для методов, не указанных в methods, действует требование Optional.
+func (methods Methods) RequireStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := methods.require(ss.Context(), info.FullMethod, ss.SetHeader)
+	if err != nil {
+		return err
+	}
+	if ctx != ss.Context() {
+		ss = &serverStream{ServerStream: ss, ctx: ctx}
+	}
+	return handler(srv, ss)
+}
+
+// require - проверяет требование метода fullMethod и при необходимости создает пользователя.
+// Возвращает контекст с id пользователя.
+func (methods Methods) require(ctx context.Context, fullMethod string, setHeader func(metadata.MD) error) (context.Context, error) {
+	req := methods[fullMethod]
+	if _, ok := FromContext(ctx); ok || req == Optional {
+		return ctx, nil
+	}
+	// Токен не удалось проверить: не отклоняем его и не заменяем новым пользователем
+	if authFailedFromContext(ctx) {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	creator, ok := creatorFromContext(ctx)
+	if req == Required || !ok {
+		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+	userID, err := creator.createGRPCUser(ctx, setHeader)
+	if err != nil {
+		log.Err(err).Msg("auth: failed to create new user")
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return ToContext(ctx, userID), nil
+}
+
+// Scopes - права доступа API-ключа, необходимые gRPC-методам.
+// Ключ - полное имя метода: /package.Service/Method.
+// Методы, не указанные в Scopes, недоступны по API-ключу. Вызовы с токеном пользователя имеют все права.
+type Scopes map[string]models.APIScope
+
+// RequireScope - HTTP-middleware, объявляющее право доступа API-ключа, необходимое маршруту.
+// Если у API-ключа запроса нет права scope, возвращает 403. Запросы с токеном пользователя имеют все права.
+// Должно использоваться после middleware провайдера аутентификации (см. Provider.Handler).
+func RequireScope(scope models.APIScope) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !HasScope(r.Context(), scope) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// NoAPIKey - HTTP-middleware для маршрутов, недоступных по API-ключу (например, управление самими ключами).
+// Для запросов с API-ключом возвращает 403.
+func NoAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := APIKeyFromContext(r.Context()); ok {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireUnary - унарный серверный интерцептор, проверяющий права доступа API-ключа.
+// Должен использоваться после интерцептора провайдера аутентификации (см. Provider.Interceptor).
+func (scopes Scopes) RequireUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := scopes.require(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// RequireStream - потоковый серверный интерцептор, проверяющий права доступа API-ключа.
+// Должен использоваться после интерцептора провайдера аутентификации (см. Provider.StreamInterceptor).
+func (scopes Scopes) RequireStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := scopes.require(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// require - проверяет право доступа API-ключа к методу fullMethod
+func (scopes Scopes) require(ctx context.Context, fullMethod string) error {
+	if _, ok := APIKeyFromContext(ctx); !ok {
+		return nil
+	}
+	scope, ok := scopes[fullMethod]
+	if !ok || !HasScope(ctx, scope) {
+		return status.Error(codes.PermissionDenied, "permission denied")
+	}
+	return nil
+}
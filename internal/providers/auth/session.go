@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Login - выдает клиенту новый токен пользователя userID в http-куке.
+// Используется после входа или регистрации пользователя.
+// Должно вызываться в обработчике, который следует за middleware провайдера аутентификации (см. Provider.Handler).
+func Login(w http.ResponseWriter, r *http.Request, userID uint) error {
+	creator, ok := creatorFromContext(r.Context())
+	if !ok {
+		return ErrNoProvider
+	}
+	return creator.issueHTTPToken(r.Context(), w, userID)
+}
+
+// Logout - удаляет http-куку с токеном пользователя.
+// Токен не отзывается: клиенты, сохранившие его, могут пользоваться им до окончания срока действия,
+// пока не будут отозваны все токены пользователя (см. usecases.User.RevokeTokens).
+func Logout(w http.ResponseWriter, r *http.Request) {
+	if creator, ok := creatorFromContext(r.Context()); ok {
+		creator.clearHTTPToken(w)
+	}
+}
+
+// LoginGRPC - выдает клиенту новый токен пользователя userID в заголовке ответа gRPC.
+// Должно вызываться в обработчике унарного метода после интерцептора провайдера аутентификации (см. Provider.Interceptor).
+func LoginGRPC(ctx context.Context, userID uint) error {
+	creator, ok := creatorFromContext(ctx)
+	if !ok {
+		return ErrNoProvider
+	}
+	return creator.issueGRPCToken(ctx, userID, func(md metadata.MD) error { return grpc.SetHeader(ctx, md) })
+}
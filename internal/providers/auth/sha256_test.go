@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,7 +11,9 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/ofstudio/go-shortener/internal/config"
 	"github.com/ofstudio/go-shortener/internal/repo"
@@ -38,7 +41,7 @@ func (suite *sha256ProviderSuite) SetupTest() {
 
 func (suite *sha256ProviderSuite) TestCreateToken() {
 	provider := NewSHA256Provider(suite.cfg, suite.u)
-	token, err := provider.CreateToken(1)
+	token, err := provider.CreateToken(Claims{UserID: 1})
 	suite.NoError(err)
 	suite.NotEmpty(token)
 }
@@ -46,19 +49,19 @@ func (suite *sha256ProviderSuite) TestCreateToken() {
 func (suite *sha256ProviderSuite) TestVerifyToken() {
 	suite.Run("valid token", func() {
 		provider := NewSHA256Provider(suite.cfg, suite.u)
-		token, err := provider.CreateToken(100)
+		token, err := provider.CreateToken(Claims{UserID: 100})
 		suite.Require().NoError(err)
 		suite.Require().NotEmpty(token)
 
-		userID, err := provider.VerifyToken(token)
+		claims, err := provider.VerifyToken(token)
 		suite.NoError(err)
-		suite.Equal(uint(100), userID)
+		suite.Equal(uint(100), claims.UserID)
 	})
 
 	suite.Run("invalid secret", func() {
 		suite.cfg.AuthSecret = "invalid"
 		provider := NewSHA256Provider(suite.cfg, suite.u)
-		token, err := provider.CreateToken(100)
+		token, err := provider.CreateToken(Claims{UserID: 100})
 		suite.Require().NoError(err)
 		suite.Require().NotEmpty(token)
 
@@ -71,7 +74,7 @@ func (suite *sha256ProviderSuite) TestVerifyToken() {
 	suite.Run("expired token", func() {
 		suite.cfg.AuthTTL = -time.Minute
 		provider := NewSHA256Provider(suite.cfg, suite.u)
-		token, err := provider.CreateToken(100)
+		token, err := provider.CreateToken(Claims{UserID: 100})
 		suite.Require().NoError(err)
 		suite.Require().NotEmpty(token)
 
@@ -84,6 +87,36 @@ func (suite *sha256ProviderSuite) TestVerifyToken() {
 		_, err := provider.VerifyToken("0102030405")
 		suite.ErrorIs(err, ErrInvalidToken)
 	})
+
+	suite.Run("token generation and session", func() {
+		suite.cfg.AuthTTL = time.Hour
+		provider := NewSHA256Provider(suite.cfg, suite.u)
+		keys := NewStaticKeyring(suite.cfg.AuthSecret)
+		keys.active = Key{ID: "2023-03", Secret: []byte("new-secret")}
+		keys.keys[keys.active.ID] = keys.active
+		for _, k := range []*Keyring{NewStaticKeyring(suite.cfg.AuthSecret), keys} {
+			provider.UseKeyring(k)
+			// Токен поколения 0 совпадает с токеном прежнего формата
+			token, err := provider.CreateToken(Claims{UserID: 100})
+			suite.Require().NoError(err)
+			tokenBytes, err := base64.RawURLEncoding.DecodeString(token)
+			suite.Require().NoError(err)
+			suite.Len(tokenBytes, lenData+len(k.Active().ID)+lenSignature)
+
+			for _, want := range []Claims{
+				{UserID: 100, Generation: 3},
+				{UserID: 100, SessionStart: time.Unix(time.Now().Unix()-60, 0)},
+				{UserID: 100, Generation: 3, SessionStart: time.Unix(time.Now().Unix()-60, 0)},
+			} {
+				want.ExpiresAt = time.Unix(time.Now().Unix()+60, 0)
+				token, err = provider.CreateToken(want)
+				suite.Require().NoError(err)
+				claims, err := provider.VerifyToken(token)
+				suite.NoError(err)
+				suite.Equal(want, claims)
+			}
+		}
+	})
 }
 
 type sha256ProviderHTTPSuite struct {
@@ -106,9 +139,12 @@ func (suite *sha256ProviderHTTPSuite) SetupTest() {
 
 	r := chi.NewRouter()
 	r.Use(NewSHA256Provider(suite.cfg, suite.u).Handler)
-	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+	ok := func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
-	})
+	}
+	r.With(Require(CreateUser)).Get("/", ok)
+	r.With(Require(Required)).Get("/private", ok)
+	r.Get("/ping", ok)
 	suite.testHTTP = httptest.NewServer(r)
 }
 
@@ -136,6 +172,29 @@ func (suite *sha256ProviderHTTPSuite) TestHandler_CreateUser() {
 		suite.Require().Equal(1, n)
 	})
 }
+
+func (suite *sha256ProviderHTTPSuite) TestHandler_Lazy() {
+	suite.Run("should not create user on optional route", func() {
+		resp, err := http.Get(suite.testHTTP.URL + "/ping")
+		suite.Require().NoError(err)
+		defer suite.NoError(resp.Body.Close())
+		suite.Equal(http.StatusOK, resp.StatusCode)
+		suite.Empty(resp.Cookies())
+	})
+
+	suite.Run("should reject request without token on required route", func() {
+		resp, err := http.Get(suite.testHTTP.URL + "/private")
+		suite.Require().NoError(err)
+		defer suite.NoError(resp.Body.Close())
+		suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+		suite.Empty(resp.Cookies())
+	})
+
+	n, err := suite.u.Count(context.Background())
+	suite.Require().NoError(err)
+	suite.Zero(n)
+}
+
 func (suite *sha256ProviderHTTPSuite) TestHandler_AcceptToken() {
 	suite.Run("should accept valid token", func() {
 		n, err := suite.u.Count(context.Background())
@@ -154,12 +213,16 @@ func (suite *sha256ProviderHTTPSuite) TestHandler_AcceptToken() {
 		suite.Require().NoError(err)
 		suite.Require().Equal(1, n)
 
-		req, err := http.NewRequest(http.MethodGet, suite.testHTTP.URL, nil)
-		suite.Require().NoError(err)
-		req.AddCookie(c)
-		resp, err = http.DefaultClient.Do(req)
-		suite.Require().NoError(err)
-		defer suite.NoError(resp.Body.Close())
+		for _, path := range []string{"/", "/private"} {
+			req, err := http.NewRequest(http.MethodGet, suite.testHTTP.URL+path, nil)
+			suite.Require().NoError(err)
+			req.AddCookie(c)
+			resp, err = http.DefaultClient.Do(req)
+			suite.Require().NoError(err)
+			defer suite.NoError(resp.Body.Close())
+			suite.Equal(http.StatusOK, resp.StatusCode)
+			suite.Empty(resp.Cookies())
+		}
 
 		n, err = suite.u.Count(context.Background())
 		suite.Require().NoError(err)
@@ -183,8 +246,12 @@ func (suite *sha256ProviderGRPSSuite) SetupTest() {
 		AuthTTL:    time.Hour,
 	}
 	suite.u = usecases.NewUser(repo.NewMemoryRepo())
+	methods := Methods{
+		"/pbsuite.HelloService/Hello":   CreateUser,
+		"/pbsuite.AnswerService/Answer": Required,
+	}
 	suite.StartServer(grpc.NewServer(
-		grpc.UnaryInterceptor(NewSHA256Provider(suite.cfg, suite.u).Interceptor),
+		grpc.ChainUnaryInterceptor(NewSHA256Provider(suite.cfg, suite.u).Interceptor, methods.RequireUnary),
 	))
 }
 
@@ -207,6 +274,27 @@ func (suite *sha256ProviderGRPSSuite) TestInterceptor_CreateUser() {
 	})
 }
 
+func (suite *sha256ProviderGRPSSuite) TestInterceptor_Required() {
+	suite.Run("should reject call without token", func() {
+		var header metadata.MD
+		_, err := suite.AnswerClient.Answer(context.Background(), &pbsuite.Empty{}, grpc.Header(&header))
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+		suite.Empty(header.Get("auth_token"))
+
+		n, err := suite.u.Count(context.Background())
+		suite.Require().NoError(err)
+		suite.Zero(n)
+	})
+
+	suite.Run("should accept valid token", func() {
+		token, err := NewSHA256Provider(suite.cfg, suite.u).CreateToken(Claims{UserID: 42})
+		suite.Require().NoError(err)
+		ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("auth_token", token))
+		_, err = suite.AnswerClient.Answer(ctx, &pbsuite.Empty{})
+		suite.NoError(err)
+	})
+}
+
 func (suite *sha256ProviderGRPSSuite) TestInterceptor_AcceptToken() {
 
 	suite.Run("should accept valid token", func() {
@@ -235,3 +323,63 @@ func (suite *sha256ProviderGRPSSuite) TestInterceptor_AcceptToken() {
 		suite.Require().Equal(1, n)
 	})
 }
+
+func (suite *sha256ProviderGRPSSuite) TestStreamInterceptor() {
+	p := NewSHA256Provider(suite.cfg, suite.u)
+	methods := Methods{"/test/Export": Required, "/test/Create": CreateUser}
+	handler := func(_ interface{}, ss grpc.ServerStream) error {
+		_, ok := FromContext(ss.Context())
+		suite.Require().True(ok)
+		return nil
+	}
+	call := func(method string, ss *testServerStream) error {
+		info := &grpc.StreamServerInfo{FullMethod: method}
+		return p.StreamInterceptor(nil, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+			return methods.RequireStream(srv, ss, info, handler)
+		})
+	}
+
+	suite.Run("should accept valid token", func() {
+		token, err := p.CreateToken(Claims{UserID: 42})
+		suite.Require().NoError(err)
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("auth_token", token))
+		suite.NoError(call("/test/Export", &testServerStream{ctx: ctx}))
+	})
+
+	suite.Run("should reject stream without token", func() {
+		err := call("/test/Export", &testServerStream{ctx: context.Background()})
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("auth_token", "invalid"))
+		err = call("/test/Export", &testServerStream{ctx: ctx})
+		suite.Equal(codes.Unauthenticated, status.Code(err))
+
+		n, err := suite.u.Count(context.Background())
+		suite.NoError(err)
+		suite.Zero(n)
+	})
+
+	suite.Run("should create user with token", func() {
+		ss := &testServerStream{ctx: context.Background()}
+		suite.NoError(call("/test/Create", ss))
+		suite.Len(ss.header.Get("auth_token"), 1)
+		n, err := suite.u.Count(context.Background())
+		suite.NoError(err)
+		suite.Equal(1, n)
+	})
+}
+
+// testServerStream - тестовый grpc.ServerStream с заданным контекстом
+type testServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *testServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,391 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+const (
+	httpCookieName  = "auth_token"
+	grpcMetadataKey = httpCookieName
+	// authorizationKey - http-заголовок и ключ метаданных gRPC с токеном Bearer
+	authorizationKey = "authorization"
+	bearerPrefix     = "bearer "
+)
+
+// CookieOpts - опции для HTTP-куки.
+type CookieOpts struct {
+	Domain   string
+	Path     string
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+}
+
+// tokenIssuer - создает и проверяет токены пользователей
+type tokenIssuer interface {
+	CreateToken(claims Claims) (string, error)
+	VerifyToken(token string) (Claims, error)
+}
+
+// transport - передача токенов в http-куке и метаданных gRPC.
+// Реализует middleware и интерцепторы, общие для провайдеров аутентификации:
+// провайдеры отличаются только форматом токена (см. tokenIssuer).
+//
+// Программные клиенты передают API-ключ в заголовке (метаданных) Authorization: Bearer <key>.
+// В этом же заголовке можно передать и токен пользователя.
+// Если заголовок передан, то токен из http-куки и метаданных auth_token не проверяется,
+// а при невалидном ключе или токене запрос отклоняется с ошибкой аутентификации.
+//
+// Токены выдаются с текущим поколением токенов пользователя и принимаются, только пока оно не изменилось:
+// так отзываются все выданные токены пользователя (см. usecases.User.RevokeTokens).
+//
+// Если токен из http-куки или метаданных auth_token не удалось проверить из-за ошибки хранилища,
+// то запрос обрабатывается как анонимный: публичные маршруты (перенаправления, ping) продолжают работать,
+// а маршруты и методы, которым нужен пользователь, возвращают внутреннюю ошибку (см. Require).
+//
+// Если до окончания срока действия токена из http-куки или метаданных auth_token осталось меньше renewBefore,
+// то клиенту выдается новый токен той же сессии: в http-куке или в метаданных auth_token заголовка ответа.
+// Сессия продлевается не дольше maxAge от ее начала, после чего пользователь должен войти заново.
+// Токены из заголовка Authorization не продлеваются.
+type transport struct {
+	u           *usecases.User
+	apiKeys     *usecases.APIKey // Проверка API-ключей. Если не задана, API-ключи не принимаются
+	tokens      tokenIssuer
+	CookieOpts  *CookieOpts   // Опции для HTTP-куки
+	ttl         time.Duration // Время жизни токена
+	renewBefore time.Duration // За сколько до окончания срока действия токен выдается заново
+	maxAge      time.Duration // Максимальная продолжительность сессии
+}
+
+// newTransport - конструктор transport.
+func newTransport(cfg *config.Config, u *usecases.User, tokens tokenIssuer) *transport {
+	renewBefore := cfg.AuthRenewBefore
+	if renewBefore > cfg.AuthTTL/2 {
+		renewBefore = cfg.AuthTTL / 2
+	}
+	return &transport{
+		u:           u,
+		tokens:      tokens,
+		ttl:         cfg.AuthTTL,
+		renewBefore: renewBefore,
+		maxAge:      cfg.AuthMaxAge,
+		CookieOpts: &CookieOpts{
+			Domain:   cfg.BaseURL.Hostname(),
+			Path:     "/",
+			Secure:   cfg.BaseURL.Scheme == "https",
+			HttpOnly: true,
+			SameSite: http.SameSiteDefaultMode,
+		},
+	}
+}
+
+// UseAPIKeys - подключает проверку API-ключей.
+func (p *transport) UseAPIKeys(keys *usecases.APIKey) {
+	p.apiKeys = keys
+}
+
+// Handler - HTTP-middleware для проверки авторизации.
+// Если в запросе передан валидный токен, то id пользователя устанавливается в контекст запроса.
+// Новый пользователь не создается: маршруты, которым он нужен, объявляют это с помощью Require.
+func (p *transport) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := creatorToContext(r.Context(), p)
+		// Проверяем API-ключ или токен в заголовке Authorization
+		if bearer, ok := bearerToken(r.Header.Get(authorizationKey)); ok {
+			var err error
+			ctx, err = p.bearerContext(ctx, bearer)
+			if errors.Is(err, pkgerrors.ErrInternal) {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			} else if err != nil {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+		// Проверяем наличие токена в http-куке и его валидность
+		if cookie, err := r.Cookie(httpCookieName); err == nil && cookie != nil {
+			claims, err := p.authenticate(ctx, cookie.Value)
+			if errors.Is(err, pkgerrors.ErrInternal) {
+				// Запрос обрабатывается как анонимный, а маршруты, которым нужен пользователь, вернут ошибку (см. Require)
+				log.Err(err).Msg("auth: failed to check token generation")
+				ctx = authFailedToContext(ctx)
+			} else if err == nil {
+				// Если найден валидный токен - устанавливаем userID в контекст и при необходимости продлеваем токен
+				ctx = ToContext(ctx, claims.UserID)
+				if token, expiresAt, ok := p.renewToken(claims); ok {
+					p.setCookie(w, token, cookieMaxAge(expiresAt))
+				}
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Interceptor - GRPC-middleware для проверки авторизации.
+// Если в метаданных запроса передан валидный токен, то id пользователя устанавливается в контекст.
+// Новый пользователь не создается: методы, которым он нужен, объявляются в Methods.
+func (p *transport) Interceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := p.grpcContext(ctx, func(md metadata.MD) error { return grpc.SetHeader(ctx, md) })
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor - GRPC-middleware для проверки авторизации потоковых методов. См. Interceptor.
+func (p *transport) StreamInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := p.grpcContext(ss.Context(), ss.SetHeader)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// grpcContext - возвращает контекст gRPC-запроса с провайдером и id пользователя, если передан валидный токен.
+// Продленный токен передается клиенту с помощью setHeader.
+// Если в метаданных authorization передан невалидный API-ключ или токен, возвращает ошибку Unauthenticated.
+func (p *transport) grpcContext(ctx context.Context, setHeader func(metadata.MD) error) (context.Context, error) {
+	ctx = creatorToContext(ctx, p)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(authorizationKey)) > 0 {
+		bearer, ok := bearerToken(md.Get(authorizationKey)[0])
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+		}
+		ctx, err := p.bearerContext(ctx, bearer)
+		if errors.Is(err, pkgerrors.ErrInternal) {
+			return nil, status.Error(codes.Internal, "internal error")
+		} else if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+		}
+		return ctx, nil
+	}
+	claims, err := p.claimsFromMetadata(ctx)
+	if errors.Is(err, pkgerrors.ErrInternal) {
+		// Запрос обрабатывается как анонимный, а методы, которым нужен пользователь, вернут ошибку (см. Methods)
+		log.Err(err).Msg("auth: failed to check token generation")
+		ctx = authFailedToContext(ctx)
+	} else if err == nil {
+		ctx = ToContext(ctx, claims.UserID)
+		if token, _, ok := p.renewToken(claims); ok {
+			if err = setHeader(metadata.Pairs(grpcMetadataKey, token)); err != nil {
+				log.Err(err).Msg("auth: failed to send renewed token")
+			}
+		}
+	}
+	return ctx, nil
+}
+
+// bearerContext - проверяет API-ключ или токен пользователя из заголовка Authorization
+// и возвращает контекст с id пользователя и API-ключом.
+func (p *transport) bearerContext(ctx context.Context, bearer string) (context.Context, error) {
+	if strings.HasPrefix(bearer, usecases.APIKeyPrefix) {
+		if p.apiKeys == nil {
+			return ctx, ErrInvalidToken
+		}
+		key, err := p.apiKeys.Verify(ctx, bearer)
+		if err != nil {
+			return ctx, err
+		}
+		return apiKeyToContext(ctx, key), nil
+	}
+	claims, err := p.authenticate(ctx, bearer)
+	if err != nil {
+		return ctx, err
+	}
+	return ToContext(ctx, claims.UserID), nil
+}
+
+// authenticate - проверяет токен, его поколение и продолжительность сессии и возвращает данные токена.
+// Если поколение токена устарело, возвращает ErrRevokedToken, если сессия слишком долгая - ErrExpiredToken.
+// При ошибке получения поколения токенов возвращает pkgerrors.ErrInternal.
+//
+// Для токенов без времени начала сессии оно считается равным времени выдачи токена.
+func (p *transport) authenticate(ctx context.Context, token string) (Claims, error) {
+	claims, err := p.tokens.VerifyToken(token)
+	if err != nil {
+		return Claims{}, err
+	}
+	generation, err := p.u.TokenGeneration(ctx, claims.UserID)
+	if err != nil {
+		return Claims{}, err
+	}
+	if claims.Generation != generation {
+		return Claims{}, ErrRevokedToken
+	}
+	if claims.SessionStart.IsZero() {
+		claims.SessionStart = claims.ExpiresAt.Add(-p.ttl)
+	}
+	if p.maxAge > 0 && time.Since(claims.SessionStart) > p.maxAge {
+		return Claims{}, ErrExpiredToken
+	}
+	return claims, nil
+}
+
+// createToken - создает токен новой сессии пользователя с текущим поколением.
+// Возвращает токен и время окончания его действия.
+func (p *transport) createToken(ctx context.Context, userID uint) (string, time.Time, error) {
+	generation, err := p.u.TokenGeneration(ctx, userID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	claims := Claims{UserID: userID, Generation: generation, SessionStart: time.Now()}
+	claims.ExpiresAt = p.expiresAt(claims.SessionStart)
+	token, err := p.tokens.CreateToken(claims)
+	return token, claims.ExpiresAt, err
+}
+
+// renewToken - создает новый токен той же сессии, если срок действия токена подходит к концу.
+// Возвращает false, если продлевать токен не нужно, сессия достигла максимальной продолжительности
+// или токен не удалось создать.
+func (p *transport) renewToken(claims Claims) (string, time.Time, bool) {
+	if p.renewBefore <= 0 || time.Until(claims.ExpiresAt) > p.renewBefore {
+		return "", time.Time{}, false
+	}
+	expiresAt := p.expiresAt(claims.SessionStart)
+	if !expiresAt.After(claims.ExpiresAt) {
+		return "", time.Time{}, false
+	}
+	claims.ExpiresAt = expiresAt
+	token, err := p.tokens.CreateToken(claims)
+	if err != nil {
+		log.Err(err).Msg("auth: failed to renew token")
+		return "", time.Time{}, false
+	}
+	return token, expiresAt, true
+}
+
+// expiresAt - возвращает время окончания действия токена, выданного сейчас для сессии, начатой в sessionStart
+func (p *transport) expiresAt(sessionStart time.Time) time.Time {
+	expiresAt := time.Now().Add(p.ttl)
+	if p.maxAge > 0 && expiresAt.After(sessionStart.Add(p.maxAge)) {
+		return sessionStart.Add(p.maxAge)
+	}
+	return expiresAt
+}
+
+// bearerToken - возвращает токен из значения заголовка Authorization со схемой Bearer.
+func bearerToken(header string) (string, bool) {
+	if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(bearerPrefix):]), true
+}
+
+// createHTTPUser - см. userCreator.createHTTPUser
+func (p *transport) createHTTPUser(w http.ResponseWriter, r *http.Request) (uint, error) {
+	userID, err := p.newUser(r.Context())
+	if err != nil {
+		return 0, err
+	}
+	if err = p.issueHTTPToken(r.Context(), w, userID); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// createGRPCUser - см. userCreator.createGRPCUser
+func (p *transport) createGRPCUser(ctx context.Context, setHeader func(metadata.MD) error) (uint, error) {
+	userID, err := p.newUser(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err = p.issueGRPCToken(ctx, userID, setHeader); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// issueHTTPToken - см. userCreator.issueHTTPToken
+func (p *transport) issueHTTPToken(ctx context.Context, w http.ResponseWriter, userID uint) error {
+	token, expiresAt, err := p.createToken(ctx, userID)
+	if err != nil {
+		return err
+	}
+	p.setCookie(w, token, cookieMaxAge(expiresAt))
+	return nil
+}
+
+// clearHTTPToken - см. userCreator.clearHTTPToken
+func (p *transport) clearHTTPToken(w http.ResponseWriter) {
+	p.setCookie(w, "", -1)
+}
+
+// issueGRPCToken - см. userCreator.issueGRPCToken
+func (p *transport) issueGRPCToken(ctx context.Context, userID uint, setHeader func(metadata.MD) error) error {
+	token, _, err := p.createToken(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return setHeader(metadata.Pairs(grpcMetadataKey, token))
+}
+
+// setCookie - устанавливает http-куку с токеном и временем жизни maxAge в секундах
+func (p *transport) setCookie(w http.ResponseWriter, token string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     httpCookieName,
+		Value:    token,
+		Domain:   p.CookieOpts.Domain,
+		Path:     p.CookieOpts.Path,
+		MaxAge:   maxAge,
+		Secure:   p.CookieOpts.Secure,
+		HttpOnly: p.CookieOpts.HttpOnly,
+		SameSite: http.SameSiteDefaultMode,
+	})
+}
+
+// cookieMaxAge - возвращает время жизни куки в секундах для токена, действующего до expiresAt
+func cookieMaxAge(expiresAt time.Time) int {
+	return int(time.Until(expiresAt).Round(time.Second) / time.Second)
+}
+
+// claimsFromMetadata - возвращает данные валидного токена из метаданных запроса.
+// Если токен не передан, возвращает ErrInvalidToken.
+func (p *transport) claimsFromMetadata(ctx context.Context) (Claims, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return Claims{}, ErrInvalidToken
+	}
+	token := md.Get(grpcMetadataKey)
+	if len(token) == 0 {
+		return Claims{}, ErrInvalidToken
+	}
+	return p.authenticate(ctx, token[0])
+}
+
+// serverStream - grpc.ServerStream с контекстом, содержащим id пользователя
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context - возвращает контекст потока
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// newUser - создает нового анонимного пользователя и возвращает его id.
+func (p *transport) newUser(ctx context.Context) (uint, error) {
+	user := &models.User{}
+	if err := p.u.Create(ctx, user); err != nil {
+		return 0, err
+	}
+	return user.ID, nil
+}
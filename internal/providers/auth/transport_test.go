@@ -0,0 +1,373 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/repo"
+	"github.com/ofstudio/go-shortener/internal/usecases"
+)
+
+type apiKeyTransportSuite struct {
+	suite.Suite
+	p       *SHA256Provider
+	u       *usecases.User
+	keys    *usecases.APIKey
+	readKey string // API-ключ пользователя 1 с правом read
+	router  chi.Router
+}
+
+func TestAPIKeyTransportSuite(t *testing.T) {
+	suite.Run(t, new(apiKeyTransportSuite))
+}
+
+func (suite *apiKeyTransportSuite) SetupTest() {
+	r := repo.NewMemoryRepo()
+	suite.u = usecases.NewUser(r)
+	suite.p = NewSHA256Provider(&config.Config{AuthSecret: "secret", AuthTTL: time.Hour}, suite.u)
+	suite.keys = usecases.NewAPIKey(r)
+	suite.p.UseAPIKeys(suite.keys)
+	var err error
+	_, suite.readKey, err = suite.keys.Create(context.Background(), 1, "CI", []models.APIScope{models.APIScopeRead})
+	suite.Require().NoError(err)
+
+	suite.router = chi.NewRouter()
+	suite.router.Use(suite.p.Handler)
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := FromContext(r.Context())
+		suite.Equal(uint(1), userID)
+		w.WriteHeader(http.StatusOK)
+	}
+	suite.router.With(Require(Required), RequireScope(models.APIScopeRead)).Get("/read", ok)
+	suite.router.With(Require(CreateUser), RequireScope(models.APIScopeCreate)).Post("/create", ok)
+	suite.router.With(Require(Required), NoAPIKey).Get("/keys", ok)
+}
+
+func (suite *apiKeyTransportSuite) request(method, path, authorization string) *http.Response {
+	req := httptest.NewRequest(method, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w.Result()
+}
+
+func (suite *apiKeyTransportSuite) TestHandler() {
+	token, err := suite.p.CreateToken(Claims{UserID: 1})
+	suite.Require().NoError(err)
+
+	for _, tt := range []struct {
+		name, method, path, authorization string
+		want                              int
+	}{
+		{"api key with scope", http.MethodGet, "/read", "Bearer " + suite.readKey, http.StatusOK},
+		{"case-insensitive scheme", http.MethodGet, "/read", "bearer " + suite.readKey, http.StatusOK},
+		{"api key without scope", http.MethodPost, "/create", "Bearer " + suite.readKey, http.StatusForbidden},
+		{"api key on session-only route", http.MethodGet, "/keys", "Bearer " + suite.readKey, http.StatusForbidden},
+		{"invalid api key", http.MethodGet, "/read", "Bearer " + suite.readKey + "x", http.StatusUnauthorized},
+		{"invalid bearer token", http.MethodPost, "/create", "Bearer invalid", http.StatusUnauthorized},
+		{"user token has all scopes", http.MethodPost, "/create", "Bearer " + token, http.StatusOK},
+		{"user token on session-only route", http.MethodGet, "/keys", "Bearer " + token, http.StatusOK},
+	} {
+		suite.Run(tt.name, func() {
+			resp := suite.request(tt.method, tt.path, tt.authorization)
+			suite.NoError(resp.Body.Close())
+			suite.Equal(tt.want, resp.StatusCode)
+			// По API-ключу новый пользователь не создается
+			suite.Empty(resp.Cookies())
+		})
+	}
+
+	suite.Run("revoked api key", func() {
+		keys, err := suite.keys.GetByUserID(context.Background(), 1)
+		suite.Require().NoError(err)
+		suite.Require().NoError(suite.keys.Revoke(context.Background(), 1, keys[0].ID))
+		resp := suite.request(http.MethodGet, "/read", "Bearer "+suite.readKey)
+		suite.NoError(resp.Body.Close())
+		suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func (suite *apiKeyTransportSuite) TestInterceptor() {
+	scopes := Scopes{"/test/Read": models.APIScopeRead, "/test/Create": models.APIScopeCreate}
+	call := func(method, authorization string) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", authorization))
+		info := &grpc.UnaryServerInfo{FullMethod: method}
+		_, err := suite.p.Interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return scopes.RequireUnary(ctx, req, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				userID, _ := FromContext(ctx)
+				suite.Equal(uint(1), userID)
+				return nil, nil
+			})
+		})
+		return err
+	}
+
+	suite.NoError(call("/test/Read", "Bearer "+suite.readKey))
+	suite.Equal(codes.PermissionDenied, status.Code(call("/test/Create", "Bearer "+suite.readKey)))
+	// Методы, не указанные в Scopes, недоступны по API-ключу
+	suite.Equal(codes.PermissionDenied, status.Code(call("/test/Keys", "Bearer "+suite.readKey)))
+	suite.Equal(codes.Unauthenticated, status.Code(call("/test/Read", "Bearer "+suite.readKey+"x")))
+	suite.Equal(codes.Unauthenticated, status.Code(call("/test/Read", suite.readKey)))
+
+	token, err := suite.p.CreateToken(Claims{UserID: 1})
+	suite.Require().NoError(err)
+	suite.NoError(call("/test/Keys", "Bearer "+token))
+}
+
+func (suite *apiKeyTransportSuite) TestRevokeTokens() {
+	ctx := context.Background()
+	user := &models.User{}
+	suite.Require().NoError(suite.u.Create(ctx, user))
+	suite.Require().Equal(uint(1), user.ID)
+	token, _, err := suite.p.createToken(ctx, user.ID)
+	suite.Require().NoError(err)
+
+	cookieRequest := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/read", nil)
+		req.AddCookie(&http.Cookie{Name: httpCookieName, Value: token})
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w.Code
+	}
+	grpcUser := func(token string) (uint, bool) {
+		ctx := metadata.NewIncomingContext(ctx, metadata.Pairs(grpcMetadataKey, token))
+		ctx, err := suite.p.grpcContext(ctx, noHeader)
+		suite.Require().NoError(err)
+		return FromContext(ctx)
+	}
+	suite.Equal(http.StatusOK, cookieRequest(token))
+	_, ok := grpcUser(token)
+	suite.True(ok)
+
+	// После отзыва токен не принимается ни в куке, ни в заголовке, ни в метаданных
+	_, err = suite.u.RevokeTokens(ctx, user.ID)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusUnauthorized, cookieRequest(token))
+	resp := suite.request(http.MethodGet, "/read", "Bearer "+token)
+	suite.NoError(resp.Body.Close())
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+	_, ok = grpcUser(token)
+	suite.False(ok)
+	_, err = suite.p.grpcContext(metadata.NewIncomingContext(ctx, metadata.Pairs(authorizationKey, "Bearer "+token)), noHeader)
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+
+	// Новый токен выдается с текущим поколением
+	token, _, err = suite.p.createToken(ctx, user.ID)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, cookieRequest(token))
+	_, ok = grpcUser(token)
+	suite.True(ok)
+}
+
+func (suite *apiKeyTransportSuite) TestGenerationLookupFailure() {
+	r := &failingRepo{IRepo: repo.NewMemoryRepo()}
+	p := NewSHA256Provider(&config.Config{AuthSecret: "secret", AuthTTL: time.Hour}, usecases.NewUser(r))
+	token, err := p.CreateToken(Claims{UserID: 1})
+	suite.Require().NoError(err)
+	router := chi.NewRouter()
+	router.Use(p.Handler)
+	anonymous := func(w http.ResponseWriter, r *http.Request) {
+		_, ok := FromContext(r.Context())
+		suite.False(ok)
+	}
+	router.Get("/public", anonymous)
+	router.With(Require(Required)).Get("/read", anonymous)
+	router.With(Require(CreateUser)).Post("/create", anonymous)
+
+	// Публичные маршруты обрабатывают запрос как анонимный, остальные возвращают ошибку и не создают пользователя
+	for _, tt := range []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/public", http.StatusOK},
+		{http.MethodGet, "/read", http.StatusInternalServerError},
+		{http.MethodPost, "/create", http.StatusInternalServerError},
+	} {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.AddCookie(&http.Cookie{Name: httpCookieName, Value: token})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		suite.Equal(tt.want, w.Code, tt.path)
+		suite.Empty(w.Result().Cookies(), tt.path)
+	}
+
+	methods := Methods{"/test/Read": Required, "/test/Create": CreateUser}
+	call := func(method string) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(grpcMetadataKey, token))
+		info := &grpc.UnaryServerInfo{FullMethod: method}
+		_, err := p.Interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return methods.RequireUnary(ctx, req, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				return nil, nil
+			})
+		})
+		return err
+	}
+	suite.NoError(call("/test/Public"))
+	suite.Equal(codes.Internal, status.Code(call("/test/Read")))
+	suite.Equal(codes.Internal, status.Code(call("/test/Create")))
+}
+
+// failingRepo - репозиторий, в котором не удается получить пользователя
+type failingRepo struct {
+	repo.IRepo
+}
+
+func (r *failingRepo) UserGetByID(context.Context, uint) (*models.User, error) {
+	return nil, errors.New("storage is unavailable")
+}
+
+// noHeader - не передает метаданные заголовка ответа gRPC
+func noHeader(metadata.MD) error {
+	return nil
+}
+
+type sessionTransportSuite struct {
+	suite.Suite
+	p      *SHA256Provider
+	router chi.Router
+}
+
+func TestSessionTransportSuite(t *testing.T) {
+	suite.Run(t, new(sessionTransportSuite))
+}
+
+func (suite *sessionTransportSuite) SetupTest() {
+	cfg := &config.Config{AuthSecret: "secret", AuthTTL: time.Hour, AuthRenewBefore: time.Hour, AuthMaxAge: 2 * time.Hour}
+	suite.p = NewSHA256Provider(cfg, usecases.NewUser(repo.NewMemoryRepo()))
+	suite.router = chi.NewRouter()
+	suite.router.Use(suite.p.Handler)
+	suite.router.With(Require(Required)).Get("/read", func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := FromContext(r.Context())
+		suite.Equal(uint(1), userID)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// token - возвращает токен пользователя 1 сессии, начатой sessionAge назад и действующей еще expiresIn
+func (suite *sessionTransportSuite) token(sessionAge, expiresIn time.Duration) string {
+	token, err := suite.p.CreateToken(Claims{
+		UserID:       1,
+		SessionStart: time.Now().Add(-sessionAge),
+		ExpiresAt:    time.Now().Add(expiresIn),
+	})
+	suite.Require().NoError(err)
+	return token
+}
+
+// cookieRequest - выполняет запрос с токеном в http-куке
+func (suite *sessionTransportSuite) cookieRequest(token string) *http.Response {
+	req := httptest.NewRequest(http.MethodGet, "/read", nil)
+	req.AddCookie(&http.Cookie{Name: httpCookieName, Value: token})
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w.Result()
+}
+
+// grpcRequest - проверяет токен в метаданных gRPC и возвращает продленный токен, если он выдан
+func (suite *sessionTransportSuite) grpcRequest(token string) (string, bool) {
+	var renewed []string
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(grpcMetadataKey, token))
+	ctx, err := suite.p.grpcContext(ctx, func(md metadata.MD) error {
+		renewed = md.Get(grpcMetadataKey)
+		return nil
+	})
+	suite.Require().NoError(err)
+	_, ok := FromContext(ctx)
+	suite.True(ok)
+	if len(renewed) == 0 {
+		return "", false
+	}
+	return renewed[0], true
+}
+
+func (suite *sessionTransportSuite) TestRenewal() {
+	// Токен продлевается для той же сессии: срок действия - AuthTTL, начало сессии не меняется
+	suite.Run("renew expiring token", func() {
+		sessionStart := time.Now().Add(-time.Hour).Truncate(time.Second)
+		res := suite.cookieRequest(suite.token(time.Hour, 10*time.Minute))
+		suite.NoError(res.Body.Close())
+		suite.Equal(http.StatusOK, res.StatusCode)
+		suite.Require().Len(res.Cookies(), 1)
+		cookie := res.Cookies()[0]
+		suite.InDelta(3600, cookie.MaxAge, 1)
+		claims, err := suite.p.VerifyToken(cookie.Value)
+		suite.Require().NoError(err)
+		suite.Equal(uint(1), claims.UserID)
+		suite.Equal(sessionStart.Unix(), claims.SessionStart.Unix())
+		suite.WithinDuration(time.Now().Add(time.Hour), claims.ExpiresAt, 2*time.Second)
+
+		token, ok := suite.grpcRequest(suite.token(time.Hour, 10*time.Minute))
+		suite.True(ok)
+		claims, err = suite.p.VerifyToken(token)
+		suite.Require().NoError(err)
+		suite.WithinDuration(time.Now().Add(time.Hour), claims.ExpiresAt, 2*time.Second)
+	})
+
+	// Продление не выдается для токена, срок действия которого еще не подходит к концу
+	suite.Run("keep fresh token", func() {
+		res := suite.cookieRequest(suite.token(time.Minute, 50*time.Minute))
+		suite.NoError(res.Body.Close())
+		suite.Equal(http.StatusOK, res.StatusCode)
+		suite.Empty(res.Cookies())
+		_, ok := suite.grpcRequest(suite.token(time.Minute, 50*time.Minute))
+		suite.False(ok)
+	})
+
+	// Срок действия продленного токена не выходит за максимальную продолжительность сессии
+	suite.Run("limit session max age", func() {
+		res := suite.cookieRequest(suite.token(90*time.Minute, 10*time.Minute))
+		suite.NoError(res.Body.Close())
+		suite.Require().Len(res.Cookies(), 1)
+		claims, err := suite.p.VerifyToken(res.Cookies()[0].Value)
+		suite.Require().NoError(err)
+		suite.WithinDuration(time.Now().Add(30*time.Minute), claims.ExpiresAt, 2*time.Second)
+
+		res = suite.cookieRequest(suite.token(2*time.Hour-5*time.Minute, 5*time.Minute))
+		suite.NoError(res.Body.Close())
+		suite.Equal(http.StatusOK, res.StatusCode)
+		suite.Empty(res.Cookies())
+	})
+
+	// Токен сессии, превысившей максимальную продолжительность, не принимается
+	suite.Run("reject expired session", func() {
+		res := suite.cookieRequest(suite.token(3*time.Hour, 10*time.Minute))
+		suite.NoError(res.Body.Close())
+		suite.Equal(http.StatusUnauthorized, res.StatusCode)
+	})
+}
+
+func (suite *sessionTransportSuite) TestCreateToken() {
+	// Новая сессия начинается с выдачи токена
+	token, expiresAt, err := suite.p.createToken(context.Background(), 1)
+	suite.Require().NoError(err)
+	suite.WithinDuration(time.Now().Add(time.Hour), expiresAt, time.Second)
+	claims, err := suite.p.VerifyToken(token)
+	suite.Require().NoError(err)
+	suite.WithinDuration(time.Now(), claims.SessionStart, 2*time.Second)
+
+	// Срок действия токена прежнего формата продлевается не дольше AuthMaxAge от его выдачи
+	legacy, err := suite.p.CreateToken(Claims{UserID: 1, ExpiresAt: time.Now().Add(5 * time.Minute)})
+	suite.Require().NoError(err)
+	res := suite.cookieRequest(legacy)
+	suite.NoError(res.Body.Close())
+	suite.Require().Len(res.Cookies(), 1)
+	claims, err = suite.p.VerifyToken(res.Cookies()[0].Value)
+	suite.Require().NoError(err)
+	suite.WithinDuration(time.Now().Add(5*time.Minute-time.Hour), claims.SessionStart, 2*time.Second)
+	suite.WithinDuration(time.Now().Add(time.Hour), claims.ExpiresAt, 2*time.Second)
+}
@@ -0,0 +1,2 @@
+// Package botdetect - классификация посетителей коротких ссылок: боты и люди
+package botdetect
@@ -0,0 +1,10 @@
+package botdetect
+
+import "github.com/ofstudio/go-shortener/internal/models"
+
+// Classifier - классификатор посетителей коротких ссылок.
+type Classifier interface {
+	// IsBot - возвращает true, если переход выполнен ботом:
+	// поисковым роботом, сервисом предпросмотра ссылок, мессенджером и т.п.
+	IsBot(visit *models.Visit) bool
+}
@@ -0,0 +1,138 @@
+package botdetect
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+)
+
+// defaultBots - встроенные шаблоны User-Agent ботов.
+// Охватывают поисковых роботов, сервисы предпросмотра ссылок в мессенджерах и соцсетях,
+// а также HTTP-клиенты командной строки и библиотек.
+var defaultBots = []string{
+	`bot\b`, `crawl`, `spider`, `slurp`, `archiver`,
+	`facebookexternalhit`, `facebookcatalog`, `slack-imgproxy`, `whatsapp`, `skypeuripreview`,
+	`vkshare`, `embedly`, `quora link preview`, `outbrain`, `pinterest`, `redditbot`,
+	`preview`, `headlesschrome`, `lighthouse`,
+	`^curl/`, `^wget/`, `python-requests`, `python-urllib`, `go-http-client`, `okhttp`, `java/`, `libwww-perl`,
+}
+
+// defaultHumans - встроенные шаблоны User-Agent, которые похожи на шаблоны ботов, но принадлежат людям.
+var defaultHumans = []string{
+	`cubot`, // смартфоны Cubot
+}
+
+// Rules - классификатор посетителей по шаблонам User-Agent и признакам запроса.
+// Переход считается выполненным ботом, если:
+//   - заголовок User-Agent пуст;
+//   - запрос выполнен методом HEAD;
+//   - запрос является предварительной загрузкой страницы (заголовок Purpose или Sec-Purpose содержит prefetch);
+//   - User-Agent совпадает с одним из шаблонов ботов и не совпадает ни с одним из шаблонов людей.
+//
+// Шаблоны - регулярные выражения без учета регистра.
+// Помимо встроенных шаблонов можно задать дополнительные в JSON-файле.
+// Файл может быть перезагружен без перезапуска сервиса (см. Reload).
+// Формат файла:
+//
+//	{
+//		"bots": ["^Mozilla/5\\.0 \\(compatible; UptimeProbe/"],
+//		"humans": ["^PartnerApp/"]
+//	}
+type Rules struct {
+	filePath string
+	bots     *regexp.Regexp
+	humans   *regexp.Regexp
+	mu       sync.RWMutex
+}
+
+// rulesDTO - структура для считывания шаблонов из JSON-файла.
+type rulesDTO struct {
+	Bots   []string `json:"bots"`
+	Humans []string `json:"humans"`
+}
+
+// NewRules - конструктор Rules. Загружает дополнительные шаблоны из файла filePath.
+// Если filePath не задан, то используются только встроенные шаблоны.
+func NewRules(filePath string) (*Rules, error) {
+	r := &Rules{filePath: filePath}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewDefaultRules - конструктор Rules только со встроенными шаблонами.
+func NewDefaultRules() *Rules {
+	r, err := NewRules("")
+	if err != nil {
+		// Встроенные шаблоны проверяются тестами и всегда корректны
+		panic(err)
+	}
+	return r
+}
+
+// Reload - перезагружает шаблоны из файла.
+// При ошибке загрузки остаются действовать предыдущие шаблоны.
+func (r *Rules) Reload() error {
+	dto := &rulesDTO{}
+	if r.filePath != "" {
+		f, err := os.Open(r.filePath)
+		if err != nil {
+			return err
+		}
+		//goland:noinspection GoUnhandledErrorResult
+		defer f.Close()
+
+		d := json.NewDecoder(f)
+		d.DisallowUnknownFields() // запрещаем неизвестные поля, чтобы предотвратить опечатки
+		if err = d.Decode(dto); err != nil {
+			return fmt.Errorf("failed to parse bot rules %s: %w", r.filePath, err)
+		}
+	}
+	bots, err := compilePatterns(append(dto.Bots, defaultBots...))
+	if err != nil {
+		return err
+	}
+	humans, err := compilePatterns(append(dto.Humans, defaultHumans...))
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bots, r.humans = bots, humans
+	return nil
+}
+
+// IsBot - см. Classifier.IsBot
+func (r *Rules) IsBot(visit *models.Visit) bool {
+	if visit.UserAgent == "" || visit.Method == http.MethodHead ||
+		strings.Contains(strings.ToLower(visit.Purpose), "prefetch") {
+		return true
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.bots.MatchString(visit.UserAgent) && !r.humans.MatchString(visit.UserAgent)
+}
+
+// compilePatterns - объединяет шаблоны в одно регулярное выражение без учета регистра.
+// Каждый шаблон проверяется отдельно, чтобы в ошибке был указан некорректный шаблон.
+func compilePatterns(patterns []string) (*regexp.Regexp, error) {
+	parts := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		if _, err := regexp.Compile(p); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		parts = append(parts, "(?:"+p+")")
+	}
+	return regexp.Compile("(?i)" + strings.Join(parts, "|"))
+}
@@ -0,0 +1,91 @@
+package botdetect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+)
+
+type rulesSuite struct {
+	suite.Suite
+}
+
+func TestRulesSuite(t *testing.T) {
+	suite.Run(t, new(rulesSuite))
+}
+
+func (suite *rulesSuite) TestDefault() {
+	r := NewDefaultRules()
+	bots := []string{
+		"Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)",
+		"Slack-ImgProxy (+https://api.slack.com/robots)",
+		"TelegramBot (like TwitterBot)",
+		"facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)",
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)",
+		"Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)",
+		"WhatsApp/2.23.2.72 A",
+		"curl/7.88.1",
+		"Go-http-client/1.1",
+	}
+	for _, ua := range bots {
+		suite.True(r.IsBot(&models.Visit{UserAgent: ua, Method: "GET"}), ua)
+	}
+	humans := []string{
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Mobile/15E148 Safari/604.1",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (Linux; Android 12; CUBOT KINGKONG 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Mobile Safari/537.36",
+	}
+	for _, ua := range humans {
+		suite.False(r.IsBot(&models.Visit{UserAgent: ua, Method: "GET"}), ua)
+	}
+}
+
+func (suite *rulesSuite) TestHeuristics() {
+	r := NewDefaultRules()
+	browser := "Mozilla/5.0 (Windows NT 10.0; Win64; x64)"
+	suite.True(r.IsBot(&models.Visit{Method: "GET"}))
+	suite.True(r.IsBot(&models.Visit{UserAgent: browser, Method: "HEAD"}))
+	suite.True(r.IsBot(&models.Visit{UserAgent: browser, Method: "GET", Purpose: "prefetch;prerender"}))
+	suite.False(r.IsBot(&models.Visit{UserAgent: browser, Method: "GET"}))
+}
+
+func (suite *rulesSuite) TestFile() {
+	suite.Run("additional patterns", func() {
+		r, err := NewRules("testdata/rules.json")
+		suite.Require().NoError(err)
+		suite.True(r.IsBot(&models.Visit{UserAgent: "Mozilla/5.0 (compatible; UptimeProbe/1.0)"}))
+		suite.False(r.IsBot(&models.Visit{UserAgent: "PartnerApp/3.1 (bot-free edition)"}))
+		// Встроенные шаблоны продолжают действовать
+		suite.True(r.IsBot(&models.Visit{UserAgent: "TelegramBot (like TwitterBot)"}))
+	})
+
+	suite.Run("reload", func() {
+		fileName := filepath.Join(suite.T().TempDir(), "bots.json")
+		suite.Require().NoError(os.WriteFile(fileName, []byte(`{"bots": ["^MyMonitor/"]}`), 0o600))
+		r, err := NewRules(fileName)
+		suite.Require().NoError(err)
+		suite.True(r.IsBot(&models.Visit{UserAgent: "MyMonitor/1.0"}))
+
+		suite.Require().NoError(os.WriteFile(fileName, []byte(`{"bots": ["^OtherMonitor/"]}`), 0o600))
+		suite.NoError(r.Reload())
+		suite.False(r.IsBot(&models.Visit{UserAgent: "MyMonitor/1.0"}))
+		suite.True(r.IsBot(&models.Visit{UserAgent: "OtherMonitor/1.0"}))
+
+		// При ошибке загрузки продолжают действовать прежние шаблоны
+		suite.Require().NoError(os.WriteFile(fileName, []byte(`{"bots": ["("]}`), 0o600))
+		suite.Error(r.Reload())
+		suite.True(r.IsBot(&models.Visit{UserAgent: "OtherMonitor/1.0"}))
+	})
+
+	suite.Run("invalid file", func() {
+		_, err := NewRules("testdata/invalid.json")
+		suite.Error(err)
+		_, err = NewRules("testdata/not-exists.json")
+		suite.Error(err)
+	})
+}
@@ -4,7 +4,10 @@ package providers
 import (
 	"github.com/ofstudio/go-shortener/internal/providers/auth"
 	"github.com/ofstudio/go-shortener/internal/providers/ipcheck"
+	"github.com/ofstudio/go-shortener/internal/providers/metrics"
+	"github.com/ofstudio/go-shortener/internal/providers/oidc"
 	"github.com/ofstudio/go-shortener/internal/providers/tlsconf"
+	"github.com/ofstudio/go-shortener/internal/providers/tracing"
 )
 
 // Container - контейнер провайдеров
@@ -15,4 +18,10 @@ type Container struct {
 	TLSConf tlsconf.Provider
 	// IPCheck - провайдер проверки IP
 	IPCheck ipcheck.Provider
+	// Metrics - провайдер метрик
+	Metrics metrics.Provider
+	// Tracing - провайдер трассировки
+	Tracing tracing.Provider
+	// OIDC - провайдер входа через OpenID Connect. Если вход не настроен, то nil
+	OIDC oidc.Provider
 }
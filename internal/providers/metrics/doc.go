@@ -0,0 +1,2 @@
+// Package metrics - провайдер метрик приложения в формате Prometheus
+package metrics
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+// Provider - провайдер метрик приложения.
+// Реализует middleware для http и grpc.
+type Provider interface {
+	Recorder
+	// Handler - http.HandlerFunc: учитывает количество и длительность http-запросов
+	Handler(next http.Handler) http.Handler
+	// Interceptor - grpc.UnaryServerInterceptor: учитывает количество и длительность grpc-запросов
+	Interceptor(context.Context, interface{}, *grpc.UnaryServerInfo, grpc.UnaryHandler) (interface{}, error)
+	// StreamInterceptor - grpc.StreamServerInterceptor: учитывает количество и длительность потоковых grpc-запросов
+	StreamInterceptor(interface{}, grpc.ServerStream, *grpc.StreamServerInfo, grpc.StreamHandler) error
+	// ExportHandler - возвращает http-обработчик, который отдает метрики в текстовом формате Prometheus
+	ExportHandler() http.Handler
+	// ObserveRepo - возвращает repo.ObserveFunc, которая учитывает длительность операций репозитория backend
+	ObserveRepo(backend string) repo.ObserveFunc
+}
+
+// Recorder - учет событий бизнес-логики.
+type Recorder interface {
+	// Redirect - учитывает попытку перехода по короткой ссылке: успешную (hit) или нет (miss)
+	Redirect(hit bool)
+	// DeleteQueue - изменяет количество ссылок в очереди на удаление на delta
+	DeleteQueue(delta int)
+}
+
+// Nop - реализация Recorder, которая ничего не учитывает.
+// Используется, если метрики не подключены.
+type Nop struct{}
+
+// Redirect - ничего не делает
+func (Nop) Redirect(bool) {}
+
+// DeleteQueue - ничего не делает
+func (Nop) DeleteQueue(int) {}
@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+// namespace - префикс имен метрик приложения
+const namespace = "shortener"
+
+// unmatchedRoute - значение метки route для http-запросов, не соответствующих ни одному маршруту
+const unmatchedRoute = "unmatched"
+
+// otherMethod - значение метки method для http-запросов с нестандартным методом
+const otherMethod = "other"
+
+// Prometheus - реализация Provider, которая собирает метрики в собственный реестр Prometheus.
+// Кроме метрик приложения, в реестр входят метрики среды выполнения Go и процесса.
+type Prometheus struct {
+	registry     *prometheus.Registry
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	grpcRequests *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+	repoDuration *prometheus.HistogramVec
+	redirects    *prometheus.CounterVec
+	deleteQueue  prometheus.Gauge
+}
+
+// NewPrometheus - конструктор Prometheus.
+func NewPrometheus() *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by route, method and status code.",
+		}, []string{"route", "method", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "code"}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "Number of gRPC requests by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "gRPC request latency by method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		repoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repo_operation_duration_seconds",
+			Help:      "Repository operation latency by backend, operation and result.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"backend", "operation", "result"}),
+		redirects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "redirects_total",
+			Help:      "Number of short URL redirect attempts by result (hit or miss).",
+		}, []string{"result"}),
+		deleteQueue: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "delete_queue_depth",
+			Help:      "Number of short URLs waiting for batch deletion.",
+		}),
+	}
+	p.registry.MustRegister(
+		p.httpRequests, p.httpDuration,
+		p.grpcRequests, p.grpcDuration,
+		p.repoDuration, p.redirects, p.deleteQueue,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	// Инициализируем счетчики переходов, чтобы они отображались до первого перехода
+	p.redirects.WithLabelValues("hit")
+	p.redirects.WithLabelValues("miss")
+	return p
+}
+
+// Handler - http middleware, которая учитывает количество и длительность http-запросов.
+// Маршрут определяется по шаблону маршрута chi, поэтому middleware должна быть подключена к корневому роутеру.
+func (p *Prometheus) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := unmatchedRoute
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		code := ww.Status()
+		if code == 0 {
+			code = http.StatusOK
+		}
+		labels := prometheus.Labels{"route": route, "method": httpMethod(r.Method), "code": strconv.Itoa(code)}
+		p.httpRequests.With(labels).Inc()
+		p.httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// httpMethod - возвращает значение метки method для http-метода method.
+// Нестандартные методы учитываются как otherMethod, чтобы клиенты не могли создавать произвольные значения метки.
+func httpMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return otherMethod
+}
+
+// Interceptor - grpc.UnaryServerInterceptor, который учитывает количество и длительность grpc-запросов.
+func (p *Prometheus) Interceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	p.observeGRPC(info.FullMethod, err, start)
+	return resp, err
+}
+
+// StreamInterceptor - grpc.StreamServerInterceptor, который учитывает количество потоковых grpc-запросов
+// и длительность работы потока.
+func (p *Prometheus) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	p.observeGRPC(info.FullMethod, err, start)
+	return err
+}
+
+// observeGRPC - учитывает grpc-запрос метода method, начатый в start и завершенный с ошибкой err
+func (p *Prometheus) observeGRPC(method string, err error, start time.Time) {
+	labels := prometheus.Labels{"method": method, "code": status.Code(err).String()}
+	p.grpcRequests.With(labels).Inc()
+	p.grpcDuration.With(labels).Observe(time.Since(start).Seconds())
+}
+
+// ExportHandler - возвращает http-обработчик, который отдает метрики в текстовом формате Prometheus.
+func (p *Prometheus) ExportHandler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRepo - возвращает repo.ObserveFunc, которая учитывает длительность операций репозитория backend.
+func (p *Prometheus) ObserveRepo(backend string) repo.ObserveFunc {
+	return func(ctx context.Context, op string) (context.Context, func(error)) {
+		start := time.Now()
+		return ctx, func(err error) {
+			result := "ok"
+			if err != nil {
+				result = "error"
+			}
+			p.repoDuration.WithLabelValues(backend, op, result).Observe(time.Since(start).Seconds())
+		}
+	}
+}
+
+// Redirect - учитывает попытку перехода по короткой ссылке: успешную (hit) или нет (miss)
+func (p *Prometheus) Redirect(hit bool) {
+	if hit {
+		p.redirects.WithLabelValues("hit").Inc()
+	} else {
+		p.redirects.WithLabelValues("miss").Inc()
+	}
+}
+
+// DeleteQueue - изменяет количество ссылок в очереди на удаление на delta
+func (p *Prometheus) DeleteQueue(delta int) {
+	p.deleteQueue.Add(float64(delta))
+}
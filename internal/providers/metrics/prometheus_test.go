@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+type prometheusSuite struct {
+	suite.Suite
+	p *Prometheus
+}
+
+func TestPrometheusSuite(t *testing.T) {
+	suite.Run(t, new(prometheusSuite))
+}
+
+func (suite *prometheusSuite) SetupTest() {
+	suite.p = NewPrometheus()
+}
+
+func (suite *prometheusSuite) TestHandler() {
+	r := chi.NewRouter()
+	r.Use(suite.p.Handler)
+	r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	r.Mount("/api", func() chi.Router {
+		sub := chi.NewRouter()
+		sub.Get("/ping", func(w http.ResponseWriter, r *http.Request) {})
+		return sub
+	}())
+	for _, path := range []string{"/items/1", "/items/2", "/api/ping", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	// Нестандартные методы учитываются под одним значением метки
+	for _, method := range []string{"FOO", "BAR"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/unknown", nil))
+	}
+
+	body := suite.scrape()
+	suite.Contains(body, `shortener_http_requests_total{code="418",method="GET",route="/items/{id}"} 2`)
+	suite.Contains(body, `shortener_http_requests_total{code="200",method="GET",route="/api/ping"} 1`)
+	suite.Contains(body, `shortener_http_requests_total{code="404",method="GET",route="unmatched"} 1`)
+	suite.Contains(body, `shortener_http_requests_total{code="405",method="other",route="unmatched"} 2`)
+	suite.NotContains(body, `method="FOO"`)
+	suite.Contains(body, `shortener_http_request_duration_seconds_count{code="418",method="GET",route="/items/{id}"} 2`)
+}
+
+func (suite *prometheusSuite) TestInterceptor() {
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.ShortURL/Create"}
+	_, err := suite.p.Interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, nil
+	})
+	suite.NoError(err)
+	_, err = suite.p.Interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, status.Error(codes.InvalidArgument, "invalid")
+	})
+	suite.Error(err)
+
+	body := suite.scrape()
+	suite.Contains(body, `shortener_grpc_requests_total{code="OK",method="/proto.ShortURL/Create"} 1`)
+	suite.Contains(body, `shortener_grpc_requests_total{code="InvalidArgument",method="/proto.ShortURL/Create"} 1`)
+	suite.Contains(body, `shortener_grpc_request_duration_seconds_count{code="OK",method="/proto.ShortURL/Create"} 1`)
+}
+
+func (suite *prometheusSuite) TestStreamInterceptor() {
+	info := &grpc.StreamServerInfo{FullMethod: "/proto.ShortURL/ExportClicks", IsServerStream: true}
+	err := suite.p.StreamInterceptor(nil, nil, info, func(interface{}, grpc.ServerStream) error {
+		return nil
+	})
+	suite.NoError(err)
+	err = suite.p.StreamInterceptor(nil, nil, info, func(interface{}, grpc.ServerStream) error {
+		return status.Error(codes.PermissionDenied, "denied")
+	})
+	suite.Error(err)
+
+	body := suite.scrape()
+	suite.Contains(body, `shortener_grpc_requests_total{code="OK",method="/proto.ShortURL/ExportClicks"} 1`)
+	suite.Contains(body, `shortener_grpc_requests_total{code="PermissionDenied",method="/proto.ShortURL/ExportClicks"} 1`)
+	suite.Contains(body, `shortener_grpc_request_duration_seconds_count{code="OK",method="/proto.ShortURL/ExportClicks"} 1`)
+}
+
+func (suite *prometheusSuite) TestObserveRepo() {
+	memoryRepo := repo.NewMemoryRepo()
+	r := repo.NewObservedRepo(memoryRepo, suite.p.ObserveRepo(repo.BackendName(memoryRepo)))
+	suite.Equal("memory", repo.BackendName(r))
+	suite.NoError(r.UserCreate(context.Background(), &models.User{}))
+	_, err := r.ShortURLGetByID(context.Background(), "unknown")
+	suite.True(errors.Is(err, repo.ErrNotFound))
+
+	body := suite.scrape()
+	suite.Contains(body, `shortener_repo_operation_duration_seconds_count{backend="memory",operation="UserCreate",result="ok"} 1`)
+	suite.Contains(body, `shortener_repo_operation_duration_seconds_count{backend="memory",operation="ShortURLGetByID",result="error"} 1`)
+}
+
+func (suite *prometheusSuite) TestRecorder() {
+	suite.p.Redirect(true)
+	suite.p.Redirect(true)
+	suite.p.Redirect(false)
+	suite.p.DeleteQueue(5)
+	suite.p.DeleteQueue(-2)
+
+	body := suite.scrape()
+	suite.Contains(body, `shortener_redirects_total{result="hit"} 2`)
+	suite.Contains(body, `shortener_redirects_total{result="miss"} 1`)
+	suite.Contains(body, `shortener_delete_queue_depth 3`)
+	// Метрики среды выполнения Go
+	suite.Contains(body, `go_goroutines`)
+}
+
+// scrape - возвращает метрики в текстовом формате Prometheus
+func (suite *prometheusSuite) scrape() string {
+	w := httptest.NewRecorder()
+	suite.p.ExportHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	suite.Require().Equal(http.StatusOK, w.Code)
+	body, err := io.ReadAll(w.Body)
+	suite.Require().NoError(err)
+	return string(body)
+}
@@ -0,0 +1,89 @@
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+)
+
+// CallbackPath - путь возврата от провайдера относительно BaseURL, если OIDCRedirectURL не задан
+const CallbackPath = "api/auth/oidc/callback"
+
+// Client - реализация Provider: клиент провайдера OpenID Connect.
+// Адреса провайдера и ключи проверки ID-токенов получаются из документа
+// <OIDCIssuer>/.well-known/openid-configuration.
+//
+// Код авторизации защищен PKCE (RFC 7636) методом S256,
+// ID-токен проверяется по подписи, издателю, получателю (OIDCClientID), времени действия и nonce.
+type Client struct {
+	oauth    oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// NewClient - конструктор Client. Загружает настройки провайдера cfg.OIDCIssuer.
+// Контекст ctx используется также для загрузки ключей провайдера в дальнейшем.
+func NewClient(ctx context.Context, cfg *config.Config) (*Client, error) {
+	provider, err := gooidc.NewProvider(ctx, cfg.OIDCIssuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider: %w", err)
+	}
+	redirectURL := cfg.OIDCRedirectURL
+	if redirectURL == "" {
+		u := cfg.BaseURL
+		u.Path += CallbackPath
+		redirectURL = u.String()
+	}
+	return &Client{
+		oauth: oauth2.Config{
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  redirectURL,
+			Scopes:       []string{gooidc.ScopeOpenID},
+		},
+		verifier: provider.Verifier(&gooidc.Config{ClientID: cfg.OIDCClientID}),
+	}, nil
+}
+
+// Begin - создает параметры входа и возвращает адрес страницы входа у провайдера.
+func (c *Client) Begin() (string, *Flow, error) {
+	flow, err := newFlow()
+	if err != nil {
+		return "", nil, err
+	}
+	authURL := c.oauth.AuthCodeURL(flow.State,
+		gooidc.Nonce(flow.Nonce),
+		oauth2.SetAuthURLParam("code_challenge", flow.Challenge()),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+	return authURL, flow, nil
+}
+
+// Finish - обменивает код авторизации на ID-токен и проверяет его.
+// Если провайдер не подтвердил вход или токен не прошел проверку, возвращает ErrLoginFailed.
+func (c *Client) Finish(ctx context.Context, flow *Flow, code string) (*Identity, error) {
+	if flow == nil || code == "" {
+		return nil, ErrInvalidFlow
+	}
+	token, err := c.oauth.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", flow.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLoginFailed, err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: no id_token in response", ErrLoginFailed)
+	}
+	idToken, err := c.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLoginFailed, err)
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(flow.Nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrLoginFailed)
+	}
+	return &Identity{Issuer: idToken.Issuer, Subject: idToken.Subject}, nil
+}
@@ -0,0 +1,125 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/providers/oidc/oidctest"
+)
+
+type clientSuite struct {
+	suite.Suite
+	idp    *oidctest.Server
+	client *Client
+}
+
+func TestClientSuite(t *testing.T) {
+	suite.Run(t, new(clientSuite))
+}
+
+func (suite *clientSuite) SetupTest() {
+	suite.idp = oidctest.NewServer("shortener", "secret")
+	cfg := &config.Config{
+		BaseURL:          url.URL{Scheme: "https", Host: "short.example.com", Path: "/"},
+		OIDCIssuer:       suite.idp.URL,
+		OIDCClientID:     "shortener",
+		OIDCClientSecret: "secret",
+	}
+	var err error
+	suite.client, err = NewClient(context.Background(), cfg)
+	suite.Require().NoError(err)
+}
+
+func (suite *clientSuite) TearDownTest() {
+	suite.idp.Close()
+}
+
+func (suite *clientSuite) TestBegin() {
+	authURL, flow, err := suite.client.Begin()
+	suite.Require().NoError(err)
+	u, err := url.Parse(authURL)
+	suite.Require().NoError(err)
+	q := u.Query()
+	suite.Equal(suite.idp.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	suite.Equal("https://short.example.com/"+CallbackPath, q.Get("redirect_uri"))
+	suite.Equal(flow.State, q.Get("state"))
+	suite.Equal(flow.Nonce, q.Get("nonce"))
+	suite.Equal(flow.Challenge(), q.Get("code_challenge"))
+	suite.Equal("S256", q.Get("code_challenge_method"))
+	suite.Equal("openid", q.Get("scope"))
+
+	// Параметры входа случайные и восстанавливаются из строки
+	_, other, err := suite.client.Begin()
+	suite.Require().NoError(err)
+	suite.NotEqual(flow.State, other.State)
+	parsed, err := ParseFlow(flow.String())
+	suite.NoError(err)
+	suite.Equal(flow, parsed)
+}
+
+func (suite *clientSuite) TestFinish() {
+	ctx := context.Background()
+
+	suite.Run("success", func() {
+		suite.idp.SetSubject("bob")
+		flow, code := suite.authorize()
+		identity, err := suite.client.Finish(ctx, flow, code)
+		suite.Require().NoError(err)
+		suite.Equal(&Identity{Issuer: suite.idp.URL, Subject: "bob"}, identity)
+
+		// Код авторизации одноразовый
+		_, err = suite.client.Finish(ctx, flow, code)
+		suite.ErrorIs(err, ErrLoginFailed)
+	})
+
+	suite.Run("wrong verifier", func() {
+		flow, code := suite.authorize()
+		flow.Verifier = "wrong"
+		_, err := suite.client.Finish(ctx, flow, code)
+		suite.ErrorIs(err, ErrLoginFailed)
+	})
+
+	suite.Run("wrong nonce", func() {
+		flow, code := suite.authorize()
+		flow.Nonce = "wrong"
+		_, err := suite.client.Finish(ctx, flow, code)
+		suite.ErrorIs(err, ErrLoginFailed)
+	})
+
+	suite.Run("invalid flow", func() {
+		_, err := suite.client.Finish(ctx, nil, "code")
+		suite.ErrorIs(err, ErrInvalidFlow)
+		_, err = ParseFlow("state.nonce")
+		suite.ErrorIs(err, ErrInvalidFlow)
+	})
+}
+
+func (suite *clientSuite) TestNewClient_invalidIssuer() {
+	_, err := NewClient(context.Background(), &config.Config{OIDCIssuer: suite.idp.URL + "/other"})
+	suite.Error(err)
+}
+
+// authorize - начинает вход и проходит страницу входа тестового провайдера.
+// Возвращает параметры входа и код авторизации из адреса возврата.
+func (suite *clientSuite) authorize() (*Flow, string) {
+	authURL, flow, err := suite.client.Begin()
+	suite.Require().NoError(err)
+	c := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	res, err := c.Get(authURL)
+	suite.Require().NoError(err)
+	suite.Require().NoError(res.Body.Close())
+	suite.Require().Equal(http.StatusFound, res.StatusCode)
+	location, err := res.Location()
+	suite.Require().NoError(err)
+	suite.Require().Equal(flow.State, location.Query().Get("state"))
+	return flow, location.Query().Get("code")
+}
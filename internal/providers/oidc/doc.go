@@ -0,0 +1,2 @@
+// Package oidc реализует провайдер входа пользователей через OpenID Connect.
+package oidc
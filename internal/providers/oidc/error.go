@@ -0,0 +1,9 @@
+package oidc
+
+import "errors"
+
+// ErrInvalidFlow - параметры входа не заданы или повреждены
+var ErrInvalidFlow = errors.New("invalid oidc flow")
+
+// ErrLoginFailed - провайдер не подтвердил вход пользователя
+var ErrLoginFailed = errors.New("oidc login failed")
@@ -0,0 +1,57 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// flowParamLen - длина случайных параметров входа в байтах
+const flowParamLen = 32
+
+// Flow - параметры входа, которые сохраняются у клиента до возврата от провайдера.
+type Flow struct {
+	State    string // Защита от CSRF: должен совпасть с параметром state при возврате
+	Nonce    string // Защита от повторного использования ID-токена: должен совпасть с nonce в токене
+	Verifier string // Секрет PKCE: передается провайдеру при обмене кода авторизации на токен
+}
+
+// Identity - пользователь провайдера OpenID Connect
+type Identity struct {
+	Issuer  string // Издатель ID-токена (iss)
+	Subject string // Идентификатор пользователя у издателя (sub)
+}
+
+// newFlow - создает параметры входа со случайными значениями
+func newFlow() (*Flow, error) {
+	var params [3]string
+	for i := range params {
+		b := make([]byte, flowParamLen)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		params[i] = base64.RawURLEncoding.EncodeToString(b)
+	}
+	return &Flow{State: params[0], Nonce: params[1], Verifier: params[2]}, nil
+}
+
+// ParseFlow - разбирает параметры входа, сохраненные методом Flow.String.
+func ParseFlow(s string) (*Flow, error) {
+	params := strings.Split(s, ".")
+	if len(params) != 3 || params[0] == "" || params[1] == "" || params[2] == "" {
+		return nil, ErrInvalidFlow
+	}
+	return &Flow{State: params[0], Nonce: params[1], Verifier: params[2]}, nil
+}
+
+// String - возвращает параметры входа в виде строки для сохранения у клиента
+func (f *Flow) String() string {
+	return f.State + "." + f.Nonce + "." + f.Verifier
+}
+
+// Challenge - возвращает код PKCE для метода S256 (RFC 7636)
+func (f *Flow) Challenge() string {
+	sum := sha256.Sum256([]byte(f.Verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,14 @@
+package oidc
+
+import "context"
+
+// Provider - провайдер входа пользователей через OpenID Connect
+// по схеме authorization code с PKCE.
+type Provider interface {
+	// Begin - начинает вход: возвращает адрес страницы входа у провайдера
+	// и параметры входа, которые нужно сохранить у клиента до возврата от провайдера.
+	Begin() (string, *Flow, error)
+	// Finish - завершает вход: обменивает код авторизации на ID-токен, проверяет его
+	// и возвращает пользователя провайдера.
+	Finish(ctx context.Context, flow *Flow, code string) (*Identity, error)
+}
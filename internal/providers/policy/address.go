@@ -0,0 +1,135 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SelfReference - политика, которая запрещает адреса назначения на хосте самого сервиса.
+// Такие ссылки создают цепочки перенаправлений и циклы.
+type SelfReference struct {
+	hosts []string
+}
+
+// NewSelfReference - конструктор SelfReference.
+// baseURLs - адреса, по которым доступен сервис.
+func NewSelfReference(baseURLs ...url.URL) *SelfReference {
+	hosts := make([]string, 0, len(baseURLs))
+	for _, u := range baseURLs {
+		hosts = append(hosts, strings.ToLower(u.Hostname()))
+	}
+	return &SelfReference{hosts: hosts}
+}
+
+// Check - проверяет, что адрес назначения не ведет на хост сервиса.
+func (p *SelfReference) Check(_ context.Context, u *url.URL) error {
+	host := strings.ToLower(u.Hostname())
+	for _, h := range p.hosts {
+		if host == h {
+			return fmt.Errorf("destination %s points to the shortener itself", host)
+		}
+	}
+	return nil
+}
+
+// PrivateAddress - политика, которая запрещает адреса назначения в локальных и частных сетях:
+// localhost, loopback, private, link-local и неопределенные IP-адреса.
+// Проверяются только IP-адреса, указанные в URL явно: доменные имена не разрешаются.
+// IPv4-адреса распознаются так же, как в браузерах: в том числе в десятичной, восьмеричной,
+// шестнадцатеричной и сокращенной записи (например, 2130706433 или 127.1).
+type PrivateAddress struct{}
+
+// NewPrivateAddress - конструктор PrivateAddress.
+func NewPrivateAddress() *PrivateAddress {
+	return &PrivateAddress{}
+}
+
+// Check - проверяет, что адрес назначения не находится в локальной или частной сети.
+func (p *PrivateAddress) Check(_ context.Context, u *url.URL) error {
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("destination %s is a local address", host)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil && endsInNumber(host) {
+		// Браузеры считают такой хост IPv4-адресом
+		if ip = parseIPv4(host); ip == nil {
+			return fmt.Errorf("destination %s is an invalid IPv4 address", host)
+		}
+	}
+	if ip == nil {
+		return nil
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("destination %s is a private or loopback address", host)
+	}
+	return nil
+}
+
+// endsInNumber - возвращает true, если последняя метка хоста является числом.
+// Такой хост браузеры разбирают как IPv4-адрес (см. WHATWG URL Standard).
+func endsInNumber(host string) bool {
+	labels := strings.Split(strings.TrimSuffix(host, "."), ".")
+	last := labels[len(labels)-1]
+	if last == "" {
+		return false
+	}
+	if strings.Trim(last, "0123456789") == "" {
+		return true
+	}
+	_, err := parseIPv4Number(last)
+	return err == nil
+}
+
+// parseIPv4 - разбирает IPv4-адрес так же, как браузеры: от одной до четырех частей,
+// каждая часть - десятичное, восьмеричное (с префиксом 0) или шестнадцатеричное (с префиксом 0x) число.
+// Последняя часть задает все оставшиеся байты адреса.
+// Возвращает nil, если хост не является IPv4-адресом.
+func parseIPv4(host string) net.IP {
+	parts := strings.Split(strings.TrimSuffix(host, "."), ".")
+	if len(parts) > 4 {
+		return nil
+	}
+	var addr uint64
+	for i, part := range parts {
+		n, err := parseIPv4Number(part)
+		if err != nil {
+			return nil
+		}
+		if i < len(parts)-1 {
+			if n > 255 {
+				return nil
+			}
+			addr |= n << (8 * (3 - i))
+			continue
+		}
+		// Последняя часть занимает 5-len(parts) байт
+		if n >= 1<<(8*(5-len(parts))) {
+			return nil
+		}
+		addr |= n
+	}
+	return net.IPv4(byte(addr>>24), byte(addr>>16), byte(addr>>8), byte(addr))
+}
+
+// parseIPv4Number - разбирает часть IPv4-адреса: десятичное, восьмеричное или шестнадцатеричное число
+func parseIPv4Number(s string) (uint64, error) {
+	base := 10
+	switch {
+	case s == "":
+		return 0, strconv.ErrSyntax
+	case len(s) >= 2 && (s[:2] == "0x" || s[:2] == "0X"):
+		base, s = 16, s[2:]
+		if s == "" {
+			return 0, nil
+		}
+	case len(s) >= 2 && s[0] == '0':
+		base, s = 8, s[1:]
+	}
+	return strconv.ParseUint(s, base, 32)
+}
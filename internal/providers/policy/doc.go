@@ -0,0 +1,2 @@
+// Package policy - политики проверки адресов назначения коротких ссылок
+package policy
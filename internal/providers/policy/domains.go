@@ -0,0 +1,119 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/idna"
+)
+
+// DomainList - политика, которая проверяет домен адреса назначения по спискам разрешенных и запрещенных доменов.
+// Домен в списке охватывает также все свои поддомены.
+//   - Если домен адреса есть в списке запрещенных, то адрес не допускается.
+//   - Если список разрешенных не пуст, то допускаются только адреса из этого списка.
+//
+// Списки загружаются из JSON-файла и могут быть перезагружены без перезапуска сервиса (см. Reload).
+// Формат файла:
+//
+//	{
+//		"allow": ["example.com", "example.org"],
+//		"deny": ["evil.example.com"]
+//	}
+type DomainList struct {
+	filePath string
+	allow    []string
+	deny     []string
+	mu       sync.RWMutex
+}
+
+// domainListDTO - структура для считывания списков доменов из JSON-файла.
+type domainListDTO struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// NewDomainList - конструктор DomainList, загружает списки доменов из файла filePath.
+func NewDomainList(filePath string) (*DomainList, error) {
+	p := &DomainList{filePath: filePath}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload - перезагружает списки доменов из файла.
+// При ошибке загрузки остаются действовать предыдущие списки.
+func (p *DomainList) Reload() error {
+	f, err := os.Open(p.filePath)
+	if err != nil {
+		return err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer f.Close()
+
+	d := json.NewDecoder(f)
+	d.DisallowUnknownFields() // запрещаем неизвестные поля, чтобы предотвратить опечатки
+	dto := &domainListDTO{}
+	if err = d.Decode(dto); err != nil {
+		return fmt.Errorf("failed to parse domain list %s: %w", p.filePath, err)
+	}
+	allow, err := normalizeDomains(dto.Allow)
+	if err != nil {
+		return err
+	}
+	deny, err := normalizeDomains(dto.Deny)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.allow, p.deny = allow, deny
+	return nil
+}
+
+// Check - проверяет домен адреса назначения по спискам разрешенных и запрещенных доменов.
+func (p *DomainList) Check(_ context.Context, u *url.URL) error {
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if matchDomain(p.deny, host) {
+		return fmt.Errorf("domain %s is blocked", host)
+	}
+	if len(p.allow) > 0 && !matchDomain(p.allow, host) {
+		return fmt.Errorf("domain %s is not allowed", host)
+	}
+	return nil
+}
+
+// matchDomain - проверяет, совпадает ли host с одним из доменов или является его поддоменом
+func matchDomain(domains []string, host string) bool {
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDomains - приводит домены к нижнему регистру и punycode
+func normalizeDomains(domains []string) ([]string, error) {
+	result := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d = strings.TrimSuffix(strings.TrimSpace(d), ".")
+		if d == "" {
+			continue
+		}
+		ascii, err := idna.Lookup.ToASCII(d)
+		if err != nil {
+			return nil, fmt.Errorf("invalid domain %q: %w", d, err)
+		}
+		result = append(result, ascii)
+	}
+	return result, nil
+}
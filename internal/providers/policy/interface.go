@@ -0,0 +1,27 @@
+package policy
+
+import (
+	"context"
+	"net/url"
+)
+
+// Policy - политика проверки адреса назначения короткой ссылки.
+type Policy interface {
+	// Check - проверяет адрес назначения u.
+	// Если адрес не допускается, возвращает ошибку с описанием причины.
+	Check(ctx context.Context, u *url.URL) error
+}
+
+// Chain - цепочка политик. Адрес допускается, если его допускают все политики цепочки.
+type Chain []Policy
+
+// Check - проверяет адрес назначения всеми политиками цепочки по порядку.
+// Возвращает ошибку первой политики, которая не допустила адрес.
+func (c Chain) Check(ctx context.Context, u *url.URL) error {
+	for _, p := range c {
+		if err := p.Check(ctx, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
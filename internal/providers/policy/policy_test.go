@@ -0,0 +1,111 @@
+package policy
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type policySuite struct {
+	suite.Suite
+}
+
+func TestPolicySuite(t *testing.T) {
+	suite.Run(t, new(policySuite))
+}
+
+func (suite *policySuite) TestSelfReference() {
+	p := NewSelfReference(*suite.parse("https://sho.rt/"))
+	suite.NoError(p.Check(context.Background(), suite.parse("https://example.com/")))
+	suite.NoError(p.Check(context.Background(), suite.parse("https://sub.sho.rt/")))
+	suite.Error(p.Check(context.Background(), suite.parse("https://sho.rt/abc")))
+	suite.Error(p.Check(context.Background(), suite.parse("http://SHO.RT:8080/abc")))
+}
+
+func (suite *policySuite) TestPrivateAddress() {
+	p := NewPrivateAddress()
+	allowed := []string{
+		"https://example.com/", "http://8.8.8.8/", "http://[2001:4860:4860::8888]/", "http://localhost.example.com/",
+		"http://134744072/", "http://0x8.0x8.0x8.0x8/", "http://example.123abc/", "http://1.2.3.4.example.com/",
+	}
+	for _, rawURL := range allowed {
+		suite.NoError(p.Check(context.Background(), suite.parse(rawURL)), rawURL)
+	}
+	rejected := []string{
+		"http://localhost/", "http://localhost.:8080/", "http://app.localhost/",
+		"http://127.0.0.1/", "http://10.0.0.1/", "http://172.16.0.1/", "http://192.168.0.1/",
+		"http://169.254.169.254/", "http://0.0.0.0/", "http://[::1]/", "http://[fe80::1]/", "http://[fd00::1]/",
+		// Нестандартная запись IPv4-адресов, которую браузеры разбирают как IP
+		"http://2130706433/", "http://127.1/", "http://0177.0.0.1/", "http://0x7f.1/", "http://0x7f000001/",
+		"http://10.1.1/", "http://127.000.000.001/", "http://0/", "http://[::ffff:127.0.0.1]/",
+		// Числовые хосты, которые не являются корректными IPv4-адресами
+		"http://256.1.1.1/", "http://1.2.3.4.5/", "http://4294967296/", "http://08.1.1.1/", "http://example.0x/",
+	}
+	for _, rawURL := range rejected {
+		suite.Error(p.Check(context.Background(), suite.parse(rawURL)), rawURL)
+	}
+}
+
+func (suite *policySuite) TestDomainList() {
+	suite.Run("allow and deny", func() {
+		p, err := NewDomainList("testdata/domains.json")
+		suite.Require().NoError(err)
+		allowed := []string{"https://example.com/", "https://www.EXAMPLE.com/", "https://xn--e1afmkfd.xn--p1ai/"}
+		for _, rawURL := range allowed {
+			suite.NoError(p.Check(context.Background(), suite.parse(rawURL)), rawURL)
+		}
+		rejected := []string{"https://example.org/", "https://notexample.com/", "https://evil.example.com/", "https://a.evil.example.com/"}
+		for _, rawURL := range rejected {
+			suite.Error(p.Check(context.Background(), suite.parse(rawURL)), rawURL)
+		}
+	})
+
+	suite.Run("deny only", func() {
+		p, err := NewDomainList("testdata/deny.json")
+		suite.Require().NoError(err)
+		suite.NoError(p.Check(context.Background(), suite.parse("https://example.org/")))
+		suite.Error(p.Check(context.Background(), suite.parse("https://blocked.com/")))
+		suite.Error(p.Check(context.Background(), suite.parse("https://www.blocked.com/")))
+	})
+
+	suite.Run("reload", func() {
+		fileName := filepath.Join(suite.T().TempDir(), "policy.json")
+		suite.Require().NoError(os.WriteFile(fileName, []byte(`{"deny": ["example.com"]}`), 0o600))
+		p, err := NewDomainList(fileName)
+		suite.Require().NoError(err)
+		suite.Error(p.Check(context.Background(), suite.parse("https://example.com/")))
+
+		suite.Require().NoError(os.WriteFile(fileName, []byte(`{"deny": ["example.org"]}`), 0o600))
+		suite.NoError(p.Reload())
+		suite.NoError(p.Check(context.Background(), suite.parse("https://example.com/")))
+		suite.Error(p.Check(context.Background(), suite.parse("https://example.org/")))
+
+		// При ошибке загрузки продолжают действовать прежние списки
+		suite.Require().NoError(os.WriteFile(fileName, []byte(`{"unknown": []}`), 0o600))
+		suite.Error(p.Reload())
+		suite.Error(p.Check(context.Background(), suite.parse("https://example.org/")))
+	})
+
+	suite.Run("invalid file", func() {
+		_, err := NewDomainList("testdata/not-exists.json")
+		suite.Error(err)
+	})
+}
+
+func (suite *policySuite) TestChain() {
+	c := Chain{NewSelfReference(*suite.parse("https://sho.rt/")), NewPrivateAddress()}
+	suite.NoError(c.Check(context.Background(), suite.parse("https://example.com/")))
+	suite.Error(c.Check(context.Background(), suite.parse("https://sho.rt/")))
+	suite.Error(c.Check(context.Background(), suite.parse("http://127.0.0.1/")))
+	suite.NoError(Chain{}.Check(context.Background(), suite.parse("http://127.0.0.1/")))
+}
+
+func (suite *policySuite) parse(rawURL string) *url.URL {
+	u, err := url.Parse(rawURL)
+	suite.Require().NoError(err)
+	return u
+}
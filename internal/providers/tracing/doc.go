@@ -0,0 +1,2 @@
+// Package tracing - провайдер трассировки OpenTelemetry с распространением контекста W3C Trace Context
+package tracing
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+)
+
+// NewExporter - создает экспортер трассировки согласно конфигурации.
+// Возвращает nil, если экспортер не задан.
+func NewExporter(ctx context.Context, cfg *config.Config) (sdktrace.SpanExporter, error) {
+	switch cfg.TraceExporter {
+	case "", config.TraceExporterNone:
+		return nil, nil
+	case config.TraceExporterStdout:
+		return NewWriterExporter(os.Stdout)
+	case config.TraceExporterOTLP:
+		return otlptracehttp.New(ctx)
+	}
+	if path, ok := cfg.TraceFile(); ok {
+		return NewFileExporter(path)
+	}
+	return nil, fmt.Errorf("unknown trace exporter: %s", cfg.TraceExporter)
+}
+
+// NewWriterExporter - создает экспортер, который записывает span в w в формате JSON.
+func NewWriterExporter(w io.Writer) (sdktrace.SpanExporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(w))
+}
+
+// NewFileExporter - создает экспортер, который дописывает span в файл path в формате JSON.
+// Файл закрывается при остановке экспортера.
+func NewFileExporter(path string) (sdktrace.SpanExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := NewWriterExporter(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &fileExporter{SpanExporter: exporter, f: f}, nil
+}
+
+// fileExporter - экспортер в файл
+type fileExporter struct {
+	sdktrace.SpanExporter
+	f *os.File
+}
+
+// Shutdown - останавливает экспортер и закрывает файл
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+	if closeErr := e.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
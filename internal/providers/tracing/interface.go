@@ -0,0 +1,25 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+// Provider - провайдер трассировки.
+// Реализует middleware для http и grpc.
+type Provider interface {
+	// Handler - http.HandlerFunc: продолжает трассировку из заголовков запроса и создает span запроса
+	Handler(next http.Handler) http.Handler
+	// Interceptor - grpc.UnaryServerInterceptor: продолжает трассировку из метаданных запроса и создает span запроса
+	Interceptor(context.Context, interface{}, *grpc.UnaryServerInfo, grpc.UnaryHandler) (interface{}, error)
+	// StreamInterceptor - grpc.StreamServerInterceptor: то же, что Interceptor, для потоковых методов
+	StreamInterceptor(interface{}, grpc.ServerStream, *grpc.StreamServerInfo, grpc.StreamHandler) error
+	// ObserveRepo - возвращает repo.ObserveFunc, которая создает span для каждой операции репозитория backend
+	ObserveRepo(backend string) repo.ObserveFunc
+	// Shutdown - отправляет накопленные span и останавливает экспортер
+	Shutdown(ctx context.Context) error
+}
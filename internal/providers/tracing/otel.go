@@ -0,0 +1,181 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+// serviceName - имя сервиса в ресурсе трассировки
+const serviceName = "shortener"
+
+// instrumentationName - имя инструментирующей библиотеки
+const instrumentationName = "github.com/ofstudio/go-shortener/internal/providers/tracing"
+
+// OTel - реализация Provider на основе OpenTelemetry SDK.
+// Распространяет контекст трассировки в формате W3C Trace Context и W3C Baggage.
+type OTel struct {
+	tp         *sdktrace.TracerProvider
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewOTel - конструктор OTel.
+// Если exporter равен nil, span создаются и распространяются, но никуда не отправляются.
+// Провайдер трассировки и пропагатор устанавливаются глобальными,
+// чтобы span юзкейсов и репозитория попадали в ту же трассировку.
+func NewOTel(exporter sdktrace.SpanExporter) *OTel {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String(serviceName))),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	o := &OTel{
+		tp:         sdktrace.NewTracerProvider(opts...),
+		propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+	o.tracer = o.tp.Tracer(instrumentationName)
+	otel.SetTracerProvider(o.tp)
+	otel.SetTextMapPropagator(o.propagator)
+	return o
+}
+
+// Handler - http middleware, которая продолжает трассировку из заголовков traceparent и tracestate
+// и создает серверный span запроса.
+// Имя span определяется по шаблону маршрута chi, поэтому middleware должна быть подключена к корневому роутеру.
+func (o *OTel) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := o.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := o.tracer.Start(ctx, "HTTP "+r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPMethodKey.String(r.Method), semconv.HTTPTargetKey.String(r.URL.Path)),
+		)
+		defer span.End()
+
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetName(r.Method + " " + rctx.RoutePattern())
+			span.SetAttributes(semconv.HTTPRouteKey.String(rctx.RoutePattern()))
+		}
+		code := ww.Status()
+		if code == 0 {
+			code = http.StatusOK
+		}
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(code))
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(code, trace.SpanKindServer))
+	})
+}
+
+// Interceptor - grpc.UnaryServerInterceptor, который продолжает трассировку из метаданных запроса
+// и создает серверный span запроса.
+func (o *OTel) Interceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := o.grpcSpan(ctx, info.FullMethod)
+	defer span.End()
+
+	resp, err := handler(ctx, req)
+	setGRPCStatus(span, err)
+	return resp, err
+}
+
+// StreamInterceptor - grpc.StreamServerInterceptor, который продолжает трассировку из метаданных потока
+// и создает серверный span на все время работы потока.
+func (o *OTel) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := o.grpcSpan(ss.Context(), info.FullMethod)
+	defer span.End()
+
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	setGRPCStatus(span, err)
+	return err
+}
+
+// grpcSpan - продолжает трассировку из метаданных запроса и создает серверный span метода method
+func (o *OTel) grpcSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = o.propagator.Extract(ctx, metadataCarrier(md))
+	return o.tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.RPCSystemKey.String("grpc")),
+	)
+}
+
+// setGRPCStatus - записывает в span код ответа gRPC и статус ошибки
+func setGRPCStatus(span trace.Span, err error) {
+	s, _ := status.FromError(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int64(int64(s.Code())))
+	if err != nil {
+		span.SetStatus(codes.Error, s.Message())
+	}
+}
+
+// ObserveRepo - возвращает repo.ObserveFunc, которая создает span для каждой операции репозитория backend.
+func (o *OTel) ObserveRepo(backend string) repo.ObserveFunc {
+	return func(ctx context.Context, op string) (context.Context, func(error)) {
+		ctx, span := o.tracer.Start(ctx, "repo."+op, trace.WithAttributes(attribute.String("repo.backend", backend)))
+		return ctx, func(err error) {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}
+	}
+}
+
+// Shutdown - отправляет накопленные span и останавливает экспортер.
+func (o *OTel) Shutdown(ctx context.Context) error {
+	return o.tp.Shutdown(ctx)
+}
+
+// metadataCarrier - реализация propagation.TextMapCarrier для метаданных gRPC
+type metadataCarrier metadata.MD
+
+// Get - возвращает первое значение ключа
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Set - устанавливает значение ключа
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys - возвращает список ключей
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// serverStream - grpc.ServerStream с контекстом, содержащим span запроса
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context - возвращает контекст потока
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
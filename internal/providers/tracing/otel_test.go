@@ -0,0 +1,150 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	traceparent = "00-" + traceID + "-00f067aa0ba902b7-01"
+)
+
+type otelSuite struct {
+	suite.Suite
+	exporter *tracetest.InMemoryExporter
+	o        *OTel
+}
+
+func TestOTelSuite(t *testing.T) {
+	suite.Run(t, new(otelSuite))
+}
+
+func (suite *otelSuite) SetupTest() {
+	suite.exporter = tracetest.NewInMemoryExporter()
+	suite.o = NewOTel(suite.exporter)
+}
+
+func (suite *otelSuite) TestHandler() {
+	r := chi.NewRouter()
+	r.Use(suite.o.Handler)
+	r.Get("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		// Span юзкейса, созданный через глобальный провайдер
+		_, span := otel.Tracer("test").Start(r.Context(), "usecase")
+		span.End()
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set("traceparent", traceparent)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := suite.spans()
+	suite.Require().Len(spans, 2)
+	suite.Equal("usecase", spans[0].Name)
+	suite.Equal("GET /items/{id}", spans[1].Name)
+	suite.Equal(codes.Error, spans[1].Status.Code)
+	// Трассировка продолжена из заголовка traceparent
+	suite.Equal(traceID, spans[1].SpanContext.TraceID().String())
+	suite.Equal("00f067aa0ba902b7", spans[1].Parent.SpanID().String())
+	suite.Equal(spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+}
+
+func (suite *otelSuite) TestInterceptor() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceparent))
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.ShortURL/Create"}
+	_, err := suite.o.Interceptor(ctx, nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, status.Error(grpccodes.InvalidArgument, "invalid")
+	})
+	suite.Error(err)
+
+	spans := suite.spans()
+	suite.Require().Len(spans, 1)
+	suite.Equal("/proto.ShortURL/Create", spans[0].Name)
+	suite.Equal(traceID, spans[0].SpanContext.TraceID().String())
+	suite.Equal(codes.Error, spans[0].Status.Code)
+	suite.Equal("invalid", spans[0].Status.Description)
+}
+
+func (suite *otelSuite) TestStreamInterceptor() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceparent))
+	info := &grpc.StreamServerInfo{FullMethod: "/proto.ShortURL/ExportClicks", IsServerStream: true}
+	err := suite.o.StreamInterceptor(nil, &serverStream{ctx: ctx}, info, func(_ interface{}, ss grpc.ServerStream) error {
+		// Контекст потока содержит span запроса
+		_, span := suite.o.tracer.Start(ss.Context(), "usecase")
+		span.End()
+		return status.Error(grpccodes.PermissionDenied, "denied")
+	})
+	suite.Error(err)
+
+	spans := suite.spans()
+	suite.Require().Len(spans, 2)
+	suite.Equal("usecase", spans[0].Name)
+	suite.Equal("/proto.ShortURL/ExportClicks", spans[1].Name)
+	suite.Equal(traceID, spans[1].SpanContext.TraceID().String())
+	suite.Equal(spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	suite.Equal(codes.Error, spans[1].Status.Code)
+	suite.Equal("denied", spans[1].Status.Description)
+}
+
+func (suite *otelSuite) TestObserveRepo() {
+	r := repo.NewObservedRepo(repo.NewMemoryRepo(), suite.o.ObserveRepo("memory"))
+	_, err := r.ShortURLGetByID(context.Background(), "unknown")
+	suite.ErrorIs(err, repo.ErrNotFound)
+	_, err = r.UserCount(context.Background())
+	suite.NoError(err)
+
+	spans := suite.spans()
+	suite.Require().Len(spans, 2)
+	suite.Equal("repo.ShortURLGetByID", spans[0].Name)
+	suite.Equal(codes.Error, spans[0].Status.Code)
+	suite.Equal("repo.UserCount", spans[1].Name)
+	suite.Equal(codes.Unset, spans[1].Status.Code)
+}
+
+func (suite *otelSuite) TestFileExporter() {
+	path := filepath.Join(suite.T().TempDir(), "trace.json")
+	exporter, err := NewExporter(context.Background(), &config.Config{TraceExporter: "file:" + path})
+	suite.Require().NoError(err)
+	o := NewOTel(exporter)
+	_, span := otel.Tracer("test").Start(context.Background(), "file-span")
+	span.End()
+	suite.NoError(o.Shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	suite.NoError(err)
+	suite.Contains(string(data), `"Name":"file-span"`)
+}
+
+func (suite *otelSuite) TestNewExporter() {
+	exporter, err := NewExporter(context.Background(), &config.Config{TraceExporter: config.TraceExporterNone})
+	suite.NoError(err)
+	suite.Nil(exporter)
+	exporter, err = NewExporter(context.Background(), &config.Config{TraceExporter: config.TraceExporterStdout})
+	suite.NoError(err)
+	suite.NotNil(exporter)
+	_, err = NewExporter(context.Background(), &config.Config{TraceExporter: "unknown"})
+	suite.Error(err)
+}
+
+// spans - отправляет накопленные span в экспортер и возвращает их
+func (suite *otelSuite) spans() tracetest.SpanStubs {
+	suite.Require().NoError(suite.o.tp.ForceFlush(context.Background()))
+	return suite.exporter.GetSpans()
+}
@@ -7,25 +7,59 @@ import (
 	"io"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/ofstudio/go-shortener/internal/models"
 )
 
 // aofRecord - структура одной JSON-записи для хранения в AOF-файле.
 type aofRecord struct {
-	UserCreate     *models.User     `json:"user_create,omitempty"`
-	ShortURLCreate *models.ShortURL `json:"short_url_create,omitempty"`
-	ShortURLDelete *models.ShortURL `json:"short_url_update,omitempty"`
+	UserCreate      *models.User          `json:"user_create,omitempty"`
+	UserCredentials *models.User          `json:"user_credentials,omitempty"`
+	UserOIDCSubject *models.User          `json:"user_oidc_subject,omitempty"`
+	UserTokens      *models.User          `json:"user_tokens,omitempty"`
+	UserPlan        *models.User          `json:"user_plan,omitempty"`
+	ShortURLCreate  *models.ShortURL      `json:"short_url_create,omitempty"`
+	ShortURLBatch   []*models.ShortURL    `json:"short_url_create_batch,omitempty"`
+	ShortURLDelete  *models.ShortURL      `json:"short_url_update,omitempty"`
+	ShortURLReplace *models.ShortURL      `json:"short_url_replace,omitempty"`
+	VariantClick    *aofVariantClick      `json:"variant_click,omitempty"`
+	VisitorSketch   *models.VisitorSketch `json:"visitor_sketch,omitempty"`
+	APIKeyCreate    *models.APIKey        `json:"api_key_create,omitempty"`
+	APIKeyRevoke    *models.APIKey        `json:"api_key_revoke,omitempty"`
+	TeamCreate      *aofTeam              `json:"team_create,omitempty"`
+	TeamMember      *models.TeamMember    `json:"team_member,omitempty"`
+	TeamMemberDel   *models.TeamMember    `json:"team_member_delete,omitempty"`
 }
 
+// aofTeam - запись о создании команды
+type aofTeam struct {
+	models.Team
+	OwnerID uint `json:"owner_id"`
+}
+
+// aofVariantClick - запись о переходах по варианту сплит-ссылки.
+// Count равный 0 означает один переход: так записывались переходы в прежних версиях файла.
+type aofVariantClick struct {
+	ID      string `json:"id"`
+	Variant int    `json:"variant"`
+	Count   int64  `json:"count,omitempty"`
+}
+
+// aofClicksSuffix - суффикс имени отдельного AOF-файла для событий переходов
+const aofClicksSuffix = ".clicks"
+
 // AOFRepo - реализация IRepo для хранения данных в append-only файле (AOF).
 // При создании репозитория производится загрузка данных из файла в память.
 // При чтении из репозитория используются данные из памяти.
 // При записи в репозиторий, данные сохраняются в память, а также записываются в файл в виде JSON-строк.
+// События переходов хранятся в отдельном файле с суффиксом ".clicks" по одной JSON-строке на событие.
 // После завершения работы необходимо закрывать репозиторий AOFRepo.Close.
 type AOFRepo struct {
-	aof     *os.File
-	encoder *json.Encoder
+	aof           *os.File
+	encoder       *json.Encoder
+	clicksAOF     *os.File
+	clicksEncoder *json.Encoder
 	*MemoryRepo
 	mu sync.Mutex
 }
@@ -37,12 +71,27 @@ func NewAOFRepo(filePath string) (*AOFRepo, error) {
 	if err != nil {
 		return nil, err
 	}
-	// Открываем файл для записи
+	if err = loadClicksFromFile(filePath+aofClicksSuffix, memoryRepo); err != nil {
+		return nil, err
+	}
+	// Открываем файлы для записи
 	aof, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return nil, ErrAOFOpen
 	}
-	return &AOFRepo{aof: aof, encoder: json.NewEncoder(aof), MemoryRepo: memoryRepo}, nil
+	clicksAOF, err := os.OpenFile(filePath+aofClicksSuffix, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		//goland:noinspection GoUnhandledErrorResult
+		aof.Close()
+		return nil, ErrAOFOpen
+	}
+	return &AOFRepo{
+		aof:           aof,
+		encoder:       json.NewEncoder(aof),
+		clicksAOF:     clicksAOF,
+		clicksEncoder: json.NewEncoder(clicksAOF),
+		MemoryRepo:    memoryRepo,
+	}, nil
 }
 
 // UserCreate - добавляет нового пользователя в репозиторий.
@@ -61,6 +110,79 @@ func (r *AOFRepo) UserCreate(ctx context.Context, user *models.User) error {
 	return nil
 }
 
+// UserSetCredentials - устанавливает email и хеш пароля пользователя.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) UserSetCredentials(ctx context.Context, id uint, email, passwordHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev, err := r.MemoryRepo.UserGetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = r.MemoryRepo.UserSetCredentials(ctx, id, email, passwordHash); err != nil {
+		return err
+	}
+	if err = r.encoder.Encode(aofRecord{UserCredentials: &models.User{ID: id, Email: email, PasswordHash: passwordHash}}); err != nil {
+		r.MemoryRepo.userRestoreCredentials(*prev)
+		return ErrAOFWrite
+	}
+	return nil
+}
+
+// UserSetOIDCSubject - устанавливает идентификатор пользователя у провайдера OpenID Connect.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) UserSetOIDCSubject(ctx context.Context, id uint, subject string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev, err := r.MemoryRepo.UserGetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = r.MemoryRepo.UserSetOIDCSubject(ctx, id, subject); err != nil {
+		return err
+	}
+	if err = r.encoder.Encode(aofRecord{UserOIDCSubject: &models.User{ID: id, OIDCSubject: subject}}); err != nil {
+		r.MemoryRepo.userRestoreOIDCSubject(*prev)
+		return ErrAOFWrite
+	}
+	return nil
+}
+
+// UserRevokeTokens - увеличивает поколение токенов пользователя и возвращает новое поколение.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) UserRevokeTokens(ctx context.Context, id uint) (uint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	generation, err := r.MemoryRepo.UserRevokeTokens(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if err = r.encoder.Encode(aofRecord{UserTokens: &models.User{ID: id, TokenGeneration: generation}}); err != nil {
+		r.MemoryRepo.userSetTokenGeneration(id, generation-1)
+		return 0, ErrAOFWrite
+	}
+	return generation, nil
+}
+
+// UserSetPlan - устанавливает тарифный план пользователя.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) UserSetPlan(ctx context.Context, id uint, plan string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev, err := r.MemoryRepo.UserGetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = r.MemoryRepo.UserSetPlan(ctx, id, plan); err != nil {
+		return err
+	}
+	if err = r.encoder.Encode(aofRecord{UserPlan: &models.User{ID: id, Plan: plan}}); err != nil {
+		r.MemoryRepo.userRestorePlan(*prev)
+		return ErrAOFWrite
+	}
+	return nil
+}
+
 // ShortURLCreate - создает новую короткую ссылку в репозитории.
 // Если короткая ссылка с таким id уже существует, возвращает ErrDuplicate.
 // При ошибке записи в файл, возвращает ErrAOFWrite.
@@ -77,6 +199,126 @@ func (r *AOFRepo) ShortURLCreate(ctx context.Context, shortURL *models.ShortURL)
 	return nil
 }
 
+// ShortURLCreateWithLimit - создает новые короткие ссылки пользователя, если не будут превышены ограничения limit.
+// Ссылки записываются в файл одной записью, чтобы при загрузке они восстанавливались вместе.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) ShortURLCreateWithLimit(ctx context.Context, userID uint, shortURLs []*models.ShortURL, limit Limit) error {
+	if len(shortURLs) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.MemoryRepo.ShortURLCreateWithLimit(ctx, userID, shortURLs, limit); err != nil {
+		return err
+	}
+	if err := r.encoder.Encode(aofRecord{ShortURLBatch: shortURLs}); err != nil {
+		for _, shortURL := range shortURLs {
+			r.MemoryRepo.shortURLPurge(shortURL.ID)
+		}
+		return ErrAOFWrite
+	}
+	return nil
+}
+
+// ShortURLUpdate - сохраняет изменяемые поля сокращенной ссылки пользователя.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) ShortURLUpdate(ctx context.Context, shortURL *models.ShortURL) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev, err := r.MemoryRepo.ShortURLGetByID(ctx, shortURL.ID)
+	if err != nil {
+		return err
+	}
+	if err = r.MemoryRepo.ShortURLUpdate(ctx, shortURL); err != nil {
+		return err
+	}
+	// Записываем в файл сохраненное состояние ссылки
+	updated, err := r.MemoryRepo.ShortURLGetByID(ctx, shortURL.ID)
+	if err != nil {
+		return err
+	}
+	if err = r.encoder.Encode(aofRecord{ShortURLReplace: updated}); err != nil {
+		r.MemoryRepo.shortURLReplace(prev)
+		return ErrAOFWrite
+	}
+	return nil
+}
+
+// ShortURLVariantClick - увеличивает счетчик переходов по варианту сплит-ссылки на n.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) ShortURLVariantClick(ctx context.Context, id string, variant int, n int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.MemoryRepo.ShortURLVariantClick(ctx, id, variant, n); err != nil {
+		return err
+	}
+	if err := r.encoder.Encode(aofRecord{VariantClick: &aofVariantClick{ID: id, Variant: variant, Count: n}}); err != nil {
+		r.MemoryRepo.variantUnclick(id, variant, n)
+		return ErrAOFWrite
+	}
+	return nil
+}
+
+// ClickAddBatch - сохраняет события переходов по сокращенным ссылкам.
+// При ошибке записи в файл, возвращает ErrAOFWrite: события, записанные в файл до ошибки, сохраняются в памяти.
+func (r *AOFRepo) ClickAddBatch(ctx context.Context, clicks []models.Click) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range clicks {
+		if err := r.clicksEncoder.Encode(clicks[i]); err != nil {
+			_ = r.MemoryRepo.ClickAddBatch(ctx, clicks[:i])
+			return ErrAOFWrite
+		}
+	}
+	return r.MemoryRepo.ClickAddBatch(ctx, clicks)
+}
+
+// VisitorSketchMerge - объединяет скетчи посетителей с сохраненными скетчами тех же ссылок за те же сутки.
+// Скетчи записываются в файл до объединения в памяти, тк объединение нельзя отменить.
+// При ошибке записи в файл, возвращает ErrAOFWrite: скетчи, записанные в файл до ошибки, объединяются в памяти.
+func (r *AOFRepo) VisitorSketchMerge(ctx context.Context, sketches []models.VisitorSketch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range sketches {
+		if err := r.encoder.Encode(aofRecord{VisitorSketch: &sketches[i]}); err != nil {
+			_ = r.MemoryRepo.VisitorSketchMerge(ctx, sketches[:i])
+			return ErrAOFWrite
+		}
+	}
+	return r.MemoryRepo.VisitorSketchMerge(ctx, sketches)
+}
+
+// APIKeyCreate - сохраняет новый API-ключ.
+// Если ключ с таким id уже существует, возвращает ErrDuplicate.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) APIKeyCreate(ctx context.Context, key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.MemoryRepo.APIKeyCreate(ctx, key); err != nil {
+		return err
+	}
+	if err := r.encoder.Encode(aofRecord{APIKeyCreate: key}); err != nil {
+		r.MemoryRepo.apiKeyPurge(key.ID)
+		return ErrAOFWrite
+	}
+	return nil
+}
+
+// APIKeyRevoke - отзывает API-ключ пользователя в момент at.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) APIKeyRevoke(ctx context.Context, userID uint, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.MemoryRepo.APIKeyRevoke(ctx, userID, id, at); err != nil {
+		return err
+	}
+	if err := r.encoder.Encode(aofRecord{APIKeyRevoke: &models.APIKey{ID: id, UserID: userID, RevokedAt: &at}}); err != nil {
+		r.MemoryRepo.apiKeyRestore(id)
+		return ErrAOFWrite
+	}
+	return nil
+}
+
 // ShortURLDelete - помечает удаленной короткую ссылку пользователя по ее id.
 func (r *AOFRepo) ShortURLDelete(ctx context.Context, userID uint, id string) error {
 	r.mu.Lock()
@@ -121,9 +363,90 @@ loop:
 	return int64(n), nil
 }
 
+// TeamCreate - добавляет новую команду и ее владельца ownerID.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) TeamCreate(ctx context.Context, team *models.Team, ownerID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.MemoryRepo.TeamCreate(ctx, team, ownerID); err != nil {
+		return err
+	}
+	if err := r.encoder.Encode(aofRecord{TeamCreate: &aofTeam{Team: *team, OwnerID: ownerID}}); err != nil {
+		r.MemoryRepo.teamPurge(team.ID)
+		return ErrAOFWrite
+	}
+	return nil
+}
+
+// TeamMemberSet - добавляет участника команды или изменяет его роль.
+// Если изменение роли оставит команду без владельцев, возвращает ErrLastOwner.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) TeamMemberSet(ctx context.Context, member *models.TeamMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if member == nil {
+		return ErrInvalidModel
+	}
+	prev, err := r.MemoryRepo.TeamMemberGet(ctx, member.TeamID, member.UserID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err = r.MemoryRepo.TeamMemberSet(ctx, member); err != nil {
+		return err
+	}
+	if err = r.encoder.Encode(aofRecord{TeamMember: member}); err != nil {
+		r.MemoryRepo.teamMemberRestore(member.TeamID, member.UserID, prev)
+		return ErrAOFWrite
+	}
+	return nil
+}
+
+// TeamMemberDelete - удаляет участника команды.
+// Если удаление оставит команду без владельцев, возвращает ErrLastOwner.
+// При ошибке записи в файл, возвращает ErrAOFWrite.
+func (r *AOFRepo) TeamMemberDelete(ctx context.Context, teamID, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev, err := r.MemoryRepo.TeamMemberGet(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if err = r.MemoryRepo.TeamMemberDelete(ctx, teamID, userID); err != nil {
+		return err
+	}
+	if err = r.encoder.Encode(aofRecord{TeamMemberDel: prev}); err != nil {
+		r.MemoryRepo.teamMemberRestore(teamID, userID, prev)
+		return ErrAOFWrite
+	}
+	return nil
+}
+
+// StorageStats - возвращает статистику хранилища.
+// Размер хранилища - суммарный размер AOF-файлов данных и событий переходов.
+func (r *AOFRepo) StorageStats(ctx context.Context, now time.Time) (*models.StorageStats, error) {
+	stats, err := r.MemoryRepo.StorageStats(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, f := range []*os.File{r.aof, r.clicksAOF} {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		stats.Size += info.Size()
+	}
+	return stats, nil
+}
+
 // Close - закрывает репозиторий для записи.
 func (r *AOFRepo) Close() error {
-	return r.aof.Close()
+	err := r.aof.Close()
+	if clicksErr := r.clicksAOF.Close(); err == nil {
+		err = clicksErr
+	}
+	return err
 }
 
 // loadRepoFromFile - считывает данные из файла в MemoryRepo.
@@ -154,6 +477,32 @@ func loadRepoFromFile(aofPath string) (*MemoryRepo, error) {
 	return repo, nil
 }
 
+// loadClicksFromFile - считывает события переходов из файла в MemoryRepo.
+// При ошибке чтения или парсинга JSON возвращает ErrAOFRead.
+func loadClicksFromFile(aofPath string, repo *MemoryRepo) error {
+	f, err := os.OpenFile(aofPath, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return ErrAOFOpen
+	}
+	//goland:noinspection ALL
+	defer f.Close()
+	decoder := json.NewDecoder(f)
+
+	for {
+		click := models.Click{}
+		err = decoder.Decode(&click)
+		if errors.Is(err, io.EOF) { // Конец файла
+			break
+		} else if err != nil { // Ошибка чтения
+			return ErrAOFRead
+		}
+		if err = repo.ClickAddBatch(context.Background(), []models.Click{click}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // loadRecord - загружает одну JSON-запись aofRecord в MemoryRepo.
 // При несоответствии структуры данных возвращает ErrAOFStructure.
 func loadRecord(r *aofRecord, repo *MemoryRepo) error {
@@ -166,10 +515,74 @@ func loadRecord(r *aofRecord, repo *MemoryRepo) error {
 		if err := repo.ShortURLCreate(context.Background(), r.ShortURLCreate); err != nil {
 			return err
 		}
+	case len(r.ShortURLBatch) > 0:
+		if err := repo.ShortURLCreateWithLimit(context.Background(), r.ShortURLBatch[0].UserID, r.ShortURLBatch, Limit{}); err != nil {
+			return err
+		}
 	case r.ShortURLDelete != nil:
 		if err := repo.ShortURLDelete(context.Background(), r.ShortURLDelete.UserID, r.ShortURLDelete.ID); err != nil {
 			return err
 		}
+	case r.ShortURLReplace != nil:
+		if err := repo.ShortURLUpdate(context.Background(), r.ShortURLReplace); err != nil {
+			return err
+		}
+	case r.VariantClick != nil:
+		count := r.VariantClick.Count
+		if count == 0 {
+			count = 1
+		}
+		if err := repo.ShortURLVariantClick(context.Background(), r.VariantClick.ID, r.VariantClick.Variant, count); err != nil {
+			return err
+		}
+	case r.VisitorSketch != nil:
+		if r.VisitorSketch.Sketch == nil {
+			return ErrAOFStructure
+		}
+		if err := repo.VisitorSketchMerge(context.Background(), []models.VisitorSketch{*r.VisitorSketch}); err != nil {
+			return ErrAOFStructure
+		}
+	case r.UserCredentials != nil:
+		if err := repo.UserSetCredentials(context.Background(), r.UserCredentials.ID, r.UserCredentials.Email, r.UserCredentials.PasswordHash); err != nil {
+			return err
+		}
+	case r.UserOIDCSubject != nil:
+		if err := repo.UserSetOIDCSubject(context.Background(), r.UserOIDCSubject.ID, r.UserOIDCSubject.OIDCSubject); err != nil {
+			return err
+		}
+	case r.UserTokens != nil:
+		if _, err := repo.UserGetByID(context.Background(), r.UserTokens.ID); err != nil {
+			return err
+		}
+		repo.userSetTokenGeneration(r.UserTokens.ID, r.UserTokens.TokenGeneration)
+	case r.UserPlan != nil:
+		if err := repo.UserSetPlan(context.Background(), r.UserPlan.ID, r.UserPlan.Plan); err != nil {
+			return err
+		}
+	case r.APIKeyCreate != nil:
+		if err := repo.APIKeyCreate(context.Background(), r.APIKeyCreate); err != nil {
+			return err
+		}
+	case r.APIKeyRevoke != nil:
+		if r.APIKeyRevoke.RevokedAt == nil {
+			return ErrAOFStructure
+		}
+		if err := repo.APIKeyRevoke(context.Background(), r.APIKeyRevoke.UserID, r.APIKeyRevoke.ID, *r.APIKeyRevoke.RevokedAt); err != nil {
+			return err
+		}
+	case r.TeamCreate != nil:
+		team := r.TeamCreate.Team
+		if err := repo.TeamCreate(context.Background(), &team, r.TeamCreate.OwnerID); err != nil {
+			return err
+		}
+	case r.TeamMember != nil:
+		if err := repo.TeamMemberSet(context.Background(), r.TeamMember); err != nil {
+			return err
+		}
+	case r.TeamMemberDel != nil:
+		if err := repo.TeamMemberDelete(context.Background(), r.TeamMemberDel.TeamID, r.TeamMemberDel.UserID); err != nil {
+			return err
+		}
 	default:
 		return ErrAOFStructure
 	}
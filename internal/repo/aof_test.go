@@ -5,10 +5,12 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
 	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/pkg/hll"
 )
 
 type aofRepoSuite struct {
@@ -117,6 +119,313 @@ func (suite *aofRepoSuite) TestAOFRepo_ShortURLCreate() {
 	suite.Equal(0, len(userURLs))
 }
 
+func (suite *aofRepoSuite) TestAOFRepo_ShortURLCreateWithQueryTemplate() {
+	// Создаем репозиторий и записываем в него сокращенную ссылку с шаблоном query-параметров
+	shortURL := &models.ShortURL{
+		ID:          "tmpl1",
+		OriginalURL: "https://www.example.com",
+		UserID:      1,
+		QueryTemplate: &models.QueryTemplate{
+			Params: map[string]string{"utm_source": "{referrer_host}"},
+			Policy: models.QueryMergeOverride,
+		},
+	}
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	suite.NoError(repo1.ShortURLCreate(context.Background(), shortURL))
+	suite.NoError(repo1.Close())
+
+	// Открываем репозиторий и проверяем, что шаблон сохранился
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	actual, err := repo2.ShortURLGetByID(context.Background(), shortURL.ID)
+	suite.NoError(err)
+	suite.Equal(shortURL, actual)
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_ShortURLUpdate() {
+	// Создаем репозиторий, записываем в него сокращенную ссылку и обновляем ее
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	suite.NoError(repo1.ShortURLCreate(context.Background(), suite.testShortURLs[0]))
+	updated := *suite.testShortURLs[0]
+	updated.Rules = []models.RedirectRule{{URL: "https://www.example.com/ru", Languages: []string{"ru"}}}
+	suite.NoError(repo1.ShortURLUpdate(context.Background(), &updated))
+	// Обновление чужой ссылки
+	notOwned := updated
+	notOwned.UserID = 2
+	suite.ErrorIs(repo1.ShortURLUpdate(context.Background(), &notOwned), ErrNotFound)
+	suite.NoError(repo1.Close())
+
+	// Открываем репозиторий и проверяем, что обновление сохранилось
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	actual, err := repo2.ShortURLGetByID(context.Background(), updated.ID)
+	suite.NoError(err)
+	suite.Equal(&updated, actual)
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_ShortURLVariantClick() {
+	// Создаем репозиторий и засчитываем переходы по вариантам
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	suite.NoError(repo1.ShortURLCreate(context.Background(), suite.testShortURLs[0]))
+	suite.NoError(repo1.ShortURLVariantClick(context.Background(), suite.testShortURLs[0].ID, 1, 1))
+	suite.NoError(repo1.ShortURLVariantClick(context.Background(), suite.testShortURLs[0].ID, 2, 1))
+	suite.NoError(repo1.ShortURLVariantClick(context.Background(), suite.testShortURLs[0].ID, 2, 3))
+	suite.ErrorIs(repo1.ShortURLVariantClick(context.Background(), "unknown", 1, 1), ErrNotFound)
+	suite.NoError(repo1.Close())
+
+	// Открываем репозиторий и проверяем, что счетчики восстановлены
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	clicks, err := repo2.ShortURLVariantClicks(context.Background(), suite.testShortURLs[0].ID)
+	suite.NoError(err)
+	suite.Equal(map[int]int64{1: 1, 2: 4}, clicks)
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_Clicks() {
+	// Создаем репозиторий и сохраняем события переходов
+	now := time.Now().UTC().Truncate(time.Second)
+	clicks := []models.Click{
+		{ShortURLID: suite.testShortURLs[0].ID, Time: now.Add(-time.Hour), Device: models.DeviceIOS, IP: "10.0.0.0"},
+		{ShortURLID: suite.testShortURLs[0].ID, Time: now, RefererHost: "example.com", Device: models.DeviceDesktop},
+	}
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	suite.NoError(repo1.ShortURLCreate(context.Background(), suite.testShortURLs[0]))
+	suite.NoError(repo1.ClickAddBatch(context.Background(), clicks))
+	suite.NoError(repo1.Close())
+
+	// События хранятся в отдельном файле
+	suite.FileExists(suite.filePath + aofClicksSuffix)
+
+	// Открываем репозиторий и проверяем, что события восстановлены
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	actual, err := repo2.ClickGetByShortURLID(context.Background(), suite.testShortURLs[0].ID, time.Time{}, time.Time{})
+	suite.NoError(err)
+	suite.Equal(clicks, actual)
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_VisitorSketches() {
+	day := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	sketch, err := hll.New(hll.DefaultPrecision)
+	suite.Require().NoError(err)
+	sketch.Add([]byte("a"))
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	suite.NoError(repo1.VisitorSketchMerge(context.Background(), []models.VisitorSketch{{ShortURLID: "12345", Day: day, Sketch: sketch}}))
+	sketch.Add([]byte("b"))
+	suite.NoError(repo1.VisitorSketchMerge(context.Background(), []models.VisitorSketch{{ShortURLID: "12345", Day: day, Sketch: sketch}}))
+	suite.NoError(repo1.Close())
+
+	// Открываем репозиторий и проверяем, что скетчи восстановлены и объединены
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	actual, err := repo2.VisitorSketchGet(context.Background(), "12345", day, day.AddDate(0, 0, 1))
+	suite.NoError(err)
+	suite.Require().Len(actual, 1)
+	suite.Equal(uint64(2), actual[0].Sketch.Count())
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_UserCredentials() {
+	ctx := context.Background()
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	user := &models.User{}
+	suite.NoError(repo1.UserCreate(ctx, user))
+	suite.NoError(repo1.UserSetCredentials(ctx, user.ID, "user@example.com", "hash"))
+	suite.NoError(repo1.Close())
+
+	// Открываем репозиторий и проверяем, что учетные данные восстановлены
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	actual, err := repo2.UserGetByEmail(ctx, "user@example.com")
+	suite.NoError(err)
+	suite.Equal(&models.User{ID: user.ID, Email: "user@example.com", PasswordHash: "hash"}, actual)
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_UserOIDCSubject() {
+	ctx := context.Background()
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	user := &models.User{}
+	suite.NoError(repo1.UserCreate(ctx, user))
+	suite.NoError(repo1.UserSetOIDCSubject(ctx, user.ID, "https://idp.example.com 42"))
+	suite.NoError(repo1.Close())
+
+	// Открываем репозиторий и проверяем, что идентификатор восстановлен
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	actual, err := repo2.UserGetByOIDCSubject(ctx, "https://idp.example.com 42")
+	suite.NoError(err)
+	suite.Equal(&models.User{ID: user.ID, OIDCSubject: "https://idp.example.com 42"}, actual)
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_UserRevokeTokens() {
+	ctx := context.Background()
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	user := &models.User{}
+	suite.NoError(repo1.UserCreate(ctx, user))
+	_, err = repo1.UserRevokeTokens(ctx, user.ID)
+	suite.NoError(err)
+	generation, err := repo1.UserRevokeTokens(ctx, user.ID)
+	suite.NoError(err)
+	suite.Equal(uint(2), generation)
+	suite.NoError(repo1.Close())
+
+	// Открываем репозиторий и проверяем, что поколение токенов восстановлено
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	actual, err := repo2.UserGetByID(ctx, user.ID)
+	suite.NoError(err)
+	suite.Equal(uint(2), actual.TokenGeneration)
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_UserSetPlan() {
+	ctx := context.Background()
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	user := &models.User{}
+	suite.NoError(repo1.UserCreate(ctx, user))
+	suite.NoError(repo1.UserSetPlan(ctx, user.ID, "pro"))
+	suite.ErrorIs(repo1.UserSetPlan(ctx, user.ID+100, "pro"), ErrNotFound)
+	suite.NoError(repo1.Close())
+
+	// Открываем репозиторий и проверяем, что план восстановлен
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	actual, err := repo2.UserGetByID(ctx, user.ID)
+	suite.NoError(err)
+	suite.Equal("pro", actual.Plan)
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_ShortURLCreateWithLimit() {
+	ctx := context.Background()
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	suite.NoError(repo1.UserCreate(ctx, &models.User{}))
+	suite.ErrorIs(repo1.ShortURLCreateWithLimit(ctx, 1, suite.testShortURLs[:3], Limit{MaxActive: 2}), ErrLimitExceeded)
+	suite.NoError(repo1.ShortURLCreateWithLimit(ctx, 1, suite.testShortURLs[:2], Limit{MaxActive: 2}))
+	suite.NoError(repo1.Close())
+
+	// Открываем репозиторий и проверяем, что созданы только ссылки из успешного пакета
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	shortURLs, err := repo2.ShortURLGetByUserID(ctx, 1)
+	suite.NoError(err)
+	suite.Equal([]models.ShortURL{*suite.testShortURLs[0], *suite.testShortURLs[1]}, shortURLs)
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_APIKeys() {
+	ctx := context.Background()
+	key := &models.APIKey{
+		ID:        "key1",
+		UserID:    1,
+		Name:      "CI",
+		Scopes:    []models.APIScope{models.APIScopeRead, models.APIScopeDelete},
+		Hash:      "hash",
+		CreatedAt: time.Date(2023, 3, 8, 9, 0, 0, 0, time.UTC),
+	}
+	revokedAt := key.CreatedAt.Add(time.Hour)
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	suite.NoError(repo1.APIKeyCreate(ctx, key))
+	suite.NoError(repo1.APIKeyCreate(ctx, &models.APIKey{ID: "key2", UserID: 1, Name: "Bot"}))
+	suite.NoError(repo1.APIKeyRevoke(ctx, 1, "key2", revokedAt))
+	suite.NoError(repo1.Close())
+
+	// Открываем репозиторий и проверяем, что ключи и их отзыв восстановлены
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	keys, err := repo2.APIKeyGetByUserID(ctx, 1)
+	suite.NoError(err)
+	suite.Require().Len(keys, 2)
+	suite.Equal(*key, keys[0])
+	suite.Require().True(keys[1].Revoked())
+	suite.Equal(revokedAt, *keys[1].RevokedAt)
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_Teams() {
+	ctx := context.Background()
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	owner, editor, viewer := &models.User{}, &models.User{}, &models.User{}
+	suite.NoError(repo1.UserCreate(ctx, owner))
+	suite.NoError(repo1.UserCreate(ctx, editor))
+	suite.NoError(repo1.UserCreate(ctx, viewer))
+	team := &models.Team{Name: "Marketing", CreatedAt: time.Date(2023, 3, 8, 9, 0, 0, 0, time.UTC)}
+	suite.NoError(repo1.TeamCreate(ctx, team, owner.ID))
+	suite.ErrorIs(repo1.TeamCreate(ctx, &models.Team{Name: "Sales"}, 100), ErrNotFound)
+	suite.NoError(repo1.TeamMemberSet(ctx, &models.TeamMember{TeamID: team.ID, UserID: editor.ID, Role: models.TeamRoleEditor}))
+	suite.NoError(repo1.TeamMemberSet(ctx, &models.TeamMember{TeamID: team.ID, UserID: viewer.ID, Role: models.TeamRoleViewer}))
+	suite.NoError(repo1.ShortURLCreate(ctx, &models.ShortURL{ID: "team1", OriginalURL: "https://team1.com", UserID: owner.ID, TeamID: team.ID}))
+	suite.NoError(repo1.ShortURLCreate(ctx, &models.ShortURL{ID: "team2", OriginalURL: "https://team2.com", UserID: owner.ID, TeamID: team.ID}))
+	suite.NoError(repo1.ShortURLDelete(ctx, editor.ID, "team1"))
+	suite.NoError(repo1.TeamMemberDelete(ctx, team.ID, editor.ID))
+	suite.ErrorIs(repo1.ShortURLDelete(ctx, editor.ID, "team2"), ErrNotFound)
+	suite.NoError(repo1.Close())
+
+	// Открываем репозиторий и проверяем, что команда, участники и ссылки восстановлены
+	repo2, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	actual, err := repo2.TeamGetByID(ctx, team.ID)
+	suite.NoError(err)
+	suite.Equal(team, actual)
+	members, err := repo2.TeamMemberGetByTeamID(ctx, team.ID)
+	suite.NoError(err)
+	suite.Equal([]models.TeamMember{
+		{TeamID: team.ID, UserID: owner.ID, Role: models.TeamRoleOwner},
+		{TeamID: team.ID, UserID: viewer.ID, Role: models.TeamRoleViewer},
+	}, members)
+	urls, err := repo2.ShortURLGetByTeamID(ctx, team.ID)
+	suite.NoError(err)
+	suite.Require().Len(urls, 2)
+	suite.True(urls[0].Deleted)
+	suite.False(urls[1].Deleted)
+	// Следующая команда получает новый id
+	next := &models.Team{Name: "Sales"}
+	suite.NoError(repo2.TeamCreate(ctx, next, viewer.ID))
+	suite.Equal(team.ID+1, next.ID)
+	suite.NoError(repo2.Close())
+}
+
+func (suite *aofRepoSuite) TestAOFRepo_StorageStats() {
+	repo1, err := NewAOFRepo(suite.filePath)
+	suite.NoError(err)
+	stats, err := repo1.StorageStats(context.Background(), time.Now())
+	suite.NoError(err)
+	suite.Zero(stats.Size)
+
+	// Размер хранилища - суммарный размер файлов данных и событий переходов
+	suite.NoError(repo1.ShortURLCreate(context.Background(), suite.testShortURLs[0]))
+	suite.NoError(repo1.ClickAddBatch(context.Background(), []models.Click{{ShortURLID: suite.testShortURLs[0].ID, Time: time.Now()}}))
+	stats, err = repo1.StorageStats(context.Background(), time.Now())
+	suite.NoError(err)
+	suite.Equal(1, stats.URLs)
+	suite.NoError(repo1.Close())
+
+	aofInfo, err := os.Stat(suite.filePath)
+	suite.NoError(err)
+	clicksInfo, err := os.Stat(suite.filePath + aofClicksSuffix)
+	suite.NoError(err)
+	suite.Positive(clicksInfo.Size())
+	suite.Equal(aofInfo.Size()+clicksInfo.Size(), stats.Size)
+}
+
 func (suite *aofRepoSuite) TestShortURLDelete() {
 	// Создаем репозиторий и записываем в него сокращенные ссылки
 	repo1, err := NewAOFRepo(suite.filePath)
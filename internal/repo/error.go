@@ -11,6 +11,12 @@ var ErrDuplicate = errors.New("duplicate id")
 // ErrNotFound - не найдено
 var ErrNotFound = errors.New("not found")
 
+// ErrLimitExceeded - превышено ограничение количества ссылок пользователя (см. Limit)
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// ErrLastOwner - изменение оставит команду без владельцев
+var ErrLastOwner = errors.New("last team owner")
+
 // ErrAOFOpen - ошибка открытия AOF-файла
 var ErrAOFOpen = errors.New("aof open error")
 
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/ofstudio/go-shortener/internal/models"
 )
@@ -14,22 +15,116 @@ type IRepo interface {
 	UserGetByID(context.Context, uint) (*models.User, error)
 	// UserCount - возвращает количество пользователей в репозитории.
 	UserCount(context.Context) (int, error)
+	// UserGetByEmail - возвращает зарегистрированного пользователя по email.
+	UserGetByEmail(context.Context, string) (*models.User, error)
+	// UserSetCredentials - устанавливает email и хеш пароля пользователя.
+	// Если пользователь не найден, возвращает ErrNotFound.
+	// Если email уже принадлежит другому пользователю, возвращает ErrDuplicate.
+	UserSetCredentials(ctx context.Context, id uint, email, passwordHash string) error
+	// UserGetByOIDCSubject - возвращает пользователя по идентификатору у провайдера OpenID Connect.
+	UserGetByOIDCSubject(context.Context, string) (*models.User, error)
+	// UserSetOIDCSubject - устанавливает идентификатор пользователя у провайдера OpenID Connect.
+	// Если пользователь не найден, возвращает ErrNotFound.
+	// Если идентификатор уже принадлежит другому пользователю, возвращает ErrDuplicate.
+	UserSetOIDCSubject(ctx context.Context, id uint, subject string) error
+	// UserRevokeTokens - увеличивает поколение токенов пользователя и возвращает новое поколение.
+	// Если пользователь не найден, возвращает ErrNotFound.
+	UserRevokeTokens(ctx context.Context, id uint) (uint, error)
+	// UserSetPlan - устанавливает тарифный план пользователя.
+	// Если пользователь не найден, возвращает ErrNotFound.
+	UserSetPlan(ctx context.Context, id uint, plan string) error
 	// ShortURLCreate - добавляет новую сокращенную ссылку в репозиторий.
 	ShortURLCreate(context.Context, *models.ShortURL) error
+	// ShortURLCreateWithLimit - добавляет новые сокращенные ссылки пользователя userID, если после этого
+	// не будут превышены ограничения limit. Проверка и добавление выполняются атомарно: либо добавляются все ссылки, либо ни одной.
+	// Если пользователь не найден, возвращает ErrNotFound.
+	// Если ограничения будут превышены, возвращает ErrLimitExceeded.
+	// Если ссылка с таким id или оригинальным url уже существует, возвращает ErrDuplicate.
+	ShortURLCreateWithLimit(ctx context.Context, userID uint, shortURLs []*models.ShortURL, limit Limit) error
 	// ShortURLGetByID - возвращает сокращенную ссылку по ее id.
 	ShortURLGetByID(context.Context, string) (*models.ShortURL, error)
 	// ShortURLGetByUserID - возвращает сокращенные ссылки пользователя.
 	// Если пользователь не найден, или у пользователя нет ссылок возвращает nil.
 	ShortURLGetByUserID(context.Context, uint) ([]models.ShortURL, error)
-	// ShortURLGetByOriginalURL - возвращает сокращенную ссылку по ее оригинальному url.
-	ShortURLGetByOriginalURL(context.Context, string) (*models.ShortURL, error)
+	// ShortURLGetByTeamID - возвращает сокращенные ссылки команды.
+	// Если у команды нет ссылок, возвращает nil.
+	ShortURLGetByTeamID(context.Context, uint) ([]models.ShortURL, error)
+	// ShortURLCountByUserID - возвращает количество неудаленных ссылок, созданных пользователем,
+	// и количество ссылок, созданных им начиная с момента since, в тч удаленных.
+	ShortURLCountByUserID(ctx context.Context, userID uint, since time.Time) (active, created int, err error)
+	// ShortURLGetByOriginalURL - возвращает простую ссылку пользователя (см. models.ShortURL.Plain) по ее оригинальному url.
+	// У пользователя может быть только одна простая ссылка с каждым оригинальным url.
+	ShortURLGetByOriginalURL(ctx context.Context, userID uint, originalURL string) (*models.ShortURL, error)
+	// ShortURLUpdate - сохраняет изменяемые поля сокращенной ссылки пользователя, в тч команду ссылки.
+	// Поля ID, OriginalURL, SubmittedURL, UserID, CreatedAt и Deleted не изменяются.
+	// Если ссылка становится простой, а у пользователя уже есть простая ссылка с тем же url, возвращает ErrDuplicate.
+	ShortURLUpdate(context.Context, *models.ShortURL) error
 	// ShortURLDelete - помечает удаленной короткую ссылку пользователя по ее id.
+	// Личную ссылку может удалить только создавший ее пользователь,
+	// ссылку команды - участник команды с ролью owner или editor.
+	// Если ссылка не найдена или пользователь не может ее удалить, возвращает ErrNotFound.
 	ShortURLDelete(context.Context, uint, string) error
 	// ShortURLDeleteBatch - помечает удаленными несколько сокращенных ссылок пользователя по их id.
+	// Права на удаление проверяются так же, как в ShortURLDelete.
 	// Принимает на вход список каналов для передачи идентификаторов.
 	// Возвращает количество удаленных сокращенных ссылок.
 	ShortURLDeleteBatch(context.Context, uint, ...chan string) (int64, error)
+	// ShortURLVariantClick - увеличивает счетчик переходов по варианту сплит-ссылки на n.
+	ShortURLVariantClick(ctx context.Context, id string, variant int, n int64) error
+	// ShortURLVariantClicks - возвращает счетчики переходов по вариантам сплит-ссылки по их номерам.
+	// Варианты без переходов в результат не попадают.
+	ShortURLVariantClicks(ctx context.Context, id string) (map[int]int64, error)
 	// ShortURLCount - возвращает количество сокращенных ссылок в репозитории.
 	ShortURLCount(context.Context) (int, error)
+	// StorageStats - возвращает статистику хранилища.
+	// Ссылки, созданные за последний час и сутки, считаются относительно now.
+	StorageStats(ctx context.Context, now time.Time) (*models.StorageStats, error)
+	// ClickAddBatch - сохраняет события переходов по сокращенным ссылкам.
+	ClickAddBatch(context.Context, []models.Click) error
+	// ClickGetByShortURLID - возвращает события переходов по сокращенной ссылке за период [from, until).
+	// Нулевое значение from или until означает, что период не ограничен с соответствующей стороны.
+	ClickGetByShortURLID(ctx context.Context, id string, from, until time.Time) ([]models.Click, error)
+	// ClickIterate - вызывает fn для каждого события перехода по сокращенной ссылке за период [from, until),
+	// не загружая события в память целиком. Нулевое значение from или until означает,
+	// что период не ограничен с соответствующей стороны.
+	// Если fn возвращает ошибку, перебор прекращается и ClickIterate возвращает эту ошибку.
+	ClickIterate(ctx context.Context, id string, from, until time.Time, fn func(models.Click) error) error
+	// VisitorSketchMerge - объединяет скетчи посетителей с сохраненными скетчами тех же ссылок за те же сутки.
+	VisitorSketchMerge(context.Context, []models.VisitorSketch) error
+	// VisitorSketchGet - возвращает скетчи посетителей ссылки id за сутки в периоде [from, until), упорядоченные по времени.
+	// Для скетчей всех ссылок сервиса используется id равный models.VisitorSketchAll.
+	VisitorSketchGet(ctx context.Context, id string, from, until time.Time) ([]models.VisitorSketch, error)
+	// APIKeyCreate - сохраняет новый API-ключ.
+	APIKeyCreate(context.Context, *models.APIKey) error
+	// APIKeyGetByID - возвращает API-ключ по его id.
+	APIKeyGetByID(context.Context, string) (*models.APIKey, error)
+	// APIKeyGetByUserID - возвращает API-ключи пользователя, в тч отозванные.
+	// Если у пользователя нет ключей, возвращает nil.
+	APIKeyGetByUserID(context.Context, uint) ([]models.APIKey, error)
+	// APIKeyRevoke - отзывает API-ключ пользователя в момент at.
+	// Если ключ не найден, принадлежит другому пользователю или уже отозван, возвращает ErrNotFound.
+	APIKeyRevoke(ctx context.Context, userID uint, id string, at time.Time) error
+	// TeamCreate - добавляет новую команду и ее владельца ownerID в репозиторий.
+	TeamCreate(ctx context.Context, team *models.Team, ownerID uint) error
+	// TeamGetByID - возвращает команду по ее id.
+	TeamGetByID(context.Context, uint) (*models.Team, error)
+	// TeamGetByUserID - возвращает команды пользователя и его роли в них в порядке создания команд.
+	// Если пользователь не состоит в командах, возвращает nil.
+	TeamGetByUserID(context.Context, uint) ([]models.TeamMembership, error)
+	// TeamMemberGet - возвращает участника команды.
+	// Если пользователь не состоит в команде, возвращает ErrNotFound.
+	TeamMemberGet(ctx context.Context, teamID, userID uint) (*models.TeamMember, error)
+	// TeamMemberGetByTeamID - возвращает участников команды в порядке их id.
+	TeamMemberGetByTeamID(context.Context, uint) ([]models.TeamMember, error)
+	// TeamMemberSet - добавляет участника команды или изменяет его роль.
+	// Если команда или пользователь не найдены, возвращает ErrNotFound.
+	// Если изменение роли оставит команду без владельцев, возвращает ErrLastOwner.
+	// Проверка и изменение выполняются атомарно.
+	TeamMemberSet(context.Context, *models.TeamMember) error
+	// TeamMemberDelete - удаляет участника команды.
+	// Если пользователь не состоит в команде, возвращает ErrNotFound.
+	// Если удаление оставит команду без владельцев, возвращает ErrLastOwner.
+	// Проверка и удаление выполняются атомарно.
+	TeamMemberDelete(ctx context.Context, teamID, userID uint) error
 	Close() error
 }
@@ -0,0 +1,18 @@
+package repo
+
+import "time"
+
+// Limit - ограничения количества ссылок пользователя, которые проверяются при создании ссылок
+// в той же операции, что и их добавление (см. IRepo.ShortURLCreateWithLimit).
+// Значение 0 означает отсутствие ограничения.
+type Limit struct {
+	MaxActive  int       // Максимальное количество неудаленных ссылок пользователя
+	MaxCreated int       // Максимальное количество ссылок, созданных пользователем начиная с Since, в тч удаленных
+	Since      time.Time // Начало периода, за который считается MaxCreated
+}
+
+// exceeded - возвращает true, если создание еще n ссылок при текущем количестве active и created превысит ограничения
+func (l Limit) exceeded(active, created, n int) bool {
+	return (l.MaxActive > 0 && active+n > l.MaxActive) ||
+		(l.MaxCreated > 0 && created+n > l.MaxCreated)
+}
@@ -2,18 +2,31 @@ package repo
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/pkg/hll"
 )
 
 // MemoryRepo - реализация IRepo для хранения данных в памяти.
 type MemoryRepo struct {
 	shortURLs      map[string]*models.ShortURL
 	users          map[uint]*models.User
+	userEmails     map[string]uint // Индекс пользователей по email
+	userSubjects   map[string]uint // Индекс пользователей по идентификатору OpenID Connect
 	userShortURLs  map[uint][]string
-	originalURLIdx map[string]string
+	originalURLIdx map[originalURLKey]string // Индекс простых ссылок (см. models.ShortURL.Plain)
+	variantClicks  map[string]map[int]int64
+	clicks         map[string][]models.Click
+	sketches       map[string]map[int64]*hll.Sketch // Скетчи посетителей по id ссылки и Unix-времени начала суток
+	apiKeys        map[string]*models.APIKey
+	userAPIKeys    map[uint][]string
+	teams          map[uint]*models.Team
+	teamMembers    map[uint]map[uint]models.TeamRole // Роли участников по id команды и id пользователя
 	nextUserID     uint
+	nextTeamID     uint
 	mu             sync.RWMutex
 }
 
@@ -22,9 +35,19 @@ func NewMemoryRepo() *MemoryRepo {
 	return &MemoryRepo{
 		shortURLs:      make(map[string]*models.ShortURL),
 		users:          make(map[uint]*models.User),
+		userEmails:     make(map[string]uint),
+		userSubjects:   make(map[string]uint),
 		userShortURLs:  make(map[uint][]string),
-		originalURLIdx: make(map[string]string),
+		originalURLIdx: make(map[originalURLKey]string),
+		variantClicks:  make(map[string]map[int]int64),
+		clicks:         make(map[string][]models.Click),
+		sketches:       make(map[string]map[int64]*hll.Sketch),
+		apiKeys:        make(map[string]*models.APIKey),
+		userAPIKeys:    make(map[uint][]string),
+		teams:          make(map[uint]*models.Team),
+		teamMembers:    make(map[uint]map[uint]models.TeamRole),
 		nextUserID:     1,
+		nextTeamID:     1,
 	}
 }
 
@@ -40,7 +63,20 @@ func (r *MemoryRepo) UserCreate(_ context.Context, user *models.User) error {
 	if _, exist := r.users[user.ID]; exist {
 		return ErrDuplicate
 	}
-	r.users[user.ID] = user
+	if _, exist := r.userEmails[user.Email]; exist && user.Email != "" {
+		return ErrDuplicate
+	}
+	if _, exist := r.userSubjects[user.OIDCSubject]; exist && user.OIDCSubject != "" {
+		return ErrDuplicate
+	}
+	stored := *user
+	r.users[user.ID] = &stored
+	if user.Email != "" {
+		r.userEmails[user.Email] = user.ID
+	}
+	if user.OIDCSubject != "" {
+		r.userSubjects[user.OIDCSubject] = user.ID
+	}
 	return nil
 }
 
@@ -49,7 +85,8 @@ func (r *MemoryRepo) UserGetByID(_ context.Context, id uint) (*models.User, erro
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	if user, ok := r.users[id]; ok {
-		return user, nil
+		result := *user
+		return &result, nil
 	}
 	return nil, ErrNotFound
 }
@@ -61,6 +98,90 @@ func (r *MemoryRepo) UserCount(_ context.Context) (int, error) {
 	return len(r.users), nil
 }
 
+// UserGetByEmail - возвращает зарегистрированного пользователя по email либо ErrNotFound.
+func (r *MemoryRepo) UserGetByEmail(_ context.Context, email string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	id, ok := r.userEmails[email]
+	if !ok || email == "" {
+		return nil, ErrNotFound
+	}
+	result := *r.users[id]
+	return &result, nil
+}
+
+// UserSetCredentials - устанавливает email и хеш пароля пользователя.
+// Если пользователь не найден, возвращает ErrNotFound.
+// Если email уже принадлежит другому пользователю, возвращает ErrDuplicate.
+func (r *MemoryRepo) UserSetCredentials(_ context.Context, id uint, email, passwordHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if owner, exist := r.userEmails[email]; exist && owner != id {
+		return ErrDuplicate
+	}
+	r.setCredentials(user, email, passwordHash)
+	return nil
+}
+
+// UserGetByOIDCSubject - возвращает пользователя по идентификатору у провайдера OpenID Connect либо ErrNotFound.
+func (r *MemoryRepo) UserGetByOIDCSubject(_ context.Context, subject string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	id, ok := r.userSubjects[subject]
+	if !ok || subject == "" {
+		return nil, ErrNotFound
+	}
+	result := *r.users[id]
+	return &result, nil
+}
+
+// UserSetOIDCSubject - устанавливает идентификатор пользователя у провайдера OpenID Connect.
+// Если пользователь не найден, возвращает ErrNotFound.
+// Если идентификатор уже принадлежит другому пользователю, возвращает ErrDuplicate.
+func (r *MemoryRepo) UserSetOIDCSubject(_ context.Context, id uint, subject string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if owner, exist := r.userSubjects[subject]; exist && owner != id {
+		return ErrDuplicate
+	}
+	r.setOIDCSubject(user, subject)
+	return nil
+}
+
+// UserRevokeTokens - увеличивает поколение токенов пользователя и возвращает новое поколение.
+// Если пользователь не найден, возвращает ErrNotFound.
+func (r *MemoryRepo) UserRevokeTokens(_ context.Context, id uint) (uint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	user.TokenGeneration++
+	return user.TokenGeneration, nil
+}
+
+// UserSetPlan - устанавливает тарифный план пользователя.
+// Если пользователь не найден, возвращает ErrNotFound.
+func (r *MemoryRepo) UserSetPlan(_ context.Context, id uint, plan string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	user.Plan = plan
+	return nil
+}
+
 // ShortURLCreate - создает новую короткую ссылку в репозитории.
 // Если короткая ссылка с таким id уже существует, возвращает ErrDuplicate.
 func (r *MemoryRepo) ShortURLCreate(_ context.Context, shortURL *models.ShortURL) error {
@@ -72,12 +193,54 @@ func (r *MemoryRepo) ShortURLCreate(_ context.Context, shortURL *models.ShortURL
 	if _, exist := r.shortURLs[shortURL.ID]; exist {
 		return ErrDuplicate
 	}
-	if _, exist := r.originalURLIdx[shortURL.OriginalURL]; exist {
+	if _, exist := r.originalURLIdx[newOriginalURLKey(shortURL)]; exist && shortURL.Plain() {
 		return ErrDuplicate
 	}
 	r.shortURLs[shortURL.ID] = shortURL
 	r.userShortURLs[shortURL.UserID] = append(r.userShortURLs[shortURL.UserID], shortURL.ID)
-	r.originalURLIdx[shortURL.OriginalURL] = shortURL.ID
+	r.indexOriginalURL(shortURL)
+	return nil
+}
+
+// ShortURLCreateWithLimit - создает новые короткие ссылки пользователя userID, если после этого
+// не будут превышены ограничения limit. См. IRepo.ShortURLCreateWithLimit.
+func (r *MemoryRepo) ShortURLCreateWithLimit(_ context.Context, userID uint, shortURLs []*models.ShortURL, limit Limit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exist := r.users[userID]; !exist {
+		return ErrNotFound
+	}
+	ids := make(map[string]struct{}, len(shortURLs))
+	originalURLs := make(map[string]struct{}, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		if shortURL == nil || shortURL.UserID != userID {
+			return ErrInvalidModel
+		}
+		_, existID := r.shortURLs[shortURL.ID]
+		_, dupID := ids[shortURL.ID]
+		if existID || dupID {
+			return ErrDuplicate
+		}
+		ids[shortURL.ID] = struct{}{}
+		if !shortURL.Plain() {
+			continue
+		}
+		_, existURL := r.originalURLIdx[newOriginalURLKey(shortURL)]
+		_, dupURL := originalURLs[shortURL.OriginalURL]
+		if existURL || dupURL {
+			return ErrDuplicate
+		}
+		originalURLs[shortURL.OriginalURL] = struct{}{}
+	}
+	active, created := r.shortURLCount(userID, limit.Since)
+	if limit.exceeded(active, created, len(shortURLs)) {
+		return ErrLimitExceeded
+	}
+	for _, shortURL := range shortURLs {
+		r.shortURLs[shortURL.ID] = shortURL
+		r.userShortURLs[userID] = append(r.userShortURLs[userID], shortURL.ID)
+		r.indexOriginalURL(shortURL)
+	}
 	return nil
 }
 
@@ -107,11 +270,53 @@ func (r *MemoryRepo) ShortURLGetByUserID(_ context.Context, userID uint) ([]mode
 	return result, nil
 }
 
-// ShortURLGetByOriginalURL - возвращает сокращенную ссылку по ее оригинальному url.
-func (r *MemoryRepo) ShortURLGetByOriginalURL(_ context.Context, originalURL string) (*models.ShortURL, error) {
+// ShortURLCountByUserID - возвращает количество неудаленных ссылок, созданных пользователем,
+// и количество ссылок, созданных им начиная с момента since, в тч удаленных.
+func (r *MemoryRepo) ShortURLCountByUserID(_ context.Context, userID uint, since time.Time) (active, created int, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	active, created = r.shortURLCount(userID, since)
+	return active, created, nil
+}
+
+// shortURLCount - см. ShortURLCountByUserID. Вызывается под блокировкой.
+func (r *MemoryRepo) shortURLCount(userID uint, since time.Time) (active, created int) {
+	for _, id := range r.userShortURLs[userID] {
+		shortURL := r.shortURLs[id]
+		if !shortURL.Deleted {
+			active++
+		}
+		if !shortURL.CreatedAt.Before(since) {
+			created++
+		}
+	}
+	return active, created
+}
+
+// ShortURLGetByTeamID - возвращает список коротких ссылок команды в порядке создания.
+func (r *MemoryRepo) ShortURLGetByTeamID(_ context.Context, teamID uint) ([]models.ShortURL, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []models.ShortURL
+	for _, shortURL := range r.shortURLs {
+		if shortURL.TeamID == teamID && teamID != 0 {
+			result = append(result, *shortURL)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// ShortURLGetByOriginalURL - возвращает простую ссылку пользователя (см. models.ShortURL.Plain) по ее оригинальному url.
+func (r *MemoryRepo) ShortURLGetByOriginalURL(_ context.Context, userID uint, originalURL string) (*models.ShortURL, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	if id, ok := r.originalURLIdx[originalURL]; ok {
+	if id, ok := r.originalURLIdx[originalURLKey{userID: userID, originalURL: originalURL}]; ok {
 		if shortURL, ok := r.shortURLs[id]; ok {
 			return shortURL, nil
 		}
@@ -119,6 +324,149 @@ func (r *MemoryRepo) ShortURLGetByOriginalURL(_ context.Context, originalURL str
 	return nil, ErrNotFound
 }
 
+// ShortURLUpdate - сохраняет изменяемые поля сокращенной ссылки пользователя.
+// Поля ID, OriginalURL, SubmittedURL, UserID, CreatedAt и Deleted не изменяются.
+// Если ссылка не найдена или принадлежит другому пользователю, возвращает ErrNotFound.
+// Если ссылка становится простой, а у пользователя уже есть простая ссылка с тем же URL, возвращает ErrDuplicate.
+func (r *MemoryRepo) ShortURLUpdate(_ context.Context, shortURL *models.ShortURL) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if shortURL == nil {
+		return ErrInvalidModel
+	}
+	stored, exist := r.shortURLs[shortURL.ID]
+	if !exist || stored.UserID != shortURL.UserID {
+		return ErrNotFound
+	}
+	// Сохраняем копию, чтобы не изменять объект, который мог быть возвращен ранее
+	updated := *shortURL
+	updated.OriginalURL = stored.OriginalURL
+	updated.SubmittedURL = stored.SubmittedURL
+	updated.CreatedAt = stored.CreatedAt
+	updated.Deleted = stored.Deleted
+	if id, exist := r.originalURLIdx[newOriginalURLKey(&updated)]; exist && id != updated.ID && updated.Plain() {
+		return ErrDuplicate
+	}
+	r.unindexOriginalURL(stored)
+	r.shortURLs[shortURL.ID] = &updated
+	r.indexOriginalURL(&updated)
+	return nil
+}
+
+// ShortURLVariantClick - увеличивает счетчик переходов по варианту сплит-ссылки на n.
+// Если ссылка не найдена, возвращает ErrNotFound.
+func (r *MemoryRepo) ShortURLVariantClick(_ context.Context, id string, variant int, n int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exist := r.shortURLs[id]; !exist {
+		return ErrNotFound
+	}
+	if r.variantClicks[id] == nil {
+		r.variantClicks[id] = make(map[int]int64)
+	}
+	r.variantClicks[id][variant] += n
+	return nil
+}
+
+// ShortURLVariantClicks - возвращает счетчики переходов по вариантам сплит-ссылки по их номерам.
+// Если ссылка не найдена, возвращает ErrNotFound.
+func (r *MemoryRepo) ShortURLVariantClicks(_ context.Context, id string) (map[int]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, exist := r.shortURLs[id]; !exist {
+		return nil, ErrNotFound
+	}
+	clicks := make(map[int]int64, len(r.variantClicks[id]))
+	for variant, n := range r.variantClicks[id] {
+		clicks[variant] = n
+	}
+	return clicks, nil
+}
+
+// ClickAddBatch - сохраняет события переходов по сокращенным ссылкам.
+func (r *MemoryRepo) ClickAddBatch(_ context.Context, clicks []models.Click) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, click := range clicks {
+		r.clicks[click.ShortURLID] = append(r.clicks[click.ShortURLID], click)
+	}
+	return nil
+}
+
+// ClickGetByShortURLID - возвращает события переходов по сокращенной ссылке за период [from, until).
+// Нулевое значение from или until означает, что период не ограничен с соответствующей стороны.
+func (r *MemoryRepo) ClickGetByShortURLID(ctx context.Context, id string, from, until time.Time) ([]models.Click, error) {
+	var result []models.Click
+	err := r.ClickIterate(ctx, id, from, until, func(click models.Click) error {
+		result = append(result, click)
+		return nil
+	})
+	return result, err
+}
+
+// ClickIterate - вызывает fn для каждого события перехода по сокращенной ссылке за период [from, until).
+// События только добавляются в конец списка и не изменяются,
+// поэтому fn вызывается без блокировки репозитория для событий, сохраненных на момент вызова.
+func (r *MemoryRepo) ClickIterate(ctx context.Context, id string, from, until time.Time, fn func(models.Click) error) error {
+	r.mu.RLock()
+	clicks := r.clicks[id]
+	r.mu.RUnlock()
+	for _, click := range clicks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !from.IsZero() && click.Time.Before(from) {
+			continue
+		}
+		if !until.IsZero() && !click.Time.Before(until) {
+			continue
+		}
+		if err := fn(click); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VisitorSketchMerge - объединяет скетчи посетителей с сохраненными скетчами тех же ссылок за те же сутки.
+// Сохраняются копии скетчей.
+func (r *MemoryRepo) VisitorSketchMerge(_ context.Context, sketches []models.VisitorSketch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range sketches {
+		days, ok := r.sketches[s.ShortURLID]
+		if !ok {
+			days = make(map[int64]*hll.Sketch)
+			r.sketches[s.ShortURLID] = days
+		}
+		day := s.Day.Unix()
+		stored, ok := days[day]
+		if !ok {
+			days[day] = s.Sketch.Clone()
+			continue
+		}
+		if err := stored.Merge(s.Sketch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VisitorSketchGet - возвращает копии скетчей посетителей ссылки id за сутки в периоде [from, until), упорядоченные по времени.
+func (r *MemoryRepo) VisitorSketchGet(_ context.Context, id string, from, until time.Time) ([]models.VisitorSketch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []models.VisitorSketch
+	for day, sketch := range r.sketches[id] {
+		if day < from.Unix() || day >= until.Unix() {
+			continue
+		}
+		result = append(result, models.VisitorSketch{ShortURLID: id, Day: time.Unix(day, 0).UTC(), Sketch: sketch.Clone()})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Day.Before(result[j].Day) })
+	return result, nil
+}
+
 // ShortURLDeleteBatch - помечает удаленными несколько сокращенных ссылок пользователя по их id.
 // Принимает на вход список каналов для передачи идентификаторов.
 // Возвращает количество удаленных сокращенных ссылок.
@@ -150,6 +498,7 @@ loop:
 }
 
 // ShortURLDelete - помечает удаленной короткую ссылку пользователя по ее id.
+// Если ссылка не найдена или пользователь не может ее удалить, возвращает ErrNotFound.
 func (r *MemoryRepo) ShortURLDelete(_ context.Context, userID uint, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -157,7 +506,7 @@ func (r *MemoryRepo) ShortURLDelete(_ context.Context, userID uint, id string) e
 	if !exist {
 		return ErrNotFound
 	}
-	if shortURL.UserID != userID {
+	if !r.canEdit(shortURL, userID) {
 		return ErrNotFound
 	}
 	shortURL.Deleted = true
@@ -172,6 +521,219 @@ func (r *MemoryRepo) ShortURLCount(_ context.Context) (int, error) {
 	return len(r.shortURLs), nil
 }
 
+// APIKeyCreate - сохраняет новый API-ключ.
+// Если ключ с таким id уже существует, возвращает ErrDuplicate.
+func (r *MemoryRepo) APIKeyCreate(_ context.Context, key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key == nil {
+		return ErrInvalidModel
+	}
+	if _, exist := r.apiKeys[key.ID]; exist {
+		return ErrDuplicate
+	}
+	stored := *key
+	r.apiKeys[key.ID] = &stored
+	r.userAPIKeys[key.UserID] = append(r.userAPIKeys[key.UserID], key.ID)
+	return nil
+}
+
+// APIKeyGetByID - возвращает API-ключ по его id либо ErrNotFound.
+func (r *MemoryRepo) APIKeyGetByID(_ context.Context, id string) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.apiKeys[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	result := *key
+	return &result, nil
+}
+
+// APIKeyGetByUserID - возвращает API-ключи пользователя в порядке создания.
+func (r *MemoryRepo) APIKeyGetByUserID(_ context.Context, userID uint) ([]models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	index := r.userAPIKeys[userID]
+	if len(index) == 0 {
+		return nil, nil
+	}
+	result := make([]models.APIKey, 0, len(index))
+	for _, id := range index {
+		result = append(result, *r.apiKeys[id])
+	}
+	return result, nil
+}
+
+// APIKeyRevoke - отзывает API-ключ пользователя.
+// Если ключ не найден, принадлежит другому пользователю или уже отозван, возвращает ErrNotFound.
+func (r *MemoryRepo) APIKeyRevoke(_ context.Context, userID uint, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.apiKeys[id]
+	if !ok || key.UserID != userID || key.Revoked() {
+		return ErrNotFound
+	}
+	key.RevokedAt = &at
+	return nil
+}
+
+// TeamCreate - добавляет новую команду и ее владельца ownerID.
+// Если владелец не найден, возвращает ErrNotFound.
+// Если команда с таким id уже существует, возвращает ErrDuplicate.
+func (r *MemoryRepo) TeamCreate(_ context.Context, team *models.Team, ownerID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if team == nil {
+		return ErrInvalidModel
+	}
+	if _, exist := r.users[ownerID]; !exist {
+		return ErrNotFound
+	}
+	autoIncrement(&team.ID, &r.nextTeamID)
+	if _, exist := r.teams[team.ID]; exist {
+		return ErrDuplicate
+	}
+	stored := *team
+	r.teams[team.ID] = &stored
+	r.teamMembers[team.ID] = map[uint]models.TeamRole{ownerID: models.TeamRoleOwner}
+	return nil
+}
+
+// TeamGetByID - возвращает команду по ее id либо ErrNotFound.
+func (r *MemoryRepo) TeamGetByID(_ context.Context, id uint) (*models.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	team, ok := r.teams[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	result := *team
+	return &result, nil
+}
+
+// TeamGetByUserID - возвращает команды пользователя и его роли в них в порядке создания команд.
+func (r *MemoryRepo) TeamGetByUserID(_ context.Context, userID uint) ([]models.TeamMembership, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []models.TeamMembership
+	for id, members := range r.teamMembers {
+		if role, ok := members[userID]; ok {
+			result = append(result, models.TeamMembership{Team: *r.teams[id], Role: role})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// TeamMemberGet - возвращает участника команды либо ErrNotFound.
+func (r *MemoryRepo) TeamMemberGet(_ context.Context, teamID, userID uint) (*models.TeamMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	role, ok := r.teamMembers[teamID][userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &models.TeamMember{TeamID: teamID, UserID: userID, Role: role}, nil
+}
+
+// TeamMemberGetByTeamID - возвращает участников команды в порядке их id.
+func (r *MemoryRepo) TeamMemberGetByTeamID(_ context.Context, teamID uint) ([]models.TeamMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	members := r.teamMembers[teamID]
+	if len(members) == 0 {
+		return nil, nil
+	}
+	result := make([]models.TeamMember, 0, len(members))
+	for userID, role := range members {
+		result = append(result, models.TeamMember{TeamID: teamID, UserID: userID, Role: role})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserID < result[j].UserID })
+	return result, nil
+}
+
+// TeamMemberSet - добавляет участника команды или изменяет его роль.
+// Если команда или пользователь не найдены, возвращает ErrNotFound.
+// Если изменение роли оставит команду без владельцев, возвращает ErrLastOwner.
+func (r *MemoryRepo) TeamMemberSet(_ context.Context, member *models.TeamMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if member == nil {
+		return ErrInvalidModel
+	}
+	if _, exist := r.teams[member.TeamID]; !exist {
+		return ErrNotFound
+	}
+	if _, exist := r.users[member.UserID]; !exist {
+		return ErrNotFound
+	}
+	if member.Role != models.TeamRoleOwner && r.lastOwner(member.TeamID, member.UserID) {
+		return ErrLastOwner
+	}
+	r.teamMembers[member.TeamID][member.UserID] = member.Role
+	return nil
+}
+
+// TeamMemberDelete - удаляет участника команды.
+// Если пользователь не состоит в команде, возвращает ErrNotFound.
+// Если удаление оставит команду без владельцев, возвращает ErrLastOwner.
+func (r *MemoryRepo) TeamMemberDelete(_ context.Context, teamID, userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.teamMembers[teamID][userID]; !ok {
+		return ErrNotFound
+	}
+	if r.lastOwner(teamID, userID) {
+		return ErrLastOwner
+	}
+	delete(r.teamMembers[teamID], userID)
+	return nil
+}
+
+// lastOwner - возвращает true, если userID - единственный владелец команды teamID.
+// Вызывается под блокировкой mu.
+func (r *MemoryRepo) lastOwner(teamID, userID uint) bool {
+	members := r.teamMembers[teamID]
+	if members[userID] != models.TeamRoleOwner {
+		return false
+	}
+	for id, role := range members {
+		if id != userID && role == models.TeamRoleOwner {
+			return false
+		}
+	}
+	return true
+}
+
+// StorageStats - возвращает статистику хранилища.
+// Размер хранилища в памяти не учитывается.
+func (r *MemoryRepo) StorageStats(_ context.Context, now time.Time) (*models.StorageStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	stats := &models.StorageStats{
+		URLs:  len(r.shortURLs),
+		Users: len(r.users),
+	}
+	activeUsers := make(map[uint]struct{})
+	for _, u := range r.shortURLs {
+		if u.Deleted {
+			stats.DeletedURLs++
+		} else {
+			stats.ActiveURLs++
+			activeUsers[u.UserID] = struct{}{}
+		}
+		if !u.CreatedAt.Before(now.Add(-time.Hour)) {
+			stats.URLsLastHour++
+		}
+		if !u.CreatedAt.Before(now.Add(-24 * time.Hour)) {
+			stats.URLsLastDay++
+		}
+	}
+	stats.ActiveUsers = len(activeUsers)
+	return stats, nil
+}
+
 // Close - закрывает репозиторий для записи.
 // В этой реализации не делает ничего.
 func (r *MemoryRepo) Close() error {
@@ -183,10 +745,78 @@ func (r *MemoryRepo) Close() error {
 func (r *MemoryRepo) userPurge(id uint) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if user, exist := r.users[id]; exist {
+		delete(r.userEmails, user.Email)
+		delete(r.userSubjects, user.OIDCSubject)
+	}
 	delete(r.users, id)
 	delete(r.userShortURLs, id)
 }
 
+// userRestoreCredentials - восстанавливает прежние учетные данные пользователя.
+// Вызывается при неудачной попытке их изменения в AOFRepo.UserSetCredentials.
+func (r *MemoryRepo) userRestoreCredentials(prev models.User) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, exist := r.users[prev.ID]; exist {
+		r.setCredentials(user, prev.Email, prev.PasswordHash)
+	}
+}
+
+// setCredentials - устанавливает учетные данные пользователя и обновляет индекс по email.
+// Вызывается под блокировкой mu.
+func (r *MemoryRepo) setCredentials(user *models.User, email, passwordHash string) {
+	if user.Email != "" {
+		delete(r.userEmails, user.Email)
+	}
+	user.Email, user.PasswordHash = email, passwordHash
+	if email != "" {
+		r.userEmails[email] = user.ID
+	}
+}
+
+// userRestoreOIDCSubject - восстанавливает прежний идентификатор пользователя у провайдера OpenID Connect.
+// Вызывается при неудачной попытке его изменения в AOFRepo.UserSetOIDCSubject.
+func (r *MemoryRepo) userRestoreOIDCSubject(prev models.User) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, exist := r.users[prev.ID]; exist {
+		r.setOIDCSubject(user, prev.OIDCSubject)
+	}
+}
+
+// userSetTokenGeneration - устанавливает поколение токенов пользователя.
+// Вызывается при неудачной попытке его изменения в AOFRepo.UserRevokeTokens и при загрузке AOF-файла.
+func (r *MemoryRepo) userSetTokenGeneration(id, generation uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, exist := r.users[id]; exist {
+		user.TokenGeneration = generation
+	}
+}
+
+// userRestorePlan - восстанавливает прежний тарифный план пользователя.
+// Вызывается при неудачной попытке его изменения в AOFRepo.UserSetPlan.
+func (r *MemoryRepo) userRestorePlan(prev models.User) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user, exist := r.users[prev.ID]; exist {
+		user.Plan = prev.Plan
+	}
+}
+
+// setOIDCSubject - устанавливает идентификатор пользователя у провайдера OpenID Connect
+// и обновляет индекс по нему. Вызывается под блокировкой mu.
+func (r *MemoryRepo) setOIDCSubject(user *models.User, subject string) {
+	if user.OIDCSubject != "" {
+		delete(r.userSubjects, user.OIDCSubject)
+	}
+	user.OIDCSubject = subject
+	if subject != "" {
+		r.userSubjects[subject] = user.ID
+	}
+}
+
 // shortURLPurge - удаляет короткую ссылку, в тч из индекса ссылок пользователя.
 // Вызывается при неудачной попытке создания короткой ссылки в AOFRepo.ShortURLCreate.
 func (r *MemoryRepo) shortURLPurge(id string) {
@@ -197,11 +827,59 @@ func (r *MemoryRepo) shortURLPurge(id string) {
 		userID := shortURL.UserID
 		r.userShortURLs[userID] = findAndDelete(r.userShortURLs[userID], shortURL.ID)
 		// Удаляем короткую ссылку
-		delete(r.originalURLIdx, shortURL.OriginalURL)
+		r.unindexOriginalURL(shortURL)
 		delete(r.shortURLs, id)
 	}
 }
 
+// shortURLReplace - заменяет хранимую короткую ссылку на shortURL.
+// Вызывается при неудачной попытке изменения короткой ссылки в AOFRepo.ShortURLUpdate.
+func (r *MemoryRepo) shortURLReplace(shortURL *models.ShortURL) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, exist := r.shortURLs[shortURL.ID]; exist {
+		r.unindexOriginalURL(stored)
+		r.shortURLs[shortURL.ID] = shortURL
+		r.indexOriginalURL(shortURL)
+	}
+}
+
+// originalURLKey - ключ индекса простых ссылок: оригинальный URL различается для каждого пользователя
+type originalURLKey struct {
+	userID      uint
+	originalURL string
+}
+
+// newOriginalURLKey - возвращает ключ индекса простых ссылок для shortURL
+func newOriginalURLKey(shortURL *models.ShortURL) originalURLKey {
+	return originalURLKey{userID: shortURL.UserID, originalURL: shortURL.OriginalURL}
+}
+
+// indexOriginalURL - добавляет ссылку в индекс, если она простая. Вызывается под блокировкой mu.
+func (r *MemoryRepo) indexOriginalURL(shortURL *models.ShortURL) {
+	if shortURL.Plain() {
+		r.originalURLIdx[newOriginalURLKey(shortURL)] = shortURL.ID
+	}
+}
+
+// unindexOriginalURL - удаляет ссылку из индекса простых ссылок. Вызывается под блокировкой mu.
+func (r *MemoryRepo) unindexOriginalURL(shortURL *models.ShortURL) {
+	key := newOriginalURLKey(shortURL)
+	if r.originalURLIdx[key] == shortURL.ID {
+		delete(r.originalURLIdx, key)
+	}
+}
+
+// variantUnclick - уменьшает счетчик переходов по варианту сплит-ссылки на n.
+// Вызывается при неудачной попытке записи переходов в AOFRepo.ShortURLVariantClick.
+func (r *MemoryRepo) variantUnclick(id string, variant int, n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.variantClicks[id][variant] >= n {
+		r.variantClicks[id][variant] -= n
+	}
+}
+
 // shortURLRestore - восстанавливает короткую ссылку.
 // Вызывается при неудачной попытке создания короткой ссылки в AOFRepo.ShortURLDeleteBatch.
 func (r *MemoryRepo) shortURLRestore(id string) {
@@ -213,6 +891,63 @@ func (r *MemoryRepo) shortURLRestore(id string) {
 	}
 }
 
+// apiKeyPurge - удаляет API-ключ, в тч из индекса ключей пользователя.
+// Вызывается при неудачной попытке создания ключа в AOFRepo.APIKeyCreate.
+func (r *MemoryRepo) apiKeyPurge(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key, exist := r.apiKeys[id]; exist {
+		r.userAPIKeys[key.UserID] = findAndDelete(r.userAPIKeys[key.UserID], id)
+		delete(r.apiKeys, id)
+	}
+}
+
+// apiKeyRestore - отменяет отзыв API-ключа.
+// Вызывается при неудачной попытке отзыва ключа в AOFRepo.APIKeyRevoke.
+func (r *MemoryRepo) apiKeyRestore(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key, exist := r.apiKeys[id]; exist {
+		key.RevokedAt = nil
+	}
+}
+
+// canEdit - возвращает true, если пользователь может изменять и удалять ссылку:
+// личную ссылку - создавший ее пользователь, ссылку команды - участник команды с ролью owner или editor.
+// Вызывается под блокировкой mu.
+func (r *MemoryRepo) canEdit(shortURL *models.ShortURL, userID uint) bool {
+	if shortURL.TeamID == 0 {
+		return shortURL.UserID == userID
+	}
+	return r.teamMembers[shortURL.TeamID][userID].CanEdit()
+}
+
+// teamPurge - удаляет команду и ее участников.
+// Вызывается при неудачной попытке создания команды в AOFRepo.TeamCreate.
+func (r *MemoryRepo) teamPurge(id uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.teams, id)
+	delete(r.teamMembers, id)
+}
+
+// teamMemberRestore - восстанавливает прежнее состояние участника команды: роль prev
+// либо отсутствие в команде, если prev равен nil.
+// Вызывается при неудачной попытке изменения участника в AOFRepo.TeamMemberSet и AOFRepo.TeamMemberDelete.
+func (r *MemoryRepo) teamMemberRestore(teamID, userID uint, prev *models.TeamMember) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	members, exist := r.teamMembers[teamID]
+	if !exist {
+		return
+	}
+	if prev == nil {
+		delete(members, userID)
+		return
+	}
+	members[userID] = prev.Role
+}
+
 // autoIncrement - устанавливает значение id и next
 // таким образом, чтобы next всегда был больше id.
 //
@@ -2,11 +2,14 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
 	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/pkg/hll"
 )
 
 type memoryRepoSuite struct {
@@ -87,6 +90,39 @@ func (suite *memoryRepoSuite) TestShortURLCreate() {
 	suite.Equal(ErrInvalidModel, suite.repo.ShortURLCreate(context.Background(), nil))
 }
 
+func (suite *memoryRepoSuite) TestShortURLGetByOriginalURL() {
+	ctx := context.Background()
+	plain := &models.ShortURL{ID: "plain", OriginalURL: "https://www.example.com", UserID: 1}
+	suite.Require().NoError(suite.repo.ShortURLCreate(ctx, plain))
+	actual, err := suite.repo.ShortURLGetByOriginalURL(ctx, 1, plain.OriginalURL)
+	suite.NoError(err)
+	suite.Equal(plain, actual)
+
+	// Простые ссылки различаются по пользователю
+	_, err = suite.repo.ShortURLGetByOriginalURL(ctx, 2, plain.OriginalURL)
+	suite.ErrorIs(err, ErrNotFound)
+	suite.NoError(suite.repo.ShortURLCreate(ctx, &models.ShortURL{ID: "other", OriginalURL: plain.OriginalURL, UserID: 2}))
+	suite.ErrorIs(suite.repo.ShortURLCreate(ctx, &models.ShortURL{ID: "again", OriginalURL: plain.OriginalURL, UserID: 1}), ErrDuplicate)
+
+	// Ссылка с правилами не является простой
+	rules := []models.RedirectRule{{URL: "https://www.example.net"}}
+	routed := &models.ShortURL{ID: "routed", OriginalURL: plain.OriginalURL, UserID: 1, Rules: rules}
+	suite.NoError(suite.repo.ShortURLCreate(ctx, routed))
+	actual, err = suite.repo.ShortURLGetByOriginalURL(ctx, 1, plain.OriginalURL)
+	suite.NoError(err)
+	suite.Equal(plain.ID, actual.ID)
+
+	// Ссылка перестает быть простой и снова становится простой
+	suite.NoError(suite.repo.ShortURLUpdate(ctx, &models.ShortURL{ID: plain.ID, UserID: 1, Rules: rules}))
+	_, err = suite.repo.ShortURLGetByOriginalURL(ctx, 1, plain.OriginalURL)
+	suite.ErrorIs(err, ErrNotFound)
+	suite.NoError(suite.repo.ShortURLUpdate(ctx, &models.ShortURL{ID: routed.ID, UserID: 1}))
+	suite.ErrorIs(suite.repo.ShortURLUpdate(ctx, &models.ShortURL{ID: plain.ID, UserID: 1}), ErrDuplicate)
+	actual, err = suite.repo.ShortURLGetByOriginalURL(ctx, 1, plain.OriginalURL)
+	suite.NoError(err)
+	suite.Equal(routed.ID, actual.ID)
+}
+
 func (suite *memoryRepoSuite) TestShortURLGetById() {
 	// Создаем первую сокращенную ссылку
 	suite.NoError(suite.repo.ShortURLCreate(context.Background(), suite.testShortURLs[0]))
@@ -197,6 +233,350 @@ func (suite *memoryRepoSuite) TestShortURLCount() {
 	suite.Equal(4, count)
 }
 
+func (suite *memoryRepoSuite) TestClicks() {
+	now := time.Now()
+	clicks := []models.Click{
+		{ShortURLID: "12345", Time: now.Add(-2 * time.Hour), Device: models.DeviceIOS},
+		{ShortURLID: "12345", Time: now.Add(-time.Hour), Device: models.DeviceAndroid},
+		{ShortURLID: "67890", Time: now, Device: models.DeviceDesktop},
+		{ShortURLID: "12345", Time: now, RefererHost: "example.com", Device: models.DeviceDesktop},
+	}
+	suite.NoError(suite.repo.ClickAddBatch(context.Background(), clicks))
+
+	actual, err := suite.repo.ClickGetByShortURLID(context.Background(), "12345", time.Time{}, time.Time{})
+	suite.NoError(err)
+	suite.Equal([]models.Click{clicks[0], clicks[1], clicks[3]}, actual)
+
+	actual, err = suite.repo.ClickGetByShortURLID(context.Background(), "12345", now.Add(-time.Hour), now)
+	suite.NoError(err)
+	suite.Equal([]models.Click{clicks[1]}, actual)
+
+	actual, err = suite.repo.ClickGetByShortURLID(context.Background(), "unknown", time.Time{}, time.Time{})
+	suite.NoError(err)
+	suite.Empty(actual)
+}
+
+func (suite *memoryRepoSuite) TestClickIterate() {
+	now := time.Now()
+	clicks := []models.Click{
+		{ShortURLID: "12345", Time: now.Add(-2 * time.Hour), Device: models.DeviceIOS},
+		{ShortURLID: "12345", Time: now.Add(-time.Hour), Device: models.DeviceAndroid},
+		{ShortURLID: "12345", Time: now, Device: models.DeviceDesktop},
+	}
+	suite.NoError(suite.repo.ClickAddBatch(context.Background(), clicks))
+
+	var actual []models.Click
+	suite.NoError(suite.repo.ClickIterate(context.Background(), "12345", now.Add(-time.Hour), time.Time{}, func(c models.Click) error {
+		actual = append(actual, c)
+		return nil
+	}))
+	suite.Equal([]models.Click{clicks[1], clicks[2]}, actual)
+
+	// Ошибка fn прекращает перебор
+	errStop := errors.New("stop")
+	n := 0
+	err := suite.repo.ClickIterate(context.Background(), "12345", time.Time{}, time.Time{}, func(models.Click) error {
+		n++
+		return errStop
+	})
+	suite.ErrorIs(err, errStop)
+	suite.Equal(1, n)
+
+	// Перебор прекращается при отмене контекста
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	suite.ErrorIs(suite.repo.ClickIterate(ctx, "12345", time.Time{}, time.Time{}, func(models.Click) error { return nil }), context.Canceled)
+}
+
+func (suite *memoryRepoSuite) TestStorageStats() {
+	now := time.Now()
+	suite.testShortURLs[0].CreatedAt = now.Add(-10 * time.Minute)
+	suite.testShortURLs[1].CreatedAt = now.Add(-2 * time.Hour)
+	suite.testShortURLs[2].CreatedAt = now.Add(-48 * time.Hour)
+	suite.testShortURLs[3].CreatedAt = now.Add(-30 * time.Minute)
+	suite.NoError(suite.repo.UserCreate(context.Background(), &models.User{}))
+	suite.NoError(suite.repo.UserCreate(context.Background(), &models.User{}))
+	suite.NoError(suite.repo.UserCreate(context.Background(), &models.User{}))
+	for _, u := range suite.testShortURLs {
+		suite.NoError(suite.repo.ShortURLCreate(context.Background(), u))
+	}
+	// У второго пользователя не остается неудаленных ссылок
+	suite.NoError(suite.repo.ShortURLDelete(context.Background(), 2, "bbbbb"))
+
+	stats, err := suite.repo.StorageStats(context.Background(), now)
+	suite.NoError(err)
+	suite.Equal(&models.StorageStats{
+		URLs:         4,
+		ActiveURLs:   3,
+		DeletedURLs:  1,
+		URLsLastHour: 2,
+		URLsLastDay:  3,
+		Users:        3,
+		ActiveUsers:  1,
+	}, stats)
+}
+
+func (suite *memoryRepoSuite) TestVisitorSketches() {
+	day := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	sketch := func(visitors ...string) *hll.Sketch {
+		s, err := hll.New(hll.DefaultPrecision)
+		suite.Require().NoError(err)
+		for _, v := range visitors {
+			s.Add([]byte(v))
+		}
+		return s
+	}
+	suite.NoError(suite.repo.VisitorSketchMerge(context.Background(), []models.VisitorSketch{
+		{ShortURLID: "12345", Day: day, Sketch: sketch("a", "b")},
+		{ShortURLID: "12345", Day: day.AddDate(0, 0, 1), Sketch: sketch("a")},
+		{ShortURLID: "67890", Day: day, Sketch: sketch("c")},
+	}))
+	// Скетчи за те же сутки объединяются
+	suite.NoError(suite.repo.VisitorSketchMerge(context.Background(), []models.VisitorSketch{
+		{ShortURLID: "12345", Day: day, Sketch: sketch("b", "c")},
+	}))
+
+	actual, err := suite.repo.VisitorSketchGet(context.Background(), "12345", day, day.AddDate(0, 0, 2))
+	suite.NoError(err)
+	suite.Require().Len(actual, 2)
+	suite.Equal(day, actual[0].Day)
+	suite.Equal(uint64(3), actual[0].Sketch.Count())
+	suite.Equal(day.AddDate(0, 0, 1), actual[1].Day)
+	suite.Equal(uint64(1), actual[1].Sketch.Count())
+
+	actual, err = suite.repo.VisitorSketchGet(context.Background(), "12345", day.AddDate(0, 0, 1), day.AddDate(0, 0, 2))
+	suite.NoError(err)
+	suite.Len(actual, 1)
+
+	actual, err = suite.repo.VisitorSketchGet(context.Background(), "unknown", day, day.AddDate(0, 0, 2))
+	suite.NoError(err)
+	suite.Empty(actual)
+}
+
+func (suite *memoryRepoSuite) TestUserCredentials() {
+	ctx := context.Background()
+	user1, user2 := &models.User{}, &models.User{}
+	suite.NoError(suite.repo.UserCreate(ctx, user1))
+	suite.NoError(suite.repo.UserCreate(ctx, user2))
+	_, err := suite.repo.UserGetByEmail(ctx, "")
+	suite.ErrorIs(err, ErrNotFound)
+
+	suite.NoError(suite.repo.UserSetCredentials(ctx, user1.ID, "user@example.com", "hash"))
+	suite.ErrorIs(suite.repo.UserSetCredentials(ctx, user2.ID, "user@example.com", "hash"), ErrDuplicate)
+	suite.ErrorIs(suite.repo.UserSetCredentials(ctx, 100, "other@example.com", "hash"), ErrNotFound)
+	actual, err := suite.repo.UserGetByEmail(ctx, "user@example.com")
+	suite.NoError(err)
+	suite.Equal(&models.User{ID: user1.ID, Email: "user@example.com", PasswordHash: "hash"}, actual)
+
+	// Смена email освобождает прежний
+	suite.NoError(suite.repo.UserSetCredentials(ctx, user1.ID, "new@example.com", "hash2"))
+	_, err = suite.repo.UserGetByEmail(ctx, "user@example.com")
+	suite.ErrorIs(err, ErrNotFound)
+	actual, err = suite.repo.UserGetByID(ctx, user1.ID)
+	suite.NoError(err)
+	suite.Equal("new@example.com", actual.Email)
+}
+
+func (suite *memoryRepoSuite) TestUserOIDCSubject() {
+	ctx := context.Background()
+	user1, user2 := &models.User{}, &models.User{}
+	suite.NoError(suite.repo.UserCreate(ctx, user1))
+	suite.NoError(suite.repo.UserCreate(ctx, user2))
+	_, err := suite.repo.UserGetByOIDCSubject(ctx, "")
+	suite.ErrorIs(err, ErrNotFound)
+
+	suite.NoError(suite.repo.UserSetOIDCSubject(ctx, user1.ID, "https://idp.example.com 42"))
+	suite.ErrorIs(suite.repo.UserSetOIDCSubject(ctx, user2.ID, "https://idp.example.com 42"), ErrDuplicate)
+	suite.ErrorIs(suite.repo.UserSetOIDCSubject(ctx, 100, "https://idp.example.com 43"), ErrNotFound)
+	actual, err := suite.repo.UserGetByOIDCSubject(ctx, "https://idp.example.com 42")
+	suite.NoError(err)
+	suite.Equal(&models.User{ID: user1.ID, OIDCSubject: "https://idp.example.com 42"}, actual)
+	suite.True(actual.Registered())
+	_, err = suite.repo.UserGetByOIDCSubject(ctx, "https://idp.example.com 43")
+	suite.ErrorIs(err, ErrNotFound)
+}
+
+func (suite *memoryRepoSuite) TestUserRevokeTokens() {
+	ctx := context.Background()
+	user := &models.User{}
+	suite.NoError(suite.repo.UserCreate(ctx, user))
+	generation, err := suite.repo.UserRevokeTokens(ctx, user.ID)
+	suite.NoError(err)
+	suite.Equal(uint(1), generation)
+	generation, err = suite.repo.UserRevokeTokens(ctx, user.ID)
+	suite.NoError(err)
+	suite.Equal(uint(2), generation)
+	actual, err := suite.repo.UserGetByID(ctx, user.ID)
+	suite.NoError(err)
+	suite.Equal(uint(2), actual.TokenGeneration)
+	_, err = suite.repo.UserRevokeTokens(ctx, 100)
+	suite.ErrorIs(err, ErrNotFound)
+}
+
+func (suite *memoryRepoSuite) TestUserSetPlan() {
+	ctx := context.Background()
+	user := &models.User{}
+	suite.NoError(suite.repo.UserCreate(ctx, user))
+	suite.NoError(suite.repo.UserSetPlan(ctx, user.ID, "pro"))
+	actual, err := suite.repo.UserGetByID(ctx, user.ID)
+	suite.NoError(err)
+	suite.Equal("pro", actual.Plan)
+	suite.ErrorIs(suite.repo.UserSetPlan(ctx, 100, "pro"), ErrNotFound)
+}
+
+func (suite *memoryRepoSuite) TestShortURLCountByUserID() {
+	ctx := context.Background()
+	now := time.Now()
+	suite.testShortURLs[0].CreatedAt = now.Add(-10 * time.Minute)
+	suite.testShortURLs[1].CreatedAt = now.Add(-2 * time.Hour)
+	suite.testShortURLs[2].CreatedAt = now.Add(-48 * time.Hour)
+	suite.testShortURLs[3].CreatedAt = now
+	for _, u := range suite.testShortURLs {
+		suite.NoError(suite.repo.ShortURLCreate(ctx, u))
+	}
+	suite.NoError(suite.repo.ShortURLDelete(ctx, 1, suite.testShortURLs[0].ID))
+
+	// Удаленные ссылки не считаются активными, но учитываются среди созданных за период
+	active, created, err := suite.repo.ShortURLCountByUserID(ctx, 1, now.Add(-24*time.Hour))
+	suite.NoError(err)
+	suite.Equal(2, active)
+	suite.Equal(2, created)
+
+	active, created, err = suite.repo.ShortURLCountByUserID(ctx, 100, now.Add(-24*time.Hour))
+	suite.NoError(err)
+	suite.Zero(active)
+	suite.Zero(created)
+}
+
+func (suite *memoryRepoSuite) TestShortURLCreateWithLimit() {
+	ctx := context.Background()
+	suite.Require().NoError(suite.repo.UserCreate(ctx, &models.User{}))
+	now := time.Now()
+	limit := Limit{MaxActive: 2, MaxCreated: 3, Since: now.Add(-24 * time.Hour)}
+	for _, u := range suite.testShortURLs[:3] {
+		u.CreatedAt = now
+	}
+
+	// Пакет превышает ограничение активных ссылок и не создается частично
+	suite.ErrorIs(suite.repo.ShortURLCreateWithLimit(ctx, 1, suite.testShortURLs[:3], limit), ErrLimitExceeded)
+	_, err := suite.repo.ShortURLGetByID(ctx, suite.testShortURLs[0].ID)
+	suite.ErrorIs(err, ErrNotFound)
+
+	suite.NoError(suite.repo.ShortURLCreateWithLimit(ctx, 1, suite.testShortURLs[:2], limit))
+	suite.ErrorIs(suite.repo.ShortURLCreateWithLimit(ctx, 1, suite.testShortURLs[2:3], limit), ErrLimitExceeded)
+	suite.ErrorIs(suite.repo.ShortURLCreateWithLimit(ctx, 1, suite.testShortURLs[:1], Limit{}), ErrDuplicate)
+
+	// Удаленная ссылка освобождает ограничение активных ссылок, но не ограничение созданных за период
+	suite.NoError(suite.repo.ShortURLDelete(ctx, 1, suite.testShortURLs[0].ID))
+	suite.NoError(suite.repo.ShortURLCreateWithLimit(ctx, 1, suite.testShortURLs[2:3], limit))
+	suite.NoError(suite.repo.ShortURLDelete(ctx, 1, suite.testShortURLs[1].ID))
+	next := &models.ShortURL{ID: "ccccc", OriginalURL: "https://www.yandex.ru", UserID: 1, CreatedAt: now}
+	suite.ErrorIs(suite.repo.ShortURLCreateWithLimit(ctx, 1, []*models.ShortURL{next}, limit), ErrLimitExceeded)
+
+	// Ссылки другого пользователя и несуществующий пользователь
+	suite.ErrorIs(suite.repo.ShortURLCreateWithLimit(ctx, 1, suite.testShortURLs[3:], Limit{}), ErrInvalidModel)
+	suite.ErrorIs(suite.repo.ShortURLCreateWithLimit(ctx, 2, suite.testShortURLs[3:], Limit{}), ErrNotFound)
+}
+
+func (suite *memoryRepoSuite) TestAPIKeys() {
+	ctx := context.Background()
+	key1 := &models.APIKey{ID: "key1", UserID: 1, Name: "CI", Scopes: []models.APIScope{models.APIScopeRead}, Hash: "hash1"}
+	key2 := &models.APIKey{ID: "key2", UserID: 1, Name: "Bot", Scopes: []models.APIScope{models.APIScopeCreate}, Hash: "hash2"}
+	suite.NoError(suite.repo.APIKeyCreate(ctx, key1))
+	suite.NoError(suite.repo.APIKeyCreate(ctx, key2))
+	suite.ErrorIs(suite.repo.APIKeyCreate(ctx, key1), ErrDuplicate)
+
+	actual, err := suite.repo.APIKeyGetByID(ctx, "key1")
+	suite.NoError(err)
+	suite.Equal(key1, actual)
+	_, err = suite.repo.APIKeyGetByID(ctx, "unknown")
+	suite.ErrorIs(err, ErrNotFound)
+
+	keys, err := suite.repo.APIKeyGetByUserID(ctx, 1)
+	suite.NoError(err)
+	suite.Equal([]models.APIKey{*key1, *key2}, keys)
+	keys, err = suite.repo.APIKeyGetByUserID(ctx, 2)
+	suite.NoError(err)
+	suite.Nil(keys)
+
+	// Отзывать можно только собственный неотозванный ключ
+	at := time.Now().UTC()
+	suite.ErrorIs(suite.repo.APIKeyRevoke(ctx, 2, "key1", at), ErrNotFound)
+	suite.NoError(suite.repo.APIKeyRevoke(ctx, 1, "key1", at))
+	suite.ErrorIs(suite.repo.APIKeyRevoke(ctx, 1, "key1", at), ErrNotFound)
+	actual, err = suite.repo.APIKeyGetByID(ctx, "key1")
+	suite.NoError(err)
+	suite.True(actual.Revoked())
+	suite.Equal(at, *actual.RevokedAt)
+}
+
+func (suite *memoryRepoSuite) TestTeams() {
+	ctx := context.Background()
+	owner, editor, viewer := &models.User{}, &models.User{}, &models.User{}
+	suite.NoError(suite.repo.UserCreate(ctx, owner))
+	suite.NoError(suite.repo.UserCreate(ctx, editor))
+	suite.NoError(suite.repo.UserCreate(ctx, viewer))
+
+	team := &models.Team{Name: "Marketing"}
+	suite.NoError(suite.repo.TeamCreate(ctx, team, owner.ID))
+	suite.Equal(uint(1), team.ID)
+	suite.ErrorIs(suite.repo.TeamCreate(ctx, &models.Team{Name: "Sales"}, 100), ErrNotFound)
+	actual, err := suite.repo.TeamGetByID(ctx, team.ID)
+	suite.NoError(err)
+	suite.Equal(team, actual)
+	_, err = suite.repo.TeamGetByID(ctx, 100)
+	suite.ErrorIs(err, ErrNotFound)
+
+	// Добавляем участников и меняем роль
+	suite.NoError(suite.repo.TeamMemberSet(ctx, &models.TeamMember{TeamID: team.ID, UserID: editor.ID, Role: models.TeamRoleViewer}))
+	suite.NoError(suite.repo.TeamMemberSet(ctx, &models.TeamMember{TeamID: team.ID, UserID: editor.ID, Role: models.TeamRoleEditor}))
+	suite.NoError(suite.repo.TeamMemberSet(ctx, &models.TeamMember{TeamID: team.ID, UserID: viewer.ID, Role: models.TeamRoleViewer}))
+	suite.ErrorIs(suite.repo.TeamMemberSet(ctx, &models.TeamMember{TeamID: 100, UserID: viewer.ID, Role: models.TeamRoleViewer}), ErrNotFound)
+	suite.ErrorIs(suite.repo.TeamMemberSet(ctx, &models.TeamMember{TeamID: team.ID, UserID: 100, Role: models.TeamRoleViewer}), ErrNotFound)
+	// Единственного владельца нельзя понизить или удалить
+	suite.ErrorIs(suite.repo.TeamMemberSet(ctx, &models.TeamMember{TeamID: team.ID, UserID: owner.ID, Role: models.TeamRoleEditor}), ErrLastOwner)
+	suite.ErrorIs(suite.repo.TeamMemberDelete(ctx, team.ID, owner.ID), ErrLastOwner)
+	members, err := suite.repo.TeamMemberGetByTeamID(ctx, team.ID)
+	suite.NoError(err)
+	suite.Equal([]models.TeamMember{
+		{TeamID: team.ID, UserID: owner.ID, Role: models.TeamRoleOwner},
+		{TeamID: team.ID, UserID: editor.ID, Role: models.TeamRoleEditor},
+		{TeamID: team.ID, UserID: viewer.ID, Role: models.TeamRoleViewer},
+	}, members)
+	member, err := suite.repo.TeamMemberGet(ctx, team.ID, editor.ID)
+	suite.NoError(err)
+	suite.Equal(models.TeamRoleEditor, member.Role)
+	teams, err := suite.repo.TeamGetByUserID(ctx, viewer.ID)
+	suite.NoError(err)
+	suite.Equal([]models.TeamMembership{{Team: *team, Role: models.TeamRoleViewer}}, teams)
+
+	// Ссылку команды могут удалить владелец и редактор, но не наблюдатель и не посторонний
+	suite.NoError(suite.repo.ShortURLCreate(ctx, &models.ShortURL{ID: "team1", OriginalURL: "https://team1.com", UserID: owner.ID, TeamID: team.ID}))
+	suite.NoError(suite.repo.ShortURLCreate(ctx, &models.ShortURL{ID: "team2", OriginalURL: "https://team2.com", UserID: owner.ID, TeamID: team.ID}))
+	urls, err := suite.repo.ShortURLGetByTeamID(ctx, team.ID)
+	suite.NoError(err)
+	suite.Len(urls, 2)
+	suite.ErrorIs(suite.repo.ShortURLDelete(ctx, viewer.ID, "team1"), ErrNotFound)
+	suite.ErrorIs(suite.repo.ShortURLDelete(ctx, 100, "team1"), ErrNotFound)
+	suite.NoError(suite.repo.ShortURLDelete(ctx, editor.ID, "team1"))
+	ch := make(chan string, 1)
+	ch <- "team2"
+	close(ch)
+	count, err := suite.repo.ShortURLDeleteBatch(ctx, viewer.ID, ch)
+	suite.NoError(err)
+	suite.Equal(int64(0), count)
+
+	// Удаленный участник теряет доступ к ссылкам команды
+	suite.NoError(suite.repo.TeamMemberDelete(ctx, team.ID, editor.ID))
+	suite.ErrorIs(suite.repo.TeamMemberDelete(ctx, team.ID, editor.ID), ErrNotFound)
+	_, err = suite.repo.TeamMemberGet(ctx, team.ID, editor.ID)
+	suite.ErrorIs(err, ErrNotFound)
+	suite.ErrorIs(suite.repo.ShortURLDelete(ctx, editor.ID, "team2"), ErrNotFound)
+	teams, err = suite.repo.TeamGetByUserID(ctx, editor.ID)
+	suite.NoError(err)
+	suite.Nil(teams)
+}
+
 func (suite *memoryRepoSuite) Test_autoIncrement() {
 	// Создаем первого пользователя
 	user1 := &models.User{}
@@ -0,0 +1,350 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+)
+
+// ObserveFunc - функция наблюдения за операциями репозитория.
+// Вызывается перед операцией op и возвращает контекст для выполнения операции
+// и функцию, которая вызывается после завершения операции с ее ошибкой.
+type ObserveFunc func(ctx context.Context, op string) (context.Context, func(err error))
+
+// ObservedRepo - обертка над IRepo, которая вызывает ObserveFunc для каждой операции репозитория.
+// Используется для сбора метрик и трассировки.
+type ObservedRepo struct {
+	repo    IRepo
+	observe ObserveFunc
+}
+
+// NewObservedRepo - конструктор ObservedRepo.
+func NewObservedRepo(repo IRepo, observe ObserveFunc) *ObservedRepo {
+	return &ObservedRepo{repo: repo, observe: observe}
+}
+
+// ChainObserve - объединяет несколько ObserveFunc в одну.
+// Функции вызываются в порядке перечисления, а функции завершения - в обратном порядке.
+func ChainObserve(observers ...ObserveFunc) ObserveFunc {
+	return func(ctx context.Context, op string) (context.Context, func(error)) {
+		dones := make([]func(error), len(observers))
+		for i, observe := range observers {
+			ctx, dones[i] = observe(ctx, op)
+		}
+		return ctx, func(err error) {
+			for i := len(dones) - 1; i >= 0; i-- {
+				dones[i](err)
+			}
+		}
+	}
+}
+
+// Unwrap - возвращает исходный репозиторий
+func (r *ObservedRepo) Unwrap() IRepo {
+	return r.repo
+}
+
+// Unwrap - возвращает исходный репозиторий, если repo является оберткой над ним.
+// Иначе возвращает repo без изменений.
+func Unwrap(repo IRepo) IRepo {
+	for {
+		w, ok := repo.(interface{ Unwrap() IRepo })
+		if !ok {
+			return repo
+		}
+		repo = w.Unwrap()
+	}
+}
+
+// BackendName - возвращает название хранилища репозитория: postgres, aof или memory.
+func BackendName(repo IRepo) string {
+	switch Unwrap(repo).(type) {
+	case *SQLRepo:
+		return "postgres"
+	case *AOFRepo:
+		return "aof"
+	case *MemoryRepo:
+		return "memory"
+	default:
+		return "unknown"
+	}
+}
+
+// UserCreate - см. IRepo.UserCreate
+func (r *ObservedRepo) UserCreate(ctx context.Context, user *models.User) (err error) {
+	ctx, done := r.observe(ctx, "UserCreate")
+	defer func() { done(err) }()
+	return r.repo.UserCreate(ctx, user)
+}
+
+// UserGetByID - см. IRepo.UserGetByID
+func (r *ObservedRepo) UserGetByID(ctx context.Context, id uint) (_ *models.User, err error) {
+	ctx, done := r.observe(ctx, "UserGetByID")
+	defer func() { done(err) }()
+	return r.repo.UserGetByID(ctx, id)
+}
+
+// UserCount - см. IRepo.UserCount
+func (r *ObservedRepo) UserCount(ctx context.Context) (_ int, err error) {
+	ctx, done := r.observe(ctx, "UserCount")
+	defer func() { done(err) }()
+	return r.repo.UserCount(ctx)
+}
+
+// UserGetByEmail - см. IRepo.UserGetByEmail
+func (r *ObservedRepo) UserGetByEmail(ctx context.Context, email string) (_ *models.User, err error) {
+	ctx, done := r.observe(ctx, "UserGetByEmail")
+	defer func() { done(err) }()
+	return r.repo.UserGetByEmail(ctx, email)
+}
+
+// UserSetCredentials - см. IRepo.UserSetCredentials
+func (r *ObservedRepo) UserSetCredentials(ctx context.Context, id uint, email, passwordHash string) (err error) {
+	ctx, done := r.observe(ctx, "UserSetCredentials")
+	defer func() { done(err) }()
+	return r.repo.UserSetCredentials(ctx, id, email, passwordHash)
+}
+
+// UserGetByOIDCSubject - см. IRepo.UserGetByOIDCSubject
+func (r *ObservedRepo) UserGetByOIDCSubject(ctx context.Context, subject string) (_ *models.User, err error) {
+	ctx, done := r.observe(ctx, "UserGetByOIDCSubject")
+	defer func() { done(err) }()
+	return r.repo.UserGetByOIDCSubject(ctx, subject)
+}
+
+// UserSetOIDCSubject - см. IRepo.UserSetOIDCSubject
+func (r *ObservedRepo) UserSetOIDCSubject(ctx context.Context, id uint, subject string) (err error) {
+	ctx, done := r.observe(ctx, "UserSetOIDCSubject")
+	defer func() { done(err) }()
+	return r.repo.UserSetOIDCSubject(ctx, id, subject)
+}
+
+// UserRevokeTokens - см. IRepo.UserRevokeTokens
+func (r *ObservedRepo) UserRevokeTokens(ctx context.Context, id uint) (_ uint, err error) {
+	ctx, done := r.observe(ctx, "UserRevokeTokens")
+	defer func() { done(err) }()
+	return r.repo.UserRevokeTokens(ctx, id)
+}
+
+// UserSetPlan - см. IRepo.UserSetPlan
+func (r *ObservedRepo) UserSetPlan(ctx context.Context, id uint, plan string) (err error) {
+	ctx, done := r.observe(ctx, "UserSetPlan")
+	defer func() { done(err) }()
+	return r.repo.UserSetPlan(ctx, id, plan)
+}
+
+// ShortURLCreate - см. IRepo.ShortURLCreate
+func (r *ObservedRepo) ShortURLCreate(ctx context.Context, shortURL *models.ShortURL) (err error) {
+	ctx, done := r.observe(ctx, "ShortURLCreate")
+	defer func() { done(err) }()
+	return r.repo.ShortURLCreate(ctx, shortURL)
+}
+
+// ShortURLGetByID - см. IRepo.ShortURLGetByID
+func (r *ObservedRepo) ShortURLGetByID(ctx context.Context, id string) (_ *models.ShortURL, err error) {
+	ctx, done := r.observe(ctx, "ShortURLGetByID")
+	defer func() { done(err) }()
+	return r.repo.ShortURLGetByID(ctx, id)
+}
+
+// ShortURLGetByUserID - см. IRepo.ShortURLGetByUserID
+func (r *ObservedRepo) ShortURLGetByUserID(ctx context.Context, id uint) (_ []models.ShortURL, err error) {
+	ctx, done := r.observe(ctx, "ShortURLGetByUserID")
+	defer func() { done(err) }()
+	return r.repo.ShortURLGetByUserID(ctx, id)
+}
+
+// ShortURLCreateWithLimit - см. IRepo.ShortURLCreateWithLimit
+func (r *ObservedRepo) ShortURLCreateWithLimit(ctx context.Context, userID uint, shortURLs []*models.ShortURL, limit Limit) (err error) {
+	ctx, done := r.observe(ctx, "ShortURLCreateWithLimit")
+	defer func() { done(err) }()
+	return r.repo.ShortURLCreateWithLimit(ctx, userID, shortURLs, limit)
+}
+
+// ShortURLCountByUserID - см. IRepo.ShortURLCountByUserID
+func (r *ObservedRepo) ShortURLCountByUserID(ctx context.Context, userID uint, since time.Time) (active, created int, err error) {
+	ctx, done := r.observe(ctx, "ShortURLCountByUserID")
+	defer func() { done(err) }()
+	return r.repo.ShortURLCountByUserID(ctx, userID, since)
+}
+
+// ShortURLGetByTeamID - см. IRepo.ShortURLGetByTeamID
+func (r *ObservedRepo) ShortURLGetByTeamID(ctx context.Context, teamID uint) (_ []models.ShortURL, err error) {
+	ctx, done := r.observe(ctx, "ShortURLGetByTeamID")
+	defer func() { done(err) }()
+	return r.repo.ShortURLGetByTeamID(ctx, teamID)
+}
+
+// ShortURLGetByOriginalURL - см. IRepo.ShortURLGetByOriginalURL
+func (r *ObservedRepo) ShortURLGetByOriginalURL(ctx context.Context, userID uint, originalURL string) (_ *models.ShortURL, err error) {
+	ctx, done := r.observe(ctx, "ShortURLGetByOriginalURL")
+	defer func() { done(err) }()
+	return r.repo.ShortURLGetByOriginalURL(ctx, userID, originalURL)
+}
+
+// ShortURLUpdate - см. IRepo.ShortURLUpdate
+func (r *ObservedRepo) ShortURLUpdate(ctx context.Context, shortURL *models.ShortURL) (err error) {
+	ctx, done := r.observe(ctx, "ShortURLUpdate")
+	defer func() { done(err) }()
+	return r.repo.ShortURLUpdate(ctx, shortURL)
+}
+
+// ShortURLDelete - см. IRepo.ShortURLDelete
+func (r *ObservedRepo) ShortURLDelete(ctx context.Context, userID uint, id string) (err error) {
+	ctx, done := r.observe(ctx, "ShortURLDelete")
+	defer func() { done(err) }()
+	return r.repo.ShortURLDelete(ctx, userID, id)
+}
+
+// ShortURLDeleteBatch - см. IRepo.ShortURLDeleteBatch
+func (r *ObservedRepo) ShortURLDeleteBatch(ctx context.Context, userID uint, chans ...chan string) (_ int64, err error) {
+	ctx, done := r.observe(ctx, "ShortURLDeleteBatch")
+	defer func() { done(err) }()
+	return r.repo.ShortURLDeleteBatch(ctx, userID, chans...)
+}
+
+// ShortURLVariantClick - см. IRepo.ShortURLVariantClick
+func (r *ObservedRepo) ShortURLVariantClick(ctx context.Context, id string, variant int, n int64) (err error) {
+	ctx, done := r.observe(ctx, "ShortURLVariantClick")
+	defer func() { done(err) }()
+	return r.repo.ShortURLVariantClick(ctx, id, variant, n)
+}
+
+// ShortURLVariantClicks - см. IRepo.ShortURLVariantClicks
+func (r *ObservedRepo) ShortURLVariantClicks(ctx context.Context, id string) (_ map[int]int64, err error) {
+	ctx, done := r.observe(ctx, "ShortURLVariantClicks")
+	defer func() { done(err) }()
+	return r.repo.ShortURLVariantClicks(ctx, id)
+}
+
+// ShortURLCount - см. IRepo.ShortURLCount
+func (r *ObservedRepo) ShortURLCount(ctx context.Context) (_ int, err error) {
+	ctx, done := r.observe(ctx, "ShortURLCount")
+	defer func() { done(err) }()
+	return r.repo.ShortURLCount(ctx)
+}
+
+// StorageStats - см. IRepo.StorageStats
+func (r *ObservedRepo) StorageStats(ctx context.Context, now time.Time) (_ *models.StorageStats, err error) {
+	ctx, done := r.observe(ctx, "StorageStats")
+	defer func() { done(err) }()
+	return r.repo.StorageStats(ctx, now)
+}
+
+// ClickAddBatch - см. IRepo.ClickAddBatch
+func (r *ObservedRepo) ClickAddBatch(ctx context.Context, clicks []models.Click) (err error) {
+	ctx, done := r.observe(ctx, "ClickAddBatch")
+	defer func() { done(err) }()
+	return r.repo.ClickAddBatch(ctx, clicks)
+}
+
+// ClickGetByShortURLID - см. IRepo.ClickGetByShortURLID
+func (r *ObservedRepo) ClickGetByShortURLID(ctx context.Context, id string, from, until time.Time) (_ []models.Click, err error) {
+	ctx, done := r.observe(ctx, "ClickGetByShortURLID")
+	defer func() { done(err) }()
+	return r.repo.ClickGetByShortURLID(ctx, id, from, until)
+}
+
+// ClickIterate - см. IRepo.ClickIterate
+func (r *ObservedRepo) ClickIterate(ctx context.Context, id string, from, until time.Time, fn func(models.Click) error) (err error) {
+	ctx, done := r.observe(ctx, "ClickIterate")
+	defer func() { done(err) }()
+	return r.repo.ClickIterate(ctx, id, from, until, fn)
+}
+
+// VisitorSketchMerge - см. IRepo.VisitorSketchMerge
+func (r *ObservedRepo) VisitorSketchMerge(ctx context.Context, sketches []models.VisitorSketch) (err error) {
+	ctx, done := r.observe(ctx, "VisitorSketchMerge")
+	defer func() { done(err) }()
+	return r.repo.VisitorSketchMerge(ctx, sketches)
+}
+
+// VisitorSketchGet - см. IRepo.VisitorSketchGet
+func (r *ObservedRepo) VisitorSketchGet(ctx context.Context, id string, from, until time.Time) (_ []models.VisitorSketch, err error) {
+	ctx, done := r.observe(ctx, "VisitorSketchGet")
+	defer func() { done(err) }()
+	return r.repo.VisitorSketchGet(ctx, id, from, until)
+}
+
+// APIKeyCreate - см. IRepo.APIKeyCreate
+func (r *ObservedRepo) APIKeyCreate(ctx context.Context, key *models.APIKey) (err error) {
+	ctx, done := r.observe(ctx, "APIKeyCreate")
+	defer func() { done(err) }()
+	return r.repo.APIKeyCreate(ctx, key)
+}
+
+// APIKeyGetByID - см. IRepo.APIKeyGetByID
+func (r *ObservedRepo) APIKeyGetByID(ctx context.Context, id string) (_ *models.APIKey, err error) {
+	ctx, done := r.observe(ctx, "APIKeyGetByID")
+	defer func() { done(err) }()
+	return r.repo.APIKeyGetByID(ctx, id)
+}
+
+// APIKeyGetByUserID - см. IRepo.APIKeyGetByUserID
+func (r *ObservedRepo) APIKeyGetByUserID(ctx context.Context, userID uint) (_ []models.APIKey, err error) {
+	ctx, done := r.observe(ctx, "APIKeyGetByUserID")
+	defer func() { done(err) }()
+	return r.repo.APIKeyGetByUserID(ctx, userID)
+}
+
+// APIKeyRevoke - см. IRepo.APIKeyRevoke
+func (r *ObservedRepo) APIKeyRevoke(ctx context.Context, userID uint, id string, at time.Time) (err error) {
+	ctx, done := r.observe(ctx, "APIKeyRevoke")
+	defer func() { done(err) }()
+	return r.repo.APIKeyRevoke(ctx, userID, id, at)
+}
+
+// TeamCreate - см. IRepo.TeamCreate
+func (r *ObservedRepo) TeamCreate(ctx context.Context, team *models.Team, ownerID uint) (err error) {
+	ctx, done := r.observe(ctx, "TeamCreate")
+	defer func() { done(err) }()
+	return r.repo.TeamCreate(ctx, team, ownerID)
+}
+
+// TeamGetByID - см. IRepo.TeamGetByID
+func (r *ObservedRepo) TeamGetByID(ctx context.Context, id uint) (_ *models.Team, err error) {
+	ctx, done := r.observe(ctx, "TeamGetByID")
+	defer func() { done(err) }()
+	return r.repo.TeamGetByID(ctx, id)
+}
+
+// TeamGetByUserID - см. IRepo.TeamGetByUserID
+func (r *ObservedRepo) TeamGetByUserID(ctx context.Context, userID uint) (_ []models.TeamMembership, err error) {
+	ctx, done := r.observe(ctx, "TeamGetByUserID")
+	defer func() { done(err) }()
+	return r.repo.TeamGetByUserID(ctx, userID)
+}
+
+// TeamMemberGet - см. IRepo.TeamMemberGet
+func (r *ObservedRepo) TeamMemberGet(ctx context.Context, teamID, userID uint) (_ *models.TeamMember, err error) {
+	ctx, done := r.observe(ctx, "TeamMemberGet")
+	defer func() { done(err) }()
+	return r.repo.TeamMemberGet(ctx, teamID, userID)
+}
+
+// TeamMemberGetByTeamID - см. IRepo.TeamMemberGetByTeamID
+func (r *ObservedRepo) TeamMemberGetByTeamID(ctx context.Context, teamID uint) (_ []models.TeamMember, err error) {
+	ctx, done := r.observe(ctx, "TeamMemberGetByTeamID")
+	defer func() { done(err) }()
+	return r.repo.TeamMemberGetByTeamID(ctx, teamID)
+}
+
+// TeamMemberSet - см. IRepo.TeamMemberSet
+func (r *ObservedRepo) TeamMemberSet(ctx context.Context, member *models.TeamMember) (err error) {
+	ctx, done := r.observe(ctx, "TeamMemberSet")
+	defer func() { done(err) }()
+	return r.repo.TeamMemberSet(ctx, member)
+}
+
+// TeamMemberDelete - см. IRepo.TeamMemberDelete
+func (r *ObservedRepo) TeamMemberDelete(ctx context.Context, teamID, userID uint) (err error) {
+	ctx, done := r.observe(ctx, "TeamMemberDelete")
+	defer func() { done(err) }()
+	return r.repo.TeamMemberDelete(ctx, teamID, userID)
+}
+
+// Close - закрывает исходный репозиторий
+func (r *ObservedRepo) Close() error {
+	return r.repo.Close()
+}
@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// jsonColumn - обертка для чтения и записи значения в JSONB-колонку.
+// При записи nil-значение сохраняется как NULL.
+// При чтении NULL оставляет значение без изменений.
+//
+// Для записи передается само значение: jsonColumn{shortURL.QueryTemplate}
+// Для чтения передается указатель на значение: jsonColumn{&shortURL.QueryTemplate}
+type jsonColumn struct {
+	v interface{}
+}
+
+// Value - реализация driver.Valuer
+func (c jsonColumn) Value() (driver.Value, error) {
+	if c.v == nil {
+		return nil, nil
+	}
+	switch rv := reflect.ValueOf(c.v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+	}
+	b, err := json.Marshal(c.v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan - реализация sql.Scanner
+func (c jsonColumn) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, c.v)
+	case string:
+		return json.Unmarshal([]byte(data), c.v)
+	default:
+		return fmt.Errorf("unsupported json column type %T", src)
+	}
+}
@@ -8,6 +8,23 @@ func (r *SQLRepo) migrate() error {
 			id SERIAL PRIMARY KEY
 		);
 		
+		-- Учетные данные зарегистрированных пользователей.
+		-- У анонимных пользователей email не задан (NULL).
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS email TEXT;
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash TEXT NOT NULL DEFAULT '';
+		CREATE UNIQUE INDEX IF NOT EXISTS users_email_idx ON users (email);
+
+		-- Идентификатор пользователя у провайдера OpenID Connect.
+		-- У пользователей, не входивших через OpenID Connect, не задан (NULL).
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS oidc_subject TEXT;
+		CREATE UNIQUE INDEX IF NOT EXISTS users_oidc_subject_idx ON users (oidc_subject);
+
+		-- Поколение токенов пользователя: его увеличение отзывает все выданные токены
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS token_generation INTEGER NOT NULL DEFAULT 0;
+
+		-- Тарифный план пользователя: пустой план означает квоты по умолчанию
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS plan TEXT NOT NULL DEFAULT '';
+
 		-- Создаем таблицу коротких ссылок
 		CREATE TABLE IF NOT EXISTS short_urls (
 			id TEXT PRIMARY KEY,
@@ -17,8 +34,104 @@ func (r *SQLRepo) migrate() error {
 			FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
 		);
 
-		-- Создаем уникальный индекс для поля original_url
-		CREATE UNIQUE INDEX IF NOT EXISTS short_urls_original_url_idx ON short_urls (original_url);
+		-- URL в том виде, в котором его передал пользователь
+		ALTER TABLE short_urls ADD COLUMN IF NOT EXISTS submitted_url TEXT NOT NULL DEFAULT '';
+
+		-- Заголовок, дата создания и режим страницы-предупреждения
+		ALTER TABLE short_urls ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '';
+		ALTER TABLE short_urls ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+		ALTER TABLE short_urls ADD COLUMN IF NOT EXISTS interstitial BOOLEAN NOT NULL DEFAULT false;
+
+		-- Период действия ссылки
+		ALTER TABLE short_urls ADD COLUMN IF NOT EXISTS active_from TIMESTAMPTZ;
+		ALTER TABLE short_urls ADD COLUMN IF NOT EXISTS active_until TIMESTAMPTZ;
+
+		-- Шаблон query-параметров
+		ALTER TABLE short_urls ADD COLUMN IF NOT EXISTS query_template JSONB;
+
+		-- Правила условного перенаправления
+		ALTER TABLE short_urls ADD COLUMN IF NOT EXISTS rules JSONB;
+
+		-- Параметры сплит-ссылки
+		ALTER TABLE short_urls ADD COLUMN IF NOT EXISTS split JSONB;
+
+		-- Создаем таблицу счетчиков переходов по вариантам сплит-ссылок
+		CREATE TABLE IF NOT EXISTS short_url_variant_clicks (
+			short_url_id TEXT NOT NULL,
+			variant INTEGER NOT NULL,
+			clicks BIGINT NOT NULL DEFAULT 0,
+			PRIMARY KEY (short_url_id, variant),
+			FOREIGN KEY (short_url_id) REFERENCES short_urls (id) ON DELETE CASCADE
+		);
+
+		-- Создаем таблицу событий переходов по коротким ссылкам
+		CREATE TABLE IF NOT EXISTS clicks (
+			id BIGSERIAL PRIMARY KEY,
+			short_url_id TEXT NOT NULL,
+			clicked_at TIMESTAMPTZ NOT NULL,
+			referer_host TEXT NOT NULL DEFAULT '',
+			device TEXT NOT NULL DEFAULT '',
+			ip TEXT NOT NULL DEFAULT '',
+			FOREIGN KEY (short_url_id) REFERENCES short_urls (id) ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS clicks_short_url_id_clicked_at_idx ON clicks (short_url_id, clicked_at);
+
+		-- Страна и хеш посетителя для статистики переходов
+		ALTER TABLE clicks ADD COLUMN IF NOT EXISTS country TEXT NOT NULL DEFAULT '';
+		ALTER TABLE clicks ADD COLUMN IF NOT EXISTS visitor TEXT NOT NULL DEFAULT '';
+
+		-- Отметка перехода, выполненного ботом
+		ALTER TABLE clicks ADD COLUMN IF NOT EXISTS bot BOOLEAN NOT NULL DEFAULT FALSE;
+
+		-- Создаем таблицу HyperLogLog-скетчей уникальных посетителей ссылок по суткам.
+		-- Пустой short_url_id - скетч посетителей всех ссылок сервиса.
+		CREATE TABLE IF NOT EXISTS visitor_sketches (
+			short_url_id TEXT NOT NULL,
+			day TIMESTAMPTZ NOT NULL,
+			sketch BYTEA NOT NULL,
+			PRIMARY KEY (short_url_id, day)
+		);
+
+		-- Создаем таблицу API-ключей пользователей
+		CREATE TABLE IF NOT EXISTS api_keys (
+			id TEXT PRIMARY KEY,
+			user_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			scopes JSONB NOT NULL,
+			hash TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL,
+			revoked_at TIMESTAMPTZ,
+			FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys (user_id);
+
+		-- Создаем таблицу команд
+		CREATE TABLE IF NOT EXISTS teams (
+			id SERIAL PRIMARY KEY,
+			name TEXT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL
+		);
+
+		-- Создаем таблицу участников команд и их ролей: owner, editor или viewer
+		CREATE TABLE IF NOT EXISTS team_members (
+			team_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL,
+			role TEXT NOT NULL,
+			PRIMARY KEY (team_id, user_id),
+			FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS team_members_user_id_idx ON team_members (user_id);
+
+		-- Команда, которой принадлежит ссылка. У личных ссылок не задана (NULL)
+		ALTER TABLE short_urls ADD COLUMN IF NOT EXISTS team_id INTEGER REFERENCES teams (id);
+		CREATE INDEX IF NOT EXISTS short_urls_team_id_idx ON short_urls (team_id);
+
+		-- Уникальный индекс оригинальных url простых ссылок пользователя (см. models.ShortURL.Plain).
+		-- Заменяет прежний уникальный индекс для поля original_url по всем ссылкам.
+		DROP INDEX IF EXISTS short_urls_original_url_idx;
+		CREATE UNIQUE INDEX IF NOT EXISTS short_urls_user_original_url_idx ON short_urls (user_id, original_url)
+			WHERE ` + shortURLPlain + `;
 		
 `)
 
@@ -1,6 +1,13 @@
 package repo
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
 
 type (
 	stmt       uint8
@@ -11,15 +18,122 @@ const (
 	stmtUserCreate stmt = iota
 	stmtUserGetByID
 	stmtUserCount
+	stmtUserGetByEmail
+	stmtUserSetCredentials
+	stmtUserGetByOIDCSubject
+	stmtUserSetOIDCSubject
+	stmtUserRevokeTokens
+	stmtUserSetPlan
+	stmtUserLock
 	stmtShortURLCreate
 	stmtShortURLGetByID
 	stmtShortURLGetByUserID
+	stmtShortURLGetByTeamID
+	stmtShortURLCountByUserID
 	stmtShortURLGetByOriginalURL
+	stmtShortURLUpdate
 	stmtShortURLDelete
 	stmtShortURLDeleteBatch
 	stmtShortURLCount
+	stmtShortURLVariantClick
+	stmtShortURLVariantClicks
+	stmtClickAdd
+	stmtClickGetByShortURLID
+	stmtStorageStats
+	stmtVisitorSketchInsert
+	stmtVisitorSketchLock
+	stmtVisitorSketchUpdate
+	stmtVisitorSketchGet
+	stmtAPIKeyCreate
+	stmtAPIKeyGetByID
+	stmtAPIKeyGetByUserID
+	stmtAPIKeyRevoke
+	stmtTeamCreate
+	stmtTeamGetByID
+	stmtTeamGetByUserID
+	stmtTeamMemberGet
+	stmtTeamMemberGetByTeamID
+	stmtTeamMemberSet
+	stmtTeamMemberDelete
+	stmtTeamLock
+	stmtTeamOwnerCount
 )
 
+// stmtNames - имена подготовленных запросов для трассировки
+var stmtNames = map[stmt]string{
+	stmtUserCreate:               "UserCreate",
+	stmtUserGetByID:              "UserGetByID",
+	stmtUserCount:                "UserCount",
+	stmtUserGetByEmail:           "UserGetByEmail",
+	stmtUserSetCredentials:       "UserSetCredentials",
+	stmtUserGetByOIDCSubject:     "UserGetByOIDCSubject",
+	stmtUserSetOIDCSubject:       "UserSetOIDCSubject",
+	stmtUserRevokeTokens:         "UserRevokeTokens",
+	stmtUserSetPlan:              "UserSetPlan",
+	stmtUserLock:                 "UserLock",
+	stmtShortURLCreate:           "ShortURLCreate",
+	stmtShortURLGetByID:          "ShortURLGetByID",
+	stmtShortURLGetByUserID:      "ShortURLGetByUserID",
+	stmtShortURLGetByTeamID:      "ShortURLGetByTeamID",
+	stmtShortURLCountByUserID:    "ShortURLCountByUserID",
+	stmtShortURLGetByOriginalURL: "ShortURLGetByOriginalURL",
+	stmtShortURLUpdate:           "ShortURLUpdate",
+	stmtShortURLDelete:           "ShortURLDelete",
+	stmtShortURLDeleteBatch:      "ShortURLDeleteBatch",
+	stmtShortURLCount:            "ShortURLCount",
+	stmtShortURLVariantClick:     "ShortURLVariantClick",
+	stmtShortURLVariantClicks:    "ShortURLVariantClicks",
+	stmtClickAdd:                 "ClickAdd",
+	stmtClickGetByShortURLID:     "ClickGetByShortURLID",
+	stmtStorageStats:             "StorageStats",
+	stmtVisitorSketchInsert:      "VisitorSketchInsert",
+	stmtVisitorSketchLock:        "VisitorSketchLock",
+	stmtVisitorSketchUpdate:      "VisitorSketchUpdate",
+	stmtVisitorSketchGet:         "VisitorSketchGet",
+	stmtAPIKeyCreate:             "APIKeyCreate",
+	stmtAPIKeyGetByID:            "APIKeyGetByID",
+	stmtAPIKeyGetByUserID:        "APIKeyGetByUserID",
+	stmtAPIKeyRevoke:             "APIKeyRevoke",
+	stmtTeamCreate:               "TeamCreate",
+	stmtTeamGetByID:              "TeamGetByID",
+	stmtTeamGetByUserID:          "TeamGetByUserID",
+	stmtTeamMemberGet:            "TeamMemberGet",
+	stmtTeamMemberGetByTeamID:    "TeamMemberGetByTeamID",
+	stmtTeamMemberSet:            "TeamMemberSet",
+	stmtTeamMemberDelete:         "TeamMemberDelete",
+	stmtTeamLock:                 "TeamLock",
+	stmtTeamOwnerCount:           "TeamOwnerCount",
+}
+
+// tracer - трассировщик подготовленных запросов
+var tracer = otel.Tracer("github.com/ofstudio/go-shortener/internal/repo")
+
+// startSpan - начинает span выполнения подготовленного запроса.
+func (s stmt) startSpan(ctx context.Context) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "sql."+stmtNames[s],
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBStatementKey.String(queries[s])),
+	)
+}
+
+// apiKeyColumns - список колонок таблицы api_keys в порядке полей apiKeyFields
+const apiKeyColumns = `id, user_id, name, scopes, hash, created_at, revoked_at`
+
+// shortURLColumns - список колонок таблицы short_urls в порядке полей shortURLFields
+const shortURLColumns = `id, original_url, submitted_url, user_id, deleted, title, created_at, interstitial, ` +
+	`active_from, active_until, query_template, rules, split, COALESCE(team_id, 0)`
+
+// shortURLEditableBy - условие, при котором пользователь $1 может изменять и удалять ссылку:
+// личную ссылку - создавший ее пользователь, ссылку команды - участник команды с ролью owner или editor
+const shortURLEditableBy = `(team_id IS NULL AND user_id = $1 OR team_id IN (
+		SELECT team_id FROM team_members WHERE user_id = $1 AND role IN ('owner', 'editor')
+	))`
+
+// shortURLPlain - условие, при котором ссылка является простой (см. models.ShortURL.Plain):
+// у пользователя может быть только одна простая ссылка с каждым оригинальным url
+const shortURLPlain = `team_id IS NULL AND rules IS NULL AND split IS NULL AND query_template IS NULL ` +
+	`AND title = '' AND NOT interstitial AND active_from IS NULL AND active_until IS NULL`
+
 var queries = map[stmt]string{
 	stmtUserCreate: `
 		INSERT INTO users (id)
@@ -27,41 +141,212 @@ var queries = map[stmt]string{
 		RETURNING id
 	`,
 	stmtUserGetByID: `
-		SELECT id FROM users 
+		SELECT id, COALESCE(email, ''), password_hash, COALESCE(oidc_subject, ''), token_generation, plan FROM users 
 	  	WHERE id = $1
 	`,
 	stmtUserCount: `
 		SELECT COUNT(*) FROM users
 	`,
+	stmtUserGetByEmail: `
+		SELECT id, email, password_hash, COALESCE(oidc_subject, ''), token_generation, plan FROM users
+		WHERE email = $1
+	`,
+	stmtUserSetCredentials: `
+		UPDATE users
+		SET email = NULLIF($2, ''), password_hash = $3
+		WHERE id = $1
+	`,
+	stmtUserGetByOIDCSubject: `
+		SELECT id, COALESCE(email, ''), password_hash, oidc_subject, token_generation, plan FROM users
+		WHERE oidc_subject = $1
+	`,
+	stmtUserSetOIDCSubject: `
+		UPDATE users
+		SET oidc_subject = NULLIF($2, '')
+		WHERE id = $1
+	`,
+	stmtUserRevokeTokens: `
+		UPDATE users
+		SET token_generation = token_generation + 1
+		WHERE id = $1
+		RETURNING token_generation
+	`,
+	stmtUserSetPlan: `
+		UPDATE users
+		SET plan = $2
+		WHERE id = $1
+	`,
+	stmtUserLock: `
+		SELECT id FROM users
+		WHERE id = $1
+		FOR UPDATE
+	`,
 	stmtShortURLCreate: `	
-		INSERT INTO short_urls (id, original_url, user_id)
-		VALUES ($1, $2, $3)
+		INSERT INTO short_urls (id, original_url, submitted_url, user_id, title, created_at, interstitial,
+		                        active_from, active_until, query_template, rules, split, team_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, 0))
 	`,
 	stmtShortURLGetByID: `
-		SELECT id, original_url, user_id, deleted FROM short_urls 
+		SELECT ` + shortURLColumns + ` FROM short_urls 
 		WHERE id = $1
 	`,
 	stmtShortURLGetByUserID: `
-		SELECT id, original_url, user_id, deleted FROM short_urls 
+		SELECT ` + shortURLColumns + ` FROM short_urls 
+		WHERE user_id = $1
+	`,
+	stmtShortURLGetByTeamID: `
+		SELECT ` + shortURLColumns + ` FROM short_urls
+		WHERE team_id = $1
+		ORDER BY created_at, id
+	`,
+	stmtShortURLCountByUserID: `
+		SELECT COUNT(*) FILTER (WHERE NOT deleted), COUNT(*) FILTER (WHERE created_at >= $2)
+		FROM short_urls
 		WHERE user_id = $1
 	`,
 	stmtShortURLGetByOriginalURL: `
-		SELECT id, original_url, user_id, deleted FROM short_urls
-		WHERE original_url = $1
+		SELECT ` + shortURLColumns + ` FROM short_urls
+		WHERE user_id = $1 AND original_url = $2 AND ` + shortURLPlain + `
+	`,
+	stmtShortURLUpdate: `
+		UPDATE short_urls
+		SET title = $3, interstitial = $4, active_from = $5, active_until = $6,
+		    query_template = $7, rules = $8, split = $9, team_id = NULLIF($10, 0)
+		WHERE user_id = $1 AND id = $2
 	`,
 	stmtShortURLDelete: `
 		UPDATE short_urls
 		SET deleted = true
-		WHERE  user_id = $1 AND id = $2
+		WHERE id = $2 AND ` + shortURLEditableBy + `
 	`,
 	stmtShortURLDeleteBatch: `
 		UPDATE short_urls
 		SET deleted = true
-		WHERE user_id = $1 AND id = ANY($2)
+		WHERE id = ANY($2) AND ` + shortURLEditableBy + `
 	`,
 	stmtShortURLCount: `
 		SELECT COUNT(*) FROM short_urls
 	`,
+	stmtShortURLVariantClick: `
+		INSERT INTO short_url_variant_clicks (short_url_id, variant, clicks)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (short_url_id, variant) DO UPDATE
+		SET clicks = short_url_variant_clicks.clicks + $3
+	`,
+	stmtShortURLVariantClicks: `
+		SELECT variant, clicks FROM short_url_variant_clicks
+		WHERE short_url_id = $1
+	`,
+	stmtClickAdd: `
+		INSERT INTO clicks (short_url_id, clicked_at, referer_host, device, ip, country, visitor, bot)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+	stmtClickGetByShortURLID: `
+		SELECT short_url_id, clicked_at, referer_host, device, ip, country, visitor, bot FROM clicks
+		WHERE short_url_id = $1
+		  AND ($2::TIMESTAMPTZ IS NULL OR clicked_at >= $2)
+		  AND ($3::TIMESTAMPTZ IS NULL OR clicked_at < $3)
+		ORDER BY clicked_at
+	`,
+	stmtStorageStats: `
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE NOT deleted),
+		       COUNT(*) FILTER (WHERE created_at >= $1),
+		       COUNT(*) FILTER (WHERE created_at >= $2),
+		       (SELECT COUNT(*) FROM users),
+		       COUNT(DISTINCT user_id) FILTER (WHERE NOT deleted),
+		       pg_total_relation_size('users') + pg_total_relation_size('short_urls') +
+		       pg_total_relation_size('short_url_variant_clicks') + pg_total_relation_size('clicks') +
+		       pg_total_relation_size('visitor_sketches') + pg_total_relation_size('api_keys') +
+		       pg_total_relation_size('teams') + pg_total_relation_size('team_members')
+		FROM short_urls
+	`,
+	stmtVisitorSketchInsert: `
+		INSERT INTO visitor_sketches (short_url_id, day, sketch)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (short_url_id, day) DO NOTHING
+	`,
+	stmtVisitorSketchLock: `
+		SELECT sketch FROM visitor_sketches
+		WHERE short_url_id = $1 AND day = $2
+		FOR UPDATE
+	`,
+	stmtVisitorSketchUpdate: `
+		UPDATE visitor_sketches SET sketch = $3
+		WHERE short_url_id = $1 AND day = $2
+	`,
+	stmtVisitorSketchGet: `
+		SELECT day, sketch FROM visitor_sketches
+		WHERE short_url_id = $1 AND day >= $2 AND day < $3
+		ORDER BY day
+	`,
+	stmtAPIKeyCreate: `
+		INSERT INTO api_keys (` + apiKeyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+	stmtAPIKeyGetByID: `
+		SELECT ` + apiKeyColumns + ` FROM api_keys
+		WHERE id = $1
+	`,
+	stmtAPIKeyGetByUserID: `
+		SELECT ` + apiKeyColumns + ` FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`,
+	stmtAPIKeyRevoke: `
+		UPDATE api_keys
+		SET revoked_at = $3
+		WHERE user_id = $1 AND id = $2 AND revoked_at IS NULL
+	`,
+	stmtTeamCreate: `
+		WITH team AS (
+			INSERT INTO teams (name, created_at)
+			VALUES ($1, $2)
+			RETURNING id
+		)
+		INSERT INTO team_members (team_id, user_id, role)
+		SELECT id, $3, 'owner' FROM team
+		RETURNING team_id
+	`,
+	stmtTeamGetByID: `
+		SELECT id, name, created_at FROM teams
+		WHERE id = $1
+	`,
+	stmtTeamGetByUserID: `
+		SELECT t.id, t.name, t.created_at, m.role FROM teams t
+		JOIN team_members m ON m.team_id = t.id
+		WHERE m.user_id = $1
+		ORDER BY t.id
+	`,
+	stmtTeamMemberGet: `
+		SELECT team_id, user_id, role FROM team_members
+		WHERE team_id = $1 AND user_id = $2
+	`,
+	stmtTeamMemberGetByTeamID: `
+		SELECT team_id, user_id, role FROM team_members
+		WHERE team_id = $1
+		ORDER BY user_id
+	`,
+	stmtTeamMemberSet: `
+		INSERT INTO team_members (team_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (team_id, user_id) DO UPDATE
+		SET role = EXCLUDED.role
+	`,
+	stmtTeamMemberDelete: `
+		DELETE FROM team_members
+		WHERE team_id = $1 AND user_id = $2
+	`,
+	stmtTeamLock: `
+		SELECT id FROM teams
+		WHERE id = $1
+		FOR UPDATE
+	`,
+	stmtTeamOwnerCount: `
+		SELECT COUNT(*) FILTER (WHERE user_id <> $2), COUNT(*) FILTER (WHERE user_id = $2)
+		FROM team_members
+		WHERE team_id = $1 AND role = 'owner'
+	`,
 }
 
 // prepareStmts - подготавливает запросы к БД
@@ -3,12 +3,14 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgerrcode"
 	_ "github.com/jackc/pgx/v4/stdlib"
 
 	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/pkg/hll"
 )
 
 // SQLRepo - реализация IRepo для хранения данных в PostgreSQL.
@@ -51,6 +53,8 @@ func (r *SQLRepo) UserCreate(ctx context.Context, user *models.User) error {
 	if r.db == nil {
 		return ErrDBNotInitialized
 	}
+	ctx, span := stmtUserCreate.startSpan(ctx)
+	defer span.End()
 	err := r.st[stmtUserCreate].QueryRowContext(ctx).Scan(&user.ID)
 	return err
 }
@@ -60,6 +64,8 @@ func (r *SQLRepo) UserGetByID(ctx context.Context, id uint) (*models.User, error
 	if r.db == nil {
 		return nil, ErrDBNotInitialized
 	}
+	ctx, span := stmtUserGetByID.startSpan(ctx)
+	defer span.End()
 	rows, err := r.st[stmtUserGetByID].QueryContext(ctx, id)
 	if err != nil {
 		return nil, err
@@ -70,7 +76,7 @@ func (r *SQLRepo) UserGetByID(ctx context.Context, id uint) (*models.User, error
 		return nil, ErrNotFound
 	}
 	var u models.User
-	if err = rows.Scan(&u.ID); err != nil {
+	if err = rows.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.OIDCSubject, &u.TokenGeneration, &u.Plan); err != nil {
 		return nil, err
 	}
 	if rows.Err() != nil {
@@ -85,16 +91,151 @@ func (r *SQLRepo) UserCount(ctx context.Context) (int, error) {
 		return 0, ErrDBNotInitialized
 	}
 	var count int
+	ctx, span := stmtUserCount.startSpan(ctx)
+	defer span.End()
 	err := r.st[stmtUserCount].QueryRowContext(ctx).Scan(&count)
 	return count, err
 }
 
+// UserGetByEmail - возвращает зарегистрированного пользователя по email либо ErrNotFound.
+func (r *SQLRepo) UserGetByEmail(ctx context.Context, email string) (*models.User, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtUserGetByEmail.startSpan(ctx)
+	defer span.End()
+	var u models.User
+	err := r.st[stmtUserGetByEmail].QueryRowContext(ctx, email).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.OIDCSubject, &u.TokenGeneration, &u.Plan)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// UserSetCredentials - устанавливает email и хеш пароля пользователя.
+// Если пользователь не найден, возвращает ErrNotFound.
+// Если email уже принадлежит другому пользователю, возвращает ErrDuplicate.
+func (r *SQLRepo) UserSetCredentials(ctx context.Context, id uint, email, passwordHash string) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	ctx, span := stmtUserSetCredentials.startSpan(ctx)
+	defer span.End()
+	res, err := r.st[stmtUserSetCredentials].ExecContext(ctx, id, email, passwordHash)
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.UniqueViolation {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UserGetByOIDCSubject - возвращает пользователя по идентификатору у провайдера OpenID Connect либо ErrNotFound.
+func (r *SQLRepo) UserGetByOIDCSubject(ctx context.Context, subject string) (*models.User, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtUserGetByOIDCSubject.startSpan(ctx)
+	defer span.End()
+	var u models.User
+	err := r.st[stmtUserGetByOIDCSubject].QueryRowContext(ctx, subject).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.OIDCSubject, &u.TokenGeneration, &u.Plan)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// UserSetOIDCSubject - устанавливает идентификатор пользователя у провайдера OpenID Connect.
+// Если пользователь не найден, возвращает ErrNotFound.
+// Если идентификатор уже принадлежит другому пользователю, возвращает ErrDuplicate.
+func (r *SQLRepo) UserSetOIDCSubject(ctx context.Context, id uint, subject string) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	ctx, span := stmtUserSetOIDCSubject.startSpan(ctx)
+	defer span.End()
+	res, err := r.st[stmtUserSetOIDCSubject].ExecContext(ctx, id, subject)
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.UniqueViolation {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UserRevokeTokens - увеличивает поколение токенов пользователя и возвращает новое поколение.
+// Если пользователь не найден, возвращает ErrNotFound.
+func (r *SQLRepo) UserRevokeTokens(ctx context.Context, id uint) (uint, error) {
+	if r.db == nil {
+		return 0, ErrDBNotInitialized
+	}
+	ctx, span := stmtUserRevokeTokens.startSpan(ctx)
+	defer span.End()
+	var generation uint
+	err := r.st[stmtUserRevokeTokens].QueryRowContext(ctx, id).Scan(&generation)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return generation, nil
+}
+
+// UserSetPlan - устанавливает тарифный план пользователя.
+// Если пользователь не найден, возвращает ErrNotFound.
+func (r *SQLRepo) UserSetPlan(ctx context.Context, id uint, plan string) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	ctx, span := stmtUserSetPlan.startSpan(ctx)
+	defer span.End()
+	res, err := r.st[stmtUserSetPlan].ExecContext(ctx, id, plan)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // ShortURLCreate - добавляет новую сокращенную ссылку в репозиторий.
 func (r *SQLRepo) ShortURLCreate(ctx context.Context, url *models.ShortURL) error {
 	if r.db == nil {
 		return ErrDBNotInitialized
 	}
-	_, err := r.st[stmtShortURLCreate].ExecContext(ctx, url.ID, url.OriginalURL, url.UserID)
+	ctx, span := stmtShortURLCreate.startSpan(ctx)
+	defer span.End()
+	_, err := r.st[stmtShortURLCreate].ExecContext(ctx,
+		url.ID, url.OriginalURL, url.SubmittedURL, url.UserID, url.Title, url.CreatedAt, url.Interstitial, url.ActiveFrom, url.ActiveUntil,
+		jsonColumn{url.QueryTemplate}, jsonColumn{url.Rules}, jsonColumn{url.Split}, url.TeamID)
 
 	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.UniqueViolation {
 		return ErrDuplicate
@@ -102,11 +243,70 @@ func (r *SQLRepo) ShortURLCreate(ctx context.Context, url *models.ShortURL) erro
 	return err
 }
 
+// ShortURLCreateWithLimit - добавляет новые сокращенные ссылки пользователя в одной транзакции,
+// если после этого не будут превышены ограничения limit.
+// Строка пользователя блокируется до конца транзакции, поэтому одновременные запросы одного пользователя
+// проверяют ограничения по очереди.
+func (r *SQLRepo) ShortURLCreateWithLimit(ctx context.Context, userID uint, shortURLs []*models.ShortURL, limit Limit) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	if len(shortURLs) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer tx.Rollback()
+
+	lockCtx, span := stmtUserLock.startSpan(ctx)
+	err = tx.StmtContext(lockCtx, r.st[stmtUserLock]).QueryRowContext(lockCtx, userID).Scan(&userID)
+	span.End()
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+
+	var active, created int
+	countCtx, span := stmtShortURLCountByUserID.startSpan(ctx)
+	err = tx.StmtContext(countCtx, r.st[stmtShortURLCountByUserID]).QueryRowContext(countCtx, userID, limit.Since).Scan(&active, &created)
+	span.End()
+	if err != nil {
+		return err
+	}
+	if limit.exceeded(active, created, len(shortURLs)) {
+		return ErrLimitExceeded
+	}
+
+	createCtx, span := stmtShortURLCreate.startSpan(ctx)
+	defer span.End()
+	st := tx.StmtContext(createCtx, r.st[stmtShortURLCreate])
+	for _, url := range shortURLs {
+		if url == nil || url.UserID != userID {
+			return ErrInvalidModel
+		}
+		_, err = st.ExecContext(createCtx,
+			url.ID, url.OriginalURL, url.SubmittedURL, url.UserID, url.Title, url.CreatedAt, url.Interstitial, url.ActiveFrom, url.ActiveUntil,
+			jsonColumn{url.QueryTemplate}, jsonColumn{url.Rules}, jsonColumn{url.Split}, url.TeamID)
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.UniqueViolation {
+			return ErrDuplicate
+		} else if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 // ShortURLGetByID - возвращает сокращенную ссылку по ее id.
 func (r *SQLRepo) ShortURLGetByID(ctx context.Context, id string) (*models.ShortURL, error) {
 	if r.db == nil {
 		return nil, ErrDBNotInitialized
 	}
+	ctx, span := stmtShortURLGetByID.startSpan(ctx)
+	defer span.End()
 	rows, err := r.st[stmtShortURLGetByID].QueryContext(ctx, id)
 	if err != nil {
 		return nil, err
@@ -117,7 +317,7 @@ func (r *SQLRepo) ShortURLGetByID(ctx context.Context, id string) (*models.Short
 		return nil, ErrNotFound
 	}
 	var u models.ShortURL
-	if err = rows.Scan(&u.ID, &u.OriginalURL, &u.UserID, &u.Deleted); err != nil {
+	if err = rows.Scan(shortURLFields(&u)...); err != nil {
 		return nil, err
 	}
 	if rows.Err() != nil {
@@ -132,6 +332,8 @@ func (r *SQLRepo) ShortURLGetByUserID(ctx context.Context, id uint) ([]models.Sh
 	if r.db == nil {
 		return nil, ErrDBNotInitialized
 	}
+	ctx, span := stmtShortURLGetByUserID.startSpan(ctx)
+	defer span.End()
 	rows, err := r.st[stmtShortURLGetByUserID].QueryContext(ctx, id)
 	if err != nil {
 		return nil, err
@@ -141,7 +343,46 @@ func (r *SQLRepo) ShortURLGetByUserID(ctx context.Context, id uint) ([]models.Sh
 	var urls []models.ShortURL
 	for rows.Next() {
 		var u models.ShortURL
-		if err = rows.Scan(&u.ID, &u.OriginalURL, &u.UserID, &u.Deleted); err != nil {
+		if err = rows.Scan(shortURLFields(&u)...); err != nil {
+			return nil, err
+		}
+		urls = append(urls, u)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return urls, nil
+}
+
+// ShortURLCountByUserID - возвращает количество неудаленных ссылок, созданных пользователем,
+// и количество ссылок, созданных им начиная с момента since, в тч удаленных.
+func (r *SQLRepo) ShortURLCountByUserID(ctx context.Context, userID uint, since time.Time) (active, created int, err error) {
+	if r.db == nil {
+		return 0, 0, ErrDBNotInitialized
+	}
+	ctx, span := stmtShortURLCountByUserID.startSpan(ctx)
+	defer span.End()
+	err = r.st[stmtShortURLCountByUserID].QueryRowContext(ctx, userID, since).Scan(&active, &created)
+	return active, created, err
+}
+
+// ShortURLGetByTeamID - возвращает ссылки команды в порядке создания.
+func (r *SQLRepo) ShortURLGetByTeamID(ctx context.Context, teamID uint) ([]models.ShortURL, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtShortURLGetByTeamID.startSpan(ctx)
+	defer span.End()
+	rows, err := r.st[stmtShortURLGetByTeamID].QueryContext(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer rows.Close()
+	var urls []models.ShortURL
+	for rows.Next() {
+		var u models.ShortURL
+		if err = rows.Scan(shortURLFields(&u)...); err != nil {
 			return nil, err
 		}
 		urls = append(urls, u)
@@ -152,12 +393,14 @@ func (r *SQLRepo) ShortURLGetByUserID(ctx context.Context, id uint) ([]models.Sh
 	return urls, nil
 }
 
-// ShortURLGetByOriginalURL - возвращает сокращенную ссылку по ее оригинальному url.
-func (r *SQLRepo) ShortURLGetByOriginalURL(ctx context.Context, s string) (*models.ShortURL, error) {
+// ShortURLGetByOriginalURL - возвращает простую ссылку пользователя (см. models.ShortURL.Plain) по ее оригинальному url.
+func (r *SQLRepo) ShortURLGetByOriginalURL(ctx context.Context, userID uint, s string) (*models.ShortURL, error) {
 	if r.db == nil {
 		return nil, ErrDBNotInitialized
 	}
-	rows, err := r.st[stmtShortURLGetByOriginalURL].QueryContext(ctx, s)
+	ctx, span := stmtShortURLGetByOriginalURL.startSpan(ctx)
+	defer span.End()
+	rows, err := r.st[stmtShortURLGetByOriginalURL].QueryContext(ctx, userID, s)
 	if err != nil {
 		return nil, err
 	}
@@ -168,7 +411,7 @@ func (r *SQLRepo) ShortURLGetByOriginalURL(ctx context.Context, s string) (*mode
 		return nil, ErrNotFound
 	}
 	var u models.ShortURL
-	if err = rows.Scan(&u.ID, &u.OriginalURL, &u.UserID, &u.Deleted); err != nil {
+	if err = rows.Scan(shortURLFields(&u)...); err != nil {
 		return nil, err
 	}
 	if rows.Err() != nil {
@@ -177,11 +420,40 @@ func (r *SQLRepo) ShortURLGetByOriginalURL(ctx context.Context, s string) (*mode
 	return &u, nil
 }
 
+// ShortURLUpdate - сохраняет изменяемые поля сокращенной ссылки пользователя.
+// Если ссылка не найдена или принадлежит другому пользователю, возвращает ErrNotFound.
+// Если ссылка становится простой, а у пользователя уже есть простая ссылка с тем же url, возвращает ErrDuplicate.
+func (r *SQLRepo) ShortURLUpdate(ctx context.Context, url *models.ShortURL) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	ctx, span := stmtShortURLUpdate.startSpan(ctx)
+	defer span.End()
+	res, err := r.st[stmtShortURLUpdate].ExecContext(ctx,
+		url.UserID, url.ID, url.Title, url.Interstitial, url.ActiveFrom, url.ActiveUntil,
+		jsonColumn{url.QueryTemplate}, jsonColumn{url.Rules}, jsonColumn{url.Split}, url.TeamID)
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.UniqueViolation {
+		return ErrDuplicate
+	} else if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // ShortURLDelete - помечает удаленной короткую ссылку пользователя по ее id.
-func (r *SQLRepo) ShortURLDelete(_ context.Context, userID uint, id string) error {
+func (r *SQLRepo) ShortURLDelete(ctx context.Context, userID uint, id string) error {
 	if r.db == nil {
 		return ErrDBNotInitialized
 	}
+	_, span := stmtShortURLDelete.startSpan(ctx)
+	defer span.End()
 	res, err := r.st[stmtShortURLDelete].ExecContext(context.Background(), userID, id)
 	if err != nil {
 		return err
@@ -216,6 +488,8 @@ func (r *SQLRepo) ShortURLDeleteBatch(ctx context.Context, userID uint, chans ..
 	if len(ids) == 0 {
 		return 0, nil
 	}
+	ctx, span := stmtShortURLDeleteBatch.startSpan(ctx)
+	defer span.End()
 	res, err := r.st[stmtShortURLDeleteBatch].ExecContext(ctx, userID, ids)
 	if err != nil {
 		return 0, err
@@ -223,12 +497,534 @@ func (r *SQLRepo) ShortURLDeleteBatch(ctx context.Context, userID uint, chans ..
 	return res.RowsAffected()
 }
 
+// ShortURLVariantClick - увеличивает счетчик переходов по варианту сплит-ссылки на n.
+// Если ссылка не найдена, возвращает ErrNotFound.
+func (r *SQLRepo) ShortURLVariantClick(ctx context.Context, id string, variant int, n int64) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	ctx, span := stmtShortURLVariantClick.startSpan(ctx)
+	defer span.End()
+	_, err := r.st[stmtShortURLVariantClick].ExecContext(ctx, id, variant, n)
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.ForeignKeyViolation {
+		return ErrNotFound
+	}
+	return err
+}
+
+// ShortURLVariantClicks - возвращает счетчики переходов по вариантам сплит-ссылки по их номерам.
+func (r *SQLRepo) ShortURLVariantClicks(ctx context.Context, id string) (map[int]int64, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtShortURLVariantClicks.startSpan(ctx)
+	defer span.End()
+	rows, err := r.st[stmtShortURLVariantClicks].QueryContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer rows.Close()
+	clicks := make(map[int]int64)
+	for rows.Next() {
+		var (
+			variant int
+			n       int64
+		)
+		if err = rows.Scan(&variant, &n); err != nil {
+			return nil, err
+		}
+		clicks[variant] = n
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return clicks, nil
+}
+
+// ClickAddBatch - сохраняет события переходов по сокращенным ссылкам в одной транзакции.
+func (r *SQLRepo) ClickAddBatch(ctx context.Context, clicks []models.Click) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	ctx, span := stmtClickAdd.startSpan(ctx)
+	defer span.End()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer tx.Rollback()
+	st := tx.StmtContext(ctx, r.st[stmtClickAdd])
+	for _, c := range clicks {
+		if _, err = st.ExecContext(ctx, c.ShortURLID, c.Time, c.RefererHost, c.Device, c.IP, c.Country, c.Visitor, c.Bot); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ClickGetByShortURLID - возвращает события переходов по сокращенной ссылке за период [from, until).
+// Нулевое значение from или until означает, что период не ограничен с соответствующей стороны.
+func (r *SQLRepo) ClickGetByShortURLID(ctx context.Context, id string, from, until time.Time) ([]models.Click, error) {
+	var clicks []models.Click
+	err := r.ClickIterate(ctx, id, from, until, func(c models.Click) error {
+		clicks = append(clicks, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clicks, nil
+}
+
+// ClickIterate - вызывает fn для каждого события перехода по сокращенной ссылке за период [from, until)
+// в порядке времени. События считываются из БД построчно по мере обработки.
+func (r *SQLRepo) ClickIterate(ctx context.Context, id string, from, until time.Time, fn func(models.Click) error) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	ctx, span := stmtClickGetByShortURLID.startSpan(ctx)
+	defer span.End()
+	rows, err := r.st[stmtClickGetByShortURLID].QueryContext(ctx, id,
+		sql.NullTime{Time: from, Valid: !from.IsZero()},
+		sql.NullTime{Time: until, Valid: !until.IsZero()},
+	)
+	if err != nil {
+		return err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer rows.Close()
+	for rows.Next() {
+		var c models.Click
+		if err = rows.Scan(&c.ShortURLID, &c.Time, &c.RefererHost, &c.Device, &c.IP, &c.Country, &c.Visitor, &c.Bot); err != nil {
+			return err
+		}
+		if err = fn(c); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 // ShortURLCount - возвращает количество сокращенных ссылок в репозитории.
 func (r *SQLRepo) ShortURLCount(ctx context.Context) (int, error) {
 	if r.db == nil {
 		return 0, ErrDBNotInitialized
 	}
 	var count int
+	ctx, span := stmtShortURLCount.startSpan(ctx)
+	defer span.End()
 	err := r.st[stmtShortURLCount].QueryRowContext(ctx).Scan(&count)
 	return count, err
 }
+
+// StorageStats - возвращает статистику хранилища.
+// Размер хранилища - суммарный размер таблиц сервиса, включая индексы.
+func (r *SQLRepo) StorageStats(ctx context.Context, now time.Time) (*models.StorageStats, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtStorageStats.startSpan(ctx)
+	defer span.End()
+	var stats models.StorageStats
+	err := r.st[stmtStorageStats].QueryRowContext(ctx, now.Add(-time.Hour), now.Add(-24*time.Hour)).Scan(
+		&stats.URLs, &stats.ActiveURLs, &stats.URLsLastHour, &stats.URLsLastDay,
+		&stats.Users, &stats.ActiveUsers, &stats.Size,
+	)
+	if err != nil {
+		return nil, err
+	}
+	stats.DeletedURLs = stats.URLs - stats.ActiveURLs
+	return &stats, nil
+}
+
+// VisitorSketchMerge - объединяет скетчи посетителей с сохраненными скетчами тех же ссылок за те же сутки в одной транзакции.
+// Если скетча еще нет, он добавляется. Иначе сохраненный скетч блокируется до конца транзакции, объединяется и обновляется.
+func (r *SQLRepo) VisitorSketchMerge(ctx context.Context, sketches []models.VisitorSketch) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer tx.Rollback()
+	for _, s := range sketches {
+		if err = r.visitorSketchMerge(ctx, tx, s); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// visitorSketchMerge - объединяет скетч посетителей с сохраненным в транзакции tx
+func (r *SQLRepo) visitorSketchMerge(ctx context.Context, tx *sql.Tx, s models.VisitorSketch) error {
+	data, err := s.Sketch.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	insertCtx, span := stmtVisitorSketchInsert.startSpan(ctx)
+	res, err := tx.StmtContext(insertCtx, r.st[stmtVisitorSketchInsert]).ExecContext(insertCtx, s.ShortURLID, s.Day, data)
+	span.End()
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+
+	// Скетч уже существует: блокируем его и объединяем
+	var stored []byte
+	lockCtx, span := stmtVisitorSketchLock.startSpan(ctx)
+	err = tx.StmtContext(lockCtx, r.st[stmtVisitorSketchLock]).QueryRowContext(lockCtx, s.ShortURLID, s.Day).Scan(&stored)
+	span.End()
+	if err != nil {
+		return err
+	}
+	merged := &hll.Sketch{}
+	if err = merged.UnmarshalBinary(stored); err != nil {
+		return err
+	}
+	if err = merged.Merge(s.Sketch); err != nil {
+		return err
+	}
+	if data, err = merged.MarshalBinary(); err != nil {
+		return err
+	}
+	updateCtx, span := stmtVisitorSketchUpdate.startSpan(ctx)
+	defer span.End()
+	_, err = tx.StmtContext(updateCtx, r.st[stmtVisitorSketchUpdate]).ExecContext(updateCtx, s.ShortURLID, s.Day, data)
+	return err
+}
+
+// VisitorSketchGet - возвращает скетчи посетителей ссылки id за сутки в периоде [from, until), упорядоченные по времени.
+func (r *SQLRepo) VisitorSketchGet(ctx context.Context, id string, from, until time.Time) ([]models.VisitorSketch, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtVisitorSketchGet.startSpan(ctx)
+	defer span.End()
+	rows, err := r.st[stmtVisitorSketchGet].QueryContext(ctx, id, from, until)
+	if err != nil {
+		return nil, err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer rows.Close()
+	var sketches []models.VisitorSketch
+	for rows.Next() {
+		var data []byte
+		s := models.VisitorSketch{ShortURLID: id, Sketch: &hll.Sketch{}}
+		if err = rows.Scan(&s.Day, &data); err != nil {
+			return nil, err
+		}
+		if err = s.Sketch.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		s.Day = s.Day.UTC()
+		sketches = append(sketches, s)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return sketches, nil
+}
+
+// APIKeyCreate - сохраняет новый API-ключ.
+// Если ключ с таким id уже существует, возвращает ErrDuplicate.
+func (r *SQLRepo) APIKeyCreate(ctx context.Context, key *models.APIKey) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	ctx, span := stmtAPIKeyCreate.startSpan(ctx)
+	defer span.End()
+	_, err := r.st[stmtAPIKeyCreate].ExecContext(ctx,
+		key.ID, key.UserID, key.Name, jsonColumn{key.Scopes}, key.Hash, key.CreatedAt, key.RevokedAt)
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.UniqueViolation {
+		return ErrDuplicate
+	}
+	return err
+}
+
+// APIKeyGetByID - возвращает API-ключ по его id либо ErrNotFound.
+func (r *SQLRepo) APIKeyGetByID(ctx context.Context, id string) (*models.APIKey, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtAPIKeyGetByID.startSpan(ctx)
+	defer span.End()
+	var key models.APIKey
+	err := r.st[stmtAPIKeyGetByID].QueryRowContext(ctx, id).Scan(apiKeyFields(&key)...)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// APIKeyGetByUserID - возвращает API-ключи пользователя в порядке создания.
+func (r *SQLRepo) APIKeyGetByUserID(ctx context.Context, userID uint) ([]models.APIKey, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtAPIKeyGetByUserID.startSpan(ctx)
+	defer span.End()
+	rows, err := r.st[stmtAPIKeyGetByUserID].QueryContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer rows.Close()
+	var keys []models.APIKey
+	for rows.Next() {
+		var key models.APIKey
+		if err = rows.Scan(apiKeyFields(&key)...); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return keys, nil
+}
+
+// APIKeyRevoke - отзывает API-ключ пользователя.
+// Если ключ не найден, принадлежит другому пользователю или уже отозван, возвращает ErrNotFound.
+func (r *SQLRepo) APIKeyRevoke(ctx context.Context, userID uint, id string, at time.Time) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	ctx, span := stmtAPIKeyRevoke.startSpan(ctx)
+	defer span.End()
+	res, err := r.st[stmtAPIKeyRevoke].ExecContext(ctx, userID, id, at)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// TeamCreate - создает команду и добавляет в нее владельца с ролью owner.
+// Если владелец не найден, возвращает ErrNotFound.
+func (r *SQLRepo) TeamCreate(ctx context.Context, team *models.Team, ownerID uint) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	if team == nil {
+		return ErrInvalidModel
+	}
+	ctx, span := stmtTeamCreate.startSpan(ctx)
+	defer span.End()
+	err := r.st[stmtTeamCreate].QueryRowContext(ctx, team.Name, team.CreatedAt, ownerID).Scan(&team.ID)
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.ForeignKeyViolation {
+		return ErrNotFound
+	}
+	return err
+}
+
+// TeamGetByID - возвращает команду по ее id либо ErrNotFound.
+func (r *SQLRepo) TeamGetByID(ctx context.Context, id uint) (*models.Team, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtTeamGetByID.startSpan(ctx)
+	defer span.End()
+	var team models.Team
+	err := r.st[stmtTeamGetByID].QueryRowContext(ctx, id).Scan(&team.ID, &team.Name, &team.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// TeamGetByUserID - возвращает команды пользователя и его роли в них в порядке создания команд.
+func (r *SQLRepo) TeamGetByUserID(ctx context.Context, userID uint) ([]models.TeamMembership, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtTeamGetByUserID.startSpan(ctx)
+	defer span.End()
+	rows, err := r.st[stmtTeamGetByUserID].QueryContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer rows.Close()
+	var teams []models.TeamMembership
+	for rows.Next() {
+		var m models.TeamMembership
+		if err = rows.Scan(&m.ID, &m.Name, &m.CreatedAt, &m.Role); err != nil {
+			return nil, err
+		}
+		teams = append(teams, m)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return teams, nil
+}
+
+// TeamMemberGet - возвращает участника команды либо ErrNotFound.
+func (r *SQLRepo) TeamMemberGet(ctx context.Context, teamID, userID uint) (*models.TeamMember, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtTeamMemberGet.startSpan(ctx)
+	defer span.End()
+	var m models.TeamMember
+	err := r.st[stmtTeamMemberGet].QueryRowContext(ctx, teamID, userID).Scan(&m.TeamID, &m.UserID, &m.Role)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// TeamMemberGetByTeamID - возвращает участников команды в порядке их id.
+func (r *SQLRepo) TeamMemberGetByTeamID(ctx context.Context, teamID uint) ([]models.TeamMember, error) {
+	if r.db == nil {
+		return nil, ErrDBNotInitialized
+	}
+	ctx, span := stmtTeamMemberGetByTeamID.startSpan(ctx)
+	defer span.End()
+	rows, err := r.st[stmtTeamMemberGetByTeamID].QueryContext(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer rows.Close()
+	var members []models.TeamMember
+	for rows.Next() {
+		var m models.TeamMember
+		if err = rows.Scan(&m.TeamID, &m.UserID, &m.Role); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return members, nil
+}
+
+// TeamMemberSet - добавляет участника команды или изменяет его роль.
+// Если команда или пользователь не найдены, возвращает ErrNotFound.
+// Если изменение роли оставит команду без владельцев, возвращает ErrLastOwner.
+// Строка команды блокируется до конца транзакции, поэтому одновременные изменения участников команды
+// проверяют наличие владельцев по очереди.
+func (r *SQLRepo) TeamMemberSet(ctx context.Context, member *models.TeamMember) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	if member == nil {
+		return ErrInvalidModel
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer tx.Rollback()
+
+	if err = r.teamLock(ctx, tx, member.TeamID, member.UserID, member.Role != models.TeamRoleOwner); err != nil {
+		return err
+	}
+	setCtx, span := stmtTeamMemberSet.startSpan(ctx)
+	defer span.End()
+	_, err = tx.StmtContext(setCtx, r.st[stmtTeamMemberSet]).ExecContext(setCtx, member.TeamID, member.UserID, member.Role)
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == pgerrcode.ForeignKeyViolation {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// TeamMemberDelete - удаляет участника команды.
+// Если пользователь не состоит в команде, возвращает ErrNotFound.
+// Если удаление оставит команду без владельцев, возвращает ErrLastOwner.
+// Строка команды блокируется до конца транзакции (см. TeamMemberSet).
+func (r *SQLRepo) TeamMemberDelete(ctx context.Context, teamID, userID uint) error {
+	if r.db == nil {
+		return ErrDBNotInitialized
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer tx.Rollback()
+
+	if err = r.teamLock(ctx, tx, teamID, userID, true); err != nil {
+		return err
+	}
+	deleteCtx, span := stmtTeamMemberDelete.startSpan(ctx)
+	defer span.End()
+	res, err := tx.StmtContext(deleteCtx, r.st[stmtTeamMemberDelete]).ExecContext(deleteCtx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return tx.Commit()
+}
+
+// teamLock - блокирует строку команды teamID в транзакции tx.
+// Если checkOwner = true и userID - единственный владелец команды, возвращает ErrLastOwner.
+// Если команда не найдена, возвращает ErrNotFound.
+func (r *SQLRepo) teamLock(ctx context.Context, tx *sql.Tx, teamID, userID uint, checkOwner bool) error {
+	lockCtx, span := stmtTeamLock.startSpan(ctx)
+	err := tx.StmtContext(lockCtx, r.st[stmtTeamLock]).QueryRowContext(lockCtx, teamID).Scan(&teamID)
+	span.End()
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	} else if err != nil || !checkOwner {
+		return err
+	}
+
+	var others, owner int
+	countCtx, span := stmtTeamOwnerCount.startSpan(ctx)
+	err = tx.StmtContext(countCtx, r.st[stmtTeamOwnerCount]).QueryRowContext(countCtx, teamID, userID).Scan(&others, &owner)
+	span.End()
+	if err != nil {
+		return err
+	}
+	if owner > 0 && others == 0 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// apiKeyFields - возвращает указатели на поля APIKey для сканирования в порядке колонок apiKeyColumns
+func apiKeyFields(k *models.APIKey) []interface{} {
+	return []interface{}{&k.ID, &k.UserID, &k.Name, jsonColumn{&k.Scopes}, &k.Hash, &k.CreatedAt, &k.RevokedAt}
+}
+
+// shortURLFields - возвращает указатели на поля ShortURL для сканирования в порядке колонок shortURLColumns
+func shortURLFields(u *models.ShortURL) []interface{} {
+	return []interface{}{
+		&u.ID, &u.OriginalURL, &u.SubmittedURL, &u.UserID, &u.Deleted, &u.Title, &u.CreatedAt, &u.Interstitial,
+		&u.ActiveFrom, &u.ActiveUntil,
+		jsonColumn{&u.QueryTemplate}, jsonColumn{&u.Rules}, jsonColumn{&u.Split}, &u.TeamID,
+	}
+}
@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+// APIKeyPrefix - префикс API-ключей.
+// Позволяет отличить API-ключ от токена пользователя в заголовке Authorization.
+const APIKeyPrefix = "shk_"
+
+// Длины идентификатора и секретной части API-ключа в байтах
+const (
+	apiKeyIDLen     = 8
+	apiKeySecretLen = 32
+)
+
+// APIKey - бизнес-логика для работы с API-ключами пользователей.
+//
+// Ключ имеет вид shk_<id>_<secret>: по id ключ находится в хранилище,
+// а secret проверяется сравнением SHA-256 всего ключа с сохраненным хешем.
+// Сам ключ в хранилище не сохраняется и возвращается пользователю только при создании.
+type APIKey struct {
+	repo repo.IRepo
+	now  func() time.Time
+}
+
+// NewAPIKey - конструктор APIKey
+func NewAPIKey(repo repo.IRepo) *APIKey {
+	return &APIKey{repo: repo, now: time.Now}
+}
+
+// Create - создает API-ключ пользователя с названием name и правами доступа scopes.
+// Возвращает модель ключа и сам ключ.
+func (u APIKey) Create(ctx context.Context, userID uint, name string, scopes []models.APIScope) (*models.APIKey, string, error) {
+	ctx, span := tracer.Start(ctx, "APIKey.Create")
+	defer span.End()
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > models.APIKeyNameMaxLen {
+		return nil, "", pkgerrors.ErrValidation.WithDetail("name is required and must be at most 128 characters")
+	}
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	id, secret := make([]byte, apiKeyIDLen), make([]byte, apiKeySecretLen)
+	if _, err = rand.Read(id); err == nil {
+		_, err = rand.Read(secret)
+	}
+	if err != nil {
+		log.Err(err).Msg("failed to generate api key")
+		return nil, "", pkgerrors.ErrInternal
+	}
+	plain := APIKeyPrefix + hex.EncodeToString(id) + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	key := &models.APIKey{
+		ID:        hex.EncodeToString(id),
+		UserID:    userID,
+		Name:      name,
+		Scopes:    scopes,
+		Hash:      hashAPIKey(plain),
+		CreatedAt: u.now().UTC().Truncate(time.Second),
+	}
+	if err = u.repo.APIKeyCreate(ctx, key); err != nil {
+		log.Err(err).Msg("failed to create api key")
+		return nil, "", pkgerrors.ErrInternal
+	}
+	return key, plain, nil
+}
+
+// GetByUserID - возвращает API-ключи пользователя, в тч отозванные.
+func (u APIKey) GetByUserID(ctx context.Context, userID uint) ([]models.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKey.GetByUserID")
+	defer span.End()
+	keys, err := u.repo.APIKeyGetByUserID(ctx, userID)
+	if err != nil {
+		log.Err(err).Msg("failed to get api keys")
+		return nil, pkgerrors.ErrInternal
+	}
+	return keys, nil
+}
+
+// Revoke - отзывает API-ключ пользователя.
+// Если ключ не найден, принадлежит другому пользователю или уже отозван, возвращает ErrNotFound.
+func (u APIKey) Revoke(ctx context.Context, userID uint, id string) error {
+	ctx, span := tracer.Start(ctx, "APIKey.Revoke")
+	defer span.End()
+	err := u.repo.APIKeyRevoke(ctx, userID, id, u.now().UTC().Truncate(time.Second))
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkgerrors.ErrNotFound
+	} else if err != nil {
+		log.Err(err).Msg("failed to revoke api key")
+		return pkgerrors.ErrInternal
+	}
+	return nil
+}
+
+// Verify - проверяет API-ключ и возвращает его модель.
+// Если ключ не найден, неверен или отозван, возвращает ErrAuth.
+func (u APIKey) Verify(ctx context.Context, plain string) (*models.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKey.Verify")
+	defer span.End()
+	if !strings.HasPrefix(plain, APIKeyPrefix) {
+		return nil, pkgerrors.ErrAuth
+	}
+	id, _, ok := strings.Cut(strings.TrimPrefix(plain, APIKeyPrefix), "_")
+	if !ok || id == "" {
+		return nil, pkgerrors.ErrAuth
+	}
+	key, err := u.repo.APIKeyGetByID(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkgerrors.ErrAuth
+	} else if err != nil {
+		log.Err(err).Msg("failed to get api key")
+		return nil, pkgerrors.ErrInternal
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(plain)), []byte(key.Hash)) != 1 || key.Revoked() {
+		return nil, pkgerrors.ErrAuth
+	}
+	return key, nil
+}
+
+// normalizeScopes - проверяет права доступа и удаляет повторы.
+// Возвращает ErrValidation, если права не заданы или среди них есть неизвестные.
+func normalizeScopes(scopes []models.APIScope) ([]models.APIScope, error) {
+	if len(scopes) == 0 {
+		return nil, pkgerrors.ErrValidation.WithDetail("at least one scope is required")
+	}
+	known := models.APIKey{Scopes: models.APIScopes}
+	result := &models.APIKey{Scopes: make([]models.APIScope, 0, len(scopes))}
+	for _, s := range scopes {
+		if !known.HasScope(s) {
+			return nil, pkgerrors.ErrValidation.WithDetail("unknown scope: " + string(s))
+		}
+		if !result.HasScope(s) {
+			result.Scopes = append(result.Scopes, s)
+		}
+	}
+	return result.Scopes, nil
+}
+
+// hashAPIKey - возвращает SHA-256 API-ключа в шестнадцатеричном виде
+func hashAPIKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
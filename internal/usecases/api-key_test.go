@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+type apiKeySuite struct {
+	suite.Suite
+	*APIKey
+}
+
+func TestAPIKeySuite(t *testing.T) {
+	suite.Run(t, new(apiKeySuite))
+}
+
+func (suite *apiKeySuite) SetupTest() {
+	suite.APIKey = NewAPIKey(repo.NewMemoryRepo())
+}
+
+func (suite *apiKeySuite) TestCreate() {
+	ctx := context.Background()
+	suite.Run("success", func() {
+		key, plain, err := suite.Create(ctx, 1, " CI ", []models.APIScope{models.APIScopeRead, models.APIScopeCreate, models.APIScopeRead})
+		suite.Require().NoError(err)
+		suite.Equal("CI", key.Name)
+		suite.Equal([]models.APIScope{models.APIScopeRead, models.APIScopeCreate}, key.Scopes)
+		suite.True(strings.HasPrefix(plain, APIKeyPrefix+key.ID+"_"))
+		// Ключ не хранится в открытом виде
+		suite.NotContains(key.Hash, plain[len(APIKeyPrefix+key.ID+"_"):])
+	})
+
+	suite.Run("validation", func() {
+		for _, tt := range []struct {
+			name   string
+			scopes []models.APIScope
+		}{
+			{"", []models.APIScope{models.APIScopeRead}},
+			{strings.Repeat("a", models.APIKeyNameMaxLen+1), []models.APIScope{models.APIScopeRead}},
+			{"CI", nil},
+			{"CI", []models.APIScope{"admin"}},
+		} {
+			_, _, err := suite.Create(ctx, 1, tt.name, tt.scopes)
+			suite.ErrorIs(err, pkgerrors.ErrValidation)
+		}
+	})
+}
+
+func (suite *apiKeySuite) TestVerify() {
+	ctx := context.Background()
+	key, plain, err := suite.Create(ctx, 1, "CI", []models.APIScope{models.APIScopeRead})
+	suite.Require().NoError(err)
+
+	actual, err := suite.Verify(ctx, plain)
+	suite.NoError(err)
+	suite.Equal(key.ID, actual.ID)
+	suite.Equal(uint(1), actual.UserID)
+
+	// Неверная секретная часть, неизвестный id и неверный формат
+	for _, invalid := range []string{plain + "x", APIKeyPrefix + "unknown_secret", "token", APIKeyPrefix} {
+		_, err = suite.Verify(ctx, invalid)
+		suite.ErrorIs(err, pkgerrors.ErrAuth, invalid)
+	}
+
+	// Отозванный ключ не принимается, а отозвать его повторно или чужой ключ нельзя
+	suite.ErrorIs(suite.Revoke(ctx, 2, key.ID), pkgerrors.ErrNotFound)
+	suite.NoError(suite.Revoke(ctx, 1, key.ID))
+	suite.ErrorIs(suite.Revoke(ctx, 1, key.ID), pkgerrors.ErrNotFound)
+	_, err = suite.Verify(ctx, plain)
+	suite.ErrorIs(err, pkgerrors.ErrAuth)
+
+	keys, err := suite.GetByUserID(ctx, 1)
+	suite.NoError(err)
+	suite.Require().Len(keys, 1)
+	suite.True(keys[0].Revoked())
+}
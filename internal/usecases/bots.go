@@ -0,0 +1,25 @@
+package usecases
+
+import (
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/providers/botdetect"
+)
+
+// UseBotClassifier - задает классификатор ботов вместо классификатора со встроенными шаблонами.
+func (u *ShortURL) UseBotClassifier(c botdetect.Classifier) *ShortURL {
+	u.bots = c
+	return u
+}
+
+// DetectBot - определяет, выполнен ли переход ботом, и отмечает результат в visit.Bot.
+// Переходы ботов сохраняются с отметкой bot и не учитываются в статистике по умолчанию.
+func (u ShortURL) DetectBot(visit *models.Visit) bool {
+	visit.Bot = u.bots.IsBot(visit)
+	return visit.Bot
+}
+
+// BotPreview - возвращает true, если ботам нужно отдавать легкую страницу со ссылкой
+// вместо перенаправления. Переход при этом не засчитывается.
+func (u ShortURL) BotPreview() bool {
+	return u.botPreview
+}
@@ -0,0 +1,144 @@
+package usecases
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/idna"
+
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+)
+
+// defaultPorts - порты по умолчанию для схем URL
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// canonicalURL - приводит URL к каноническому виду:
+//   - схема и хост в нижнем регистре;
+//   - порт по умолчанию для схемы удаляется;
+//   - интернационализированное доменное имя (IDN) преобразуется в punycode;
+//   - в percent-кодировании используются заглавные шестнадцатеричные цифры,
+//     незарезервированные символы декодируются, недопустимые - кодируются;
+//   - пустые query и fragment удаляются;
+//   - если sortQuery = true, то query-параметры сортируются по имени (порядок одноименных параметров сохраняется).
+//
+// Предполагается, что URL уже проверен validateURL.
+func canonicalURL(rawURL string, sortQuery bool) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", pkgerrors.ErrValidation
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	// Хост: нижний регистр, punycode, без порта по умолчанию
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if ip := net.ParseIP(host); ip == nil {
+		if host, err = idna.Lookup.ToASCII(host); err != nil {
+			return "", pkgerrors.ErrValidation
+		}
+	} else if ip.To4() == nil { // IPv6
+		host = "[" + host + "]"
+	}
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+
+	// Путь
+	path := normalizePercent(u.EscapedPath())
+	if u.Path, err = url.PathUnescape(path); err != nil {
+		return "", pkgerrors.ErrValidation
+	}
+	u.RawPath = path
+
+	// Query
+	query := normalizePercent(u.RawQuery)
+	if sortQuery && query != "" {
+		query = sortQueryParams(query)
+	}
+	u.RawQuery = query
+	u.ForceQuery = false
+
+	// Fragment
+	fragment := normalizePercent(u.EscapedFragment())
+	if u.Fragment, err = url.PathUnescape(fragment); err != nil {
+		return "", pkgerrors.ErrValidation
+	}
+	u.RawFragment = fragment
+
+	return u.String(), nil
+}
+
+// normalizePercent - нормализует percent-кодирование строки:
+// шестнадцатеричные цифры приводятся к верхнему регистру,
+// незарезервированные символы (RFC 3986, раздел 2.3) декодируются,
+// пробелы, управляющие и не-ASCII символы кодируются.
+func normalizePercent(s string) string {
+	const hex = "0123456789ABCDEF"
+	b := strings.Builder{}
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			decoded := unhex(s[i+1])<<4 | unhex(s[i+2])
+			if isUnreserved(decoded) {
+				b.WriteByte(decoded)
+			} else {
+				b.WriteByte('%')
+				b.WriteByte(hex[decoded>>4])
+				b.WriteByte(hex[decoded&0x0F])
+			}
+			i += 2
+		case c <= ' ' || c >= 0x7F:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0x0F])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// sortQueryParams - сортирует параметры query-строки по имени.
+// Порядок одноименных параметров сохраняется, значения не изменяются.
+func sortQueryParams(query string) string {
+	pairs := strings.Split(query, "&")
+	sort.SliceStable(pairs, func(i, j int) bool {
+		ki, _, _ := strings.Cut(pairs[i], "=")
+		kj, _, _ := strings.Cut(pairs[j], "=")
+		return ki < kj
+	})
+	return strings.Join(pairs, "&")
+}
+
+// isUnreserved - проверяет, является ли символ незарезервированным (RFC 3986, раздел 2.3)
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// isHex - проверяет, является ли символ шестнадцатеричной цифрой
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// unhex - возвращает значение шестнадцатеричной цифры
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
@@ -0,0 +1,188 @@
+package usecases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+const (
+	// clickBufferSize - размер буфера событий переходов, ожидающих сохранения.
+	// Если буфер заполнен, новые события отбрасываются.
+	clickBufferSize = 4096
+	// clickBatchSize - максимальное количество событий, сохраняемых в репозиторий за один раз
+	clickBatchSize = 256
+	// clickFlushInterval - интервал, с которым сохраняются накопленные события
+	clickFlushInterval = time.Second
+)
+
+// clickRecorder - асинхронная запись событий переходов в репозиторий.
+// События накапливаются в буфере и сохраняются пакетами в фоне,
+// поэтому запись события никогда не задерживает перенаправление.
+// Переходы по вариантам сплит-ссылок суммируются в пакете и сохраняются одним увеличением счетчика на вариант.
+type clickRecorder struct {
+	repo repo.IRepo
+	ch   chan clickEvent
+	done chan struct{}
+}
+
+// clickEvent - событие перехода и номер засчитанного варианта сплит-ссылки (0 - вариант не засчитывается)
+type clickEvent struct {
+	click   models.Click
+	variant int
+}
+
+// variantKey - вариант сплит-ссылки, по которому суммируются переходы в пакете
+type variantKey struct {
+	id      string
+	variant int
+}
+
+// newClickRecorder - конструктор clickRecorder.
+// Запускает фоновое сохранение событий, которое завершается вместе с контекстом stopCtx.
+func newClickRecorder(stopCtx context.Context, repo repo.IRepo) *clickRecorder {
+	c := &clickRecorder{
+		repo: repo,
+		ch:   make(chan clickEvent, clickBufferSize),
+		done: make(chan struct{}),
+	}
+	go c.run(stopCtx)
+	return c
+}
+
+// record - добавляет событие в буфер без ожидания.
+// Если variant больше 0, то переход засчитывается в счетчик варианта сплит-ссылки.
+// Если буфер заполнен, событие отбрасывается.
+func (c *clickRecorder) record(click models.Click, variant int) {
+	select {
+	case c.ch <- clickEvent{click: click, variant: variant}:
+	default:
+		log.Warn().Str("id", click.ShortURLID).Msg("click buffer is full, click dropped")
+	}
+}
+
+// wait - ожидает сохранения оставшихся в буфере событий после завершения контекста stopCtx.
+func (c *clickRecorder) wait() {
+	<-c.done
+}
+
+// run - сохраняет события из буфера пакетами по мере накопления либо по таймеру.
+// После завершения контекста сохраняет оставшиеся в буфере события.
+func (c *clickRecorder) run(stopCtx context.Context) {
+	defer close(c.done)
+	ticker := time.NewTicker(clickFlushInterval)
+	defer ticker.Stop()
+	batch := make([]clickEvent, 0, clickBatchSize)
+	for {
+		select {
+		case event := <-c.ch:
+			batch = append(batch, event)
+			if len(batch) >= clickBatchSize {
+				batch = c.flush(batch)
+			}
+		case <-ticker.C:
+			batch = c.flush(batch)
+		case <-stopCtx.Done():
+			for {
+				select {
+				case event := <-c.ch:
+					batch = append(batch, event)
+					if len(batch) >= clickBatchSize {
+						batch = c.flush(batch)
+					}
+				default:
+					c.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush - сохраняет пакет событий в репозиторий, обновляет скетчи уникальных посетителей
+// и счетчики вариантов сплит-ссылок и возвращает пустой пакет.
+// Контекст stopCtx здесь не используется: при остановке сервиса оставшиеся события тоже должны быть сохранены.
+func (c *clickRecorder) flush(batch []clickEvent) []clickEvent {
+	if len(batch) == 0 {
+		return batch
+	}
+	clicks := make([]models.Click, 0, len(batch))
+	variants := make(map[variantKey]int64)
+	for _, event := range batch {
+		clicks = append(clicks, event.click)
+		if event.variant > 0 {
+			variants[variantKey{id: event.click.ShortURLID, variant: event.variant}]++
+		}
+	}
+	if err := c.repo.ClickAddBatch(context.Background(), clicks); err != nil {
+		log.Err(err).Int("count", len(clicks)).Msg("failed to save clicks")
+	}
+	if err := c.repo.VisitorSketchMerge(context.Background(), visitorSketches(clicks)); err != nil {
+		log.Err(err).Int("count", len(clicks)).Msg("failed to save visitor sketches")
+	}
+	for key, n := range variants {
+		if err := c.repo.ShortURLVariantClick(context.Background(), key.id, key.variant, n); err != nil {
+			log.Err(err).Str("id", key.id).Msg("failed to count variant clicks")
+		}
+	}
+	return batch[:0]
+}
+
+// newClick - создает событие перехода по короткой ссылке
+// key - ключ хеша посетителя (см. visitorHash).
+func newClick(shortURL *models.ShortURL, visit *models.Visit, key []byte) models.Click {
+	click := models.Click{
+		ShortURLID: shortURL.ID,
+		Time:       visit.Time.UTC(),
+		Device:     deviceClass(visit.UserAgent),
+		IP:         anonymizeIP(visit.IP),
+		Country:    visit.Country,
+		Visitor:    visitorHash(key, visit),
+		Bot:        visit.Bot,
+	}
+	if ref, err := url.Parse(visit.Referer); err == nil {
+		click.RefererHost = ref.Hostname()
+	}
+	return click
+}
+
+// visitorHash - возвращает хеш IP-адреса и User-Agent посетителя для подсчета уникальных посетителей.
+// Полный IP-адрес в событии не сохраняется. Хеш вычисляется как HMAC-SHA256 с секретным ключом сервера,
+// поэтому IP-адрес нельзя восстановить из хеша перебором без знания ключа.
+func visitorHash(key []byte, visit *models.Visit) string {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(visit.IP.String()))
+	h.Write([]byte{0})
+	h.Write([]byte(visit.UserAgent))
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// visitorHashKey - возвращает ключ хеша посетителя, производный от секретного ключа сервера.
+// Секретный ключ не используется напрямую, чтобы хеши посетителей не были связаны с подписью токенов.
+func visitorHashKey(secret string) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte("visitor"))
+	return h.Sum(nil)
+}
+
+// anonymizeIP - обнуляет младшие биты IP-адреса:
+// для IPv4 сохраняется сеть /24, для IPv6 - сеть /48.
+// Для пустого адреса возвращает пустую строку.
+func anonymizeIP(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(48, 128)).String()
+}
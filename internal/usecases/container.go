@@ -11,14 +11,22 @@ import (
 type Container struct {
 	ShortURL *ShortURL
 	User     *User
+	APIKey   *APIKey
+	Team     *Team
+	Quota    *Quota
 	Health   *Health
+	Stats    *ServiceStats
 }
 
 // NewContainer - конструктор Container
 func NewContainer(ctx context.Context, cfg *config.Config, repo repo.IRepo) *Container {
 	return &Container{
-		ShortURL: NewShortURL(ctx, repo, cfg.BaseURL.String()),
+		ShortURL: NewShortURL(ctx, cfg, repo),
 		User:     NewUser(repo),
+		APIKey:   NewAPIKey(repo),
+		Team:     NewTeam(repo),
+		Quota:    NewQuota(cfg, repo),
 		Health:   NewHealth(repo),
+		Stats:    NewServiceStats(repo),
 	}
 }
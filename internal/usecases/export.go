@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+)
+
+// ExportClicks - передает в fn события переходов по ссылке id пользователя userID за период [from, until)
+// в порядке времени, включая переходы ботов. События не загружаются в память целиком.
+// Нулевое значение from или until означает, что период не ограничен с соответствующей стороны.
+// Если fn возвращает ошибку, выгрузка прекращается и ExportClicks возвращает эту ошибку.
+func (u ShortURL) ExportClicks(ctx context.Context, userID uint, id string, from, until time.Time, fn func(models.Click) error) error {
+	ctx, span := tracer.Start(ctx, "ShortURL.ExportClicks")
+	defer span.End()
+	// Проверяем параметры
+	if !from.IsZero() && !until.IsZero() && !until.After(from) {
+		return pkgerrors.ErrValidation
+	}
+
+	// Проверяем, что ссылка принадлежит пользователю
+	if _, err := u.GetOwned(ctx, userID, id); err != nil {
+		return err
+	}
+
+	// Выгружаем события, отличая ошибки получателя от ошибок репозитория
+	var fnErr error
+	err := u.repo.ClickIterate(ctx, id, from.UTC(), until.UTC(), func(click models.Click) error {
+		fnErr = fn(click)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		log.Err(err).Str("id", id).Msg("failed to export clicks")
+		return pkgerrors.ErrInternal
+	}
+	return nil
+}
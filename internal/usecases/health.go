@@ -23,8 +23,10 @@ func NewHealth(repo repo.IRepo) *Health {
 
 // Check - выполняет проверку приложения
 func (u *Health) Check(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "Health.Check")
+	defer span.End()
 	// Если используется SQL-репозиторий, то проверяем подключение к БД.
-	if sqlRepo, ok := u.repo.(*repo.SQLRepo); ok {
+	if sqlRepo, ok := repo.Unwrap(u.repo).(*repo.SQLRepo); ok {
 		if db := sqlRepo.DB(); db != nil {
 			if err := db.PingContext(ctx); err != nil {
 				log.Err(err).Msg("failed to ping db")
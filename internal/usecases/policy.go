@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/providers/policy"
+)
+
+// defaultPolicies - политики проверки адресов назначения, которые действуют всегда:
+// запрет ссылок на сам сервис и на локальные и частные адреса.
+func defaultPolicies(cfg *config.Config) policy.Chain {
+	return policy.Chain{
+		policy.NewSelfReference(cfg.BaseURL),
+		policy.NewPrivateAddress(),
+	}
+}
+
+// UsePolicies - добавляет политики проверки адресов назначения к цепочке политик.
+func (u *ShortURL) UsePolicies(p ...policy.Policy) *ShortURL {
+	u.policies = append(u.policies, p...)
+	return u
+}
+
+// checkPolicies - проверяет адрес назначения цепочкой политик.
+// Адрес проверяется в каноническом виде.
+// Если адрес не допускается, возвращает ErrValidation с описанием причины.
+func (u ShortURL) checkPolicies(ctx context.Context, rawURL string) error {
+	canonical, err := canonicalURL(rawURL, false)
+	if err != nil {
+		return err
+	}
+	parsed, err := url.Parse(canonical)
+	if err != nil {
+		return pkgerrors.ErrValidation
+	}
+	if err = u.policies.Check(ctx, parsed); err != nil {
+		return pkgerrors.ErrValidation.WithDetail(err.Error())
+	}
+	return nil
+}
+
+// checkDestinations - проверяет цепочкой политик все адреса назначения ссылки:
+// оригинальный URL, адреса вариантов сплит-ссылки и адреса правил перенаправления.
+func (u ShortURL) checkDestinations(ctx context.Context, shortURL *models.ShortURL) error {
+	if err := u.checkPolicies(ctx, shortURL.OriginalURL); err != nil {
+		return err
+	}
+	if shortURL.Split != nil {
+		for _, v := range shortURL.Split.Variants {
+			if err := u.checkPolicies(ctx, v.URL); err != nil {
+				return err
+			}
+		}
+	}
+	for _, rule := range shortURL.Rules {
+		if err := u.checkPolicies(ctx, rule.URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package usecases
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+)
+
+// placeholderRe - регулярное выражение для поиска плейсхолдеров в значениях шаблона
+var placeholderRe = regexp.MustCompile(`\{[^{}]*}`)
+
+// knownPlaceholders - список поддерживаемых плейсхолдеров
+var knownPlaceholders = map[string]struct{}{
+	models.PlaceholderID:           {},
+	models.PlaceholderReferrerHost: {},
+	models.PlaceholderDate:         {},
+}
+
+// validateQueryTemplate - проверяет шаблон query-параметров:
+//   - количество параметров не превышает models.QueryParamsMaxCount
+//   - ключи параметров не пустые
+//   - значения содержат только поддерживаемые плейсхолдеры
+//   - политика слияния известна (пустая политика означает models.QueryMergeKeep)
+func validateQueryTemplate(t *models.QueryTemplate) error {
+	if t == nil {
+		return nil
+	}
+	if len(t.Params) == 0 || len(t.Params) > models.QueryParamsMaxCount {
+		return pkgerrors.ErrValidation
+	}
+	switch t.Policy {
+	case "", models.QueryMergeKeep, models.QueryMergeOverride, models.QueryMergeAppend:
+	default:
+		return pkgerrors.ErrValidation
+	}
+	for key, value := range t.Params {
+		if strings.TrimSpace(key) == "" {
+			return pkgerrors.ErrValidation
+		}
+		for _, p := range placeholderRe.FindAllString(value, -1) {
+			if _, ok := knownPlaceholders[p]; !ok {
+				return pkgerrors.ErrValidation
+			}
+		}
+	}
+	return nil
+}
+
+// applyQueryTemplate - добавляет к rawURL query-параметры из шаблона t,
+// подставляя в значения параметров плейсхолдеры из vars.
+//
+// Существующие параметры rawURL сохраняются в исходном виде и порядке.
+// Параметры шаблона добавляются в конец строки запроса в порядке сортировки ключей.
+// Параметры, значение которых после подстановки оказалось пустым, не добавляются:
+// например, utm_source={referrer_host} при переходе без заголовка Referer.
+func applyQueryTemplate(rawURL string, t *models.QueryTemplate, vars map[string]string) (string, error) {
+	if t == nil || len(t.Params) == 0 {
+		return rawURL, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	// Подставляем плейсхолдеры
+	oldnew := make([]string, 0, len(vars)*2)
+	for k, v := range vars {
+		oldnew = append(oldnew, k, v)
+	}
+	replacer := strings.NewReplacer(oldnew...)
+	params := make(map[string]string, len(t.Params))
+	keys := make([]string, 0, len(t.Params))
+	for key, value := range t.Params {
+		value = replacer.Replace(value)
+		if value == "" {
+			continue
+		}
+		params[key] = value
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Разбираем существующую строку запроса на пары, не меняя их кодирование
+	var pairs []string
+	existing := make(map[string]struct{})
+	if u.RawQuery != "" {
+		for _, pair := range strings.Split(u.RawQuery, "&") {
+			if pair == "" {
+				continue
+			}
+			rawKey, _, _ := strings.Cut(pair, "=")
+			key, err := url.QueryUnescape(rawKey)
+			if err != nil {
+				key = rawKey
+			}
+			existing[key] = struct{}{}
+			// При политике override отбрасываем значения, которые будут заменены
+			if _, ok := params[key]; ok && t.Policy == models.QueryMergeOverride {
+				continue
+			}
+			pairs = append(pairs, pair)
+		}
+	}
+
+	// Добавляем параметры шаблона в соответствии с политикой слияния
+	for _, key := range keys {
+		if _, ok := existing[key]; ok && (t.Policy == "" || t.Policy == models.QueryMergeKeep) {
+			continue
+		}
+		pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(params[key]))
+	}
+
+	u.RawQuery = strings.Join(pairs, "&")
+	return u.String(), nil
+}
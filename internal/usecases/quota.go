@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+// quotaWindow - период, за который считается количество созданных ссылок
+const quotaWindow = 24 * time.Hour
+
+// Quota - бизнес-логика для работы с квотами пользователей.
+//
+// Квоты пользователя определяются его тарифным планом (см. config.Config.QuotaPlans).
+// Пользователям без плана или с планом, отсутствующим в конфигурации, назначаются квоты по умолчанию.
+// Количество созданных за сутки ссылок считается за последние 24 часа, в тч удаленные ссылки.
+type Quota struct {
+	repo  repo.IRepo
+	def   models.Quota
+	plans map[string]models.Quota
+	now   func() time.Time
+}
+
+// NewQuota - конструктор Quota
+func NewQuota(cfg *config.Config, repo repo.IRepo) *Quota {
+	return &Quota{
+		repo:  repo,
+		def:   cfg.DefaultQuota(),
+		plans: cfg.QuotaPlans,
+		now:   time.Now,
+	}
+}
+
+// Usage - возвращает квоты пользователя и их текущее использование.
+// Если пользователь не найден, возвращает ErrNotFound.
+func (u Quota) Usage(ctx context.Context, userID uint) (*models.QuotaUsage, error) {
+	ctx, span := tracer.Start(ctx, "Quota.Usage")
+	defer span.End()
+	user, err := u.repo.UserGetByID(ctx, userID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkgerrors.ErrNotFound
+	} else if err != nil {
+		log.Err(err).Msg("failed to get user by id")
+		return nil, pkgerrors.ErrInternal
+	}
+	active, created, err := u.repo.ShortURLCountByUserID(ctx, userID, u.now().Add(-quotaWindow))
+	if err != nil {
+		log.Err(err).Msg("failed to count short urls by user id")
+		return nil, pkgerrors.ErrInternal
+	}
+	return &models.QuotaUsage{
+		Plan:        user.Plan,
+		Quota:       u.quota(user.Plan),
+		ActiveURLs:  active,
+		URLsLastDay: created,
+	}, nil
+}
+
+// SetPlan - назначает пользователю тарифный план.
+// Пустой план означает квоты по умолчанию.
+// Если план отсутствует в конфигурации, возвращает ErrValidation.
+// Если пользователь не найден, возвращает ErrNotFound.
+func (u Quota) SetPlan(ctx context.Context, userID uint, plan string) error {
+	ctx, span := tracer.Start(ctx, "Quota.SetPlan")
+	defer span.End()
+	if _, ok := u.plans[plan]; !ok && plan != "" {
+		return pkgerrors.ErrValidation.WithDetail("unknown plan: " + plan)
+	}
+	err := u.repo.UserSetPlan(ctx, userID, plan)
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkgerrors.ErrNotFound
+	} else if err != nil {
+		log.Err(err).Msg("failed to set user plan")
+		return pkgerrors.ErrInternal
+	}
+	return nil
+}
+
+// quota - возвращает квоты тарифного плана либо квоты по умолчанию
+func (u Quota) quota(plan string) models.Quota {
+	if q, ok := u.plans[plan]; ok {
+		return q
+	}
+	return u.def
+}
+
+// limit - возвращает ограничения репозитория для квот тарифного плана.
+// Квоты проверяются репозиторием в той же операции, что и создание ссылок.
+func (u Quota) limit(plan string) repo.Limit {
+	q := u.quota(plan)
+	return repo.Limit{
+		MaxActive:  q.MaxActiveURLs,
+		MaxCreated: q.URLsPerDay,
+		Since:      u.now().Add(-quotaWindow),
+	}
+}
+
+// checkBatchSize - возвращает ErrQuotaExceeded, если размер пакетного запроса n превышает квоту тарифного плана.
+func (u Quota) checkBatchSize(plan string, n int) error {
+	if q := u.quota(plan); q.MaxBatchSize > 0 && n > q.MaxBatchSize {
+		return pkgerrors.ErrQuotaExceeded.WithDetail(fmt.Sprintf("batch size limit is %d", q.MaxBatchSize))
+	}
+	return nil
+}
+
+// exceeded - возвращает ErrQuotaExceeded с описанием квоты, которую превысит создание еще n ссылок.
+// Вызывается, когда репозиторий отклонил создание ссылок.
+func (u Quota) exceeded(ctx context.Context, userID uint, n int) error {
+	usage, err := u.Usage(ctx, userID)
+	if err != nil {
+		return pkgerrors.ErrQuotaExceeded
+	}
+	if err = checkQuota(usage, n); err != nil {
+		return err
+	}
+	return pkgerrors.ErrQuotaExceeded
+}
+
+// checkQuota - возвращает ErrQuotaExceeded, если создание еще n ссылок превысит квоты
+func checkQuota(usage *models.QuotaUsage, n int) error {
+	if usage.MaxActiveURLs > 0 && usage.ActiveURLs+n > usage.MaxActiveURLs {
+		return pkgerrors.ErrQuotaExceeded.WithDetail(fmt.Sprintf("active links limit is %d", usage.MaxActiveURLs))
+	}
+	if usage.URLsPerDay > 0 && usage.URLsLastDay+n > usage.URLsPerDay {
+		return pkgerrors.ErrQuotaExceeded.WithDetail(fmt.Sprintf("daily links limit is %d", usage.URLsPerDay))
+	}
+	return nil
+}
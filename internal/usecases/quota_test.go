@@ -0,0 +1,148 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+type quotaSuite struct {
+	suite.Suite
+	*Quota
+	shortURL *ShortURL
+	repo     *repo.MemoryRepo
+	userID   uint
+}
+
+func TestQuotaSuite(t *testing.T) {
+	suite.Run(t, new(quotaSuite))
+}
+
+func (suite *quotaSuite) SetupTest() {
+	os.Clearenv()
+	cfg, err := config.Default(nil)
+	suite.Require().NoError(err)
+	cfg.QuotaMaxActiveURLs = 3
+	cfg.QuotaURLsPerDay = 4
+	cfg.QuotaMaxBatchSize = 2
+	cfg.QuotaPlans = map[string]models.Quota{"unlimited": {}}
+	suite.repo = repo.NewMemoryRepo()
+	suite.Quota = NewQuota(cfg, suite.repo)
+	suite.shortURL = NewShortURL(context.Background(), cfg, suite.repo)
+	suite.shortURL.quota = suite.Quota
+	user := &models.User{}
+	suite.Require().NoError(suite.repo.UserCreate(context.Background(), user))
+	suite.userID = user.ID
+}
+
+func (suite *quotaSuite) TestCreate() {
+	ctx := context.Background()
+	var ids []string
+	for _, u := range []string{"https://a.com", "https://b.com", "https://c.com"} {
+		shortURL, err := suite.shortURL.Create(ctx, suite.userID, u)
+		suite.Require().NoError(err)
+		ids = append(ids, shortURL.ID)
+	}
+
+	// Превышена квота активных ссылок
+	_, err := suite.shortURL.Create(ctx, suite.userID, "https://d.com")
+	suite.ErrorIs(err, pkgerrors.ErrQuotaExceeded)
+
+	// Удаленная ссылка освобождает квоту активных ссылок, но не суточную квоту
+	suite.Require().NoError(suite.repo.ShortURLDelete(ctx, suite.userID, ids[0]))
+	_, err = suite.shortURL.Create(ctx, suite.userID, "https://d.com")
+	suite.NoError(err)
+	suite.Require().NoError(suite.repo.ShortURLDelete(ctx, suite.userID, ids[1]))
+	_, err = suite.shortURL.Create(ctx, suite.userID, "https://e.com")
+	suite.ErrorIs(err, pkgerrors.ErrQuotaExceeded)
+
+	// Через сутки суточная квота восстанавливается
+	suite.now = func() time.Time { return time.Now().Add(quotaWindow + time.Minute) }
+	_, err = suite.shortURL.Create(ctx, suite.userID, "https://e.com")
+	suite.NoError(err)
+
+	usage, err := suite.Usage(ctx, suite.userID)
+	suite.NoError(err)
+	suite.Equal(3, usage.ActiveURLs)
+
+	_, err = suite.Usage(ctx, 100)
+	suite.ErrorIs(err, pkgerrors.ErrNotFound)
+}
+
+func (suite *quotaSuite) TestCreateDuplicate() {
+	ctx := context.Background()
+	for _, u := range []string{"https://a.com", "https://b.com", "https://c.com"} {
+		_, err := suite.shortURL.Create(ctx, suite.userID, u)
+		suite.Require().NoError(err)
+	}
+	// Существующая ссылка возвращается с ErrDuplicate и при исчерпанной квоте
+	shortURL, err := suite.shortURL.Create(ctx, suite.userID, "https://a.com")
+	suite.ErrorIs(err, pkgerrors.ErrDuplicate)
+	suite.Equal("https://a.com", shortURL.OriginalURL)
+}
+
+func (suite *quotaSuite) TestCreateBatch() {
+	ctx := context.Background()
+	batch := func(urls ...string) []BatchItem {
+		items := make([]BatchItem, len(urls))
+		for i, u := range urls {
+			items[i] = BatchItem{OriginalURL: u}
+		}
+		return items
+	}
+
+	// Превышен размер пакета
+	_, err := suite.shortURL.CreateBatch(ctx, suite.userID, batch("https://a.com", "https://b.com", "https://c.com"))
+	suite.ErrorIs(err, pkgerrors.ErrQuotaExceeded)
+
+	// Повторы URL внутри пакета создаются один раз
+	shortURLs, err := suite.shortURL.CreateBatch(ctx, suite.userID, batch("https://a.com", "https://a.com"))
+	suite.Require().NoError(err)
+	suite.Require().Len(shortURLs, 2)
+	suite.Equal(shortURLs[0].ID, shortURLs[1].ID)
+
+	// Существующие ссылки не учитываются в квоте
+	shortURLs, err = suite.shortURL.CreateBatch(ctx, suite.userID, batch("https://a.com", "https://b.com"))
+	suite.Require().NoError(err)
+	suite.Equal("https://b.com", shortURLs[1].OriginalURL)
+
+	// Пакет, превышающий квоту активных ссылок, не создается частично
+	_, err = suite.shortURL.CreateBatch(ctx, suite.userID, batch("https://c.com", "https://d.com"))
+	suite.ErrorIs(err, pkgerrors.ErrQuotaExceeded)
+	_, err = suite.shortURL.GetByOriginalURL(ctx, suite.userID, "https://c.com")
+	suite.ErrorIs(err, pkgerrors.ErrNotFound)
+
+	usage, err := suite.Usage(ctx, suite.userID)
+	suite.NoError(err)
+	suite.Equal(2, usage.ActiveURLs)
+}
+
+func (suite *quotaSuite) TestSetPlan() {
+	ctx := context.Background()
+	suite.ErrorIs(suite.SetPlan(ctx, suite.userID, "pro"), pkgerrors.ErrValidation)
+	suite.ErrorIs(suite.SetPlan(ctx, 100, "unlimited"), pkgerrors.ErrNotFound)
+
+	// Без ограничений в плане unlimited
+	suite.Require().NoError(suite.SetPlan(ctx, suite.userID, "unlimited"))
+	suite.NoError(suite.checkBatchSize("unlimited", 10000))
+	now := time.Now()
+	suite.now = func() time.Time { return now }
+	suite.Equal(repo.Limit{Since: now.Add(-quotaWindow)}, suite.limit("unlimited"))
+	usage, err := suite.Usage(ctx, suite.userID)
+	suite.NoError(err)
+	suite.Equal(&models.QuotaUsage{Plan: "unlimited"}, usage)
+
+	// Пустой план возвращает квоты по умолчанию
+	suite.Require().NoError(suite.SetPlan(ctx, suite.userID, ""))
+	usage, err = suite.Usage(ctx, suite.userID)
+	suite.NoError(err)
+	suite.Equal(models.Quota{MaxActiveURLs: 3, URLsPerDay: 4, MaxBatchSize: 2}, usage.Quota)
+}
@@ -0,0 +1,211 @@
+package usecases
+
+import (
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // База часовых поясов для расписаний правил, если в системе она отсутствует
+
+	"golang.org/x/text/language"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+)
+
+// scheduleTimeLayout - формат времени суток в расписании
+const scheduleTimeLayout = "15:04"
+
+// weekdays - соответствие дней недели в расписании и time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// locations - кеш часовых поясов расписаний по их названиям, чтобы не загружать их при каждом переходе
+var locations sync.Map
+
+// loadLocation - возвращает часовой пояс по названию в формате IANA, загружая его не более одного раза
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// validateRules - проверяет список правил перенаправления
+func (u ShortURL) validateRules(rules []models.RedirectRule) error {
+	if len(rules) > models.RedirectRulesMaxCount {
+		return pkgerrors.ErrValidation
+	}
+	for _, rule := range rules {
+		if err := u.validateURL(rule.URL); err != nil {
+			return err
+		}
+		for _, d := range rule.Devices {
+			if d != models.DeviceIOS && d != models.DeviceAndroid && d != models.DeviceDesktop {
+				return pkgerrors.ErrValidation
+			}
+		}
+		for _, lang := range rule.Languages {
+			if _, err := language.Parse(lang); err != nil {
+				return pkgerrors.ErrValidation
+			}
+		}
+		if err := validateSchedule(rule.Schedule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSchedule - проверяет расписание правила перенаправления
+func validateSchedule(s *models.Schedule) error {
+	if s == nil {
+		return nil
+	}
+	for _, day := range s.Weekdays {
+		if _, ok := weekdays[day]; !ok {
+			return pkgerrors.ErrValidation
+		}
+	}
+	// Интервал задается либо полностью, либо не задается вовсе
+	if (s.From == "") != (s.Until == "") {
+		return pkgerrors.ErrValidation
+	}
+	if s.From != "" {
+		from, err := time.Parse(scheduleTimeLayout, s.From)
+		if err != nil {
+			return pkgerrors.ErrValidation
+		}
+		until, err := time.Parse(scheduleTimeLayout, s.Until)
+		if err != nil {
+			return pkgerrors.ErrValidation
+		}
+		// Пустой интервал никогда не срабатывает: для круглосуточного правила интервал не задается
+		if from.Equal(until) {
+			return pkgerrors.ErrValidation
+		}
+	}
+	if _, err := loadLocation(s.TimeZone); err != nil {
+		return pkgerrors.ErrValidation
+	}
+	return nil
+}
+
+// matchRule - возвращает первое сработавшее правило перенаправления либо nil
+func matchRule(rules []models.RedirectRule, visit *models.Visit) *models.RedirectRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	device := deviceClass(visit.UserAgent)
+	lang := preferredLanguage(visit.AcceptLanguage)
+	for i := range rules {
+		if matchDevice(rules[i].Devices, device) &&
+			matchLanguage(rules[i].Languages, lang) &&
+			matchSchedule(rules[i].Schedule, visit.Time) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// deviceClass - определяет класс устройства посетителя по заголовку User-Agent
+func deviceClass(userAgent string) models.Device {
+	switch ua := strings.ToLower(userAgent); {
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"), strings.Contains(ua, "ipod"):
+		return models.DeviceIOS
+	case strings.Contains(ua, "android"):
+		return models.DeviceAndroid
+	default:
+		return models.DeviceDesktop
+	}
+}
+
+// preferredLanguage - возвращает предпочтительный язык посетителя из заголовка Accept-Language
+// в нижнем регистре (например, "en-us") либо пустую строку.
+func preferredLanguage(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+	return strings.ToLower(tags[0].String())
+}
+
+// matchDevice - проверяет условие правила по классу устройства
+func matchDevice(devices []models.Device, device models.Device) bool {
+	if len(devices) == 0 {
+		return true
+	}
+	for _, d := range devices {
+		if d == device {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLanguage - проверяет условие правила по языку посетителя
+func matchLanguage(languages []string, lang string) bool {
+	if len(languages) == 0 {
+		return true
+	}
+	for _, l := range languages {
+		l = strings.ToLower(l)
+		if lang == l || strings.HasPrefix(lang, l+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSchedule - проверяет условие правила по расписанию
+func matchSchedule(s *models.Schedule, t time.Time) bool {
+	if s == nil {
+		return true
+	}
+	loc, err := loadLocation(s.TimeZone)
+	if err != nil {
+		return false
+	}
+	t = t.In(loc)
+
+	if len(s.Weekdays) > 0 {
+		found := false
+		for _, day := range s.Weekdays {
+			if weekdays[day] == t.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if s.From == "" {
+		return true
+	}
+	from, err1 := time.Parse(scheduleTimeLayout, s.From)
+	until, err2 := time.Parse(scheduleTimeLayout, s.Until)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	now := t.Hour()*60 + t.Minute()
+	start := from.Hour()*60 + from.Minute()
+	end := until.Hour()*60 + until.Minute()
+	if start <= end {
+		return now >= start && now < end
+	}
+	// Интервал через полночь
+	return now >= start || now < end
+}
@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/repo"
+	"github.com/ofstudio/go-shortener/pkg/hll"
+)
+
+// ServiceStats - статистика сервиса для внутреннего API
+type ServiceStats struct {
+	repo      repo.IRepo
+	startedAt time.Time
+	build     models.BuildInfo
+}
+
+// NewServiceStats - конструктор ServiceStats.
+// Время работы сервиса отсчитывается от момента создания.
+func NewServiceStats(repo repo.IRepo) *ServiceStats {
+	return &ServiceStats{
+		repo:      repo,
+		startedAt: time.Now().UTC().Truncate(time.Second),
+		build:     models.BuildInfo{Version: "N/A", Date: "N/A", Commit: "N/A"},
+	}
+}
+
+// UseBuildInfo - задает информацию о сборке приложения.
+func (u *ServiceStats) UseBuildInfo(build models.BuildInfo) *ServiceStats {
+	u.build = build
+	return u
+}
+
+// Get - возвращает статистику сервиса
+func (u ServiceStats) Get(ctx context.Context) (*models.ServiceStats, error) {
+	ctx, span := tracer.Start(ctx, "ServiceStats.Get")
+	defer span.End()
+	now := time.Now().UTC()
+	storage, err := u.repo.StorageStats(ctx, now)
+	if err != nil {
+		log.Err(err).Msg("failed to get storage stats")
+		return nil, pkgerrors.ErrInternal
+	}
+	stats := &models.ServiceStats{
+		StorageStats:        *storage,
+		UniqueVisitorsError: hll.RelativeError(visitorSketchPrecision),
+		Backend:             repo.BackendName(u.repo),
+		StartedAt:           u.startedAt,
+		Uptime:              now.Sub(u.startedAt).Truncate(time.Second),
+		Build:               u.build,
+	}
+
+	// Оцениваем уникальных посетителей всех ссылок по скетчам за последние 30 суток
+	today := now.Truncate(24 * time.Hour)
+	sketches, err := u.repo.VisitorSketchGet(ctx, models.VisitorSketchAll, today.AddDate(0, 0, -29), today.AddDate(0, 0, 1))
+	if err != nil {
+		log.Err(err).Msg("failed to get visitor sketches")
+		return nil, pkgerrors.ErrInternal
+	}
+	if stats.UniqueVisitorsMonth, err = approxUnique(sketches); err != nil {
+		log.Err(err).Msg("failed to merge visitor sketches")
+		return nil, pkgerrors.ErrInternal
+	}
+	if n := len(sketches); n > 0 && sketches[n-1].Day.Equal(today) {
+		stats.UniqueVisitorsDay = int64(sketches[n-1].Sketch.Count())
+	}
+	return stats, nil
+}
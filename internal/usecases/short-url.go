@@ -4,76 +4,360 @@ import (
 	"context"
 	"errors"
 	"net/url"
+	"time"
+	"unicode/utf8"
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/ofstudio/go-shortener/internal/config"
 	"github.com/ofstudio/go-shortener/internal/models"
 	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/providers/botdetect"
+	"github.com/ofstudio/go-shortener/internal/providers/metrics"
+	"github.com/ofstudio/go-shortener/internal/providers/policy"
 	"github.com/ofstudio/go-shortener/internal/repo"
 	"github.com/ofstudio/go-shortener/pkg/shortid"
 )
 
 // ShortURL - бизнес-логика для сокращенных ссылок
 type ShortURL struct {
-	repo    repo.IRepo
-	stopCtx context.Context // Контекст для остановки фоновых задач
-	baseURL string
+	repo          repo.IRepo
+	stopCtx       context.Context // Контекст для остановки фоновых задач
+	baseURL       string
+	interstitial  bool         // Показывать страницу-предупреждение для всех ссылок
+	sortQuery     bool         // Сортировать query-параметры при приведении URL к каноническому виду
+	policies      policy.Chain // Политики проверки адресов назначения
+	bots          botdetect.Classifier
+	botPreview    bool // Отдавать ботам легкую страницу вместо перенаправления
+	clicks        *clickRecorder
+	visitorSecret []byte // Ключ хеша посетителя в событиях переходов
+	metrics       metrics.Recorder
+	quota         *Quota
 }
 
 // NewShortURL - конструктор ShortURL
-func NewShortURL(stopCtx context.Context, repo repo.IRepo, baseURL string) *ShortURL {
+func NewShortURL(stopCtx context.Context, cfg *config.Config, repo repo.IRepo) *ShortURL {
 	return &ShortURL{
-		stopCtx: stopCtx,
-		repo:    repo,
-		baseURL: baseURL,
+		stopCtx:       stopCtx,
+		repo:          repo,
+		baseURL:       cfg.BaseURL.String(),
+		interstitial:  cfg.Interstitial,
+		sortQuery:     cfg.SortQueryParams,
+		policies:      defaultPolicies(cfg),
+		bots:          botdetect.NewDefaultRules(),
+		botPreview:    cfg.BotPreview,
+		clicks:        newClickRecorder(stopCtx, repo),
+		visitorSecret: visitorHashKey(visitorSecret(cfg)),
+		metrics:       metrics.Nop{},
+		quota:         NewQuota(cfg, repo),
 	}
 }
 
-// Create - создает и возвращает ShortURL
-func (u ShortURL) Create(ctx context.Context, userID uint, OriginalURL string) (*models.ShortURL, error) {
-	// Проверяем URL на валидность
+// visitorSecret - возвращает секретный ключ сервера для хеша посетителя: VisitorSecret, а если он не задан - AuthSecret.
+// AuthSecret используется, только если данные хранятся в памяти (см. config.Config.ValidateVisitorSecret).
+func visitorSecret(cfg *config.Config) string {
+	if cfg.VisitorSecret != "" {
+		return cfg.VisitorSecret
+	}
+	return cfg.AuthSecret
+}
+
+// UseMetrics - задает учет событий бизнес-логики: очереди ссылок на удаление.
+func (u *ShortURL) UseMetrics(m metrics.Recorder) *ShortURL {
+	u.metrics = m
+	return u
+}
+
+// CreateOpt - дополнительный параметр создаваемой ShortURL
+type CreateOpt func(*models.ShortURL)
+
+// WithQueryTemplate - задает шаблон query-параметров, которые добавляются к оригинальному URL при переходе.
+func WithQueryTemplate(t *models.QueryTemplate) CreateOpt {
+	return func(s *models.ShortURL) {
+		s.QueryTemplate = t
+	}
+}
+
+// WithSplit - задает параметры сплит-ссылки: переходы распределяются между вариантами адресов по их весам.
+func WithSplit(split *models.Split) CreateOpt {
+	return func(s *models.ShortURL) {
+		s.Split = split
+	}
+}
+
+// WithTitle - задает заголовок ссылки, который показывается на странице предпросмотра.
+func WithTitle(title string) CreateOpt {
+	return func(s *models.ShortURL) {
+		s.Title = title
+	}
+}
+
+// WithInterstitial - включает показ страницы-предупреждения перед переходом по ссылке.
+func WithInterstitial(interstitial bool) CreateOpt {
+	return func(s *models.ShortURL) {
+		s.Interstitial = interstitial
+	}
+}
+
+// WithActiveWindow - задает период действия ссылки. Любая из границ может быть не задана (nil).
+func WithActiveWindow(from, until *time.Time) CreateOpt {
+	return func(s *models.ShortURL) {
+		s.ActiveFrom = from
+		s.ActiveUntil = until
+	}
+}
+
+// WithTeam - передает ссылку команде teamID: ссылка становится доступна участникам команды в соответствии с их ролями.
+func WithTeam(teamID uint) CreateOpt {
+	return func(s *models.ShortURL) {
+		s.TeamID = teamID
+	}
+}
+
+// BatchItem - элемент пакета ссылок для ShortURL.CreateBatch
+type BatchItem struct {
+	OriginalURL string
+	Opts        []CreateOpt
+}
+
+// Create - создает и возвращает ShortURL.
+// URL приводится к каноническому виду, переданный пользователем URL сохраняется в ShortURL.SubmittedURL.
+// Если у пользователя уже есть простая ссылка (см. models.ShortURL.Plain) с таким URL (в каноническом виде),
+// а создаваемая ссылка тоже простая, возвращает существующую ShortURL без изменений и ошибку ErrDuplicate:
+// существующая ссылка не учитывается в квотах пользователя. Ссылка с дополнительными параметрами всегда создается новой,
+// изменить их можно методами SetRules, SetQueryTemplate, SetPreview, SetActiveWindow и SetTeam.
+// Создать ссылку команды могут только ее владельцы и редакторы, иначе возвращает ErrForbidden.
+// Если квота пользователя будет превышена, возвращает ErrQuotaExceeded.
+func (u ShortURL) Create(ctx context.Context, userID uint, OriginalURL string, opts ...CreateOpt) (*models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.Create")
+	defer span.End()
+	shortURL, err := u.newShortURL(ctx, userID, OriginalURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Проверяем права пользователя в команде
+	if shortURL.TeamID != 0 {
+		if err = u.checkTeamEditor(ctx, userID, shortURL.TeamID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Сохраняем модель в репозиторий
+	shortURLs := []*models.ShortURL{shortURL}
+	dup, err := u.store(ctx, user, shortURLs)
+	if err != nil {
+		return nil, err
+	}
+	// Если такой URL уже существует, возвращаем его и ErrDuplicate
+	if dup[0] {
+		return shortURLs[0], pkgerrors.ErrDuplicate
+	}
+	return shortURLs[0], nil
+}
+
+// CreateBatch - создает пакет ShortURL пользователя и возвращает их в порядке элементов пакета.
+// Каждый элемент обрабатывается так же, как в Create, но для уже существующих URL ошибка ErrDuplicate не возвращается.
+// Размер пакета и квоты проверяются один раз для всего пакета: создаются либо все новые ссылки пакета, либо ни одной.
+// Если размер пакета или квота будут превышены, возвращает ErrQuotaExceeded.
+func (u ShortURL) CreateBatch(ctx context.Context, userID uint, items []BatchItem) ([]*models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.CreateBatch")
+	defer span.End()
+	user, err := u.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.quota.checkBatchSize(user.Plan, len(items)); err != nil {
+		return nil, err
+	}
+
+	shortURLs := make([]*models.ShortURL, len(items))
+	teams := make(map[uint]struct{})
+	for i, item := range items {
+		if shortURLs[i], err = u.newShortURL(ctx, userID, item.OriginalURL, item.Opts...); err != nil {
+			return nil, err
+		}
+		if teamID := shortURLs[i].TeamID; teamID != 0 {
+			teams[teamID] = struct{}{}
+		}
+	}
+
+	// Проверяем права пользователя в командах
+	for teamID := range teams {
+		if err = u.checkTeamEditor(ctx, userID, teamID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Сохраняем модели в репозиторий
+	if _, err = u.store(ctx, user, shortURLs); err != nil {
+		return nil, err
+	}
+	return shortURLs, nil
+}
+
+// newShortURL - проверяет URL и дополнительные параметры и возвращает новую ShortURL пользователя.
+func (u ShortURL) newShortURL(ctx context.Context, userID uint, OriginalURL string, opts ...CreateOpt) (*models.ShortURL, error) {
+	// Проверяем URL на валидность и приводим к каноническому виду
 	if err := u.validateURL(OriginalURL); err != nil {
 		return nil, err
 	}
+	canonical, err := canonicalURL(OriginalURL, u.sortQuery)
+	if err != nil {
+		return nil, err
+	}
+	// Применяем дополнительные параметры и проверяем их на валидность
+	shortURL := &models.ShortURL{
+		ID:           shortid.Generate(),
+		OriginalURL:  canonical,
+		SubmittedURL: OriginalURL,
+		UserID:       userID,
+		CreatedAt:    time.Now().UTC().Truncate(time.Second),
+	}
+	for _, opt := range opts {
+		opt(shortURL)
+	}
+	if err := validateTitle(shortURL.Title); err != nil {
+		return nil, err
+	}
+	if err := validateActiveWindow(shortURL.ActiveFrom, shortURL.ActiveUntil); err != nil {
+		return nil, err
+	}
+	if err := validateQueryTemplate(shortURL.QueryTemplate); err != nil {
+		return nil, err
+	}
+	if err := u.validateSplit(shortURL.Split); err != nil {
+		return nil, err
+	}
+	if err := u.checkDestinations(ctx, shortURL); err != nil {
+		return nil, err
+	}
+	return shortURL, nil
+}
 
-	// Проверяем, существует ли такой пользователь
-	_, err := u.repo.UserGetByID(ctx, userID)
+// getUser - возвращает пользователя по id либо ErrNotFound, если пользователь не существует.
+func (u ShortURL) getUser(ctx context.Context, userID uint) (*models.User, error) {
+	user, err := u.repo.UserGetByID(ctx, userID)
 	if errors.Is(err, repo.ErrNotFound) {
 		return nil, pkgerrors.ErrNotFound
 	} else if err != nil {
 		log.Err(err).Msg("failed to get user by id")
 		return nil, pkgerrors.ErrInternal
 	}
+	return user, nil
+}
 
-	// Создаем модель и сохраняем в репозиторий
-	shortURL := &models.ShortURL{
-		ID:          shortid.Generate(),
-		OriginalURL: OriginalURL,
-		UserID:      userID,
+// store - сохраняет новые ссылки пользователя в репозиторий, проверяя его квоты в той же операции.
+// Простые ссылки (см. models.ShortURL.Plain), URL которых уже есть у простой ссылки пользователя в репозитории
+// или ранее в shortURLs, заменяются существующими и отмечаются в dup: они не создаются и не учитываются в квотах.
+// Если существующая ссылка удалена, возвращает ErrDeleted.
+func (u ShortURL) store(ctx context.Context, user *models.User, shortURLs []*models.ShortURL) ([]bool, error) {
+	dup := make([]bool, len(shortURLs))
+	// Повторы URL внутри пакета: индекс элемента - индекс первого элемента с тем же URL
+	same := make(map[int]int)
+	first := make(map[string]int, len(shortURLs))
+	for i, shortURL := range shortURLs {
+		if !shortURL.Plain() {
+			continue
+		}
+		if j, ok := first[shortURL.OriginalURL]; ok {
+			same[i] = j
+		} else {
+			first[shortURL.OriginalURL] = i
+		}
 	}
-	err = u.repo.ShortURLCreate(ctx, shortURL)
 
-	if err != nil && !errors.Is(err, repo.ErrDuplicate) {
-		log.Err(err).Msg("failed to create short url")
-		return nil, pkgerrors.ErrInternal
-	}
+	// Если ссылка с тем же URL создана одновременно с нами, репозиторий вернет ErrDuplicate:
+	// повторяем попытку, при этом каждая неудачная попытка находит хотя бы одну существующую ссылку.
+	for attempt := 0; attempt <= len(shortURLs); attempt++ {
+		var created []*models.ShortURL
+		for i, shortURL := range shortURLs {
+			if _, ok := same[i]; ok || dup[i] {
+				continue
+			}
+			if !shortURL.Plain() {
+				created = append(created, shortURL)
+				continue
+			}
+			existing, err := u.repo.ShortURLGetByOriginalURL(ctx, user.ID, shortURL.OriginalURL)
+			if errors.Is(err, repo.ErrNotFound) {
+				created = append(created, shortURL)
+				continue
+			} else if err != nil {
+				log.Err(err).Msg("failed to get short url by original url")
+				return nil, pkgerrors.ErrInternal
+			}
+			if existing.Deleted {
+				return nil, pkgerrors.ErrDeleted
+			}
+			shortURLs[i], dup[i] = existing, true
+		}
 
-	// Если такой URL уже существует,
-	// запрашиваем его и возвращаем ErrDuplicate
-	if errors.Is(err, repo.ErrDuplicate) {
-		shortURL, err = u.GetByOriginalURL(ctx, OriginalURL)
-		if err != nil {
-			return nil, err
+		err := u.repo.ShortURLCreateWithLimit(ctx, user.ID, created, u.quota.limit(user.Plan))
+		if errors.Is(err, repo.ErrDuplicate) {
+			continue
+		} else if errors.Is(err, repo.ErrLimitExceeded) {
+			return nil, u.quota.exceeded(ctx, user.ID, len(created))
+		} else if err != nil {
+			log.Err(err).Msg("failed to create short urls")
+			return nil, pkgerrors.ErrInternal
+		}
+		for i, j := range same {
+			shortURLs[i], dup[i] = shortURLs[j], true
 		}
-		return shortURL, pkgerrors.ErrDuplicate
+		return dup, nil
 	}
+	log.Error().Msg("failed to create short urls: duplicate id")
+	return nil, pkgerrors.ErrInternal
+}
 
-	// Возвращаем модель
+// GetByID - возвращает ShortURL по его id.
+// Если период действия ссылки еще не начался, возвращает ErrNotActive (см. GetNotActive).
+// Если период действия ссылки закончился, возвращает ErrExpired.
+func (u ShortURL) GetByID(ctx context.Context, id string) (*models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.GetByID")
+	defer span.End()
+	shortURL, err := u.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if notActive(shortURL, now) {
+		return nil, pkgerrors.ErrNotActive
+	}
+	if shortURL.ActiveUntil != nil && !now.Before(*shortURL.ActiveUntil) {
+		return nil, pkgerrors.ErrExpired
+	}
 	return shortURL, nil
 }
 
-// GetByID - возвращает ShortURL по его id
-func (u ShortURL) GetByID(ctx context.Context, id string) (*models.ShortURL, error) {
+// GetNotActive - возвращает ShortURL по ее id, если период действия ссылки еще не начался.
+// Используется для страницы-заглушки, на которой показываются заголовок ссылки и начало ее действия.
+// Если ссылка уже активна, возвращает ErrNotFound.
+func (u ShortURL) GetNotActive(ctx context.Context, id string) (*models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.GetNotActive")
+	defer span.End()
+	shortURL, err := u.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !notActive(shortURL, time.Now()) {
+		return nil, pkgerrors.ErrNotFound
+	}
+	return shortURL, nil
+}
+
+// notActive - возвращает true, если в момент now период действия ссылки еще не начался
+func notActive(shortURL *models.ShortURL, now time.Time) bool {
+	return shortURL.ActiveFrom != nil && now.Before(*shortURL.ActiveFrom)
+}
+
+// get - возвращает ShortURL по его id без учета периода действия ссылки
+func (u ShortURL) get(ctx context.Context, id string) (*models.ShortURL, error) {
 	shortURL, err := u.repo.ShortURLGetByID(ctx, id)
 	if errors.Is(err, repo.ErrNotFound) {
 		return nil, pkgerrors.ErrNotFound
@@ -88,8 +372,11 @@ func (u ShortURL) GetByID(ctx context.Context, id string) (*models.ShortURL, err
 	return shortURL, nil
 }
 
-// GetByUserID - возвращает все ShortURL пользователя
+// GetByUserID - возвращает все ShortURL, созданные пользователем.
+// Ссылки команд, из которых пользователь вышел, не возвращаются.
 func (u ShortURL) GetByUserID(ctx context.Context, id uint) ([]models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.GetByUserID")
+	defer span.End()
 	shortURLs, err := u.repo.ShortURLGetByUserID(ctx, id)
 	if errors.Is(err, repo.ErrNotFound) {
 		return nil, pkgerrors.ErrNotFound
@@ -97,6 +384,38 @@ func (u ShortURL) GetByUserID(ctx context.Context, id uint) ([]models.ShortURL,
 		log.Err(err).Msg("failed to get short urls by user id")
 		return nil, pkgerrors.ErrInternal
 	}
+	teams, err := u.repo.TeamGetByUserID(ctx, id)
+	if err != nil {
+		log.Err(err).Msg("failed to get teams by user id")
+		return nil, pkgerrors.ErrInternal
+	}
+	member := make(map[uint]bool, len(teams))
+	for _, team := range teams {
+		member[team.ID] = true
+	}
+	// Отфильтровываем ссылки, помеченные как удаленные, и ссылки чужих команд
+	var result []models.ShortURL
+	for _, shortURL := range shortURLs {
+		if !shortURL.Deleted && (shortURL.TeamID == 0 || member[shortURL.TeamID]) {
+			result = append(result, shortURL)
+		}
+	}
+	return result, nil
+}
+
+// GetByTeamID - возвращает все ShortURL команды teamID.
+// Если пользователь userID не состоит в команде, возвращает ErrNotFound.
+func (u ShortURL) GetByTeamID(ctx context.Context, userID, teamID uint) ([]models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.GetByTeamID")
+	defer span.End()
+	if _, err := teamRole(ctx, u.repo, teamID, userID); err != nil {
+		return nil, err
+	}
+	shortURLs, err := u.repo.ShortURLGetByTeamID(ctx, teamID)
+	if err != nil {
+		log.Err(err).Msg("failed to get short urls by team id")
+		return nil, pkgerrors.ErrInternal
+	}
 	// Отфильтровываем ссылки, помеченные как удаленные
 	var result []models.ShortURL
 	for _, shortURL := range shortURLs {
@@ -107,9 +426,15 @@ func (u ShortURL) GetByUserID(ctx context.Context, id uint) ([]models.ShortURL,
 	return result, nil
 }
 
-// GetByOriginalURL - возвращает ShortURL по его оригинальному URL
-func (u ShortURL) GetByOriginalURL(ctx context.Context, rawURL string) (*models.ShortURL, error) {
-	shortURL, err := u.repo.ShortURLGetByOriginalURL(ctx, rawURL)
+// GetByOriginalURL - возвращает простую ShortURL пользователя userID (см. models.ShortURL.Plain) по ее оригинальному URL.
+// URL предварительно приводится к каноническому виду.
+func (u ShortURL) GetByOriginalURL(ctx context.Context, userID uint, rawURL string) (*models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.GetByOriginalURL")
+	defer span.End()
+	if canonical, err := canonicalURL(rawURL, u.sortQuery); err == nil {
+		rawURL = canonical
+	}
+	shortURL, err := u.repo.ShortURLGetByOriginalURL(ctx, userID, rawURL)
 	if errors.Is(err, repo.ErrNotFound) {
 		return nil, pkgerrors.ErrNotFound
 	} else if err != nil {
@@ -124,8 +449,14 @@ func (u ShortURL) GetByOriginalURL(ctx context.Context, rawURL string) (*models.
 }
 
 // DeleteBatch - помечает удаленными несколько сокращенных ссылок пользователя по их id.
+// Ссылки команды могут удалять только ее владельцы и редакторы.
 // Принимает на вход канал идентификаторов для удаления
 func (u ShortURL) DeleteBatch(ctx context.Context, userID uint, ids []string) error {
+	ctx, span := tracer.Start(ctx, "ShortURL.DeleteBatch")
+	defer span.End()
+	u.metrics.DeleteQueue(len(ids))
+	defer u.metrics.DeleteQueue(-len(ids))
+
 	// Демультиплексируем слайс идентификаторов на каналы
 	ch := make(chan string)
 	go func() {
@@ -147,6 +478,8 @@ func (u ShortURL) DeleteBatch(ctx context.Context, userID uint, ids []string) er
 
 // Count - возвращает количество сокращенных ссылок
 func (u ShortURL) Count(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.Count")
+	defer span.End()
 	count, err := u.repo.ShortURLCount(ctx)
 	if err != nil {
 		log.Err(err).Msg("failed to count short urls")
@@ -155,11 +488,320 @@ func (u ShortURL) Count(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+// GetOwned - возвращает ShortURL по его id, если она доступна пользователю userID:
+// личная ссылка - создавшему ее пользователю, ссылка команды - любому участнику команды.
+// Период действия ссылки не учитывается.
+// Если ссылка не найдена или недоступна пользователю, возвращает ErrNotFound.
+func (u ShortURL) GetOwned(ctx context.Context, userID uint, id string) (*models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.GetOwned")
+	defer span.End()
+	shortURL, _, err := u.getAccessible(ctx, userID, id)
+	return shortURL, err
+}
+
+// SetTeam - передает ссылку id команде teamID. Если teamID = 0, ссылка становится личной ссылкой создавшего ее пользователя:
+// если она при этом становится простой (см. models.ShortURL.Plain), а у пользователя уже есть простая ссылка
+// с тем же URL, возвращает ErrDuplicate.
+// Передать ссылку могут пользователи, которые могут ее изменять, в команду, в которой они являются владельцами или редакторами.
+// Сделать ссылку команды личной может только создавший ее пользователь.
+// Если прав недостаточно, возвращает ErrForbidden.
+func (u ShortURL) SetTeam(ctx context.Context, userID uint, id string, teamID uint) (*models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.SetTeam")
+	defer span.End()
+	shortURL, err := u.getEditable(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if teamID == 0 && shortURL.UserID != userID {
+		return nil, pkgerrors.ErrForbidden
+	}
+	if teamID != 0 {
+		if err = u.checkTeamEditor(ctx, userID, teamID); err != nil {
+			return nil, err
+		}
+	}
+	// Изменяем копию модели: репозиторий может возвращать указатель на хранимый объект
+	updated := *shortURL
+	updated.TeamID = teamID
+	if err = u.update(ctx, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// getAccessible - возвращает ShortURL, доступную пользователю для просмотра, и роль пользователя в команде ссылки.
+// Для личных ссылок роль не задана.
+func (u ShortURL) getAccessible(ctx context.Context, userID uint, id string) (*models.ShortURL, models.TeamRole, error) {
+	shortURL, err := u.get(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if shortURL.TeamID == 0 {
+		if shortURL.UserID != userID {
+			return nil, "", pkgerrors.ErrNotFound
+		}
+		return shortURL, "", nil
+	}
+	role, err := teamRole(ctx, u.repo, shortURL.TeamID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	return shortURL, role, nil
+}
+
+// getEditable - возвращает ShortURL, если пользователь может ее изменять:
+// личную ссылку - создавший ее пользователь, ссылку команды - владельцы и редакторы команды.
+// Наблюдателям команды возвращает ErrForbidden.
+func (u ShortURL) getEditable(ctx context.Context, userID uint, id string) (*models.ShortURL, error) {
+	shortURL, role, err := u.getAccessible(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if shortURL.TeamID != 0 && !role.CanEdit() {
+		return nil, pkgerrors.ErrForbidden
+	}
+	return shortURL, nil
+}
+
+// checkTeamEditor - проверяет, что пользователь является владельцем или редактором команды.
+// Если пользователь не состоит в команде, возвращает ErrNotFound, если является наблюдателем - ErrForbidden.
+func (u ShortURL) checkTeamEditor(ctx context.Context, userID, teamID uint) error {
+	role, err := teamRole(ctx, u.repo, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanEdit() {
+		return pkgerrors.ErrForbidden
+	}
+	return nil
+}
+
+// SetRules - заменяет список правил перенаправления ссылки id пользователя userID.
+// Пустой список удаляет все правила. Если ссылка при этом становится простой (см. models.ShortURL.Plain),
+// а у пользователя уже есть простая ссылка с тем же URL, возвращает ErrDuplicate.
+func (u ShortURL) SetRules(ctx context.Context, userID uint, id string, rules []models.RedirectRule) (*models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.SetRules")
+	defer span.End()
+	if err := u.validateRules(rules); err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if err := u.checkPolicies(ctx, rule.URL); err != nil {
+			return nil, err
+		}
+	}
+	shortURL, err := u.getEditable(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	// Изменяем копию модели: репозиторий может возвращать указатель на хранимый объект
+	updated := *shortURL
+	updated.Rules = rules
+	if len(rules) == 0 {
+		updated.Rules = nil
+	}
+	if err = u.update(ctx, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// SetQueryTemplate - заменяет шаблон query-параметров ссылки id пользователя userID. Значение nil удаляет шаблон.
+// Если ссылка при этом становится простой (см. models.ShortURL.Plain), а у пользователя уже есть простая ссылка
+// с тем же URL, возвращает ErrDuplicate.
+func (u ShortURL) SetQueryTemplate(ctx context.Context, userID uint, id string, t *models.QueryTemplate) (*models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.SetQueryTemplate")
+	defer span.End()
+	if err := validateQueryTemplate(t); err != nil {
+		return nil, err
+	}
+	shortURL, err := u.getEditable(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	// Изменяем копию модели: репозиторий может возвращать указатель на хранимый объект
+	updated := *shortURL
+	updated.QueryTemplate = t
+	if err = u.update(ctx, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// SetPreview - задает заголовок ссылки id пользователя userID и показ страницы-предупреждения перед переходом.
+// Пустой заголовок удаляет заголовок ссылки. Если ссылка при этом становится простой (см. models.ShortURL.Plain),
+// а у пользователя уже есть простая ссылка с тем же URL, возвращает ErrDuplicate.
+func (u ShortURL) SetPreview(ctx context.Context, userID uint, id string, title string, interstitial bool) (*models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.SetPreview")
+	defer span.End()
+	if err := validateTitle(title); err != nil {
+		return nil, err
+	}
+	shortURL, err := u.getEditable(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	// Изменяем копию модели: репозиторий может возвращать указатель на хранимый объект
+	updated := *shortURL
+	updated.Title, updated.Interstitial = title, interstitial
+	if err = u.update(ctx, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// SetActiveWindow - задает период действия ссылки id пользователя userID. Любая из границ может быть не задана (nil).
+// Если ссылка при этом становится простой (см. models.ShortURL.Plain), а у пользователя уже есть простая ссылка
+// с тем же URL, возвращает ErrDuplicate.
+func (u ShortURL) SetActiveWindow(ctx context.Context, userID uint, id string, from, until *time.Time) (*models.ShortURL, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.SetActiveWindow")
+	defer span.End()
+	if err := validateActiveWindow(from, until); err != nil {
+		return nil, err
+	}
+	shortURL, err := u.getEditable(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	// Изменяем копию модели: репозиторий может возвращать указатель на хранимый объект
+	updated := *shortURL
+	updated.ActiveFrom, updated.ActiveUntil = from, until
+	if err = u.update(ctx, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// VariantStats - возвращает статистику переходов по вариантам сплит-ссылки id пользователя userID.
+// Если ссылка не является сплит-ссылкой, возвращает пустой список.
+func (u ShortURL) VariantStats(ctx context.Context, userID uint, id string) ([]models.VariantStat, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.VariantStats")
+	defer span.End()
+	shortURL, err := u.GetOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if shortURL.Split == nil {
+		return nil, nil
+	}
+	clicks, err := u.repo.ShortURLVariantClicks(ctx, id)
+	if err != nil {
+		log.Err(err).Msg("failed to get variant clicks")
+		return nil, pkgerrors.ErrInternal
+	}
+	stats := make([]models.VariantStat, 0, len(shortURL.Split.Variants))
+	for i, v := range shortURL.Split.Variants {
+		stats = append(stats, models.VariantStat{
+			Variant: i + 1,
+			URL:     v.URL,
+			Weight:  v.Weight,
+			Clicks:  clicks[i+1],
+		})
+	}
+	return stats, nil
+}
+
+// Redirect - возвращает URL, на который нужно перенаправить посетителя короткой ссылки (см. Destination),
+// и номер выбранного варианта сплит-ссылки (0 - вариант не выбран).
+// Переход по варианту сплит-ссылки засчитывается в статистику, если он выполнен не ботом (см. DetectBot).
+// Событие перехода и переход по варианту записываются асинхронно (см. Wait).
+func (u ShortURL) Redirect(ctx context.Context, shortURL *models.ShortURL, visit *models.Visit) (string, int) {
+	_, span := tracer.Start(ctx, "ShortURL.Redirect")
+	defer span.End()
+	dest, variant := u.destination(shortURL, visit)
+	counted := variant
+	if visit.Bot {
+		counted = 0
+	}
+	u.clicks.record(newClick(shortURL, visit, u.visitorSecret), counted)
+	return dest, variant
+}
+
+// Destination - возвращает URL, на который нужно перенаправить посетителя короткой ссылки.
+// Если сработало одно из правил перенаправления, то используется URL из правила.
+// Иначе, если ссылка является сплит-ссылкой, то используется URL выбранного варианта.
+// Иначе - оригинальный URL.
+// К URL добавляются query-параметры из шаблона ссылки.
+// Если шаблон применить не удалось, возвращает URL без изменений.
+func (u ShortURL) Destination(shortURL *models.ShortURL, visit *models.Visit) string {
+	dest, _ := u.destination(shortURL, visit)
+	return dest
+}
+
+// destination - возвращает URL для перенаправления и номер выбранного варианта сплит-ссылки
+func (u ShortURL) destination(shortURL *models.ShortURL, visit *models.Visit) (string, int) {
+	target, variant := shortURL.OriginalURL, 0
+	if rule := matchRule(shortURL.Rules, visit); rule != nil {
+		target = rule.URL
+	} else if variant = pickVariant(shortURL.Split, visit.Variant); variant > 0 {
+		target = shortURL.Split.Variants[variant-1].URL
+	}
+	if shortURL.QueryTemplate == nil {
+		return target, variant
+	}
+	vars := map[string]string{
+		models.PlaceholderID:           shortURL.ID,
+		models.PlaceholderReferrerHost: "",
+		models.PlaceholderDate:         visit.Time.UTC().Format("2006-01-02"),
+	}
+	if ref, err := url.Parse(visit.Referer); err == nil {
+		vars[models.PlaceholderReferrerHost] = ref.Hostname()
+	}
+	dest, err := applyQueryTemplate(target, shortURL.QueryTemplate, vars)
+	if err != nil {
+		log.Err(err).Str("id", shortURL.ID).Msg("failed to apply query template")
+		return target, variant
+	}
+	return dest, variant
+}
+
+// Interstitial - возвращает true, если перед переходом по ссылке нужно показать страницу-предупреждение:
+// если это задано для самой ссылки либо для всех ссылок в конфигурации сервиса.
+func (u ShortURL) Interstitial(shortURL *models.ShortURL) bool {
+	return u.interstitial || shortURL.Interstitial
+}
+
+// Wait - ожидает завершения фоновой записи событий переходов после остановки сервиса.
+// Вызывается после завершения контекста stopCtx и до закрытия репозитория.
+func (u ShortURL) Wait() {
+	u.clicks.wait()
+}
+
 // Resolve - возвращает сокращенный URL по его id
 func (u ShortURL) Resolve(id string) string {
 	return u.baseURL + id
 }
 
+// update - сохраняет изменения ссылки в репозитории
+func (u ShortURL) update(ctx context.Context, shortURL *models.ShortURL) error {
+	err := u.repo.ShortURLUpdate(ctx, shortURL)
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkgerrors.ErrNotFound
+	} else if errors.Is(err, repo.ErrDuplicate) {
+		return pkgerrors.ErrDuplicate
+	} else if err != nil {
+		log.Err(err).Msg("failed to update short url")
+		return pkgerrors.ErrInternal
+	}
+	return nil
+}
+
+// validateTitle - проверяет максимальную длину заголовка ссылки
+func validateTitle(title string) error {
+	if utf8.RuneCountInString(title) > models.TitleMaxLen {
+		return pkgerrors.ErrValidation
+	}
+	return nil
+}
+
+// validateActiveWindow - проверяет, что окончание периода действия ссылки наступает после его начала
+func validateActiveWindow(from, until *time.Time) error {
+	if from != nil && until != nil && !until.After(*from) {
+		return pkgerrors.ErrValidation
+	}
+	return nil
+}
+
 // validateURL - проверяет URL на максимальную длину и http/https-протокол
 func (u ShortURL) validateURL(rawURL string) error {
 	// Проверка на максимальную длину URL
@@ -2,9 +2,13 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -15,7 +19,8 @@ import (
 )
 
 type shortURLSuite struct {
-	cfg *config.Config
+	cfg  *config.Config
+	stop context.CancelFunc // Останавливает фоновую запись событий переходов
 	*ShortURL
 	*User
 	suite.Suite
@@ -27,11 +32,17 @@ func (suite *shortURLSuite) SetupTest() {
 	suite.cfg, _ = config.Default(nil)
 	suite.Require().NoError(err)
 	r := repo.NewMemoryRepo()
-	suite.ShortURL = NewShortURL(context.Background(), r, suite.cfg.BaseURL.String())
+	var stopCtx context.Context
+	stopCtx, suite.stop = context.WithCancel(context.Background())
+	suite.ShortURL = NewShortURL(stopCtx, suite.cfg, r)
 	suite.User = NewUser(r)
 	suite.Require().NoError(suite.User.Create(context.Background(), &models.User{}))
 }
 
+func (suite *shortURLSuite) TearDownTest() {
+	suite.stop()
+}
+
 func (suite *shortURLSuite) TestCreate() {
 	// Успешное создание короткой ссылки
 	suite.Run("success", func() {
@@ -65,6 +76,35 @@ func (suite *shortURLSuite) TestCreate() {
 		suite.Equal(pkgerrors.ErrNotFound, err)
 	})
 
+	// Невалидный шаблон query-параметров
+	suite.Run("invalid query template", func() {
+		// Неизвестный плейсхолдер
+		_, err := suite.ShortURL.Create(context.Background(), 1, "https://google.com/template",
+			WithQueryTemplate(&models.QueryTemplate{Params: map[string]string{"utm_source": "{unknown}"}}))
+		suite.Equal(pkgerrors.ErrValidation, err)
+		// Неизвестная политика слияния
+		_, err = suite.ShortURL.Create(context.Background(), 1, "https://google.com/template",
+			WithQueryTemplate(&models.QueryTemplate{Params: map[string]string{"a": "b"}, Policy: "merge"}))
+		suite.Equal(pkgerrors.ErrValidation, err)
+		// Пустой ключ
+		_, err = suite.ShortURL.Create(context.Background(), 1, "https://google.com/template",
+			WithQueryTemplate(&models.QueryTemplate{Params: map[string]string{" ": "b"}}))
+		suite.Equal(pkgerrors.ErrValidation, err)
+	})
+
+	suite.Run("title and interstitial", func() {
+		shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/titled",
+			WithTitle("Отчет"), WithInterstitial(true))
+		suite.NoError(err)
+		suite.Equal("Отчет", shortURL.Title)
+		suite.True(suite.ShortURL.Interstitial(shortURL))
+		suite.WithinDuration(time.Now(), shortURL.CreatedAt, 2*time.Second)
+
+		_, err = suite.ShortURL.Create(context.Background(), 1, "https://example.com/long-title",
+			WithTitle(strings.Repeat("я", models.TitleMaxLen+1)))
+		suite.Equal(pkgerrors.ErrValidation, err)
+	})
+
 	suite.Run("duplicate url", func() {
 		s1, err := suite.ShortURL.Create(context.Background(), 1, "https://duplicate.com")
 		suite.NoError(err)
@@ -73,6 +113,102 @@ func (suite *shortURLSuite) TestCreate() {
 		suite.Equal(s1.ID, s2.ID)
 		suite.Equal(s1.OriginalURL, s2.OriginalURL)
 	})
+
+	// Ссылки другого пользователя не используются повторно
+	suite.Run("same url of another user", func() {
+		suite.Require().NoError(suite.User.Create(context.Background(), &models.User{}))
+		s1, err := suite.ShortURL.Create(context.Background(), 1, "https://another-user.com")
+		suite.NoError(err)
+		s2, err := suite.ShortURL.Create(context.Background(), 2, "https://another-user.com")
+		suite.NoError(err)
+		suite.NotEqual(s1.ID, s2.ID)
+		suite.Equal(uint(2), s2.UserID)
+	})
+}
+
+func (suite *shortURLSuite) TestCanonicalURL() {
+	tests := []struct {
+		raw       string
+		want      string
+		sortQuery bool
+	}{
+		{raw: "HTTP://Example.COM:80/a", want: "http://example.com/a"},
+		{raw: "https://example.com:443/", want: "https://example.com/"},
+		{raw: "https://example.com:8443/", want: "https://example.com:8443/"},
+		{raw: "http://user:pw@Example.com/?", want: "http://user:pw@example.com/"},
+		{raw: "http://example.com/a#", want: "http://example.com/a"},
+		{raw: "https://пример.рф/путь", want: "https://xn--e1afmkfd.xn--p1ai/%D0%BF%D1%83%D1%82%D1%8C"},
+		{raw: "https://xn--e1afmkfd.xn--p1ai/", want: "https://xn--e1afmkfd.xn--p1ai/"},
+		{raw: "http://[::1]:80/x", want: "http://[::1]/x"},
+		{raw: "http://example.com/%7euser/a%2fb%3f?q=%7e%2f#s%7e", want: "http://example.com/~user/a%2Fb%3F?q=~%2F#s~"},
+		{raw: "http://example.com/a b", want: "http://example.com/a%20b"},
+		{raw: "http://example.com/?b=2&a=1&b=1", want: "http://example.com/?b=2&a=1&b=1"},
+		{raw: "http://example.com/?b=2&a=1&b=1", want: "http://example.com/?a=1&b=2&b=1", sortQuery: true},
+	}
+	for _, tt := range tests {
+		got, err := canonicalURL(tt.raw, tt.sortQuery)
+		suite.NoError(err, tt.raw)
+		suite.Equal(tt.want, got, tt.raw)
+	}
+
+	suite.Run("deduplication", func() {
+		shortURL, err := suite.ShortURL.Create(context.Background(), 1, "HTTP://Dedup.Example.com:80/a%7e")
+		suite.Require().NoError(err)
+		suite.Equal("http://dedup.example.com/a~", shortURL.OriginalURL)
+		suite.Equal("HTTP://Dedup.Example.com:80/a%7e", shortURL.SubmittedURL)
+
+		duplicate, err := suite.ShortURL.Create(context.Background(), 1, "http://dedup.example.com/a~")
+		suite.Equal(pkgerrors.ErrDuplicate, err)
+		suite.Equal(shortURL.ID, duplicate.ID)
+	})
+}
+
+func (suite *shortURLSuite) TestPolicies() {
+	cfg := *suite.cfg
+	cfg.BaseURL = url.URL{Scheme: "https", Host: "sho.rt", Path: "/"}
+	u := NewShortURL(context.Background(), &cfg, repo.NewMemoryRepo())
+	suite.Require().NoError(u.repo.UserCreate(context.Background(), &models.User{}))
+
+	suite.Run("success", func() {
+		_, err := u.Create(context.Background(), 1, "https://example.com/policy")
+		suite.NoError(err)
+	})
+
+	suite.Run("rejected destinations", func() {
+		rejected := []string{
+			"https://sho.rt/abc",
+			"http://SHO.RT:8080/abc",
+			"http://localhost/admin",
+			"http://api.localhost/",
+			"http://127.0.0.1:8080/",
+			"http://10.0.0.1/",
+			"http://192.168.1.1/",
+			"http://169.254.169.254/latest/meta-data",
+			"http://[::1]/",
+			"http://0.0.0.0/",
+		}
+		for _, rawURL := range rejected {
+			_, err := u.Create(context.Background(), 1, rawURL)
+			suite.ErrorIs(err, pkgerrors.ErrValidation, rawURL)
+			suite.NotEmpty(err.(*pkgerrors.Error).Detail, rawURL)
+		}
+	})
+
+	suite.Run("rejected split variant", func() {
+		_, err := u.Create(context.Background(), 1, "https://example.com/split-policy",
+			WithSplit(&models.Split{Variants: []models.Variant{
+				{URL: "https://example.com/a", Weight: 1},
+				{URL: "https://sho.rt/b", Weight: 1},
+			}}))
+		suite.ErrorIs(err, pkgerrors.ErrValidation)
+	})
+
+	suite.Run("rejected rule", func() {
+		shortURL, err := u.Create(context.Background(), 1, "https://example.com/rules-policy")
+		suite.Require().NoError(err)
+		_, err = u.SetRules(context.Background(), 1, shortURL.ID, []models.RedirectRule{{URL: "http://10.1.2.3/"}})
+		suite.ErrorIs(err, pkgerrors.ErrValidation)
+	})
 }
 
 func (suite *shortURLSuite) TestGetByID() {
@@ -96,6 +232,88 @@ func (suite *shortURLSuite) TestGetByID() {
 	})
 }
 
+func (suite *shortURLSuite) TestActiveWindow() {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	suite.Run("not active yet", func() {
+		shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/soon",
+			WithActiveWindow(&future, nil))
+		suite.Require().NoError(err)
+		actual, err := suite.ShortURL.GetByID(context.Background(), shortURL.ID)
+		suite.Equal(pkgerrors.ErrNotActive, err)
+		suite.Nil(actual)
+		// Для страницы-заглушки ссылка возвращается отдельным запросом
+		actual, err = suite.ShortURL.GetNotActive(context.Background(), shortURL.ID)
+		suite.NoError(err)
+		suite.Equal(shortURL.ID, actual.ID)
+		// Владелец может управлять еще не активной ссылкой
+		_, err = suite.ShortURL.GetOwned(context.Background(), 1, shortURL.ID)
+		suite.NoError(err)
+	})
+
+	suite.Run("active", func() {
+		shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/active",
+			WithActiveWindow(&past, &future))
+		suite.Require().NoError(err)
+		_, err = suite.ShortURL.GetByID(context.Background(), shortURL.ID)
+		suite.NoError(err)
+		_, err = suite.ShortURL.GetNotActive(context.Background(), shortURL.ID)
+		suite.Equal(pkgerrors.ErrNotFound, err)
+	})
+
+	suite.Run("expired", func() {
+		shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/expired",
+			WithActiveWindow(nil, &past))
+		suite.Require().NoError(err)
+		_, err = suite.ShortURL.GetByID(context.Background(), shortURL.ID)
+		suite.Equal(pkgerrors.ErrExpired, err)
+		// Ссылка остается в списке ссылок пользователя
+		shortURLs, err := suite.ShortURL.GetByUserID(context.Background(), 1)
+		suite.NoError(err)
+		suite.Contains(shortURLs, *shortURL)
+	})
+
+	suite.Run("invalid window", func() {
+		_, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/invalid-window",
+			WithActiveWindow(&future, &past))
+		suite.Equal(pkgerrors.ErrValidation, err)
+	})
+
+	// Ссылка с периодом действия не используется повторно: для того же URL создается новая активная ссылка
+	suite.Run("not deduplicated", func() {
+		expired, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/window",
+			WithActiveWindow(nil, &past))
+		suite.Require().NoError(err)
+		plain, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/window")
+		suite.NoError(err)
+		suite.NotEqual(expired.ID, plain.ID)
+		_, err = suite.ShortURL.GetByID(context.Background(), plain.ID)
+		suite.NoError(err)
+
+		// Без периода действия у пользователя оказались бы две простые ссылки с одним URL
+		_, err = suite.ShortURL.SetActiveWindow(context.Background(), 1, expired.ID, nil, nil)
+		suite.Equal(pkgerrors.ErrDuplicate, err)
+	})
+
+	suite.Run("set window", func() {
+		shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/set-window",
+			WithActiveWindow(nil, &past))
+		suite.Require().NoError(err)
+		updated, err := suite.ShortURL.SetActiveWindow(context.Background(), 1, shortURL.ID, &past, &future)
+		suite.NoError(err)
+		suite.Equal(&future, updated.ActiveUntil)
+		_, err = suite.ShortURL.GetByID(context.Background(), shortURL.ID)
+		suite.NoError(err)
+
+		_, err = suite.ShortURL.SetActiveWindow(context.Background(), 1, shortURL.ID, &future, &past)
+		suite.Equal(pkgerrors.ErrValidation, err)
+		suite.Require().NoError(suite.User.Create(context.Background(), &models.User{}))
+		_, err = suite.ShortURL.SetActiveWindow(context.Background(), 2, shortURL.ID, nil, nil)
+		suite.Equal(pkgerrors.ErrNotFound, err)
+	})
+}
+
 func (suite *shortURLSuite) TestGetByUserID() {
 	// Успешное получение коротких ссылок пользователя
 	suite.Run("success", func() {
@@ -134,7 +352,7 @@ func (suite *shortURLSuite) TestGetByOriginalURL() {
 		shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://google.com")
 		suite.NoError(err)
 		suite.NotNil(shortURL)
-		shortURL, err = suite.ShortURL.GetByOriginalURL(context.Background(), "https://google.com")
+		shortURL, err = suite.ShortURL.GetByOriginalURL(context.Background(), 1, "https://google.com")
 		suite.NoError(err)
 		suite.NotNil(shortURL)
 		suite.Equal("https://google.com", shortURL.OriginalURL)
@@ -144,11 +362,655 @@ func (suite *shortURLSuite) TestGetByOriginalURL() {
 
 	// Несуществующая оригинальная ссылка
 	suite.Run("not found", func() {
-		_, err := suite.ShortURL.GetByOriginalURL(context.Background(), "not found")
+		_, err := suite.ShortURL.GetByOriginalURL(context.Background(), 1, "not found")
+		suite.Equal(pkgerrors.ErrNotFound, err)
+	})
+}
+
+func (suite *shortURLSuite) TestDestination() {
+	visit := &models.Visit{
+		Time:    time.Date(2023, 3, 8, 23, 30, 0, 0, time.UTC),
+		Referer: "https://t.me/channel/123",
+	}
+	params := map[string]string{
+		"utm_source":  "{referrer_host}",
+		"utm_content": "{id}",
+		"utm_term":    "{date}",
+		"ref":         "shortener",
+	}
+
+	suite.Run("without template", func() {
+		shortURL := &models.ShortURL{ID: "abc", OriginalURL: "https://example.com/?a=1"}
+		suite.Equal("https://example.com/?a=1", suite.ShortURL.Destination(shortURL, visit))
+	})
+
+	suite.Run("placeholders", func() {
+		shortURL := &models.ShortURL{ID: "abc", OriginalURL: "https://example.com/path#top",
+			QueryTemplate: &models.QueryTemplate{Params: params}}
+		suite.Equal(
+			"https://example.com/path?ref=shortener&utm_content=abc&utm_source=t.me&utm_term=2023-03-08#top",
+			suite.ShortURL.Destination(shortURL, visit),
+		)
+	})
+
+	suite.Run("empty referrer", func() {
+		shortURL := &models.ShortURL{ID: "abc", OriginalURL: "https://example.com/",
+			QueryTemplate: &models.QueryTemplate{Params: params}}
+		suite.Equal(
+			"https://example.com/?ref=shortener&utm_content=abc&utm_term=2023-03-08",
+			suite.ShortURL.Destination(shortURL, &models.Visit{Time: visit.Time}),
+		)
+	})
+
+	suite.Run("keep policy", func() {
+		shortURL := &models.ShortURL{ID: "abc", OriginalURL: "https://example.com/?ref=partner&q=a%20b",
+			QueryTemplate: &models.QueryTemplate{Params: map[string]string{"ref": "shortener", "x": "1"}}}
+		suite.Equal("https://example.com/?ref=partner&q=a%20b&x=1", suite.ShortURL.Destination(shortURL, visit))
+		shortURL.QueryTemplate.Policy = models.QueryMergeKeep
+		suite.Equal("https://example.com/?ref=partner&q=a%20b&x=1", suite.ShortURL.Destination(shortURL, visit))
+	})
+
+	suite.Run("override policy", func() {
+		shortURL := &models.ShortURL{ID: "abc", OriginalURL: "https://example.com/?ref=partner&q=a%20b&ref=other",
+			QueryTemplate: &models.QueryTemplate{Params: map[string]string{"ref": "shortener"}, Policy: models.QueryMergeOverride}}
+		suite.Equal("https://example.com/?q=a%20b&ref=shortener", suite.ShortURL.Destination(shortURL, visit))
+	})
+
+	suite.Run("append policy", func() {
+		shortURL := &models.ShortURL{ID: "abc", OriginalURL: "https://example.com/?ref=partner",
+			QueryTemplate: &models.QueryTemplate{Params: map[string]string{"ref": "shortener"}, Policy: models.QueryMergeAppend}}
+		suite.Equal("https://example.com/?ref=partner&ref=shortener", suite.ShortURL.Destination(shortURL, visit))
+	})
+}
+
+func (suite *shortURLSuite) TestSetRules() {
+	shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/rules")
+	suite.Require().NoError(err)
+
+	suite.Run("success", func() {
+		rules := []models.RedirectRule{
+			{URL: "https://apps.apple.com/app", Devices: []models.Device{models.DeviceIOS}},
+			{URL: "https://example.ru/", Languages: []string{"ru"}},
+		}
+		updated, err := suite.ShortURL.SetRules(context.Background(), 1, shortURL.ID, rules)
+		suite.NoError(err)
+		suite.Equal(rules, updated.Rules)
+		actual, err := suite.ShortURL.GetByID(context.Background(), shortURL.ID)
+		suite.NoError(err)
+		suite.Equal(rules, actual.Rules)
+		suite.Equal(shortURL.OriginalURL, actual.OriginalURL)
+	})
+
+	// Ссылка с правилами не используется повторно: для того же URL создается новая ссылка
+	suite.Run("not deduplicated", func() {
+		plain, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/rules")
+		suite.Require().NoError(err)
+		suite.NotEqual(shortURL.ID, plain.ID)
+		suite.Empty(plain.Rules)
+
+		// Без правил у пользователя оказались бы две простые ссылки с одним URL
+		_, err = suite.ShortURL.SetRules(context.Background(), 1, shortURL.ID, nil)
+		suite.Equal(pkgerrors.ErrDuplicate, err)
+	})
+
+	suite.Run("clear rules", func() {
+		other, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/clear-rules")
+		suite.Require().NoError(err)
+		_, err = suite.ShortURL.SetRules(context.Background(), 1, other.ID, []models.RedirectRule{{URL: "https://example.ru/"}})
+		suite.Require().NoError(err)
+		updated, err := suite.ShortURL.SetRules(context.Background(), 1, other.ID, []models.RedirectRule{})
+		suite.NoError(err)
+		suite.Nil(updated.Rules)
+	})
+
+	suite.Run("not owner", func() {
+		suite.Require().NoError(suite.User.Create(context.Background(), &models.User{}))
+		_, err := suite.ShortURL.SetRules(context.Background(), 2, shortURL.ID, nil)
+		suite.Equal(pkgerrors.ErrNotFound, err)
+	})
+
+	suite.Run("not found", func() {
+		_, err := suite.ShortURL.SetRules(context.Background(), 1, "unknown", nil)
+		suite.Equal(pkgerrors.ErrNotFound, err)
+	})
+
+	suite.Run("invalid rules", func() {
+		invalid := [][]models.RedirectRule{
+			{{URL: "invalid url"}},
+			{{URL: "https://example.com/", Devices: []models.Device{"tv"}}},
+			{{URL: "https://example.com/", Languages: []string{"not a language"}}},
+			{{URL: "https://example.com/", Schedule: &models.Schedule{Weekdays: []string{"monday"}}}},
+			{{URL: "https://example.com/", Schedule: &models.Schedule{From: "09:00"}}},
+			{{URL: "https://example.com/", Schedule: &models.Schedule{From: "9am", Until: "18:00"}}},
+			{{URL: "https://example.com/", Schedule: &models.Schedule{From: "09:00", Until: "09:00"}}},
+			{{URL: "https://example.com/", Schedule: &models.Schedule{TimeZone: "Mars/Olympus"}}},
+			make([]models.RedirectRule, models.RedirectRulesMaxCount+1),
+		}
+		for _, rules := range invalid {
+			_, err := suite.ShortURL.SetRules(context.Background(), 1, shortURL.ID, rules)
+			suite.Equal(pkgerrors.ErrValidation, err)
+		}
+	})
+}
+
+func (suite *shortURLSuite) TestSetQueryTemplate() {
+	template := &models.QueryTemplate{Params: map[string]string{"utm_content": "{id}"}}
+	templated, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/template", WithQueryTemplate(template))
+	suite.Require().NoError(err)
+
+	// Ссылка с шаблоном не используется повторно: для того же URL создается новая ссылка
+	suite.Run("not deduplicated", func() {
+		plain, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/template")
+		suite.NoError(err)
+		suite.NotEqual(templated.ID, plain.ID)
+		suite.Nil(plain.QueryTemplate)
+		again, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/template", WithQueryTemplate(template))
+		suite.NoError(err)
+		suite.NotEqual(templated.ID, again.ID)
+
+		// Без шаблона у пользователя оказались бы две простые ссылки с одним URL
+		_, err = suite.ShortURL.SetQueryTemplate(context.Background(), 1, templated.ID, nil)
+		suite.Equal(pkgerrors.ErrDuplicate, err)
+	})
+
+	suite.Run("success", func() {
+		shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/set-template")
+		suite.Require().NoError(err)
+		other := &models.QueryTemplate{Params: map[string]string{"utm_source": "{referrer_host}"}, Policy: models.QueryMergeOverride}
+		updated, err := suite.ShortURL.SetQueryTemplate(context.Background(), 1, shortURL.ID, other)
+		suite.NoError(err)
+		suite.Equal(other, updated.QueryTemplate)
+		actual, err := suite.ShortURL.GetByID(context.Background(), shortURL.ID)
+		suite.NoError(err)
+		suite.Equal(other, actual.QueryTemplate)
+
+		updated, err = suite.ShortURL.SetQueryTemplate(context.Background(), 1, shortURL.ID, nil)
+		suite.NoError(err)
+		suite.Nil(updated.QueryTemplate)
+	})
+
+	suite.Run("not owner", func() {
+		suite.Require().NoError(suite.User.Create(context.Background(), &models.User{}))
+		_, err := suite.ShortURL.SetQueryTemplate(context.Background(), 2, templated.ID, template)
+		suite.Equal(pkgerrors.ErrNotFound, err)
+	})
+
+	suite.Run("invalid template", func() {
+		_, err := suite.ShortURL.SetQueryTemplate(context.Background(), 1, templated.ID,
+			&models.QueryTemplate{Params: map[string]string{"utm_source": "{unknown}"}})
+		suite.Equal(pkgerrors.ErrValidation, err)
+	})
+}
+
+func (suite *shortURLSuite) TestSetPreview() {
+	titled, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/preview", WithTitle("Отчет"))
+	suite.Require().NoError(err)
+
+	// Ссылка с заголовком или страницей-предупреждения не используется повторно
+	suite.Run("not deduplicated", func() {
+		plain, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/preview")
+		suite.NoError(err)
+		suite.NotEqual(titled.ID, plain.ID)
+		suite.Empty(plain.Title)
+		warned, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/preview", WithInterstitial(true))
+		suite.NoError(err)
+		suite.NotEqual(plain.ID, warned.ID)
+
+		// Без заголовка у пользователя оказались бы две простые ссылки с одним URL
+		_, err = suite.ShortURL.SetPreview(context.Background(), 1, titled.ID, "", false)
+		suite.Equal(pkgerrors.ErrDuplicate, err)
+	})
+
+	suite.Run("success", func() {
+		updated, err := suite.ShortURL.SetPreview(context.Background(), 1, titled.ID, "Отчет за квартал", true)
+		suite.NoError(err)
+		suite.Equal("Отчет за квартал", updated.Title)
+		suite.True(updated.Interstitial)
+		actual, err := suite.ShortURL.GetByID(context.Background(), titled.ID)
+		suite.NoError(err)
+		suite.Equal("Отчет за квартал", actual.Title)
+		suite.True(suite.ShortURL.Interstitial(actual))
+	})
+
+	suite.Run("not owner", func() {
+		suite.Require().NoError(suite.User.Create(context.Background(), &models.User{}))
+		_, err := suite.ShortURL.SetPreview(context.Background(), 2, titled.ID, "Чужой", false)
+		suite.Equal(pkgerrors.ErrNotFound, err)
+	})
+
+	suite.Run("long title", func() {
+		_, err := suite.ShortURL.SetPreview(context.Background(), 1, titled.ID, strings.Repeat("я", models.TitleMaxLen+1), false)
+		suite.Equal(pkgerrors.ErrValidation, err)
+	})
+}
+
+func (suite *shortURLSuite) TestDestinationRules() {
+	const iPhone = "Mozilla/5.0 (iPhone; CPU iPhone OS 16_3 like Mac OS X) AppleWebKit/605.1.15"
+	const android = "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36"
+	// Среда, 8 марта 2023, 10:30 UTC (13:30 по Москве)
+	wednesday := time.Date(2023, 3, 8, 10, 30, 0, 0, time.UTC)
+
+	shortURL := &models.ShortURL{
+		ID:          "abc",
+		OriginalURL: "https://example.com/",
+		Rules: []models.RedirectRule{
+			{URL: "https://apps.apple.com/app", Devices: []models.Device{models.DeviceIOS}},
+			{URL: "https://play.google.com/app", Devices: []models.Device{models.DeviceAndroid}, Languages: []string{"en"}},
+			{URL: "https://example.ru/", Languages: []string{"ru"}},
+			{URL: "https://example.com/office", Schedule: &models.Schedule{
+				Weekdays: []string{"mon", "tue", "wed", "thu", "fri"},
+				From:     "09:00",
+				Until:    "18:00",
+				TimeZone: "Europe/Moscow",
+			}},
+		},
+		QueryTemplate: &models.QueryTemplate{Params: map[string]string{"ref": "{id}"}},
+	}
+
+	suite.Run("device", func() {
+		suite.Equal("https://apps.apple.com/app?ref=abc",
+			suite.ShortURL.Destination(shortURL, &models.Visit{UserAgent: iPhone}))
+	})
+
+	suite.Run("all conditions must match", func() {
+		suite.Equal("https://play.google.com/app?ref=abc",
+			suite.ShortURL.Destination(shortURL, &models.Visit{UserAgent: android, AcceptLanguage: "en-US,en;q=0.9"}))
+		suite.Equal("https://example.ru/?ref=abc",
+			suite.ShortURL.Destination(shortURL, &models.Visit{UserAgent: android, AcceptLanguage: "ru-RU,ru;q=0.9"}))
+	})
+
+	suite.Run("schedule", func() {
+		suite.Equal("https://example.com/office?ref=abc",
+			suite.ShortURL.Destination(shortURL, &models.Visit{Time: wednesday}))
+		// 16:00 UTC - 19:00 по Москве
+		suite.Equal("https://example.com/?ref=abc",
+			suite.ShortURL.Destination(shortURL, &models.Visit{Time: wednesday.Add(5*time.Hour + 30*time.Minute)}))
+		// Суббота
+		suite.Equal("https://example.com/?ref=abc",
+			suite.ShortURL.Destination(shortURL, &models.Visit{Time: wednesday.AddDate(0, 0, 3)}))
+	})
+
+	suite.Run("overnight schedule", func() {
+		night := &models.ShortURL{ID: "abc", OriginalURL: "https://example.com/", Rules: []models.RedirectRule{
+			{URL: "https://example.com/night", Schedule: &models.Schedule{From: "22:00", Until: "06:00"}},
+		}}
+		suite.Equal("https://example.com/night",
+			suite.ShortURL.Destination(night, &models.Visit{Time: time.Date(2023, 3, 8, 23, 0, 0, 0, time.UTC)}))
+		suite.Equal("https://example.com/night",
+			suite.ShortURL.Destination(night, &models.Visit{Time: time.Date(2023, 3, 8, 5, 59, 0, 0, time.UTC)}))
+		suite.Equal("https://example.com/",
+			suite.ShortURL.Destination(night, &models.Visit{Time: time.Date(2023, 3, 8, 6, 0, 0, 0, time.UTC)}))
+	})
+
+	suite.Run("no match", func() {
+		suite.Equal("https://example.com/?ref=abc",
+			suite.ShortURL.Destination(shortURL, &models.Visit{Time: wednesday.AddDate(0, 0, 3)}))
+	})
+}
+
+func (suite *shortURLSuite) TestSplit() {
+	split := &models.Split{Variants: []models.Variant{
+		{URL: "https://example.com/a", Weight: 3},
+		{URL: "https://example.com/b", Weight: 1},
+		{URL: "https://example.com/c", Weight: 0},
+	}}
+	shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/split", WithSplit(split))
+	suite.Require().NoError(err)
+
+	suite.Run("invalid split", func() {
+		invalid := []*models.Split{
+			{Variants: []models.Variant{{URL: "https://example.com/a", Weight: 1}}},
+			{Variants: []models.Variant{{URL: "https://example.com/a"}, {URL: "https://example.com/b"}}},
+			{Variants: []models.Variant{{URL: "https://example.com/a", Weight: 1}, {URL: "invalid url", Weight: 1}}},
+			{Variants: []models.Variant{{URL: "https://example.com/a", Weight: 1}, {URL: "https://example.com/b", Weight: models.SplitWeightMax + 1}}},
+			{Variants: make([]models.Variant, models.SplitVariantsMaxCount+1)},
+		}
+		for _, s := range invalid {
+			_, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/invalid-split", WithSplit(s))
+			suite.Equal(pkgerrors.ErrValidation, err)
+		}
+	})
+
+	// Сплит-ссылка не используется повторно: для того же URL создается новая ссылка
+	suite.Run("not deduplicated", func() {
+		plain, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/split")
+		suite.NoError(err)
+		suite.NotEqual(shortURL.ID, plain.ID)
+		suite.Nil(plain.Split)
+
+		other := &models.Split{Variants: []models.Variant{
+			{URL: "https://example.com/x", Weight: 1},
+			{URL: "https://example.com/y", Weight: 1},
+		}}
+		shortURLs, err := suite.ShortURL.CreateBatch(context.Background(), 1, []BatchItem{
+			{OriginalURL: "https://example.com/split", Opts: []CreateOpt{WithSplit(split)}},
+			{OriginalURL: "https://example.com/split", Opts: []CreateOpt{WithSplit(other)}},
+			{OriginalURL: "https://example.com/split"},
+		})
+		suite.NoError(err)
+		suite.NotEqual(shortURL.ID, shortURLs[0].ID)
+		suite.NotEqual(shortURLs[0].ID, shortURLs[1].ID)
+		suite.Equal(other, shortURLs[1].Split)
+		suite.Equal(plain.ID, shortURLs[2].ID)
+	})
+
+	suite.Run("weighted redirect", func() {
+		counts := make(map[string]int)
+		for i := 0; i < 400; i++ {
+			dest, variant := suite.ShortURL.Redirect(context.Background(), shortURL, &models.Visit{})
+			suite.Require().Greater(variant, 0)
+			suite.Equal(split.Variants[variant-1].URL, dest)
+			counts[dest]++
+		}
+		suite.Zero(counts["https://example.com/c"])
+		suite.Greater(counts["https://example.com/a"], counts["https://example.com/b"])
+
+		// Переходы по вариантам засчитываются при сохранении событий
+		suite.stop()
+		suite.ShortURL.Wait()
+		stats, err := suite.ShortURL.VariantStats(context.Background(), 1, shortURL.ID)
+		suite.NoError(err)
+		suite.Require().Len(stats, 3)
+		suite.Equal(int64(counts["https://example.com/a"]), stats[0].Clicks)
+		suite.Equal(int64(counts["https://example.com/b"]), stats[1].Clicks)
+		suite.Zero(stats[2].Clicks)
+	})
+
+	suite.Run("sticky variant", func() {
+		sticky := *shortURL
+		sticky.Split = &models.Split{Variants: split.Variants, Sticky: true}
+		for i := 0; i < 10; i++ {
+			suite.Equal("https://example.com/b", suite.ShortURL.Destination(&sticky, &models.Visit{Variant: 2}))
+		}
+		// Вариант с нулевым весом не закрепляется
+		suite.NotEqual("https://example.com/c", suite.ShortURL.Destination(&sticky, &models.Visit{Variant: 3}))
+		// Без закрепления вариант посетителя не учитывается
+		_, variant := suite.ShortURL.destination(shortURL, &models.Visit{Variant: 3})
+		suite.NotEqual(3, variant)
+	})
+
+	suite.Run("rules take precedence", func() {
+		withRules := *shortURL
+		withRules.Rules = []models.RedirectRule{{URL: "https://example.com/ru", Languages: []string{"ru"}}}
+		dest, variant := suite.ShortURL.Redirect(context.Background(), &withRules, &models.Visit{AcceptLanguage: "ru"})
+		suite.Equal("https://example.com/ru", dest)
+		suite.Zero(variant)
+	})
+
+	suite.Run("not a split link", func() {
+		plain, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/plain")
+		suite.Require().NoError(err)
+		stats, err := suite.ShortURL.VariantStats(context.Background(), 1, plain.ID)
+		suite.NoError(err)
+		suite.Empty(stats)
+	})
+
+	suite.Run("not owner", func() {
+		suite.Require().NoError(suite.User.Create(context.Background(), &models.User{}))
+		_, err := suite.ShortURL.VariantStats(context.Background(), 2, shortURL.ID)
+		suite.Equal(pkgerrors.ErrNotFound, err)
+	})
+}
+
+func (suite *shortURLSuite) TestClicks() {
+	stopCtx, stop := context.WithCancel(context.Background())
+	r := repo.NewMemoryRepo()
+	u := NewShortURL(stopCtx, suite.cfg, r)
+	suite.Require().NoError(r.UserCreate(context.Background(), &models.User{}))
+	shortURL, err := u.Create(context.Background(), 1, "https://example.com/clicks")
+	suite.Require().NoError(err)
+
+	visitTime := time.Date(2022, 12, 1, 10, 0, 0, 0, time.UTC)
+	u.Redirect(context.Background(), shortURL, &models.Visit{
+		Time:      visitTime,
+		Referer:   "https://news.example.org/page?id=1",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X)",
+		IP:        net.ParseIP("203.0.113.42"),
+		Country:   "DE",
+	})
+	u.Redirect(context.Background(), shortURL, &models.Visit{
+		Time: visitTime,
+		IP:   net.ParseIP("2001:db8:1234:5678::1"),
+	})
+
+	// События сохраняются в фоне: после остановки дожидаемся их записи
+	stop()
+	u.Wait()
+	clicks, err := r.ClickGetByShortURLID(context.Background(), shortURL.ID, time.Time{}, time.Time{})
+	suite.NoError(err)
+	suite.Require().Len(clicks, 2)
+	// Хеш посетителя зависит от полного IP-адреса и User-Agent
+	suite.NotEmpty(clicks[0].Visitor)
+	suite.NotEqual(clicks[0].Visitor, clicks[1].Visitor)
+	clicks[0].Visitor, clicks[1].Visitor = "", ""
+	suite.Equal([]models.Click{
+		{ShortURLID: shortURL.ID, Time: visitTime, RefererHost: "news.example.org", Device: models.DeviceIOS, IP: "203.0.113.0", Country: "DE"},
+		{ShortURLID: shortURL.ID, Time: visitTime, Device: models.DeviceDesktop, IP: "2001:db8:1234::"},
+	}, clicks)
+}
+
+func (suite *shortURLSuite) TestVisitorHash() {
+	visit := &models.Visit{
+		IP:        net.ParseIP("203.0.113.42"),
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X)",
+	}
+	// Хеш одного и того же посещения стабилен при одном ключе
+	key := visitorHashKey("secret")
+	suite.Equal(visitorHash(key, visit), visitorHash(key, visit))
+	// и различается при разных ключах
+	suite.NotEqual(visitorHash(key, visit), visitorHash(visitorHashKey("another secret"), visit))
+
+	// Ключ берется из VisitorSecret, а если он не задан - из AuthSecret
+	suite.Equal("auth", visitorSecret(&config.Config{AuthSecret: "auth"}))
+	suite.Equal("visitor", visitorSecret(&config.Config{AuthSecret: "auth", VisitorSecret: "visitor"}))
+}
+
+func (suite *shortURLSuite) TestDetectBot() {
+	stopCtx, stop := context.WithCancel(context.Background())
+	r := repo.NewMemoryRepo()
+	u := NewShortURL(stopCtx, suite.cfg, r)
+	suite.Require().NoError(r.UserCreate(context.Background(), &models.User{}))
+	shortURL, err := u.Create(context.Background(), 1, "https://example.com/bots", WithSplit(&models.Split{
+		Variants: []models.Variant{{URL: "https://example.com/a", Weight: 1}, {URL: "https://example.com/b", Weight: 1}},
+	}))
+	suite.Require().NoError(err)
+
+	human := &models.Visit{Time: time.Now(), UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64)", Method: "GET"}
+	bots := []*models.Visit{
+		{Time: time.Now(), UserAgent: "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", Method: "GET"},
+		{Time: time.Now(), UserAgent: "TelegramBot (like TwitterBot)", Method: "GET"},
+		{Time: time.Now(), UserAgent: human.UserAgent, Method: "HEAD"},
+	}
+	suite.False(u.DetectBot(human))
+	suite.False(human.Bot)
+	u.Redirect(context.Background(), shortURL, human)
+	for _, visit := range bots {
+		suite.True(u.DetectBot(visit), visit.UserAgent)
+		u.Redirect(context.Background(), shortURL, visit)
+	}
+
+	// Переходы ботов сохраняются с отметкой и не засчитываются в счетчики вариантов
+	stop()
+	u.Wait()
+	clicks, err := r.ClickGetByShortURLID(context.Background(), shortURL.ID, time.Time{}, time.Time{})
+	suite.NoError(err)
+	suite.Require().Len(clicks, 4)
+	suite.False(clicks[0].Bot)
+	suite.True(clicks[1].Bot)
+	variants, err := r.ShortURLVariantClicks(context.Background(), shortURL.ID)
+	suite.NoError(err)
+	var total int64
+	for _, n := range variants {
+		total += n
+	}
+	suite.Equal(int64(1), total)
+}
+
+func (suite *shortURLSuite) TestStats() {
+	shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/stats")
+	suite.Require().NoError(err)
+	day := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	clicks := []models.Click{
+		{ShortURLID: shortURL.ID, Time: day.Add(time.Hour), RefererHost: "news.example.org", Device: models.DeviceIOS, Country: "DE", Visitor: "a"},
+		{ShortURLID: shortURL.ID, Time: day.Add(90 * time.Minute), RefererHost: "news.example.org", Device: models.DeviceIOS, Country: "DE", Visitor: "a"},
+		{ShortURLID: shortURL.ID, Time: day.Add(25 * time.Hour), Device: models.DeviceDesktop, Country: "FR", Visitor: "b"},
+		{ShortURLID: shortURL.ID, Time: day.Add(26 * time.Hour), RefererHost: "t.co", Device: models.DeviceDesktop, Visitor: "a"},
+		{ShortURLID: shortURL.ID, Time: day.Add(-time.Hour), Device: models.DeviceAndroid, Visitor: "c"},
+		{ShortURLID: shortURL.ID, Time: day.Add(2 * time.Hour), Device: models.DeviceDesktop, Visitor: "bot", Bot: true},
+	}
+	suite.Require().NoError(suite.ShortURL.repo.ClickAddBatch(context.Background(), clicks))
+	suite.Require().NoError(suite.ShortURL.repo.VisitorSketchMerge(context.Background(), visitorSketches(clicks)))
+
+	suite.Run("daily", func() {
+		stats, err := suite.ShortURL.Stats(context.Background(), 1, shortURL.ID, day.Add(3*time.Hour), day.Add(72*time.Hour), "", false)
+		suite.Require().NoError(err)
+		suite.Equal(day, stats.From)
+		suite.Equal(models.StatsIntervalDay, stats.Interval)
+		suite.Equal(int64(4), stats.Total)
+		suite.Equal(int64(2), stats.Unique)
+		suite.Equal(int64(1), stats.Bots)
+		suite.False(stats.IncludeBots)
+		suite.Equal(int64(2), stats.ApproxUnique)
+		suite.InDelta(0.0081, stats.ApproxUniqueError, 0.0001)
+		suite.Equal([]models.StatsBucket{
+			{Time: day, Total: 2, Unique: 1},
+			{Time: day.AddDate(0, 0, 1), Total: 2, Unique: 2},
+			{Time: day.AddDate(0, 0, 2)},
+		}, stats.Buckets)
+		suite.Equal([]models.StatsCount{{Key: "news.example.org", Clicks: 2}, {Key: "t.co", Clicks: 1}}, stats.Referrers)
+		suite.Equal([]models.StatsCount{{Key: "DE", Clicks: 2}, {Key: "FR", Clicks: 1}}, stats.Countries)
+		suite.Equal([]models.StatsCount{{Key: "desktop", Clicks: 2}, {Key: "ios", Clicks: 2}}, stats.Devices)
+	})
+
+	// Переходы ботов учитываются по запросу, но не в оценке уникальных посетителей
+	suite.Run("include bots", func() {
+		stats, err := suite.ShortURL.Stats(context.Background(), 1, shortURL.ID, day.Add(3*time.Hour), day.Add(72*time.Hour), "", true)
+		suite.Require().NoError(err)
+		suite.True(stats.IncludeBots)
+		suite.Equal(int64(5), stats.Total)
+		suite.Equal(int64(3), stats.Unique)
+		suite.Equal(int64(1), stats.Bots)
+		suite.Equal(int64(2), stats.ApproxUnique)
+		suite.Equal(models.StatsBucket{Time: day, Total: 3, Unique: 2}, stats.Buckets[0])
+		suite.Equal([]models.StatsCount{{Key: "desktop", Clicks: 3}, {Key: "ios", Clicks: 2}}, stats.Devices)
+	})
+
+	suite.Run("hourly", func() {
+		stats, err := suite.ShortURL.Stats(context.Background(), 1, shortURL.ID, day, day.Add(2*time.Hour), models.StatsIntervalHour, false)
+		suite.Require().NoError(err)
+		suite.Equal([]models.StatsBucket{{Time: day}, {Time: day.Add(time.Hour), Total: 2, Unique: 1}}, stats.Buckets)
+		// Оценка учитывает полные сутки периода
+		suite.Equal(int64(1), stats.ApproxUnique)
+	})
+
+	suite.Run("default period", func() {
+		stats, err := suite.ShortURL.Stats(context.Background(), 1, shortURL.ID, time.Time{}, time.Time{}, models.StatsIntervalHour, false)
+		suite.Require().NoError(err)
+		suite.Len(stats.Buckets, 25)
+		suite.Zero(stats.Total)
+	})
+
+	suite.Run("invalid params", func() {
+		_, err := suite.ShortURL.Stats(context.Background(), 1, shortURL.ID, time.Time{}, time.Time{}, "week", false)
+		suite.Equal(pkgerrors.ErrValidation, err)
+		_, err = suite.ShortURL.Stats(context.Background(), 1, shortURL.ID, day.Add(48*time.Hour), day, "", false)
+		suite.Equal(pkgerrors.ErrValidation, err)
+		_, err = suite.ShortURL.Stats(context.Background(), 1, shortURL.ID, day, day.AddDate(1, 0, 0), models.StatsIntervalHour, false)
+		suite.Equal(pkgerrors.ErrValidation, err)
+	})
+
+	suite.Run("not owner", func() {
+		suite.Require().NoError(suite.User.Create(context.Background(), &models.User{}))
+		_, err := suite.ShortURL.Stats(context.Background(), 2, shortURL.ID, time.Time{}, time.Time{}, "", false)
+		suite.Equal(pkgerrors.ErrNotFound, err)
+	})
+}
+
+func (suite *shortURLSuite) TestExportClicks() {
+	shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://example.com/export")
+	suite.Require().NoError(err)
+	day := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	clicks := []models.Click{
+		{ShortURLID: shortURL.ID, Time: day.Add(time.Hour), Device: models.DeviceIOS, Visitor: "a"},
+		{ShortURLID: shortURL.ID, Time: day.Add(2 * time.Hour), Device: models.DeviceDesktop, Visitor: "bot", Bot: true},
+		{ShortURLID: shortURL.ID, Time: day.Add(25 * time.Hour), Device: models.DeviceDesktop, Visitor: "b"},
+	}
+	suite.Require().NoError(suite.ShortURL.repo.ClickAddBatch(context.Background(), clicks))
+
+	collect := func(from, until time.Time) ([]models.Click, error) {
+		var result []models.Click
+		err := suite.ShortURL.ExportClicks(context.Background(), 1, shortURL.ID, from, until, func(c models.Click) error {
+			result = append(result, c)
+			return nil
+		})
+		return result, err
+	}
+
+	suite.Run("all clicks including bots", func() {
+		actual, err := collect(time.Time{}, time.Time{})
+		suite.NoError(err)
+		suite.Equal(clicks, actual)
+	})
+
+	suite.Run("period", func() {
+		actual, err := collect(day.Add(2*time.Hour), day.Add(24*time.Hour))
+		suite.NoError(err)
+		suite.Equal(clicks[1:2], actual)
+	})
+
+	suite.Run("receiver error", func() {
+		errStop := errors.New("client gone")
+		err := suite.ShortURL.ExportClicks(context.Background(), 1, shortURL.ID, time.Time{}, time.Time{}, func(models.Click) error {
+			return errStop
+		})
+		suite.ErrorIs(err, errStop)
+	})
+
+	suite.Run("invalid period", func() {
+		_, err := collect(day, day)
+		suite.Equal(pkgerrors.ErrValidation, err)
+	})
+
+	suite.Run("not owner", func() {
+		suite.Require().NoError(suite.User.Create(context.Background(), &models.User{}))
+		err := suite.ShortURL.ExportClicks(context.Background(), 2, shortURL.ID, time.Time{}, time.Time{}, func(models.Click) error {
+			suite.Fail("clicks of other user exported")
+			return nil
+		})
 		suite.Equal(pkgerrors.ErrNotFound, err)
 	})
 }
 
+func (suite *shortURLSuite) TestVisitorSketches() {
+	day := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	sketches := visitorSketches([]models.Click{
+		{ShortURLID: "a", Time: day.Add(time.Hour), Visitor: "1"},
+		{ShortURLID: "a", Time: day.Add(2 * time.Hour), Visitor: "2"},
+		{ShortURLID: "b", Time: day.Add(3 * time.Hour), Visitor: "1"},
+		{ShortURLID: "a", Time: day.Add(25 * time.Hour), Visitor: "1"},
+	})
+	counts := make(map[string]uint64)
+	for _, s := range sketches {
+		counts[s.ShortURLID+"/"+s.Day.Format("2006-01-02")] = s.Sketch.Count()
+	}
+	suite.Equal(map[string]uint64{
+		"a/2022-12-01": 2,
+		"b/2022-12-01": 1,
+		"a/2022-12-02": 1,
+		// Скетчи всех посетителей сервиса
+		"/2022-12-01": 2,
+		"/2022-12-02": 1,
+	}, counts)
+
+	// Уникальные посетители сервиса за сутки по данным пакета переходов
+	suite.Require().NoError(suite.ShortURL.repo.VisitorSketchMerge(context.Background(), sketches))
+	stored, err := suite.ShortURL.repo.VisitorSketchGet(context.Background(), models.VisitorSketchAll, day, day.AddDate(0, 0, 2))
+	suite.NoError(err)
+	n, err := approxUnique(stored)
+	suite.NoError(err)
+	suite.Equal(int64(2), n)
+}
+
 func (suite *shortURLSuite) TestResolve() {
 	shortURL, err := suite.ShortURL.Create(context.Background(), 1, "https://google.com")
 	suite.NoError(err)
@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"math/rand"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+)
+
+// validateSplit - проверяет параметры сплит-ссылки
+func (u ShortURL) validateSplit(split *models.Split) error {
+	if split == nil {
+		return nil
+	}
+	if len(split.Variants) < 2 || len(split.Variants) > models.SplitVariantsMaxCount {
+		return pkgerrors.ErrValidation
+	}
+	var total uint
+	for _, v := range split.Variants {
+		if err := u.validateURL(v.URL); err != nil {
+			return err
+		}
+		if v.Weight > models.SplitWeightMax {
+			return pkgerrors.ErrValidation
+		}
+		total += v.Weight
+	}
+	// Хотя бы один вариант должен иметь ненулевой вес
+	if total == 0 {
+		return pkgerrors.ErrValidation
+	}
+	return nil
+}
+
+// pickVariant - выбирает номер варианта сплит-ссылки (начиная с 1).
+// Если за посетителем закреплен существующий вариант с ненулевым весом, то возвращается он.
+// Иначе вариант выбирается случайно пропорционально весам.
+// Если выбрать вариант невозможно, возвращает 0.
+func pickVariant(split *models.Split, sticky int) int {
+	if split == nil {
+		return 0
+	}
+	if split.Sticky && sticky > 0 && sticky <= len(split.Variants) && split.Variants[sticky-1].Weight > 0 {
+		return sticky
+	}
+	var total uint
+	for _, v := range split.Variants {
+		total += v.Weight
+	}
+	if total == 0 {
+		return 0
+	}
+	n := uint(rand.Int63n(int64(total)))
+	for i, v := range split.Variants {
+		if n < v.Weight {
+			return i + 1
+		}
+		n -= v.Weight
+	}
+	return 0
+}
@@ -0,0 +1,229 @@
+package usecases
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/pkg/hll"
+)
+
+// Периоды статистики по умолчанию для каждого интервала агрегации
+var defaultStatsPeriods = map[models.StatsInterval]time.Duration{
+	models.StatsIntervalHour: 24 * time.Hour,
+	models.StatsIntervalDay:  30 * 24 * time.Hour,
+}
+
+// Stats - возвращает статистику переходов по ссылке id пользователя userID за период [from, until)
+// с агрегацией по интервалам interval.
+//   - Если interval не задан, используется агрегация по дням.
+//   - Если until не задан, используется текущее время.
+//   - Если from не задан, используется период по умолчанию: сутки для агрегации по часам и 30 дней для агрегации по дням.
+//
+// Начало периода округляется вниз до границы интервала (в UTC).
+// Переходы ботов учитываются в статистике, только если задан includeBots.
+// Оценка уникальных посетителей ApproxUnique учитывает только людей.
+func (u ShortURL) Stats(ctx context.Context, userID uint, id string, from, until time.Time, interval models.StatsInterval, includeBots bool) (*models.LinkStats, error) {
+	ctx, span := tracer.Start(ctx, "ShortURL.Stats")
+	defer span.End()
+	// Проверяем параметры
+	if interval == "" {
+		interval = models.StatsIntervalDay
+	}
+	period, ok := defaultStatsPeriods[interval]
+	if !ok {
+		return nil, pkgerrors.ErrValidation
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+	if from.IsZero() {
+		from = until.Add(-period)
+	}
+	from, until = truncateStats(from.UTC(), interval), until.UTC()
+	if !until.After(from) {
+		return nil, pkgerrors.ErrValidation
+	}
+	buckets := statsBuckets(from, until, interval)
+	if len(buckets) > models.StatsMaxBuckets {
+		return nil, pkgerrors.ErrValidation
+	}
+
+	// Проверяем, что ссылка принадлежит пользователю
+	if _, err := u.GetOwned(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	// Агрегируем события переходов по мере их чтения, не загружая их в память целиком
+	agg := newStatsAggregator(from, until, interval, buckets, includeBots)
+	if err := u.repo.ClickIterate(ctx, id, from, until, agg.add); err != nil {
+		log.Err(err).Msg("failed to iterate clicks")
+		return nil, pkgerrors.ErrInternal
+	}
+	stats := agg.result()
+
+	// Оцениваем уникальных посетителей по скетчам за сутки, пересекающиеся с периодом
+	sketches, err := u.repo.VisitorSketchGet(ctx, id, from.Truncate(24*time.Hour), until)
+	if err != nil {
+		log.Err(err).Msg("failed to get visitor sketches")
+		return nil, pkgerrors.ErrInternal
+	}
+	if stats.ApproxUnique, err = approxUnique(sketches); err != nil {
+		log.Err(err).Msg("failed to merge visitor sketches")
+		return nil, pkgerrors.ErrInternal
+	}
+	stats.ApproxUniqueError = hll.RelativeError(visitorSketchPrecision)
+	return stats, nil
+}
+
+// statsAggregator - агрегирует события переходов за период в статистику.
+// Переходы ботов подсчитываются отдельно и учитываются в остальной статистике, только если задан includeBots.
+// В памяти хранятся только счетчики и ключи уникальных посетителей, но не сами события.
+type statsAggregator struct {
+	stats          *models.LinkStats
+	includeBots    bool
+	visitors       map[string]struct{}
+	bucketVisitors []map[string]struct{}
+	referrers      map[string]int64
+	countries      map[string]int64
+	devices        map[string]int64
+}
+
+// newStatsAggregator - конструктор statsAggregator
+func newStatsAggregator(from, until time.Time, interval models.StatsInterval, buckets []models.StatsBucket, includeBots bool) *statsAggregator {
+	return &statsAggregator{
+		stats: &models.LinkStats{
+			From:        from,
+			Until:       until,
+			Interval:    interval,
+			IncludeBots: includeBots,
+			Buckets:     buckets,
+		},
+		includeBots:    includeBots,
+		visitors:       make(map[string]struct{}),
+		bucketVisitors: make([]map[string]struct{}, len(buckets)),
+		referrers:      make(map[string]int64),
+		countries:      make(map[string]int64),
+		devices:        make(map[string]int64),
+	}
+}
+
+// add - учитывает событие перехода в статистике. Всегда возвращает nil (см. repo.IRepo.ClickIterate).
+func (a *statsAggregator) add(click models.Click) error {
+	stats := a.stats
+	i := bucketIndex(stats.From, click.Time.UTC(), stats.Interval)
+	if i < 0 || i >= len(stats.Buckets) {
+		return nil
+	}
+	if click.Bot {
+		stats.Bots++
+		if !a.includeBots {
+			return nil
+		}
+	}
+	key := visitorKey(click)
+	stats.Total++
+	a.visitors[key] = struct{}{}
+	stats.Buckets[i].Total++
+	if a.bucketVisitors[i] == nil {
+		a.bucketVisitors[i] = make(map[string]struct{})
+	}
+	a.bucketVisitors[i][key] = struct{}{}
+	if click.RefererHost != "" {
+		a.referrers[click.RefererHost]++
+	}
+	if click.Country != "" {
+		a.countries[click.Country]++
+	}
+	if click.Device != "" {
+		a.devices[string(click.Device)]++
+	}
+	return nil
+}
+
+// result - возвращает статистику по учтенным событиям переходов
+func (a *statsAggregator) result() *models.LinkStats {
+	stats := a.stats
+	stats.Unique = int64(len(a.visitors))
+	for i := range stats.Buckets {
+		stats.Buckets[i].Unique = int64(len(a.bucketVisitors[i]))
+	}
+	stats.Referrers = topCounts(a.referrers)
+	stats.Countries = topCounts(a.countries)
+	stats.Devices = topCounts(a.devices)
+	return stats
+}
+
+// visitorKey - возвращает ключ посетителя для подсчета уникальных посетителей.
+// Для событий без хеша посетителя используется анонимизированный IP-адрес и класс устройства.
+func visitorKey(click models.Click) string {
+	if click.Visitor != "" {
+		return click.Visitor
+	}
+	return click.IP + "|" + string(click.Device)
+}
+
+// topCounts - возвращает models.StatsTopCount значений с наибольшим количеством переходов.
+// При равном количестве значения упорядочиваются по возрастанию.
+func topCounts(counts map[string]int64) []models.StatsCount {
+	result := make([]models.StatsCount, 0, len(counts))
+	for key, n := range counts {
+		result = append(result, models.StatsCount{Key: key, Clicks: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Clicks != result[j].Clicks {
+			return result[i].Clicks > result[j].Clicks
+		}
+		return result[i].Key < result[j].Key
+	})
+	if len(result) > models.StatsTopCount {
+		result = result[:models.StatsTopCount]
+	}
+	return result
+}
+
+// statsBuckets - возвращает пустые интервалы статистики, покрывающие период [from, until).
+// Начало периода from должно быть округлено до границы интервала.
+// Если интервалов больше models.StatsMaxBuckets, то возвращается на один интервал больше лимита.
+func statsBuckets(from, until time.Time, interval models.StatsInterval) []models.StatsBucket {
+	var buckets []models.StatsBucket
+	for t := from; t.Before(until); t = nextStatsBucket(t, interval) {
+		buckets = append(buckets, models.StatsBucket{Time: t})
+		if len(buckets) > models.StatsMaxBuckets {
+			break
+		}
+	}
+	return buckets
+}
+
+// bucketIndex - возвращает номер интервала, в который попадает время t
+func bucketIndex(from, t time.Time, interval models.StatsInterval) int {
+	if t.Before(from) {
+		return -1
+	}
+	if interval == models.StatsIntervalHour {
+		return int(t.Sub(from) / time.Hour)
+	}
+	// Дни в UTC всегда длятся 24 часа
+	return int(t.Sub(from) / (24 * time.Hour))
+}
+
+// truncateStats - округляет время t в UTC вниз до границы интервала
+func truncateStats(t time.Time, interval models.StatsInterval) time.Time {
+	if interval == models.StatsIntervalHour {
+		return t.Truncate(time.Hour)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// nextStatsBucket - возвращает начало интервала, следующего за интервалом, начинающимся в t
+func nextStatsBucket(t time.Time, interval models.StatsInterval) time.Time {
+	if interval == models.StatsIntervalHour {
+		return t.Add(time.Hour)
+	}
+	return t.AddDate(0, 0, 1)
+}
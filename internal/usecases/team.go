@@ -0,0 +1,169 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+// Team - бизнес-логика для работы с командами.
+//
+// Создавший команду пользователь становится ее владельцем (owner).
+// Владельцы управляют участниками команды, владельцы и редакторы (editor)
+// создают, изменяют и удаляют ссылки команды, наблюдатели (viewer) только просматривают их.
+// В команде всегда остается хотя бы один владелец: это проверяется в репозитории в той же операции,
+// что и изменение участника.
+type Team struct {
+	repo repo.IRepo
+	now  func() time.Time
+}
+
+// errLastOwner - ошибка изменения, которое оставит команду без владельцев
+var errLastOwner = pkgerrors.ErrValidation.WithDetail("team must have at least one owner")
+
+// NewTeam - конструктор Team
+func NewTeam(repo repo.IRepo) *Team {
+	return &Team{repo: repo, now: time.Now}
+}
+
+// Create - создает команду с названием name, владельцем которой становится пользователь userID.
+func (u Team) Create(ctx context.Context, userID uint, name string) (*models.Team, error) {
+	ctx, span := tracer.Start(ctx, "Team.Create")
+	defer span.End()
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > models.TeamNameMaxLen {
+		return nil, pkgerrors.ErrValidation.WithDetail("name is required and must be at most 128 characters")
+	}
+	team := &models.Team{Name: name, CreatedAt: u.now().UTC().Truncate(time.Second)}
+	err := u.repo.TeamCreate(ctx, team, userID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkgerrors.ErrNotFound
+	} else if err != nil {
+		log.Err(err).Msg("failed to create team")
+		return nil, pkgerrors.ErrInternal
+	}
+	return team, nil
+}
+
+// GetByUserID - возвращает команды пользователя и его роли в них.
+func (u Team) GetByUserID(ctx context.Context, userID uint) ([]models.TeamMembership, error) {
+	ctx, span := tracer.Start(ctx, "Team.GetByUserID")
+	defer span.End()
+	teams, err := u.repo.TeamGetByUserID(ctx, userID)
+	if err != nil {
+		log.Err(err).Msg("failed to get teams by user id")
+		return nil, pkgerrors.ErrInternal
+	}
+	return teams, nil
+}
+
+// Members - возвращает участников команды teamID.
+// Если пользователь userID не состоит в команде, возвращает ErrNotFound.
+func (u Team) Members(ctx context.Context, userID, teamID uint) ([]models.TeamMember, error) {
+	ctx, span := tracer.Start(ctx, "Team.Members")
+	defer span.End()
+	if _, err := teamRole(ctx, u.repo, teamID, userID); err != nil {
+		return nil, err
+	}
+	return u.members(ctx, teamID)
+}
+
+// SetMember - добавляет в команду teamID зарегистрированного пользователя с адресом email
+// или изменяет его роль. Доступно только владельцам команды, иначе возвращает ErrForbidden.
+// Если команда или пользователь с таким email не найдены, возвращает ErrNotFound.
+// Если изменение роли оставит команду без владельцев, возвращает ErrValidation.
+func (u Team) SetMember(ctx context.Context, userID, teamID uint, email string, role models.TeamRole) (*models.TeamMember, error) {
+	ctx, span := tracer.Start(ctx, "Team.SetMember")
+	defer span.End()
+	if !role.Valid() {
+		return nil, pkgerrors.ErrValidation.WithDetail("unknown role: " + string(role))
+	}
+	if err := u.checkManager(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+	user, err := u.repo.UserGetByEmail(ctx, normalizeEmail(email))
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkgerrors.ErrNotFound
+	} else if err != nil {
+		log.Err(err).Msg("failed to get user by email")
+		return nil, pkgerrors.ErrInternal
+	}
+	member := &models.TeamMember{TeamID: teamID, UserID: user.ID, Role: role}
+	err = u.repo.TeamMemberSet(ctx, member)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkgerrors.ErrNotFound
+	} else if errors.Is(err, repo.ErrLastOwner) {
+		return nil, errLastOwner
+	} else if err != nil {
+		log.Err(err).Msg("failed to set team member")
+		return nil, pkgerrors.ErrInternal
+	}
+	return member, nil
+}
+
+// RemoveMember - удаляет участника memberID из команды teamID.
+// Владелец может удалить любого участника, остальные участники - только себя, иначе возвращает ErrForbidden.
+// Если участник не найден, возвращает ErrNotFound.
+// Если удаление оставит команду без владельцев, возвращает ErrValidation.
+func (u Team) RemoveMember(ctx context.Context, userID, teamID, memberID uint) error {
+	ctx, span := tracer.Start(ctx, "Team.RemoveMember")
+	defer span.End()
+	if userID != memberID {
+		if err := u.checkManager(ctx, userID, teamID); err != nil {
+			return err
+		}
+	}
+	err := u.repo.TeamMemberDelete(ctx, teamID, memberID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkgerrors.ErrNotFound
+	} else if errors.Is(err, repo.ErrLastOwner) {
+		return errLastOwner
+	} else if err != nil {
+		log.Err(err).Msg("failed to delete team member")
+		return pkgerrors.ErrInternal
+	}
+	return nil
+}
+
+// checkManager - проверяет, что пользователь userID может управлять участниками команды teamID
+func (u Team) checkManager(ctx context.Context, userID, teamID uint) error {
+	role, err := teamRole(ctx, u.repo, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !role.CanManage() {
+		return pkgerrors.ErrForbidden
+	}
+	return nil
+}
+
+// members - возвращает участников команды из репозитория
+func (u Team) members(ctx context.Context, teamID uint) ([]models.TeamMember, error) {
+	members, err := u.repo.TeamMemberGetByTeamID(ctx, teamID)
+	if err != nil {
+		log.Err(err).Msg("failed to get team members")
+		return nil, pkgerrors.ErrInternal
+	}
+	return members, nil
+}
+
+// teamRole - возвращает роль пользователя userID в команде teamID.
+// Если команда не найдена или пользователь в ней не состоит, возвращает ErrNotFound.
+func teamRole(ctx context.Context, r repo.IRepo, teamID, userID uint) (models.TeamRole, error) {
+	member, err := r.TeamMemberGet(ctx, teamID, userID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return "", pkgerrors.ErrNotFound
+	} else if err != nil {
+		log.Err(err).Msg("failed to get team member")
+		return "", pkgerrors.ErrInternal
+	}
+	return member.Role, nil
+}
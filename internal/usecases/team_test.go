@@ -0,0 +1,205 @@
+package usecases
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ofstudio/go-shortener/internal/config"
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+type teamSuite struct {
+	suite.Suite
+	*Team
+	shortURL *ShortURL
+	repo     *repo.MemoryRepo
+	// Пользователи: владелец, редактор, наблюдатель и посторонний
+	owner, editor, viewer, stranger uint
+}
+
+func TestTeamSuite(t *testing.T) {
+	suite.Run(t, new(teamSuite))
+}
+
+func (suite *teamSuite) SetupTest() {
+	os.Clearenv()
+	cfg, err := config.Default(nil)
+	suite.Require().NoError(err)
+	suite.repo = repo.NewMemoryRepo()
+	suite.Team = NewTeam(suite.repo)
+	suite.shortURL = NewShortURL(context.Background(), cfg, suite.repo)
+	suite.owner = suite.createUser("owner@example.com")
+	suite.editor = suite.createUser("editor@example.com")
+	suite.viewer = suite.createUser("viewer@example.com")
+	suite.stranger = suite.createUser("stranger@example.com")
+}
+
+func (suite *teamSuite) TestCreate() {
+	ctx := context.Background()
+	team, err := suite.Create(ctx, suite.owner, " Marketing ")
+	suite.Require().NoError(err)
+	suite.Equal("Marketing", team.Name)
+	teams, err := suite.GetByUserID(ctx, suite.owner)
+	suite.NoError(err)
+	suite.Equal([]models.TeamMembership{{Team: *team, Role: models.TeamRoleOwner}}, teams)
+
+	for _, name := range []string{"", " ", strings.Repeat("a", models.TeamNameMaxLen+1)} {
+		_, err = suite.Create(ctx, suite.owner, name)
+		suite.ErrorIs(err, pkgerrors.ErrValidation)
+	}
+	_, err = suite.Create(ctx, 100, "Sales")
+	suite.ErrorIs(err, pkgerrors.ErrNotFound)
+}
+
+func (suite *teamSuite) TestMembers() {
+	ctx := context.Background()
+	team := suite.createTeam()
+
+	members, err := suite.Members(ctx, suite.viewer, team.ID)
+	suite.NoError(err)
+	suite.Len(members, 3)
+	_, err = suite.Members(ctx, suite.stranger, team.ID)
+	suite.ErrorIs(err, pkgerrors.ErrNotFound)
+
+	suite.Run("set member", func() {
+		// Участниками управляют только владельцы
+		_, err := suite.SetMember(ctx, suite.editor, team.ID, "stranger@example.com", models.TeamRoleViewer)
+		suite.ErrorIs(err, pkgerrors.ErrForbidden)
+		_, err = suite.SetMember(ctx, suite.stranger, team.ID, "stranger@example.com", models.TeamRoleViewer)
+		suite.ErrorIs(err, pkgerrors.ErrNotFound)
+		_, err = suite.SetMember(ctx, suite.owner, team.ID, "unknown@example.com", models.TeamRoleViewer)
+		suite.ErrorIs(err, pkgerrors.ErrNotFound)
+		_, err = suite.SetMember(ctx, suite.owner, team.ID, "stranger@example.com", "admin")
+		suite.ErrorIs(err, pkgerrors.ErrValidation)
+		// Единственный владелец не может понизить свою роль
+		_, err = suite.SetMember(ctx, suite.owner, team.ID, "owner@example.com", models.TeamRoleEditor)
+		suite.ErrorIs(err, pkgerrors.ErrValidation)
+	})
+
+	suite.Run("remove member", func() {
+		suite.ErrorIs(suite.RemoveMember(ctx, suite.editor, team.ID, suite.viewer), pkgerrors.ErrForbidden)
+		suite.ErrorIs(suite.RemoveMember(ctx, suite.owner, team.ID, suite.owner), pkgerrors.ErrValidation)
+		suite.ErrorIs(suite.RemoveMember(ctx, suite.owner, team.ID, suite.stranger), pkgerrors.ErrNotFound)
+		// Участник может выйти из команды сам
+		suite.NoError(suite.RemoveMember(ctx, suite.viewer, team.ID, suite.viewer))
+		suite.NoError(suite.RemoveMember(ctx, suite.owner, team.ID, suite.editor))
+		members, err := suite.Members(ctx, suite.owner, team.ID)
+		suite.NoError(err)
+		suite.Equal([]models.TeamMember{{TeamID: team.ID, UserID: suite.owner, Role: models.TeamRoleOwner}}, members)
+	})
+
+	suite.Run("concurrent owners", func() {
+		// Два владельца одновременно удаляют друг друга: в команде должен остаться владелец
+		_, err := suite.SetMember(ctx, suite.owner, team.ID, "editor@example.com", models.TeamRoleOwner)
+		suite.Require().NoError(err)
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i, ids := range [][2]uint{{suite.owner, suite.editor}, {suite.editor, suite.owner}} {
+			wg.Add(1)
+			go func(i int, userID, memberID uint) {
+				defer wg.Done()
+				errs[i] = suite.RemoveMember(ctx, userID, team.ID, memberID)
+			}(i, ids[0], ids[1])
+		}
+		wg.Wait()
+		// Одно удаление выполнено, второе отклонено
+		suite.True((errs[0] == nil) != (errs[1] == nil), errs)
+		members, err := suite.repo.TeamMemberGetByTeamID(ctx, team.ID)
+		suite.NoError(err)
+		suite.Require().Len(members, 1)
+		suite.Equal(models.TeamRoleOwner, members[0].Role)
+	})
+}
+
+func (suite *teamSuite) TestShortURLs() {
+	ctx := context.Background()
+	team := suite.createTeam()
+
+	suite.Run("create", func() {
+		_, err := suite.shortURL.Create(ctx, suite.viewer, "https://viewer.com", WithTeam(team.ID))
+		suite.ErrorIs(err, pkgerrors.ErrForbidden)
+		_, err = suite.shortURL.Create(ctx, suite.stranger, "https://stranger.com", WithTeam(team.ID))
+		suite.ErrorIs(err, pkgerrors.ErrNotFound)
+		shortURL, err := suite.shortURL.Create(ctx, suite.editor, "https://editor.com", WithTeam(team.ID))
+		suite.Require().NoError(err)
+		suite.Equal(team.ID, shortURL.TeamID)
+	})
+
+	shortURL, err := suite.shortURL.Create(ctx, suite.owner, "https://team.com", WithTeam(team.ID))
+	suite.Require().NoError(err)
+
+	suite.Run("read", func() {
+		urls, err := suite.shortURL.GetByTeamID(ctx, suite.viewer, team.ID)
+		suite.NoError(err)
+		suite.Len(urls, 2)
+		_, err = suite.shortURL.GetByTeamID(ctx, suite.stranger, team.ID)
+		suite.ErrorIs(err, pkgerrors.ErrNotFound)
+		_, err = suite.shortURL.GetOwned(ctx, suite.viewer, shortURL.ID)
+		suite.NoError(err)
+		_, err = suite.shortURL.GetOwned(ctx, suite.stranger, shortURL.ID)
+		suite.ErrorIs(err, pkgerrors.ErrNotFound)
+	})
+
+	suite.Run("edit", func() {
+		rules := []models.RedirectRule{{URL: "https://team.de", Languages: []string{"de"}}}
+		_, err := suite.shortURL.SetRules(ctx, suite.viewer, shortURL.ID, rules)
+		suite.ErrorIs(err, pkgerrors.ErrForbidden)
+		updated, err := suite.shortURL.SetRules(ctx, suite.editor, shortURL.ID, rules)
+		suite.NoError(err)
+		suite.Equal(rules, updated.Rules)
+	})
+
+	suite.Run("set team", func() {
+		personal, err := suite.shortURL.Create(ctx, suite.editor, "https://personal.com")
+		suite.Require().NoError(err)
+		_, err = suite.shortURL.SetTeam(ctx, suite.viewer, personal.ID, team.ID)
+		suite.ErrorIs(err, pkgerrors.ErrNotFound)
+		updated, err := suite.shortURL.SetTeam(ctx, suite.editor, personal.ID, team.ID)
+		suite.Require().NoError(err)
+		suite.Equal(team.ID, updated.TeamID)
+		// Сделать ссылку личной может только создавший ее пользователь
+		_, err = suite.shortURL.SetTeam(ctx, suite.owner, personal.ID, 0)
+		suite.ErrorIs(err, pkgerrors.ErrForbidden)
+		updated, err = suite.shortURL.SetTeam(ctx, suite.editor, personal.ID, 0)
+		suite.Require().NoError(err)
+		suite.Zero(updated.TeamID)
+	})
+
+	suite.Run("left team", func() {
+		// Ссылки команды остаются у команды после выхода создавшего их участника
+		suite.NoError(suite.RemoveMember(ctx, suite.editor, team.ID, suite.editor))
+		urls, err := suite.shortURL.GetByUserID(ctx, suite.editor)
+		suite.NoError(err)
+		suite.Len(urls, 1)
+		urls, err = suite.shortURL.GetByTeamID(ctx, suite.owner, team.ID)
+		suite.NoError(err)
+		suite.Len(urls, 2)
+	})
+}
+
+// createUser - создает зарегистрированного пользователя с адресом email
+func (suite *teamSuite) createUser(email string) uint {
+	user := &models.User{}
+	suite.Require().NoError(suite.repo.UserCreate(context.Background(), user))
+	suite.Require().NoError(suite.repo.UserSetCredentials(context.Background(), user.ID, email, "hash"))
+	return user.ID
+}
+
+// createTeam - создает команду с владельцем, редактором и наблюдателем
+func (suite *teamSuite) createTeam() *models.Team {
+	ctx := context.Background()
+	team, err := suite.Create(ctx, suite.owner, "Marketing")
+	suite.Require().NoError(err)
+	_, err = suite.SetMember(ctx, suite.owner, team.ID, "editor@example.com", models.TeamRoleEditor)
+	suite.Require().NoError(err)
+	_, err = suite.SetMember(ctx, suite.owner, team.ID, "Viewer@example.com", models.TeamRoleViewer)
+	suite.Require().NoError(err)
+	return team
+}
@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// generationTTL - время, в течение которого поколение токенов пользователя берется из кеша.
+	// Токены, отозванные на другом экземпляре сервиса, принимаются не дольше этого времени.
+	generationTTL = 10 * time.Second
+	// generationCacheSize - количество пользователей в кеше, при котором из него удаляются устаревшие записи
+	generationCacheSize = 10000
+)
+
+// generationCache - кеш поколений токенов пользователей.
+// Позволяет не читать пользователя из репозитория при каждом запросе с токеном.
+type generationCache struct {
+	mu      sync.Mutex
+	entries map[uint]generationEntry
+}
+
+// generationEntry - запись кеша поколений токенов
+type generationEntry struct {
+	generation uint
+	expiresAt  time.Time
+}
+
+// newGenerationCache - конструктор generationCache
+func newGenerationCache() *generationCache {
+	return &generationCache{entries: make(map[uint]generationEntry)}
+}
+
+// get - возвращает поколение токенов пользователя id, если оно есть в кеше и не устарело
+func (c *generationCache) get(id uint) (uint, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[id]
+	if !ok || !time.Now().Before(e.expiresAt) {
+		return 0, false
+	}
+	return e.generation, true
+}
+
+// set - сохраняет в кеш поколение токенов пользователя id
+func (c *generationCache) set(id, generation uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= generationCacheSize {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	if len(c.entries) >= generationCacheSize {
+		c.entries = make(map[uint]generationEntry)
+	}
+	c.entries[id] = generationEntry{generation: generation, expiresAt: now.Add(generationTTL)}
+}
+
+// forget - удаляет из кеша поколение токенов пользователя id
+func (c *generationCache) forget(id uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+}
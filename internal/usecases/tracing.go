@@ -0,0 +1,7 @@
+package usecases
+
+import "go.opentelemetry.io/otel"
+
+// tracer - трассировщик юзкейсов.
+// Span юзкейсов становятся дочерними к span http- или grpc-запроса и родительскими к span репозитория.
+var tracer = otel.Tracer("github.com/ofstudio/go-shortener/internal/usecases")
@@ -2,26 +2,39 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/ofstudio/go-shortener/internal/models"
 	"github.com/ofstudio/go-shortener/internal/pkgerrors"
 	"github.com/ofstudio/go-shortener/internal/repo"
 )
 
+// dummyPasswordHash - хеш для сравнения пароля при входе с неизвестным email.
+// Позволяет не раскрывать по времени ответа, зарегистрирован ли email.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
 // User - бизнес-логика для работы с пользователями
 type User struct {
-	repo repo.IRepo
+	repo        repo.IRepo
+	cost        int              // Сложность bcrypt-хеша пароля
+	generations *generationCache // Кеш поколений токенов пользователей
 }
 
 // NewUser - конструктор User
 func NewUser(repo repo.IRepo) *User {
-	return &User{repo: repo}
+	return &User{repo: repo, cost: bcrypt.DefaultCost, generations: newGenerationCache()}
 }
 
 // Create - создает нового пользователя
 func (u User) Create(ctx context.Context, user *models.User) error {
+	ctx, span := tracer.Start(ctx, "User.Create")
+	defer span.End()
 	if err := u.repo.UserCreate(ctx, user); err != nil {
 		log.Err(err).Msg("failed to create user")
 		return pkgerrors.ErrInternal
@@ -31,6 +44,8 @@ func (u User) Create(ctx context.Context, user *models.User) error {
 
 // Count - возвращает количество пользователей
 func (u User) Count(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "User.Count")
+	defer span.End()
 	count, err := u.repo.UserCount(ctx)
 	if err != nil {
 		log.Err(err).Msg("failed to count users")
@@ -38,3 +53,169 @@ func (u User) Count(ctx context.Context) (int, error) {
 	}
 	return count, nil
 }
+
+// Register - регистрирует пользователя с email и паролем.
+// Если передан id текущего анонимного пользователя (claimID), то регистрируется он сам:
+// все его ссылки сохраняются за зарегистрированным пользователем.
+// Иначе, а также если текущий пользователь уже зарегистрирован, создается новый пользователь.
+// Если email уже зарегистрирован, возвращает ErrDuplicate.
+func (u User) Register(ctx context.Context, claimID uint, email, password string) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "User.Register")
+	defer span.End()
+	email, err := validateCredentials(email, password)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), u.cost)
+	if err != nil {
+		log.Err(err).Msg("failed to hash password")
+		return nil, pkgerrors.ErrInternal
+	}
+
+	// Определяем, какого пользователя регистрировать
+	user, err := u.repo.UserGetByID(ctx, claimID)
+	if errors.Is(err, repo.ErrNotFound) || err == nil && user.Registered() {
+		user = &models.User{}
+		if err = u.Create(ctx, user); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		log.Err(err).Msg("failed to get user by id")
+		return nil, pkgerrors.ErrInternal
+	}
+
+	// Сохраняем учетные данные
+	err = u.repo.UserSetCredentials(ctx, user.ID, email, string(hash))
+	if errors.Is(err, repo.ErrDuplicate) {
+		return nil, pkgerrors.ErrDuplicate
+	} else if err != nil {
+		log.Err(err).Msg("failed to set user credentials")
+		return nil, pkgerrors.ErrInternal
+	}
+	user.Email, user.PasswordHash = email, string(hash)
+	return user, nil
+}
+
+// Login - возвращает зарегистрированного пользователя по email и паролю.
+// Если email не зарегистрирован или пароль неверен, возвращает ErrAuth.
+func (u User) Login(ctx context.Context, email, password string) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "User.Login")
+	defer span.End()
+	user, err := u.repo.UserGetByEmail(ctx, normalizeEmail(email))
+	if errors.Is(err, repo.ErrNotFound) {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return nil, pkgerrors.ErrAuth
+	} else if err != nil {
+		log.Err(err).Msg("failed to get user by email")
+		return nil, pkgerrors.ErrInternal
+	}
+	if err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, pkgerrors.ErrAuth
+	}
+	return user, nil
+}
+
+// TokenGeneration - возвращает текущее поколение токенов пользователя.
+// Для пользователя, отсутствующего в хранилище, возвращает 0: его токены проверяются только по подписи.
+// Поколение кешируется на generationTTL: токены, отозванные на другом экземпляре сервиса,
+// принимаются не дольше этого времени.
+func (u User) TokenGeneration(ctx context.Context, id uint) (uint, error) {
+	ctx, span := tracer.Start(ctx, "User.TokenGeneration")
+	defer span.End()
+	if generation, ok := u.generations.get(id); ok {
+		return generation, nil
+	}
+	var generation uint
+	user, err := u.repo.UserGetByID(ctx, id)
+	if err == nil {
+		generation = user.TokenGeneration
+	} else if !errors.Is(err, repo.ErrNotFound) {
+		log.Err(err).Msg("failed to get user by id")
+		return 0, pkgerrors.ErrInternal
+	}
+	u.generations.set(id, generation)
+	return generation, nil
+}
+
+// RevokeTokens - отзывает все выданные токены пользователя и возвращает новое поколение токенов.
+// Если пользователь не найден, возвращает ErrNotFound.
+func (u User) RevokeTokens(ctx context.Context, id uint) (uint, error) {
+	ctx, span := tracer.Start(ctx, "User.RevokeTokens")
+	defer span.End()
+	generation, err := u.repo.UserRevokeTokens(ctx, id)
+	u.generations.forget(id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return 0, pkgerrors.ErrNotFound
+	} else if err != nil {
+		log.Err(err).Msg("failed to revoke user tokens")
+		return 0, pkgerrors.ErrInternal
+	}
+	return generation, nil
+}
+
+// LoginOIDC - возвращает пользователя, вошедшего через провайдера OpenID Connect issuer
+// с идентификатором subject.
+// Если пользователь входит впервые, то он регистрируется так же, как в Register:
+// текущий анонимный пользователь claimID, либо новый пользователь.
+func (u User) LoginOIDC(ctx context.Context, claimID uint, issuer, subject string) (*models.User, error) {
+	ctx, span := tracer.Start(ctx, "User.LoginOIDC")
+	defer span.End()
+	if issuer == "" || subject == "" {
+		return nil, pkgerrors.ErrAuth
+	}
+	oidcSubject := issuer + " " + subject
+
+	// Ищем пользователя, уже входившего через провайдера
+	user, err := u.repo.UserGetByOIDCSubject(ctx, oidcSubject)
+	if err == nil {
+		return user, nil
+	} else if !errors.Is(err, repo.ErrNotFound) {
+		log.Err(err).Msg("failed to get user by oidc subject")
+		return nil, pkgerrors.ErrInternal
+	}
+
+	// Определяем, какого пользователя регистрировать
+	user, err = u.repo.UserGetByID(ctx, claimID)
+	if errors.Is(err, repo.ErrNotFound) || err == nil && user.Registered() {
+		user = &models.User{}
+		if err = u.Create(ctx, user); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		log.Err(err).Msg("failed to get user by id")
+		return nil, pkgerrors.ErrInternal
+	}
+
+	// Сохраняем идентификатор
+	err = u.repo.UserSetOIDCSubject(ctx, user.ID, oidcSubject)
+	if errors.Is(err, repo.ErrDuplicate) {
+		// Пользователь зарегистрирован параллельным запросом
+		if user, err = u.repo.UserGetByOIDCSubject(ctx, oidcSubject); err == nil {
+			return user, nil
+		}
+	}
+	if err != nil {
+		log.Err(err).Msg("failed to set user oidc subject")
+		return nil, pkgerrors.ErrInternal
+	}
+	user.OIDCSubject = oidcSubject
+	return user, nil
+}
+
+// validateCredentials - проверяет email и пароль. Возвращает email в нормализованном виде.
+func validateCredentials(email, password string) (string, error) {
+	email = normalizeEmail(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > models.EmailMaxLen {
+		return "", pkgerrors.ErrValidation.WithDetail("invalid email")
+	}
+	if utf8.RuneCountInString(password) < models.PasswordMinLen || len(password) > models.PasswordMaxLen {
+		return "", pkgerrors.ErrValidation.WithDetail("password must be 8 to 72 bytes long")
+	}
+	return email, nil
+}
+
+// normalizeEmail - приводит email к нормализованному виду: без пробелов по краям и в нижнем регистре
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
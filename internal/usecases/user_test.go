@@ -0,0 +1,158 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/internal/pkgerrors"
+	"github.com/ofstudio/go-shortener/internal/repo"
+)
+
+type userSuite struct {
+	suite.Suite
+	*User
+}
+
+func TestUserSuite(t *testing.T) {
+	suite.Run(t, new(userSuite))
+}
+
+func (suite *userSuite) SetupTest() {
+	suite.User = NewUser(repo.NewMemoryRepo())
+	suite.User.cost = bcrypt.MinCost
+}
+
+func (suite *userSuite) TestRegister() {
+	ctx := context.Background()
+
+	suite.Run("claim anonymous user", func() {
+		anonymous := &models.User{}
+		suite.Require().NoError(suite.Create(ctx, anonymous))
+		user, err := suite.Register(ctx, anonymous.ID, " User@Example.com ", "password")
+		suite.Require().NoError(err)
+		suite.Equal(anonymous.ID, user.ID)
+		suite.Equal("user@example.com", user.Email)
+		suite.NotEqual("password", user.PasswordHash)
+	})
+
+	suite.Run("new user", func() {
+		user, err := suite.Register(ctx, 0, "new@example.com", "password")
+		suite.Require().NoError(err)
+		suite.NotZero(user.ID)
+		// Зарегистрированный пользователь не может быть зарегистрирован повторно: создается новый
+		other, err := suite.Register(ctx, user.ID, "other@example.com", "password")
+		suite.Require().NoError(err)
+		suite.NotEqual(user.ID, other.ID)
+	})
+
+	suite.Run("duplicate email", func() {
+		_, err := suite.Register(ctx, 0, "USER@example.com", "password")
+		suite.ErrorIs(err, pkgerrors.ErrDuplicate)
+	})
+
+	suite.Run("validation", func() {
+		for _, tt := range []struct{ email, password string }{
+			{"", "password"},
+			{"not an email", "password"},
+			{"Name <name@example.com>", "password"},
+			{"short@example.com", "passwd"},
+			{"long@example.com", strings.Repeat("a", models.PasswordMaxLen+1)},
+		} {
+			_, err := suite.Register(ctx, 0, tt.email, tt.password)
+			suite.ErrorIs(err, pkgerrors.ErrValidation, tt.email)
+		}
+	})
+}
+
+func (suite *userSuite) TestLogin() {
+	ctx := context.Background()
+	registered, err := suite.Register(ctx, 0, "user@example.com", "password")
+	suite.Require().NoError(err)
+
+	user, err := suite.Login(ctx, "User@example.com", "password")
+	suite.NoError(err)
+	suite.Equal(registered.ID, user.ID)
+
+	_, err = suite.Login(ctx, "user@example.com", "wrong password")
+	suite.ErrorIs(err, pkgerrors.ErrAuth)
+	_, err = suite.Login(ctx, "unknown@example.com", "password")
+	suite.ErrorIs(err, pkgerrors.ErrAuth)
+}
+
+func (suite *userSuite) TestLoginOIDC() {
+	ctx := context.Background()
+	const issuer = "https://idp.example.com"
+
+	suite.Run("claim anonymous user", func() {
+		anonymous := &models.User{}
+		suite.Require().NoError(suite.Create(ctx, anonymous))
+		user, err := suite.LoginOIDC(ctx, anonymous.ID, issuer, "alice")
+		suite.Require().NoError(err)
+		suite.Equal(anonymous.ID, user.ID)
+		suite.Equal(issuer+" alice", user.OIDCSubject)
+
+		// Повторный вход возвращает того же пользователя
+		again, err := suite.LoginOIDC(ctx, 0, issuer, "alice")
+		suite.Require().NoError(err)
+		suite.Equal(user.ID, again.ID)
+	})
+
+	suite.Run("new user", func() {
+		registered, err := suite.Register(ctx, 0, "user@example.com", "password")
+		suite.Require().NoError(err)
+		// Зарегистрированный пользователь не присваивается: создается новый
+		user, err := suite.LoginOIDC(ctx, registered.ID, issuer, "bob")
+		suite.Require().NoError(err)
+		suite.NotEqual(registered.ID, user.ID)
+		// Одинаковый subject у разных издателей - разные пользователи
+		other, err := suite.LoginOIDC(ctx, 0, "https://other.example.com", "bob")
+		suite.Require().NoError(err)
+		suite.NotEqual(user.ID, other.ID)
+	})
+
+	suite.Run("empty subject", func() {
+		_, err := suite.LoginOIDC(ctx, 0, issuer, "")
+		suite.ErrorIs(err, pkgerrors.ErrAuth)
+	})
+}
+
+func (suite *userSuite) TestRevokeTokens() {
+	ctx := context.Background()
+	user := &models.User{}
+	suite.Require().NoError(suite.Create(ctx, user))
+
+	generation, err := suite.TokenGeneration(ctx, user.ID)
+	suite.NoError(err)
+	suite.Zero(generation)
+	generation, err = suite.RevokeTokens(ctx, user.ID)
+	suite.NoError(err)
+	suite.Equal(uint(1), generation)
+	generation, err = suite.TokenGeneration(ctx, user.ID)
+	suite.NoError(err)
+	suite.Equal(uint(1), generation)
+
+	// Отсутствующий пользователь
+	_, err = suite.RevokeTokens(ctx, 100)
+	suite.ErrorIs(err, pkgerrors.ErrNotFound)
+	generation, err = suite.TokenGeneration(ctx, 100)
+	suite.NoError(err)
+	suite.Zero(generation)
+
+	suite.Run("cached generation", func() {
+		// Отзыв токенов в обход User (на другом экземпляре сервиса) виден после истечения кеша
+		_, err := suite.repo.UserRevokeTokens(ctx, user.ID)
+		suite.Require().NoError(err)
+		generation, err := suite.TokenGeneration(ctx, user.ID)
+		suite.NoError(err)
+		suite.Equal(uint(1), generation)
+		suite.generations.forget(user.ID)
+		generation, err = suite.TokenGeneration(ctx, user.ID)
+		suite.NoError(err)
+		suite.Equal(uint(2), generation)
+	})
+}
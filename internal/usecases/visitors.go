@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/ofstudio/go-shortener/internal/models"
+	"github.com/ofstudio/go-shortener/pkg/hll"
+)
+
+// visitorSketchPrecision - точность скетчей уникальных посетителей: 16 КБ на ссылку в сутки, ошибка 0.81%
+const visitorSketchPrecision = hll.DefaultPrecision
+
+// visitorSketchKey - ключ скетча посетителей: ссылка и начало суток
+type visitorSketchKey struct {
+	id  string
+	day time.Time
+}
+
+// visitorSketches - строит скетчи посетителей пакета событий переходов по ссылкам и суткам (UTC).
+// Кроме скетчей ссылок, строит скетчи всех посетителей сервиса с id models.VisitorSketchAll.
+// Переходы ботов в скетчах не учитываются.
+func visitorSketches(clicks []models.Click) []models.VisitorSketch {
+	sketches := make(map[visitorSketchKey]*hll.Sketch)
+	var order []visitorSketchKey
+	add := func(key visitorSketchKey, visitor []byte) {
+		s, ok := sketches[key]
+		if !ok {
+			s = newVisitorSketch()
+			sketches[key] = s
+			order = append(order, key)
+		}
+		s.Add(visitor)
+	}
+	for _, click := range clicks {
+		if click.Bot {
+			continue
+		}
+		day := click.Time.UTC().Truncate(24 * time.Hour)
+		visitor := []byte(visitorKey(click))
+		add(visitorSketchKey{id: click.ShortURLID, day: day}, visitor)
+		add(visitorSketchKey{id: models.VisitorSketchAll, day: day}, visitor)
+	}
+	result := make([]models.VisitorSketch, 0, len(order))
+	for _, key := range order {
+		result = append(result, models.VisitorSketch{ShortURLID: key.id, Day: key.day, Sketch: sketches[key]})
+	}
+	return result
+}
+
+// approxUnique - возвращает оценку количества уникальных посетителей по объединению скетчей
+func approxUnique(sketches []models.VisitorSketch) (int64, error) {
+	merged := newVisitorSketch()
+	for _, s := range sketches {
+		if err := merged.Merge(s.Sketch); err != nil {
+			return 0, err
+		}
+	}
+	return int64(merged.Count()), nil
+}
+
+// newVisitorSketch - создает пустой скетч посетителей
+func newVisitorSketch() *hll.Sketch {
+	// Ошибка невозможна: точность задана константой из допустимого диапазона
+	s, _ := hll.New(visitorSketchPrecision)
+	return s
+}
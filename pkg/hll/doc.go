@@ -0,0 +1,6 @@
+// Package hll - HyperLogLog-скетч для приблизительного подсчета количества уникальных элементов.
+//
+// Скетч занимает 2^precision байт, относительная стандартная ошибка оценки - 1.04/sqrt(2^precision).
+// Скетчи с одинаковой точностью можно объединять: оценка объединения равна оценке скетча,
+// построенного по всем элементам сразу.
+package hll
@@ -0,0 +1,227 @@
+package hll
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// Допустимая точность скетча
+const (
+	MinPrecision     uint8 = 4
+	MaxPrecision     uint8 = 16
+	DefaultPrecision uint8 = 14 // 16 КБ, стандартная ошибка 0.81%
+)
+
+// Ошибки скетча
+var (
+	ErrPrecision         = errors.New("hll: precision out of range")
+	ErrPrecisionMismatch = errors.New("hll: precision mismatch")
+	ErrInvalidData       = errors.New("hll: invalid data")
+)
+
+// Формат бинарного представления скетча
+const (
+	formatVersion  byte = 1
+	encodingDense  byte = 0 // все регистры подряд
+	encodingSparse byte = 1 // только ненулевые регистры: 2 байта номер + 1 байт значение
+	headerSize          = 3
+	sparseEntrySize     = 3
+)
+
+// Sketch - HyperLogLog-скетч.
+// Не является потокобезопасным.
+type Sketch struct {
+	p         uint8
+	registers []uint8
+}
+
+// New - создает пустой скетч с точностью precision в диапазоне [MinPrecision, MaxPrecision].
+func New(precision uint8) (*Sketch, error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, ErrPrecision
+	}
+	return &Sketch{p: precision, registers: make([]uint8, 1<<precision)}, nil
+}
+
+// Precision - возвращает точность скетча
+func (s *Sketch) Precision() uint8 {
+	return s.p
+}
+
+// Add - добавляет элемент в скетч
+func (s *Sketch) Add(data []byte) {
+	s.AddHash(hash(data))
+}
+
+// AddHash - добавляет в скетч элемент по его 64-битному хешу.
+// Хеш должен быть равномерно распределен.
+func (s *Sketch) AddHash(x uint64) {
+	idx := x >> (64 - s.p)
+	// Младший бит-ограничитель гарантирует ранг не больше 64-p+1
+	w := x<<s.p | 1<<(s.p-1)
+	rank := uint8(bits.LeadingZeros64(w)) + 1
+	if rank > s.registers[idx] {
+		s.registers[idx] = rank
+	}
+}
+
+// Count - возвращает оценку количества уникальных элементов
+func (s *Sketch) Count() uint64 {
+	m := float64(len(s.registers))
+	var sum float64
+	zeros := 0
+	for _, r := range s.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := alpha(m) * m * m / sum
+	// Для малых значений используется линейный подсчет
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// Merge - объединяет скетч other с текущим.
+// Возвращает ErrPrecisionMismatch, если точность скетчей отличается.
+func (s *Sketch) Merge(other *Sketch) error {
+	if s.p != other.p {
+		return ErrPrecisionMismatch
+	}
+	for i, r := range other.registers {
+		if r > s.registers[i] {
+			s.registers[i] = r
+		}
+	}
+	return nil
+}
+
+// Clone - возвращает копию скетча
+func (s *Sketch) Clone() *Sketch {
+	registers := make([]uint8, len(s.registers))
+	copy(registers, s.registers)
+	return &Sketch{p: s.p, registers: registers}
+}
+
+// RelativeError - возвращает относительную стандартную ошибку оценки скетча
+func (s *Sketch) RelativeError() float64 {
+	return RelativeError(s.p)
+}
+
+// RelativeError - возвращает относительную стандартную ошибку оценки для точности precision
+func RelativeError(precision uint8) float64 {
+	return 1.04 / math.Sqrt(float64(uint64(1)<<precision))
+}
+
+// MarshalBinary - реализует encoding.BinaryMarshaler.
+// Скетч с небольшим количеством ненулевых регистров кодируется в разреженном формате.
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	nonZero := 0
+	for _, r := range s.registers {
+		if r != 0 {
+			nonZero++
+		}
+	}
+	if nonZero*sparseEntrySize >= len(s.registers) {
+		data := make([]byte, headerSize, headerSize+len(s.registers))
+		data[0], data[1], data[2] = formatVersion, s.p, encodingDense
+		return append(data, s.registers...), nil
+	}
+	data := make([]byte, headerSize, headerSize+nonZero*sparseEntrySize)
+	data[0], data[1], data[2] = formatVersion, s.p, encodingSparse
+	for i, r := range s.registers {
+		if r != 0 {
+			data = binary.BigEndian.AppendUint16(data, uint16(i))
+			data = append(data, r)
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary - реализует encoding.BinaryUnmarshaler
+func (s *Sketch) UnmarshalBinary(data []byte) error {
+	if len(data) < headerSize || data[0] != formatVersion {
+		return ErrInvalidData
+	}
+	sketch, err := New(data[1])
+	if err != nil {
+		return ErrInvalidData
+	}
+	body := data[headerSize:]
+	switch data[2] {
+	case encodingDense:
+		if len(body) != len(sketch.registers) {
+			return ErrInvalidData
+		}
+		copy(sketch.registers, body)
+	case encodingSparse:
+		if len(body)%sparseEntrySize != 0 {
+			return ErrInvalidData
+		}
+		for i := 0; i < len(body); i += sparseEntrySize {
+			idx := int(binary.BigEndian.Uint16(body[i:]))
+			if idx >= len(sketch.registers) {
+				return ErrInvalidData
+			}
+			sketch.registers[idx] = body[i+2]
+		}
+	default:
+		return ErrInvalidData
+	}
+	*s = *sketch
+	return nil
+}
+
+// MarshalText - реализует encoding.TextMarshaler: бинарное представление в base64
+func (s *Sketch) MarshalText() ([]byte, error) {
+	data, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	text := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(text, data)
+	return text, nil
+}
+
+// UnmarshalText - реализует encoding.TextUnmarshaler
+func (s *Sketch) UnmarshalText(text []byte) error {
+	data := make([]byte, base64.StdEncoding.DecodedLen(len(text)))
+	n, err := base64.StdEncoding.Decode(data, text)
+	if err != nil {
+		return ErrInvalidData
+	}
+	return s.UnmarshalBinary(data[:n])
+}
+
+// alpha - поправочный коэффициент оценки для m регистров
+func alpha(m float64) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/m)
+	}
+}
+
+// hash - 64-битный хеш FNV-1a с финальным перемешиванием MurmurHash3 для равномерного распределения бит
+func hash(data []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,123 @@
+package hll
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("valid precision", func(t *testing.T) {
+		s, err := New(DefaultPrecision)
+		require.NoError(t, err)
+		require.Equal(t, DefaultPrecision, s.Precision())
+		require.Zero(t, s.Count())
+	})
+	t.Run("invalid precision", func(t *testing.T) {
+		_, err := New(MinPrecision - 1)
+		require.ErrorIs(t, err, ErrPrecision)
+		_, err = New(MaxPrecision + 1)
+		require.ErrorIs(t, err, ErrPrecision)
+	})
+}
+
+func TestSketch_Count(t *testing.T) {
+	for _, n := range []int{10, 1000, 100000} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			s, err := New(DefaultPrecision)
+			require.NoError(t, err)
+			for i := 0; i < n; i++ {
+				// Повторы не увеличивают оценку
+				s.Add([]byte("visitor-" + strconv.Itoa(i)))
+				s.Add([]byte("visitor-" + strconv.Itoa(i)))
+			}
+			requireEstimate(t, n, s)
+		})
+	}
+}
+
+func TestSketch_Merge(t *testing.T) {
+	a, _ := New(DefaultPrecision)
+	b, _ := New(DefaultPrecision)
+	for i := 0; i < 6000; i++ {
+		a.Add([]byte(strconv.Itoa(i)))
+	}
+	for i := 4000; i < 10000; i++ {
+		b.Add([]byte(strconv.Itoa(i)))
+	}
+	merged := a.Clone()
+	require.NoError(t, merged.Merge(b))
+	requireEstimate(t, 10000, merged)
+	// Исходный скетч не изменился
+	requireEstimate(t, 6000, a)
+
+	other, _ := New(DefaultPrecision - 1)
+	require.ErrorIs(t, a.Merge(other), ErrPrecisionMismatch)
+}
+
+func TestSketch_MarshalBinary(t *testing.T) {
+	t.Run("sparse", func(t *testing.T) {
+		s, _ := New(DefaultPrecision)
+		for i := 0; i < 100; i++ {
+			s.Add([]byte(strconv.Itoa(i)))
+		}
+		data, err := s.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, encodingSparse, data[2])
+		require.Less(t, len(data), 1<<DefaultPrecision)
+		actual := &Sketch{}
+		require.NoError(t, actual.UnmarshalBinary(data))
+		require.Equal(t, s, actual)
+	})
+	t.Run("dense", func(t *testing.T) {
+		s, _ := New(MinPrecision)
+		for i := 0; i < 1000; i++ {
+			s.Add([]byte(strconv.Itoa(i)))
+		}
+		data, err := s.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, encodingDense, data[2])
+		actual := &Sketch{}
+		require.NoError(t, actual.UnmarshalBinary(data))
+		require.Equal(t, s, actual)
+	})
+	t.Run("invalid data", func(t *testing.T) {
+		s := &Sketch{}
+		require.ErrorIs(t, s.UnmarshalBinary(nil), ErrInvalidData)
+		require.ErrorIs(t, s.UnmarshalBinary([]byte{2, DefaultPrecision, encodingDense}), ErrInvalidData)
+		require.ErrorIs(t, s.UnmarshalBinary([]byte{formatVersion, MaxPrecision + 1, encodingDense}), ErrInvalidData)
+		require.ErrorIs(t, s.UnmarshalBinary([]byte{formatVersion, MinPrecision, encodingDense, 1}), ErrInvalidData)
+		require.ErrorIs(t, s.UnmarshalBinary([]byte{formatVersion, MinPrecision, encodingSparse, 0, 16, 1}), ErrInvalidData)
+	})
+}
+
+func TestSketch_JSON(t *testing.T) {
+	s, _ := New(DefaultPrecision)
+	s.Add([]byte("visitor"))
+	data, err := json.Marshal(struct {
+		Sketch *Sketch `json:"sketch"`
+	}{s})
+	require.NoError(t, err)
+
+	var actual struct {
+		Sketch *Sketch `json:"sketch"`
+	}
+	require.NoError(t, json.Unmarshal(data, &actual))
+	require.Equal(t, s, actual.Sketch)
+	require.Equal(t, uint64(1), actual.Sketch.Count())
+}
+
+func TestRelativeError(t *testing.T) {
+	require.InDelta(t, 0.0081, RelativeError(DefaultPrecision), 0.0001)
+	require.InDelta(t, 0.26, RelativeError(MinPrecision), 0.001)
+}
+
+// requireEstimate - проверяет, что оценка скетча отличается от n не более чем на 3 стандартные ошибки
+func requireEstimate(t *testing.T, n int, s *Sketch) {
+	t.Helper()
+	delta := math.Max(1, 3*s.RelativeError()*float64(n))
+	require.InDelta(t, float64(n), float64(s.Count()), delta)
+}
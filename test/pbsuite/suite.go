@@ -1,6 +1,7 @@
 package pbsuite
 
 import (
+	"errors"
 	"net"
 	"strconv"
 
@@ -53,16 +54,20 @@ func (suite *Suite) StartServer(server *grpc.Server) {
 	suite.conn, err = grpc.Dial(":"+port, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	suite.Require().NoError(err)
 
-	// Запускаем сервер
-	suite.Server = server
-	go func() {
-		suite.Require().NoError(suite.Server.Serve(suite.listener))
-	}()
-
 	// Регистрируем сервисы
+	suite.Server = server
 	RegisterHelloServiceServer(suite.Server, &HelloService{})
 	RegisterAnswerServiceServer(suite.Server, &AnswerService{})
 
+	// Запускаем сервер.
+	// Если тест завершился раньше, чем сервер начал работу, Serve вернет grpc.ErrServerStopped.
+	listener := suite.listener
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			panic(err)
+		}
+	}()
+
 	// Подключаем клиентов
 	suite.HelloClient = NewHelloServiceClient(suite.conn)
 	suite.AnswerClient = NewAnswerServiceClient(suite.conn)